@@ -0,0 +1,120 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+provider "alicloud" {
+  region = "${var.region}"
+}
+
+data "alicloud_zones" "default" {
+  available_instance_type     = "${var.instance_type}"
+  available_resource_creation = "VSwitch"
+}
+
+data "alicloud_images" "ubuntu" {
+  owners      = "system"
+  name_regex  = "${var.image_name_regex}"
+  most_recent = true
+}
+
+resource "alicloud_vpc" "vpc" {
+  name       = "${var.cluster_name}"
+  cidr_block = "${var.vpc_cidr}"
+}
+
+resource "alicloud_vswitch" "vswitch" {
+  name              = "${var.cluster_name}"
+  vpc_id            = "${alicloud_vpc.vpc.id}"
+  cidr_block        = "${var.vswitch_cidr}"
+  availability_zone = "${data.alicloud_zones.default.zones.0.id}"
+}
+
+resource "alicloud_security_group" "common" {
+  name        = "${var.cluster_name}-common"
+  description = "cluster ${var.cluster_name} common rules"
+  vpc_id      = "${alicloud_vpc.vpc.id}"
+}
+
+resource "alicloud_security_group_rule" "ingress_self" {
+  type              = "ingress"
+  ip_protocol       = "all"
+  port_range        = "-1/-1"
+  security_group_id = "${alicloud_security_group.common.id}"
+  cidr_ip           = "${var.vpc_cidr}"
+}
+
+resource "alicloud_security_group_rule" "ingress_ssh" {
+  type              = "ingress"
+  ip_protocol       = "tcp"
+  port_range        = "${var.ssh_port}/${var.ssh_port}"
+  security_group_id = "${alicloud_security_group.common.id}"
+  cidr_ip           = "0.0.0.0/0"
+}
+
+resource "alicloud_security_group_rule" "ingress_apiserver" {
+  type              = "ingress"
+  ip_protocol       = "tcp"
+  port_range        = "6443/6443"
+  security_group_id = "${alicloud_security_group.common.id}"
+  cidr_ip           = "0.0.0.0/0"
+}
+
+resource "alicloud_key_pair" "deployer" {
+  key_name   = "${var.cluster_name}-deployer-key"
+  public_key = "${file("${var.ssh_public_key_file}")}"
+}
+
+resource "alicloud_instance" "control_plane" {
+  count         = "${var.control_plane_count}"
+  instance_name = "${var.cluster_name}-control-plane-${count.index + 1}"
+  host_name     = "${var.cluster_name}-control-plane-${count.index + 1}"
+
+  image_id        = "${data.alicloud_images.ubuntu.images.0.id}"
+  instance_type   = "${var.instance_type}"
+  security_groups = ["${alicloud_security_group.common.id}"]
+  vswitch_id      = "${alicloud_vswitch.vswitch.id}"
+  key_name        = "${alicloud_key_pair.deployer.key_name}"
+
+  system_disk_category = "cloud_efficiency"
+  system_disk_size     = "${var.disk_size}"
+
+  # a public IP is only assigned with outgoing bandwidth
+  internet_max_bandwidth_out = "${var.internet_max_bandwidth_out}"
+
+  tags = {
+    "kubernetes-cluster" = "${var.cluster_name}"
+  }
+}
+
+resource "alicloud_slb" "control_plane" {
+  name          = "${var.cluster_name}-api-lb"
+  address_type  = "internet"
+  specification = "slb.s1.small"
+}
+
+resource "alicloud_slb_listener" "apiserver" {
+  load_balancer_id          = "${alicloud_slb.control_plane.id}"
+  frontend_port             = 6443
+  backend_port              = 6443
+  protocol                  = "tcp"
+  bandwidth                 = -1
+  health_check_type         = "tcp"
+  health_check_connect_port = 6443
+}
+
+resource "alicloud_slb_attachment" "control_plane" {
+  load_balancer_id = "${alicloud_slb.control_plane.id}"
+  instance_ids     = ["${alicloud_instance.control_plane.*.id}"]
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+output "kubeone_schema_version" {
+  description = "Version of the output format expected by KubeOne"
+  value       = "2"
+}
+
+output "kubeone_api" {
+  description = "kube-apiserver LB endpoint"
+
+  value = {
+    endpoint = "${alicloud_slb.control_plane.address}"
+  }
+}
+
+output "kubeone_hosts" {
+  description = "Control plane endpoints to SSH to"
+
+  # machine-controller doesn't support Alibaba Cloud, so the cluster is
+  # created without a cloud provider and without workers
+  value = {
+    control_plane = {
+      cluster_name         = "${var.cluster_name}"
+      cloud_provider       = "none"
+      private_address      = "${alicloud_instance.control_plane.*.private_ip}"
+      public_address       = "${alicloud_instance.control_plane.*.public_ip}"
+      ssh_agent_socket     = "${var.ssh_agent_socket}"
+      ssh_port             = "${var.ssh_port}"
+      ssh_private_key_file = "${var.ssh_private_key_file}"
+      ssh_user             = "${var.ssh_username}"
+    }
+  }
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+variable "cluster_name" {
+  description = "Name of the cluster"
+}
+
+variable "region" {
+  default     = "eu-central-1"
+  description = "Alibaba Cloud region to create the cluster in"
+}
+
+variable "control_plane_count" {
+  default     = 3
+  description = "Number of control plane instances"
+}
+
+variable "vpc_cidr" {
+  default     = "172.16.0.0/16"
+  description = "CIDR of the VPC"
+}
+
+variable "vswitch_cidr" {
+  default     = "172.16.1.0/24"
+  description = "CIDR of the VSwitch the instances are attached to"
+}
+
+variable "instance_type" {
+  default     = "ecs.g5.large"
+  description = "Type of the control plane instances"
+}
+
+variable "image_name_regex" {
+  default     = "^ubuntu_18_04_x64"
+  description = "Regular expression matching the name of the system image"
+}
+
+variable "disk_size" {
+  default     = 50
+  description = "Size of the system disk in GB"
+}
+
+variable "internet_max_bandwidth_out" {
+  default     = 10
+  description = "Maximum outgoing bandwidth in Mbps, required for the instances to get a public IP"
+}
+
+variable "ssh_public_key_file" {
+  description = "SSH public key file"
+  default     = "~/.ssh/id_rsa.pub"
+}
+
+variable "ssh_port" {
+  default     = 22
+  description = "SSH port to be used to provision instances"
+}
+
+variable "ssh_username" {
+  default     = "root"
+  description = "SSH user, used only in output"
+}
+
+variable "ssh_private_key_file" {
+  description = "SSH private key file used to access instances"
+  default     = ""
+}
+
+variable "ssh_agent_socket" {
+  description = "SSH Agent socket, default to grab from $SSH_AUTH_SOCK"
+  default     = "env:SSH_AUTH_SOCK"
+}
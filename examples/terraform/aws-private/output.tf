@@ -1,3 +1,8 @@
+output "kubeone_schema_version" {
+  description = "Version of the output format expected by KubeOne"
+  value       = "2"
+}
+
 output "kubeone_api" {
   description = "kube-apiserver LB endpoint"
 
@@ -26,7 +26,8 @@ locals {
   kube_cluster_tag = "kubernetes.io/cluster/${var.cluster_name}"
   vpc_id           = "${var.vpc_id == "default" ? aws_default_vpc.default.id : var.vpc_id}"
   
-  ami = "${var.ami == "" ? data.aws_ami.ubuntu.id : var.ami}"
+  os_ami = "${lookup(map("ubuntu", data.aws_ami.ubuntu.id, "flatcar", data.aws_ami.flatcar.id, "amzn2", data.aws_ami.amzn2.id), var.os)}"
+  ami    = "${var.ami == "" ? local.os_ami : var.ami}"
 }
 
 data "aws_availability_zones" "available" {}
@@ -36,7 +37,7 @@ data "aws_ami" "ubuntu" {
 
   filter {
     name   = "name"
-    values = ["ubuntu/images/hvm-ssd/ubuntu-bionic-18.04-amd64-server-*"]
+    values = ["ubuntu/images/hvm-ssd/ubuntu-${var.ubuntu_release}-amd64-server-*"]
   }
 
   filter {
@@ -47,6 +48,38 @@ data "aws_ami" "ubuntu" {
   owners = ["099720109477"] # Canonical
 }
 
+data "aws_ami" "flatcar" {
+  most_recent = true
+
+  filter {
+    name   = "name"
+    values = ["Flatcar-stable-*"]
+  }
+
+  filter {
+    name   = "virtualization-type"
+    values = ["hvm"]
+  }
+
+  owners = ["075585003325"] # Kinvolk
+}
+
+data "aws_ami" "amzn2" {
+  most_recent = true
+
+  filter {
+    name   = "name"
+    values = ["amzn2-ami-hvm-2.0.*-x86_64-gp2"]
+  }
+
+  filter {
+    name   = "virtualization-type"
+    values = ["hvm"]
+  }
+
+  owners = ["137112412989"] # Amazon
+}
+
 data "aws_subnet_ids" "default" {
   vpc_id = "${local.vpc_id}"
 }
@@ -210,6 +243,46 @@ resource "aws_lb_target_group_attachment" "control_plane_api" {
   port             = 6443
 }
 
+resource "aws_lb" "control_plane_internal" {
+  count              = "${var.create_internal_lb ? 1 : 0}"
+  name               = "${var.cluster_name}-api-internal-lb"
+  internal           = true
+  load_balancer_type = "network"
+  subnets            = ["${local.all_subnets}"]
+
+  tags = "${map(
+    "Cluster", "${var.cluster_name}",
+    "${local.kube_cluster_tag}", "shared",
+  )}"
+}
+
+resource "aws_lb_target_group" "control_plane_api_internal" {
+  count    = "${var.create_internal_lb ? 1 : 0}"
+  name     = "${var.cluster_name}-api-internal"
+  port     = 6443
+  protocol = "TCP"
+  vpc_id   = "${local.vpc_id}"
+}
+
+resource "aws_lb_listener" "control_plane_api_internal" {
+  count             = "${var.create_internal_lb ? 1 : 0}"
+  load_balancer_arn = "${aws_lb.control_plane_internal.arn}"
+  port              = 6443
+  protocol          = "TCP"
+
+  default_action {
+    target_group_arn = "${aws_lb_target_group.control_plane_api_internal.arn}"
+    type             = "forward"
+  }
+}
+
+resource "aws_lb_target_group_attachment" "control_plane_api_internal" {
+  count            = "${var.create_internal_lb ? var.control_plane_count : 0}"
+  target_group_arn = "${aws_lb_target_group.control_plane_api_internal.arn}"
+  target_id        = "${element(aws_instance.control_plane.*.id, count.index)}"
+  port             = 6443
+}
+
 resource "aws_instance" "control_plane" {
   count = "${var.control_plane_count}"
 
@@ -14,11 +14,17 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
+output "kubeone_schema_version" {
+  description = "Version of the output format expected by KubeOne"
+  value       = "2"
+}
+
 output "kubeone_api" {
   description = "kube-apiserver LB endpoint"
 
   value = {
-    endpoint = "${aws_lb.control_plane.dns_name}"
+    endpoint              = "${aws_lb.control_plane.dns_name}"
+    api_server_lb_address = "${join("", aws_lb.control_plane_internal.*.dns_name)}"
   }
 }
 
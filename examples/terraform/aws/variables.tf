@@ -68,7 +68,22 @@ variable "control_plane_volume_size" {
   description = "Size of the EBS volume, in Gb"
 }
 
+variable "os" {
+  default     = "ubuntu"
+  description = "Operating system of the control plane instances, ubuntu, flatcar (login with the core user) or amzn2 (login with the ec2-user user)"
+}
+
+variable "ubuntu_release" {
+  default     = "bionic-18.04"
+  description = "Ubuntu release of the control plane instances, e.g. jammy-22.04"
+}
+
 variable "ami" {
   default     = ""
   description = "AMI ID, use it to fixate control-plane AMI in order to avoid force-recreation it at later times"
 }
+
+variable "create_internal_lb" {
+  default     = false
+  description = "Create an internal NLB for kube-apiserver, reachable only from within the VPC"
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+provider "openstack" {
+  auth_url    = "https://auth.cloud.ovh.net/v3"
+  domain_name = "default"
+  tenant_id   = "${var.project_id}"
+  user_name   = "${var.openstack_user_name}"
+  password    = "${var.openstack_password}"
+  region      = "${var.region}"
+}
+
+resource "openstack_compute_keypair_v2" "deployer" {
+  name       = "${var.cluster_name}-deployer-key"
+  public_key = "${file("${var.ssh_public_key_file}")}"
+}
+
+resource "openstack_compute_instance_v2" "control_plane" {
+  count = "${var.control_plane_count}"
+
+  name        = "${var.cluster_name}-cp-${count.index}"
+  image_name  = "${var.image}"
+  flavor_name = "${var.control_plane_flavor}"
+  key_pair    = "${openstack_compute_keypair_v2.deployer.name}"
+
+  # instances attached to Ext-Net get a public IPv4 address
+  network {
+    name = "Ext-Net"
+  }
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+output "kubeone_schema_version" {
+  description = "Version of the output format expected by KubeOne"
+  value       = "2"
+}
+
+output "kubeone_api" {
+  description = "kube-apiserver endpoint"
+
+  # there is no load balancer, the API is reached through the first
+  # control plane instance
+  value = {
+    endpoint = "${openstack_compute_instance_v2.control_plane.0.access_ip_v4}"
+  }
+}
+
+output "kubeone_hosts" {
+  description = "Control plane endpoints to SSH to"
+
+  # workers aren't created, so no cloud provider credentials need to be
+  # deployed on the cluster
+  value = {
+    control_plane = {
+      cluster_name         = "${var.cluster_name}"
+      cloud_provider       = "none"
+      private_address      = "${openstack_compute_instance_v2.control_plane.*.access_ip_v4}"
+      public_address       = "${openstack_compute_instance_v2.control_plane.*.access_ip_v4}"
+      ssh_agent_socket     = "${var.ssh_agent_socket}"
+      ssh_port             = "${var.ssh_port}"
+      ssh_private_key_file = "${var.ssh_private_key_file}"
+      ssh_user             = "${var.ssh_username}"
+    }
+  }
+}
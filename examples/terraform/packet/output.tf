@@ -14,6 +14,11 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
+output "kubeone_schema_version" {
+  description = "Version of the output format expected by KubeOne"
+  value       = "2"
+}
+
 output "kubeone_api" {
   description = "kube-apiserver LB endpoint"
 
@@ -24,6 +24,7 @@ BUILD_ID=${BUILD_ID:-"${USER}-local"}
 PROVIDER=${PROVIDER:-"aws"}
 TEST_SET=${TEST_SET:-"conformance"}
 TEST_CLUSTER_TARGET_VERSION=${TEST_CLUSTER_VERSION:-"v1.14.1"}
+TEST_MATRIX=${TEST_MATRIX:-"test/e2e/testdata/test-matrix.yaml"}
 export TF_VAR_cluster_name=${BUILD_ID}
 
 # Install dependencies
@@ -64,21 +65,14 @@ function cleanup() {
     sleep ${try}s
   done
 }
-trap cleanup EXIT
-
-# If the following variable is set then this script is running in CI
-# and the assumption is that the image contains kubernetes binaries
-#
-# note:
-# kubetest assumes that the last part of that path contains "kubernetes", if not then it complains,
-# additionally the version must be in a very specific format.
-if [ -n "${RUNNING_IN_CI}" ]; then
-  # set up terraform remote backend configuration
-  for dir in ${TERRAFORM_DIR}/*; do
-    ln -s "$(go env GOPATH)/src/github.com/kubermatic/kubeone/test/e2e/testdata/s3_backend.tf" $dir/s3_backend.tf
-  done
+# the matrix provisions each combination in its own terraform directory,
+# which is destroyed by the tests
+if [[ ${TEST_SET} != "matrix" ]]; then
+  trap cleanup EXIT
+fi
 
-  case ${PROVIDER} in
+function exportCredentials() {
+  case $1 in
   "aws")
     export AWS_ACCESS_KEY_ID=${AWS_E2E_TESTS_KEY_ID}
     export AWS_SECRET_ACCESS_KEY=${AWS_E2E_TESTS_SECRET}
@@ -89,11 +83,41 @@ if [ -n "${RUNNING_IN_CI}" ]; then
   "hetzner")
     export HCLOUD_TOKEN=${HZ_E2E_TOKEN}
     ;;
+  "alicloud")
+    export ALICLOUD_ACCESS_KEY=${ALICLOUD_E2E_TESTS_KEY}
+    export ALICLOUD_SECRET_KEY=${ALICLOUD_E2E_TESTS_SECRET}
+    ;;
+  "ovh")
+    export TF_VAR_project_id=${OVH_E2E_TESTS_PROJECT_ID}
+    export TF_VAR_openstack_user_name=${OVH_E2E_TESTS_OPENSTACK_USER_NAME}
+    export TF_VAR_openstack_password=${OVH_E2E_TESTS_OPENSTACK_PASSWORD}
+    ;;
   *)
-    echo "unknown provider ${PROVIDER}"
+    echo "unknown provider $1"
     exit -1
     ;;
   esac
+}
+
+# If the following variable is set then this script is running in CI
+# and the assumption is that the image contains kubernetes binaries
+#
+# note:
+# kubetest assumes that the last part of that path contains "kubernetes", if not then it complains,
+# additionally the version must be in a very specific format.
+if [ -n "${RUNNING_IN_CI}" ]; then
+  # set up terraform remote backend configuration
+  for dir in ${TERRAFORM_DIR}/*; do
+    ln -s "$(go env GOPATH)/src/github.com/kubermatic/kubeone/test/e2e/testdata/s3_backend.tf" $dir/s3_backend.tf
+  done
+
+  if [[ ${TEST_SET} == "matrix" ]]; then
+    for provider in $(grep -oP '^\s*-?\s*provider:\s*\K\S+' ${TEST_MATRIX} | sort -u); do
+      exportCredentials ${provider}
+    done
+  else
+    exportCredentials ${PROVIDER}
+  fi
 
   KUBE_TEST_DIR="/opt/kube-test"
   if [ -d "${KUBE_TEST_DIR}" ]; then
@@ -135,6 +159,7 @@ make install
 function runE2E() {
   local test_set=$1
   local timeout=$2
+  shift 2
   set -x
 
   go test \
@@ -146,7 +171,8 @@ function runE2E() {
     ./test/e2e/... \
     -identifier=${BUILD_ID} \
     -provider=${PROVIDER} \
-    -cluster-version=${TEST_CLUSTER_TARGET_VERSION}
+    -cluster-version=${TEST_CLUSTER_TARGET_VERSION} \
+    "$@"
 }
 
 # Start the tests
@@ -158,6 +184,23 @@ case ${TEST_SET} in
 "upgrades")
   runE2E "TestClusterUpgrade" "120m"
   ;;
+"machineset")
+  runE2E "TestMachineSetReady" "60m"
+  ;;
+"crio")
+  runE2E "TestCRIOClusterConformance" "60m"
+  ;;
+"scenario")
+  # TEST_SCENARIO selects the scenario, defaults to basic
+  runE2E "TestSelectedScenario" "120m"
+  ;;
+"matrix")
+  # all combinations are run unless MATRIX_PROVIDER or MATRIX_CLUSTER_VERSION are set
+  runE2E "TestClusterMatrix" "180m" \
+    -test-matrix="$(pwd)/${TEST_MATRIX}" \
+    -provider="${MATRIX_PROVIDER:-}" \
+    -cluster-version="${MATRIX_CLUSTER_VERSION:-}"
+  ;;
 *)
   echo "unknown TEST_SET: ${TEST_SET}"
   exit -1
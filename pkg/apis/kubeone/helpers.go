@@ -18,6 +18,8 @@ package kubeone
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/Masterminds/semver"
 )
@@ -39,14 +41,59 @@ func (c KubeOneCluster) Followers() []HostConfig {
 	return c.Hosts[1:]
 }
 
+// WorkerCloudProvider returns the cloud provider the workerset is created on,
+// defaulting to the machine-controller provider
+func (c KubeOneCluster) WorkerCloudProvider(w WorkerConfig) CloudProviderName {
+	if w.Config.CloudProvider != "" {
+		return w.Config.CloudProvider
+	}
+	if c.MachineController != nil && c.MachineController.Provider != "" {
+		return c.MachineController.Provider
+	}
+	return c.CloudProvider.Name
+}
+
+// WorkerCloudProviders returns the distinct cloud providers the workersets
+// are created on, in the order of the workersets
+func (c KubeOneCluster) WorkerCloudProviders() []CloudProviderName {
+	seen := make(map[CloudProviderName]bool)
+	providers := []CloudProviderName{}
+	for _, w := range c.Workers {
+		p := c.WorkerCloudProvider(w)
+		if !seen[p] {
+			seen[p] = true
+			providers = append(providers, p)
+		}
+	}
+	return providers
+}
+
+// ImageFor returns the given image reference pulled from the configured
+// image repository, if any. Like with kubeadm, the registry is replaced,
+// but the rest of the path is kept, e.g. quay.io/calico/cni:v3.4.0
+// becomes <imageRepository>/calico/cni:v3.4.0.
+func (c KubeOneCluster) ImageFor(image string) string {
+	if c.ImageRepository == "" {
+		return image
+	}
+
+	parts := strings.SplitN(image, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		image = parts[1]
+	}
+
+	return strings.TrimSuffix(c.ImageRepository, "/") + "/" + image
+}
+
 // SetHostname sets the hostname for the given host
 func (h *HostConfig) SetHostname(hostname string) {
 	h.Hostname = hostname
 }
 
-// SetOperatingSystem sets the operating system for the given host
-func (h *HostConfig) SetOperatingSystem(os string) {
+// SetOperatingSystem sets the operating system and its version for the given host
+func (h *HostConfig) SetOperatingSystem(os OperatingSystemName, version string) {
 	h.OperatingSystem = os
+	h.OperatingSystemVersion = version
 }
 
 // SetLeader sets is the given host leader
@@ -80,3 +127,66 @@ func (m VersionConfig) KubernetesCNIVersion() string {
 		return "0.7.5"
 	}
 }
+
+// CRIOVersion returns the CRI-O release matching the Kubernetes version.
+// CRI-O follows the Kubernetes minor versions, e.g. CRI-O 1.14 is
+// used with Kubernetes 1.14.
+func (m VersionConfig) CRIOVersion() string {
+	s := semver.MustParse(m.Kubernetes)
+	return fmt.Sprintf("%d.%d", s.Major(), s.Minor())
+}
+
+// CgroupV2Supported returns whether kubelet supports nodes booted with the
+// unified cgroup v2 hierarchy, which is the case since Kubernetes 1.25
+func (m VersionConfig) CgroupV2Supported() bool {
+	s := semver.MustParse(m.Kubernetes)
+	return s.Major() > 1 || s.Minor() >= 25
+}
+
+// EvictionHard returns the kubelet --eviction-hard flag for the configured
+// thresholds, or an empty string if the kubelet defaults are kept. The
+// flag replaces all the default thresholds, so they are repeated.
+func (c KubeletConfig) EvictionHard() string {
+	if c.DiskPressureThreshold == "" && c.InodePressureThreshold == "" {
+		return ""
+	}
+
+	nodefs, imagefs, inodes := "10%", "15%", "5%"
+	if c.DiskPressureThreshold != "" {
+		nodefs, imagefs = c.DiskPressureThreshold, c.DiskPressureThreshold
+	}
+	if c.InodePressureThreshold != "" {
+		inodes = c.InodePressureThreshold
+	}
+
+	return fmt.Sprintf("memory.available<100Mi,nodefs.available<%s,nodefs.inodesFree<%s,imagefs.available<%s", nodefs, inodes, imagefs)
+}
+
+// Service returns the name of the systemd service of the container runtime
+func (c ContainerRuntimeConfig) Service() string {
+	if c.Runtime == ContainerRuntimeCRIO {
+		return "crio"
+	}
+	return "docker"
+}
+
+// DockerHubRegistry is the registry whose mirrors Docker supports
+const DockerHubRegistry = "docker.io"
+
+// Apt returns the base URL of the Kubernetes apt repository, which is
+// the configured mirror if any.
+func (m PackageMirrorsConfig) Apt() string {
+	if m.AptMirror == "" {
+		return "https://packages.cloud.google.com/apt"
+	}
+	return strings.TrimSuffix(m.AptMirror, "/")
+}
+
+// Yum returns the base URL of the Kubernetes yum repository, which is
+// the configured mirror if any.
+func (m PackageMirrorsConfig) Yum() string {
+	if m.YumMirror == "" {
+		return "https://packages.cloud.google.com/yum"
+	}
+	return strings.TrimSuffix(m.YumMirror, "/")
+}
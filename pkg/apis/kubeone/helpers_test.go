@@ -0,0 +1,64 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeone
+
+import (
+	"testing"
+)
+
+func TestImageFor(t *testing.T) {
+	testcases := []struct {
+		name            string
+		imageRepository string
+		image           string
+		expectedImage   string
+	}{
+		{
+			name:          "no image repository",
+			image:         "quay.io/calico/cni:v3.4.0",
+			expectedImage: "quay.io/calico/cni:v3.4.0",
+		},
+		{
+			name:            "registry is replaced",
+			imageRepository: "registry.local:5000",
+			image:           "quay.io/calico/cni:v3.4.0",
+			expectedImage:   "registry.local:5000/calico/cni:v3.4.0",
+		},
+		{
+			name:            "docker hub image without registry",
+			imageRepository: "registry.local:5000",
+			image:           "kubermatic/machine-controller:v1.1.5",
+			expectedImage:   "registry.local:5000/kubermatic/machine-controller:v1.1.5",
+		},
+		{
+			name:            "image without path",
+			imageRepository: "registry.local/",
+			image:           "k8s.gcr.io/metrics-server-amd64:v0.3.1",
+			expectedImage:   "registry.local/metrics-server-amd64:v0.3.1",
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			cluster := KubeOneCluster{ImageRepository: tc.imageRepository}
+			if image := cluster.ImageFor(tc.image); image != tc.expectedImage {
+				t.Fatalf("expected image %q, but got %q", tc.expectedImage, image)
+			}
+		})
+	}
+}
@@ -19,6 +19,8 @@ package kubeone
 import (
 	"encoding/json"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -40,12 +42,38 @@ type KubeOneCluster struct {
 	Versions VersionConfig `json:"versions,omitempty"`
 	// ClusterNetwork configures the in-cluster networking
 	ClusterNetwork ClusterNetworkConfig `json:"clusterNetwork,omitempty"`
+	// KubeProxy configures the kube-proxy mode
+	KubeProxy KubeProxyConfig `json:"kubeProxy,omitempty"`
+	// Kubelet configures the kubelet running on the control plane nodes
+	Kubelet KubeletConfig `json:"kubelet,omitempty"`
+	// CoreDNS configures the cluster DNS
+	CoreDNS CoreDNSConfig `json:"coreDNS,omitempty"`
+	// ContainerRuntime configures the container runtime installed on the control plane nodes
+	ContainerRuntime ContainerRuntimeConfig `json:"containerRuntime,omitempty"`
 	// Proxy configures proxy used while installing Kubernetes and by the Docker daemon
 	Proxy ProxyConfig `json:"proxy,omitempty"`
+	// ImageRepository overrides the registry all the images are pulled from,
+	// which is used to install clusters without internet access
+	ImageRepository string `json:"imageRepository,omitempty"`
+	// PackageMirrors configures the package repositories kubeadm, kubelet
+	// and kubectl are installed from
+	PackageMirrors PackageMirrorsConfig `json:"packageMirrors,omitempty"`
+	// OSUpgrade configures the upgrade of the operating system packages
+	OSUpgrade OSUpgradeConfig `json:"osUpgrade,omitempty"`
 	// Workers is used to create worker nodes using the Kubermatic machine-controller
 	Workers []WorkerConfig `json:"workers,omitempty"`
 	// MachineController configures the Kubermatic machine-controller component
 	MachineController *MachineControllerConfig `json:"machineController,omitempty"`
+	// CSI configures the CSI driver providing persistent storage
+	CSI *CSIConfig `json:"csi,omitempty"`
+	// ClusterAutoscaler configures the cluster-autoscaler
+	ClusterAutoscaler *ClusterAutoscalerConfig `json:"clusterAutoscaler,omitempty"`
+	// Velero configures backups of the cluster
+	Velero *VeleroConfig `json:"velero,omitempty"`
+	// NodeLocalDNS configures the NodeLocal DNSCache
+	NodeLocalDNS *NodeLocalDNSConfig `json:"nodeLocalDNS,omitempty"`
+	// Monitoring configures the integration with the Prometheus Operator
+	Monitoring *MonitoringConfig `json:"monitoring,omitempty"`
 	// Features enables and configures additional cluster features
 	Features Features `json:"features,omitempty"`
 	// Credentials used for machine-controller and external CCM
@@ -60,14 +88,37 @@ type HostConfig struct {
 	SSHPort           int    `json:"sshPort"`
 	SSHUsername       string `json:"sshUsername"`
 	SSHPrivateKeyFile string `json:"sshPrivateKeyFile"`
-	SSHAgentSocket    string `json:"sshAgentSocket"`
+	// SSHPrivateKeyFiles are tried in order, after SSHPrivateKeyFile, until one
+	// authenticates, e.g. while the keys are being rotated
+	SSHPrivateKeyFiles []string `json:"sshPrivateKeyFiles,omitempty"`
+	SSHAgentSocket     string   `json:"sshAgentSocket"`
+	// SSHStrictHostKeyChecking verifies the host key against SSHKnownHostsFile
+	// instead of accepting any host key
+	SSHStrictHostKeyChecking bool `json:"sshStrictHostKeyChecking,omitempty"`
+	// SSHKnownHostsFile defaults to ~/.ssh/known_hosts
+	SSHKnownHostsFile string `json:"sshKnownHostsFile,omitempty"`
 
 	// Information populated at the runtime
-	Hostname        string `json:"-"`
-	OperatingSystem string `json:"-"`
-	IsLeader        bool   `json:"-"`
+	Hostname               string              `json:"-"`
+	OperatingSystem        OperatingSystemName `json:"-"`
+	OperatingSystemVersion string              `json:"-"`
+	IsLeader               bool                `json:"-"`
 }
 
+// OperatingSystemName is the distribution of a host, as identified by
+// the ID field of its /etc/os-release
+type OperatingSystemName string
+
+// List of supported operating systems
+const (
+	OperatingSystemNameUbuntu      OperatingSystemName = "ubuntu"
+	OperatingSystemNameDebian      OperatingSystemName = "debian"
+	OperatingSystemNameCentOS      OperatingSystemName = "centos"
+	OperatingSystemNameAmazonLinux OperatingSystemName = "amzn"
+	OperatingSystemNameCoreOS      OperatingSystemName = "coreos"
+	OperatingSystemNameFlatcar     OperatingSystemName = "flatcar"
+)
+
 // APIEndpoint is the endpoint used to communicate with the Kubernetes API
 type APIEndpoint struct {
 	// Host is the hostname on which API is running
@@ -97,6 +148,8 @@ type CloudProviderSpec struct {
 	Name        CloudProviderName `json:"name"`
 	External    bool              `json:"external"`
 	CloudConfig string            `json:"cloudConfig"`
+	// CCMVersion overrides the version of the external cloud controller manager
+	CCMVersion string `json:"ccmVersion,omitempty"`
 }
 
 // VersionConfig describes the versions of components that are installed on the machines
@@ -143,13 +196,98 @@ type CNI struct {
 	Encrypted bool `json:"encrypted"`
 }
 
-// ProxyConfig configures proxy for the Docker daemon and is used by KubeOne scripts
+// ContainerRuntime type
+type ContainerRuntime string
+
+// List of container runtimes
+const (
+	// ContainerRuntimeDocker is the Docker container runtime
+	ContainerRuntimeDocker ContainerRuntime = "docker"
+
+	// ContainerRuntimeCRIO is the CRI-O container runtime. kubelet talks
+	// to it over the CRI socket /var/run/crio/crio.sock.
+	// More info: https://cri-o.io
+	ContainerRuntimeCRIO ContainerRuntime = "crio"
+)
+
+// ContainerRuntimeConfig configures the container runtime
+type ContainerRuntimeConfig struct {
+	// Runtime choice
+	Runtime ContainerRuntime `json:"runtime"`
+	// RegistryMirrors are the mirrors images are pulled from, keyed by the
+	// registry host they mirror, e.g. docker.io. The mirrors are URLs, e.g.
+	// https://mirror.local:5000. Docker only supports mirrors of docker.io.
+	RegistryMirrors map[string][]string `json:"registryMirrors,omitempty"`
+}
+
+// KubeProxyMode type
+type KubeProxyMode string
+
+// List of kube-proxy modes
+const (
+	// KubeProxyModeIPTables implements services using iptables rules
+	KubeProxyModeIPTables KubeProxyMode = "iptables"
+
+	// KubeProxyModeIPVS implements services using IPVS virtual servers
+	KubeProxyModeIPVS KubeProxyMode = "ipvs"
+)
+
+// KubeProxyConfig configures kube-proxy
+type KubeProxyConfig struct {
+	// Mode is either iptables or ipvs, defaults to the kube-proxy default
+	Mode KubeProxyMode `json:"mode,omitempty"`
+	// IPVSScheduler is the IPVS scheduling algorithm, e.g. rr or lc
+	IPVSScheduler string `json:"ipvsScheduler,omitempty"`
+}
+
+// KubeletConfig configures kubelet
+type KubeletConfig struct {
+	// DiskPressureThreshold is the free space of the node and image
+	// filesystems below which pods are evicted, e.g. 10% or 5Gi,
+	// defaults to the kubelet defaults of 10% and 15%
+	DiskPressureThreshold string `json:"diskPressureThreshold,omitempty"`
+	// InodePressureThreshold is the number of free inodes of the node
+	// filesystem below which pods are evicted, e.g. 5% or 100000,
+	// defaults to the kubelet default of 5%
+	InodePressureThreshold string `json:"inodePressureThreshold,omitempty"`
+}
+
+// CoreDNSConfig configures the CoreDNS deployed by kubeadm
+type CoreDNSConfig struct {
+	// Replicas of the CoreDNS Deployment, defaults to the kubeadm default
+	Replicas int32 `json:"replicas,omitempty"`
+	// CPURequest of the CoreDNS container, e.g. 100m
+	CPURequest string `json:"cpuRequest,omitempty"`
+	// MemoryRequest of the CoreDNS container, e.g. 70Mi
+	MemoryRequest string `json:"memoryRequest,omitempty"`
+	// Corefile replaces the CoreDNS configuration generated by kubeadm
+	Corefile string `json:"corefile,omitempty"`
+}
+
+// ProxyConfig configures proxy for the container runtime and kubelet, is set
+// system-wide on the nodes and is used by KubeOne scripts
 type ProxyConfig struct {
 	HTTP    string `json:"http"`
 	HTTPS   string `json:"https"`
 	NoProxy string `json:"noProxy"`
 }
 
+// OSUpgradeConfig configures the operating system packages upgraded by
+// 'kubeone upgrade --include-os-packages'
+type OSUpgradeConfig struct {
+	// Packages limits the upgrade to the given packages, all packages are
+	// upgraded if empty
+	Packages []string `json:"packages,omitempty"`
+}
+
+// PackageMirrorsConfig configures mirrors for the upstream Kubernetes package repositories
+type PackageMirrorsConfig struct {
+	// AptMirror replaces https://packages.cloud.google.com/apt on Debian and Ubuntu
+	AptMirror string `json:"aptMirror,omitempty"`
+	// YumMirror replaces https://packages.cloud.google.com/yum on CentOS and Amazon Linux
+	YumMirror string `json:"yumMirror,omitempty"`
+}
+
 // WorkerConfig describes a set of worker machines
 type WorkerConfig struct {
 	Name     string       `json:"name"`
@@ -159,11 +297,34 @@ type WorkerConfig struct {
 
 // ProviderSpec describes a worker node
 type ProviderSpec struct {
+	// CloudProvider the worker machines are created on, defaults to the
+	// machine-controller provider. It allows creating workers on a different
+	// cloud provider than the control plane, in which case its credentials
+	// must be provided as well.
+	CloudProvider       CloudProviderName `json:"cloudProvider,omitempty"`
 	CloudProviderSpec   json.RawMessage   `json:"cloudProviderSpec"`
 	Labels              map[string]string `json:"labels"`
 	SSHPublicKeys       []string          `json:"sshPublicKeys"`
 	OperatingSystem     string            `json:"operatingSystem"`
 	OperatingSystemSpec json.RawMessage   `json:"operatingSystemSpec"`
+	// DiskSize is the size of the root disk of the worker machines in GiB.
+	// Only supported on AWS and GCE, it takes precedence over the diskSize
+	// of the cloudProviderSpec.
+	DiskSize int32 `json:"diskSize,omitempty"`
+	// DataDisks are additional disks attached to the worker machines, e.g.
+	// for Ceph or Rook. Only supported on AWS and GCE.
+	DataDisks []DataDisk `json:"dataDisks,omitempty"`
+}
+
+// DataDisk describes an additional disk of the worker machines
+type DataDisk struct {
+	// Size of the disk in GiB
+	Size int32 `json:"size"`
+	// Type of the disk, e.g. gp2 or io1 on AWS and pd-standard or pd-ssd
+	// on GCE, defaults to the default type of the cloud provider
+	Type string `json:"type,omitempty"`
+	// IOPS provisioned for the disk, only supported for io1 disks on AWS
+	IOPS int32 `json:"iops,omitempty"`
 }
 
 // MachineControllerConfig configures kubermatic machine-controller deployment
@@ -172,6 +333,152 @@ type MachineControllerConfig struct {
 	// Provider is provider to be used for machine-controller
 	// Defaults and must be same as chosen cloud provider, unless cloud provider is set to None
 	Provider CloudProviderName `json:"provider"`
+	// LeaderElectionLeaseDuration is the duration that non-leader candidates will wait
+	// to force acquire leadership. Machine-controller default is used if not set.
+	LeaderElectionLeaseDuration *metav1.Duration `json:"leaderElectionLeaseDuration,omitempty"`
+	// LeaderElectionRenewDeadline is the duration that the acting leader will retry
+	// refreshing leadership before giving up. Must be less than the lease duration.
+	LeaderElectionRenewDeadline *metav1.Duration `json:"leaderElectionRenewDeadline,omitempty"`
+	// LeaderElectionRetryPeriod is the duration the leader election clients should wait
+	// between tries of actions
+	LeaderElectionRetryPeriod *metav1.Duration `json:"leaderElectionRetryPeriod,omitempty"`
+	// Resources are compute resources required by the machine-controller container
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// TopologySpreadConstraints describes how machine-controller pods are spread across topology domains.
+	// The constraints are rendered as preferred pod anti-affinity.
+	TopologySpreadConstraints []TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+	// CredentialsSecretRef references a Secret in the kube-system namespace holding the
+	// cloud provider credentials, which are then loaded as environment variables of
+	// machine-controller instead of being read from the local environment
+	CredentialsSecretRef *corev1.SecretReference `json:"credentialsSecretRef,omitempty"`
+	// WebhookTimeoutSeconds is how long the API server waits for the machine-controller
+	// webhook, between 1 and 30 seconds. Requires Kubernetes 1.14 or newer. Defaults to 30.
+	WebhookTimeoutSeconds int32 `json:"webhookTimeoutSeconds,omitempty"`
+	// WorkerCount is the number of worker threads machine-controller uses to process
+	// machines, between 1 and 50. Defaults to 5.
+	WorkerCount int32 `json:"workerCount,omitempty"`
+	// MetricsPort is the port of the machine-controller Service exposing the
+	// Prometheus metrics. Defaults to 8080.
+	MetricsPort int32 `json:"metricsPort,omitempty"`
+	// UpdateStrategy is the strategy used to replace the machine-controller pods.
+	// Defaults to RollingUpdate with maxUnavailable 0 and maxSurge 1.
+	UpdateStrategy *appsv1.DeploymentStrategy `json:"updateStrategy,omitempty"`
+}
+
+// UnsatisfiableConstraintAction defines what to do when a topology spread constraint can't be satisfied
+type UnsatisfiableConstraintAction string
+
+// List of unsatisfiable constraint actions
+const (
+	// DoNotSchedule prevents scheduling pods when the constraint can't be satisfied
+	DoNotSchedule UnsatisfiableConstraintAction = "DoNotSchedule"
+	// ScheduleAnyway schedules pods even when the constraint can't be satisfied
+	ScheduleAnyway UnsatisfiableConstraintAction = "ScheduleAnyway"
+)
+
+// TopologySpreadConstraint describes how pods are spread across the topology domains
+type TopologySpreadConstraint struct {
+	// MaxSkew is the maximum permitted difference in number of pods between any two topology domains
+	MaxSkew int32 `json:"maxSkew"`
+	// TopologyKey is the node label key used to identify the topology domain
+	TopologyKey string `json:"topologyKey"`
+	// WhenUnsatisfiable defines what to do when the constraint can't be satisfied
+	WhenUnsatisfiable UnsatisfiableConstraintAction `json:"whenUnsatisfiable"`
+}
+
+// ClusterAutoscalerConfig configures the cluster-autoscaler scaling
+// the worker MachineDeployments
+type ClusterAutoscalerConfig struct {
+	// Enabled deploys the cluster-autoscaler
+	Enabled bool `json:"enabled"`
+	// Version of the cluster-autoscaler image, defaults to the version tested with KubeOne
+	Version string `json:"version,omitempty"`
+	// MinNodes is the minimum number of nodes of each MachineDeployment
+	MinNodes int `json:"minNodes"`
+	// MaxNodes is the maximum number of nodes of each MachineDeployment
+	MaxNodes int `json:"maxNodes"`
+	// ScaleDownDelay is how long after a scale up scale down evaluation
+	// resumes. cluster-autoscaler default is used if not set.
+	ScaleDownDelay metav1.Duration `json:"scaleDownDelay,omitempty"`
+}
+
+// VeleroBackend type
+type VeleroBackend string
+
+// List of object storage backends Velero stores backups in
+const (
+	// VeleroBackendS3 stores backups in an AWS S3 bucket
+	VeleroBackendS3 VeleroBackend = "s3"
+
+	// VeleroBackendGCS stores backups in a Google Cloud Storage bucket
+	VeleroBackendGCS VeleroBackend = "gcs"
+
+	// VeleroBackendAzure stores backups in an Azure Blob Storage container
+	VeleroBackendAzure VeleroBackend = "azure"
+)
+
+// VeleroConfig configures Velero backing up the cluster
+type VeleroConfig struct {
+	// Enabled deploys Velero and a daily backup schedule
+	Enabled bool `json:"enabled"`
+	// Version of Velero, defaults to the version tested with KubeOne
+	Version string `json:"version,omitempty"`
+	// BackendType is the object storage backups are stored in
+	BackendType VeleroBackend `json:"backendType"`
+	// BucketName is the bucket, or Azure container, backups are stored in
+	BucketName string `json:"bucketName"`
+	// Region of the bucket, required for the s3 backend
+	Region string `json:"region,omitempty"`
+}
+
+// MonitoringConfig configures the integration with the Prometheus Operator
+type MonitoringConfig struct {
+	// Enabled creates ServiceMonitors for the components deployed by KubeOne.
+	// Requires the Prometheus Operator CRDs to be installed.
+	Enabled bool `json:"enabled"`
+}
+
+// NodeLocalDNSConfig configures the NodeLocal DNSCache running on every node
+type NodeLocalDNSConfig struct {
+	// Enabled deploys the NodeLocal DNSCache
+	Enabled bool `json:"enabled"`
+	// LocalAddress is the link-local IP address the cache listens on
+	LocalAddress string `json:"localAddress,omitempty"`
+}
+
+// CSIDriver type
+type CSIDriver string
+
+// List of CSI drivers
+const (
+	// CSIDriverAWSEBS is the Amazon Elastic Block Store CSI driver
+	// More info: https://github.com/kubernetes-sigs/aws-ebs-csi-driver
+	CSIDriverAWSEBS CSIDriver = "aws-ebs"
+
+	// CSIDriverGCPPD is the Google Compute Engine Persistent Disk CSI driver
+	// More info: https://github.com/kubernetes-sigs/gcp-compute-persistent-disk-csi-driver
+	CSIDriverGCPPD CSIDriver = "gcp-pd"
+)
+
+// CSIConfig configures the CSI driver
+type CSIConfig struct {
+	// Driver choice, must match the cloud provider
+	Driver CSIDriver `json:"driver"`
+	// Version of the driver image, defaults to the version tested with KubeOne
+	Version string `json:"version,omitempty"`
+	// StorageClasses provisioning volumes using the driver
+	StorageClasses []StorageClass `json:"storageClasses,omitempty"`
+}
+
+// StorageClass describes a StorageClass provisioning volumes using the CSI driver
+type StorageClass struct {
+	Name string `json:"name"`
+	// Default marks the StorageClass as the default one of the cluster
+	Default bool `json:"default,omitempty"`
+	// ReclaimPolicy is either Delete (default) or Retain
+	ReclaimPolicy string `json:"reclaimPolicy,omitempty"`
+	// Parameters are passed to the driver, e.g. the volume type
+	Parameters map[string]string `json:"parameters,omitempty"`
 }
 
 // Features controls what features will be enabled on the cluster
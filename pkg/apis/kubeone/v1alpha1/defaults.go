@@ -17,7 +17,12 @@ limitations under the License.
 package v1alpha1
 
 import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 const (
@@ -29,6 +34,28 @@ const (
 	DefaultServiceDNS = "cluster.local"
 	// DefaultNodePortRange defines the default NodePort range
 	DefaultNodePortRange = "30000-32767"
+	// DefaultMachineControllerCPURequest defines the default CPU request for machine-controller
+	DefaultMachineControllerCPURequest = "50m"
+	// DefaultMachineControllerMemoryRequest defines the default memory request for machine-controller
+	DefaultMachineControllerMemoryRequest = "64Mi"
+	// DefaultMachineControllerCPULimit defines the default CPU limit for machine-controller
+	DefaultMachineControllerCPULimit = "500m"
+	// DefaultMachineControllerMemoryLimit defines the default memory limit for machine-controller
+	DefaultMachineControllerMemoryLimit = "512Mi"
+	// DefaultMachineControllerWebhookTimeoutSeconds defines the default timeout of the machine-controller webhook
+	DefaultMachineControllerWebhookTimeoutSeconds = 30
+	// DefaultMachineControllerWorkerCount defines the default number of machine-controller worker threads
+	DefaultMachineControllerWorkerCount = 5
+	// DefaultMachineControllerMetricsPort defines the default port of the machine-controller metrics Service
+	DefaultMachineControllerMetricsPort = 8080
+	// DefaultMachineControllerMaxUnavailable defines the default number of machine-controller pods
+	// that can be unavailable during a rolling update
+	DefaultMachineControllerMaxUnavailable = 0
+	// DefaultMachineControllerMaxSurge defines the default number of machine-controller pods
+	// created above the desired number during a rolling update
+	DefaultMachineControllerMaxSurge = 1
+	// DefaultNodeLocalDNSAddress defines the default link-local address the NodeLocal DNSCache listens on
+	DefaultNodeLocalDNSAddress = "169.254.20.10"
 )
 
 func addDefaultingFuncs(scheme *runtime.Scheme) error {
@@ -39,7 +66,9 @@ func SetDefaults_KubeOneCluster(obj *KubeOneCluster) {
 	SetDefaults_Hosts(obj)
 	SetDefaults_APIEndpoints(obj)
 	SetDefaults_ClusterNetwork(obj)
+	SetDefaults_ContainerRuntime(obj)
 	SetDefaults_MachineController(obj)
+	SetDefaults_NodeLocalDNS(obj)
 	SetDefaults_Features(obj)
 }
 
@@ -93,6 +122,12 @@ func SetDefaults_ClusterNetwork(obj *KubeOneCluster) {
 	}
 }
 
+func SetDefaults_ContainerRuntime(obj *KubeOneCluster) {
+	if obj.ContainerRuntime.Runtime == "" {
+		obj.ContainerRuntime.Runtime = ContainerRuntimeDocker
+	}
+}
+
 func SetDefaults_MachineController(obj *KubeOneCluster) {
 	if obj.MachineController == nil {
 		obj.MachineController = &MachineControllerConfig{
@@ -103,6 +138,59 @@ func SetDefaults_MachineController(obj *KubeOneCluster) {
 	if obj.MachineController.Provider == "" {
 		obj.MachineController.Provider = obj.CloudProvider.Name
 	}
+
+	if obj.MachineController.Resources == nil {
+		obj.MachineController.Resources = &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(DefaultMachineControllerCPURequest),
+				corev1.ResourceMemory: resource.MustParse(DefaultMachineControllerMemoryRequest),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(DefaultMachineControllerCPULimit),
+				corev1.ResourceMemory: resource.MustParse(DefaultMachineControllerMemoryLimit),
+			},
+		}
+	}
+
+	if obj.MachineController.WebhookTimeoutSeconds == 0 {
+		obj.MachineController.WebhookTimeoutSeconds = DefaultMachineControllerWebhookTimeoutSeconds
+	}
+
+	if obj.MachineController.WorkerCount == 0 {
+		obj.MachineController.WorkerCount = DefaultMachineControllerWorkerCount
+	}
+
+	if obj.MachineController.MetricsPort == 0 {
+		obj.MachineController.MetricsPort = DefaultMachineControllerMetricsPort
+	}
+
+	if obj.MachineController.UpdateStrategy == nil {
+		obj.MachineController.UpdateStrategy = &appsv1.DeploymentStrategy{}
+	}
+	strategy := obj.MachineController.UpdateStrategy
+	if strategy.Type == "" {
+		strategy.Type = appsv1.RollingUpdateDeploymentStrategyType
+	}
+	// keep machine-controller running until the new pod is ready, so machines
+	// aren't left unprocessed during upgrades
+	if strategy.Type == appsv1.RollingUpdateDeploymentStrategyType && strategy.RollingUpdate == nil {
+		maxUnavailable := intstr.FromInt(DefaultMachineControllerMaxUnavailable)
+		maxSurge := intstr.FromInt(DefaultMachineControllerMaxSurge)
+		strategy.RollingUpdate = &appsv1.RollingUpdateDeployment{
+			MaxUnavailable: &maxUnavailable,
+			MaxSurge:       &maxSurge,
+		}
+	}
+
+	if obj.MachineController.CredentialsSecretRef != nil && obj.MachineController.CredentialsSecretRef.Namespace == "" {
+		obj.MachineController.CredentialsSecretRef.Namespace = metav1.NamespaceSystem
+	}
+}
+
+func SetDefaults_NodeLocalDNS(obj *KubeOneCluster) {
+	if obj.NodeLocalDNS != nil && obj.NodeLocalDNS.LocalAddress == "" {
+		obj.NodeLocalDNS.LocalAddress = DefaultNodeLocalDNSAddress
+	}
 }
 
 func SetDefaults_Features(obj *KubeOneCluster) {
@@ -120,7 +208,7 @@ func defaultHostConfig(obj *HostConfig) {
 	if len(obj.PrivateAddress) == 0 && len(obj.PublicAddress) > 0 {
 		obj.PrivateAddress = obj.PublicAddress
 	}
-	if len(obj.SSHPrivateKeyFile) == 0 && len(obj.SSHAgentSocket) == 0 {
+	if len(obj.SSHPrivateKeyFile) == 0 && len(obj.SSHPrivateKeyFiles) == 0 && len(obj.SSHAgentSocket) == 0 {
 		obj.SSHAgentSocket = "env:SSH_AUTH_SOCK"
 	}
 	if obj.SSHUsername == "" {
@@ -25,6 +25,9 @@ import (
 	unsafe "unsafe"
 
 	kubeone "github.com/kubermatic/kubeone/pkg/apis/kubeone"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	conversion "k8s.io/apimachinery/pkg/conversion"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -56,6 +59,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*CSIConfig)(nil), (*kubeone.CSIConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_CSIConfig_To_kubeone_CSIConfig(a.(*CSIConfig), b.(*kubeone.CSIConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.CSIConfig)(nil), (*CSIConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_CSIConfig_To_v1alpha1_CSIConfig(a.(*kubeone.CSIConfig), b.(*CSIConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*CloudProviderSpec)(nil), (*kubeone.CloudProviderSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_CloudProviderSpec_To_kubeone_CloudProviderSpec(a.(*CloudProviderSpec), b.(*kubeone.CloudProviderSpec), scope)
 	}); err != nil {
@@ -66,6 +79,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ClusterAutoscalerConfig)(nil), (*kubeone.ClusterAutoscalerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ClusterAutoscalerConfig_To_kubeone_ClusterAutoscalerConfig(a.(*ClusterAutoscalerConfig), b.(*kubeone.ClusterAutoscalerConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.ClusterAutoscalerConfig)(nil), (*ClusterAutoscalerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_ClusterAutoscalerConfig_To_v1alpha1_ClusterAutoscalerConfig(a.(*kubeone.ClusterAutoscalerConfig), b.(*ClusterAutoscalerConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ClusterNetworkConfig)(nil), (*kubeone.ClusterNetworkConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_ClusterNetworkConfig_To_kubeone_ClusterNetworkConfig(a.(*ClusterNetworkConfig), b.(*kubeone.ClusterNetworkConfig), scope)
 	}); err != nil {
@@ -76,6 +99,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ContainerRuntimeConfig)(nil), (*kubeone.ContainerRuntimeConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ContainerRuntimeConfig_To_kubeone_ContainerRuntimeConfig(a.(*ContainerRuntimeConfig), b.(*kubeone.ContainerRuntimeConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.ContainerRuntimeConfig)(nil), (*ContainerRuntimeConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_ContainerRuntimeConfig_To_v1alpha1_ContainerRuntimeConfig(a.(*kubeone.ContainerRuntimeConfig), b.(*ContainerRuntimeConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*CoreDNSConfig)(nil), (*kubeone.CoreDNSConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_CoreDNSConfig_To_kubeone_CoreDNSConfig(a.(*CoreDNSConfig), b.(*kubeone.CoreDNSConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.CoreDNSConfig)(nil), (*CoreDNSConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_CoreDNSConfig_To_v1alpha1_CoreDNSConfig(a.(*kubeone.CoreDNSConfig), b.(*CoreDNSConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*DataDisk)(nil), (*kubeone.DataDisk)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_DataDisk_To_kubeone_DataDisk(a.(*DataDisk), b.(*kubeone.DataDisk), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.DataDisk)(nil), (*DataDisk)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_DataDisk_To_v1alpha1_DataDisk(a.(*kubeone.DataDisk), b.(*DataDisk), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*DynamicAuditLog)(nil), (*kubeone.DynamicAuditLog)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_DynamicAuditLog_To_kubeone_DynamicAuditLog(a.(*DynamicAuditLog), b.(*kubeone.DynamicAuditLog), scope)
 	}); err != nil {
@@ -116,6 +169,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*KubeProxyConfig)(nil), (*kubeone.KubeProxyConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_KubeProxyConfig_To_kubeone_KubeProxyConfig(a.(*KubeProxyConfig), b.(*kubeone.KubeProxyConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.KubeProxyConfig)(nil), (*KubeProxyConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_KubeProxyConfig_To_v1alpha1_KubeProxyConfig(a.(*kubeone.KubeProxyConfig), b.(*KubeProxyConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*KubeletConfig)(nil), (*kubeone.KubeletConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_KubeletConfig_To_kubeone_KubeletConfig(a.(*KubeletConfig), b.(*kubeone.KubeletConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.KubeletConfig)(nil), (*KubeletConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_KubeletConfig_To_v1alpha1_KubeletConfig(a.(*kubeone.KubeletConfig), b.(*KubeletConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*MachineControllerConfig)(nil), (*kubeone.MachineControllerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_MachineControllerConfig_To_kubeone_MachineControllerConfig(a.(*MachineControllerConfig), b.(*kubeone.MachineControllerConfig), scope)
 	}); err != nil {
@@ -136,6 +209,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*MonitoringConfig)(nil), (*kubeone.MonitoringConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_MonitoringConfig_To_kubeone_MonitoringConfig(a.(*MonitoringConfig), b.(*kubeone.MonitoringConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.MonitoringConfig)(nil), (*MonitoringConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_MonitoringConfig_To_v1alpha1_MonitoringConfig(a.(*kubeone.MonitoringConfig), b.(*MonitoringConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NodeLocalDNSConfig)(nil), (*kubeone.NodeLocalDNSConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_NodeLocalDNSConfig_To_kubeone_NodeLocalDNSConfig(a.(*NodeLocalDNSConfig), b.(*kubeone.NodeLocalDNSConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.NodeLocalDNSConfig)(nil), (*NodeLocalDNSConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_NodeLocalDNSConfig_To_v1alpha1_NodeLocalDNSConfig(a.(*kubeone.NodeLocalDNSConfig), b.(*NodeLocalDNSConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*OpenIDConnect)(nil), (*kubeone.OpenIDConnect)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_OpenIDConnect_To_kubeone_OpenIDConnect(a.(*OpenIDConnect), b.(*kubeone.OpenIDConnect), scope)
 	}); err != nil {
@@ -146,6 +239,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*OSUpgradeConfig)(nil), (*kubeone.OSUpgradeConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_OSUpgradeConfig_To_kubeone_OSUpgradeConfig(a.(*OSUpgradeConfig), b.(*kubeone.OSUpgradeConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.OSUpgradeConfig)(nil), (*OSUpgradeConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_OSUpgradeConfig_To_v1alpha1_OSUpgradeConfig(a.(*kubeone.OSUpgradeConfig), b.(*OSUpgradeConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*OpenIDConnectConfig)(nil), (*kubeone.OpenIDConnectConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_OpenIDConnectConfig_To_kubeone_OpenIDConnectConfig(a.(*OpenIDConnectConfig), b.(*kubeone.OpenIDConnectConfig), scope)
 	}); err != nil {
@@ -156,6 +259,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*PackageMirrorsConfig)(nil), (*kubeone.PackageMirrorsConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_PackageMirrorsConfig_To_kubeone_PackageMirrorsConfig(a.(*PackageMirrorsConfig), b.(*kubeone.PackageMirrorsConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.PackageMirrorsConfig)(nil), (*PackageMirrorsConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_PackageMirrorsConfig_To_v1alpha1_PackageMirrorsConfig(a.(*kubeone.PackageMirrorsConfig), b.(*PackageMirrorsConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*PodSecurityPolicy)(nil), (*kubeone.PodSecurityPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_PodSecurityPolicy_To_kubeone_PodSecurityPolicy(a.(*PodSecurityPolicy), b.(*kubeone.PodSecurityPolicy), scope)
 	}); err != nil {
@@ -186,6 +299,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*StorageClass)(nil), (*kubeone.StorageClass)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_StorageClass_To_kubeone_StorageClass(a.(*StorageClass), b.(*kubeone.StorageClass), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.StorageClass)(nil), (*StorageClass)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_StorageClass_To_v1alpha1_StorageClass(a.(*kubeone.StorageClass), b.(*StorageClass), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*TopologySpreadConstraint)(nil), (*kubeone.TopologySpreadConstraint)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_TopologySpreadConstraint_To_kubeone_TopologySpreadConstraint(a.(*TopologySpreadConstraint), b.(*kubeone.TopologySpreadConstraint), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.TopologySpreadConstraint)(nil), (*TopologySpreadConstraint)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_TopologySpreadConstraint_To_v1alpha1_TopologySpreadConstraint(a.(*kubeone.TopologySpreadConstraint), b.(*TopologySpreadConstraint), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*VeleroConfig)(nil), (*kubeone.VeleroConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_VeleroConfig_To_kubeone_VeleroConfig(a.(*VeleroConfig), b.(*kubeone.VeleroConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kubeone.VeleroConfig)(nil), (*VeleroConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kubeone_VeleroConfig_To_v1alpha1_VeleroConfig(a.(*kubeone.VeleroConfig), b.(*VeleroConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*VersionConfig)(nil), (*kubeone.VersionConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_VersionConfig_To_kubeone_VersionConfig(a.(*VersionConfig), b.(*kubeone.VersionConfig), scope)
 	}); err != nil {
@@ -253,10 +396,35 @@ func Convert_kubeone_CNI_To_v1alpha1_CNI(in *kubeone.CNI, out *CNI, s conversion
 	return autoConvert_kubeone_CNI_To_v1alpha1_CNI(in, out, s)
 }
 
+func autoConvert_v1alpha1_CSIConfig_To_kubeone_CSIConfig(in *CSIConfig, out *kubeone.CSIConfig, s conversion.Scope) error {
+	out.Driver = kubeone.CSIDriver(in.Driver)
+	out.Version = in.Version
+	out.StorageClasses = *(*[]kubeone.StorageClass)(unsafe.Pointer(&in.StorageClasses))
+	return nil
+}
+
+// Convert_v1alpha1_CSIConfig_To_kubeone_CSIConfig is an autogenerated conversion function.
+func Convert_v1alpha1_CSIConfig_To_kubeone_CSIConfig(in *CSIConfig, out *kubeone.CSIConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_CSIConfig_To_kubeone_CSIConfig(in, out, s)
+}
+
+func autoConvert_kubeone_CSIConfig_To_v1alpha1_CSIConfig(in *kubeone.CSIConfig, out *CSIConfig, s conversion.Scope) error {
+	out.Driver = CSIDriver(in.Driver)
+	out.Version = in.Version
+	out.StorageClasses = *(*[]StorageClass)(unsafe.Pointer(&in.StorageClasses))
+	return nil
+}
+
+// Convert_kubeone_CSIConfig_To_v1alpha1_CSIConfig is an autogenerated conversion function.
+func Convert_kubeone_CSIConfig_To_v1alpha1_CSIConfig(in *kubeone.CSIConfig, out *CSIConfig, s conversion.Scope) error {
+	return autoConvert_kubeone_CSIConfig_To_v1alpha1_CSIConfig(in, out, s)
+}
+
 func autoConvert_v1alpha1_CloudProviderSpec_To_kubeone_CloudProviderSpec(in *CloudProviderSpec, out *kubeone.CloudProviderSpec, s conversion.Scope) error {
 	out.Name = kubeone.CloudProviderName(in.Name)
 	out.External = in.External
 	out.CloudConfig = in.CloudConfig
+	out.CCMVersion = in.CCMVersion
 	return nil
 }
 
@@ -269,6 +437,7 @@ func autoConvert_kubeone_CloudProviderSpec_To_v1alpha1_CloudProviderSpec(in *kub
 	out.Name = CloudProviderName(in.Name)
 	out.External = in.External
 	out.CloudConfig = in.CloudConfig
+	out.CCMVersion = in.CCMVersion
 	return nil
 }
 
@@ -277,6 +446,34 @@ func Convert_kubeone_CloudProviderSpec_To_v1alpha1_CloudProviderSpec(in *kubeone
 	return autoConvert_kubeone_CloudProviderSpec_To_v1alpha1_CloudProviderSpec(in, out, s)
 }
 
+func autoConvert_v1alpha1_ClusterAutoscalerConfig_To_kubeone_ClusterAutoscalerConfig(in *ClusterAutoscalerConfig, out *kubeone.ClusterAutoscalerConfig, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.Version = in.Version
+	out.MinNodes = in.MinNodes
+	out.MaxNodes = in.MaxNodes
+	out.ScaleDownDelay = in.ScaleDownDelay
+	return nil
+}
+
+// Convert_v1alpha1_ClusterAutoscalerConfig_To_kubeone_ClusterAutoscalerConfig is an autogenerated conversion function.
+func Convert_v1alpha1_ClusterAutoscalerConfig_To_kubeone_ClusterAutoscalerConfig(in *ClusterAutoscalerConfig, out *kubeone.ClusterAutoscalerConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ClusterAutoscalerConfig_To_kubeone_ClusterAutoscalerConfig(in, out, s)
+}
+
+func autoConvert_kubeone_ClusterAutoscalerConfig_To_v1alpha1_ClusterAutoscalerConfig(in *kubeone.ClusterAutoscalerConfig, out *ClusterAutoscalerConfig, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.Version = in.Version
+	out.MinNodes = in.MinNodes
+	out.MaxNodes = in.MaxNodes
+	out.ScaleDownDelay = in.ScaleDownDelay
+	return nil
+}
+
+// Convert_kubeone_ClusterAutoscalerConfig_To_v1alpha1_ClusterAutoscalerConfig is an autogenerated conversion function.
+func Convert_kubeone_ClusterAutoscalerConfig_To_v1alpha1_ClusterAutoscalerConfig(in *kubeone.ClusterAutoscalerConfig, out *ClusterAutoscalerConfig, s conversion.Scope) error {
+	return autoConvert_kubeone_ClusterAutoscalerConfig_To_v1alpha1_ClusterAutoscalerConfig(in, out, s)
+}
+
 func autoConvert_v1alpha1_ClusterNetworkConfig_To_kubeone_ClusterNetworkConfig(in *ClusterNetworkConfig, out *kubeone.ClusterNetworkConfig, s conversion.Scope) error {
 	out.PodSubnet = in.PodSubnet
 	out.ServiceSubnet = in.ServiceSubnet
@@ -305,6 +502,78 @@ func Convert_kubeone_ClusterNetworkConfig_To_v1alpha1_ClusterNetworkConfig(in *k
 	return autoConvert_kubeone_ClusterNetworkConfig_To_v1alpha1_ClusterNetworkConfig(in, out, s)
 }
 
+func autoConvert_v1alpha1_ContainerRuntimeConfig_To_kubeone_ContainerRuntimeConfig(in *ContainerRuntimeConfig, out *kubeone.ContainerRuntimeConfig, s conversion.Scope) error {
+	out.Runtime = kubeone.ContainerRuntime(in.Runtime)
+	out.RegistryMirrors = *(*map[string][]string)(unsafe.Pointer(&in.RegistryMirrors))
+	return nil
+}
+
+// Convert_v1alpha1_ContainerRuntimeConfig_To_kubeone_ContainerRuntimeConfig is an autogenerated conversion function.
+func Convert_v1alpha1_ContainerRuntimeConfig_To_kubeone_ContainerRuntimeConfig(in *ContainerRuntimeConfig, out *kubeone.ContainerRuntimeConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ContainerRuntimeConfig_To_kubeone_ContainerRuntimeConfig(in, out, s)
+}
+
+func autoConvert_kubeone_ContainerRuntimeConfig_To_v1alpha1_ContainerRuntimeConfig(in *kubeone.ContainerRuntimeConfig, out *ContainerRuntimeConfig, s conversion.Scope) error {
+	out.Runtime = ContainerRuntime(in.Runtime)
+	out.RegistryMirrors = *(*map[string][]string)(unsafe.Pointer(&in.RegistryMirrors))
+	return nil
+}
+
+// Convert_kubeone_ContainerRuntimeConfig_To_v1alpha1_ContainerRuntimeConfig is an autogenerated conversion function.
+func Convert_kubeone_ContainerRuntimeConfig_To_v1alpha1_ContainerRuntimeConfig(in *kubeone.ContainerRuntimeConfig, out *ContainerRuntimeConfig, s conversion.Scope) error {
+	return autoConvert_kubeone_ContainerRuntimeConfig_To_v1alpha1_ContainerRuntimeConfig(in, out, s)
+}
+
+func autoConvert_v1alpha1_CoreDNSConfig_To_kubeone_CoreDNSConfig(in *CoreDNSConfig, out *kubeone.CoreDNSConfig, s conversion.Scope) error {
+	out.Replicas = in.Replicas
+	out.CPURequest = in.CPURequest
+	out.MemoryRequest = in.MemoryRequest
+	out.Corefile = in.Corefile
+	return nil
+}
+
+// Convert_v1alpha1_CoreDNSConfig_To_kubeone_CoreDNSConfig is an autogenerated conversion function.
+func Convert_v1alpha1_CoreDNSConfig_To_kubeone_CoreDNSConfig(in *CoreDNSConfig, out *kubeone.CoreDNSConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_CoreDNSConfig_To_kubeone_CoreDNSConfig(in, out, s)
+}
+
+func autoConvert_kubeone_CoreDNSConfig_To_v1alpha1_CoreDNSConfig(in *kubeone.CoreDNSConfig, out *CoreDNSConfig, s conversion.Scope) error {
+	out.Replicas = in.Replicas
+	out.CPURequest = in.CPURequest
+	out.MemoryRequest = in.MemoryRequest
+	out.Corefile = in.Corefile
+	return nil
+}
+
+// Convert_kubeone_CoreDNSConfig_To_v1alpha1_CoreDNSConfig is an autogenerated conversion function.
+func Convert_kubeone_CoreDNSConfig_To_v1alpha1_CoreDNSConfig(in *kubeone.CoreDNSConfig, out *CoreDNSConfig, s conversion.Scope) error {
+	return autoConvert_kubeone_CoreDNSConfig_To_v1alpha1_CoreDNSConfig(in, out, s)
+}
+
+func autoConvert_v1alpha1_DataDisk_To_kubeone_DataDisk(in *DataDisk, out *kubeone.DataDisk, s conversion.Scope) error {
+	out.Size = in.Size
+	out.Type = in.Type
+	out.IOPS = in.IOPS
+	return nil
+}
+
+// Convert_v1alpha1_DataDisk_To_kubeone_DataDisk is an autogenerated conversion function.
+func Convert_v1alpha1_DataDisk_To_kubeone_DataDisk(in *DataDisk, out *kubeone.DataDisk, s conversion.Scope) error {
+	return autoConvert_v1alpha1_DataDisk_To_kubeone_DataDisk(in, out, s)
+}
+
+func autoConvert_kubeone_DataDisk_To_v1alpha1_DataDisk(in *kubeone.DataDisk, out *DataDisk, s conversion.Scope) error {
+	out.Size = in.Size
+	out.Type = in.Type
+	out.IOPS = in.IOPS
+	return nil
+}
+
+// Convert_kubeone_DataDisk_To_v1alpha1_DataDisk is an autogenerated conversion function.
+func Convert_kubeone_DataDisk_To_v1alpha1_DataDisk(in *kubeone.DataDisk, out *DataDisk, s conversion.Scope) error {
+	return autoConvert_kubeone_DataDisk_To_v1alpha1_DataDisk(in, out, s)
+}
+
 func autoConvert_v1alpha1_DynamicAuditLog_To_kubeone_DynamicAuditLog(in *DynamicAuditLog, out *kubeone.DynamicAuditLog, s conversion.Scope) error {
 	out.Enable = in.Enable
 	return nil
@@ -358,9 +627,13 @@ func autoConvert_v1alpha1_HostConfig_To_kubeone_HostConfig(in *HostConfig, out *
 	out.SSHPort = in.SSHPort
 	out.SSHUsername = in.SSHUsername
 	out.SSHPrivateKeyFile = in.SSHPrivateKeyFile
+	out.SSHPrivateKeyFiles = *(*[]string)(unsafe.Pointer(&in.SSHPrivateKeyFiles))
 	out.SSHAgentSocket = in.SSHAgentSocket
+	out.SSHStrictHostKeyChecking = in.SSHStrictHostKeyChecking
+	out.SSHKnownHostsFile = in.SSHKnownHostsFile
 	out.Hostname = in.Hostname
-	out.OperatingSystem = in.OperatingSystem
+	out.OperatingSystem = kubeone.OperatingSystemName(in.OperatingSystem)
+	out.OperatingSystemVersion = in.OperatingSystemVersion
 	out.IsLeader = in.IsLeader
 	return nil
 }
@@ -377,9 +650,13 @@ func autoConvert_kubeone_HostConfig_To_v1alpha1_HostConfig(in *kubeone.HostConfi
 	out.SSHPort = in.SSHPort
 	out.SSHUsername = in.SSHUsername
 	out.SSHPrivateKeyFile = in.SSHPrivateKeyFile
+	out.SSHPrivateKeyFiles = *(*[]string)(unsafe.Pointer(&in.SSHPrivateKeyFiles))
 	out.SSHAgentSocket = in.SSHAgentSocket
+	out.SSHStrictHostKeyChecking = in.SSHStrictHostKeyChecking
+	out.SSHKnownHostsFile = in.SSHKnownHostsFile
 	out.Hostname = in.Hostname
-	out.OperatingSystem = in.OperatingSystem
+	out.OperatingSystem = OperatingSystemName(in.OperatingSystem)
+	out.OperatingSystemVersion = in.OperatingSystemVersion
 	out.IsLeader = in.IsLeader
 	return nil
 }
@@ -404,11 +681,35 @@ func autoConvert_v1alpha1_KubeOneCluster_To_kubeone_KubeOneCluster(in *KubeOneCl
 	if err := Convert_v1alpha1_ClusterNetworkConfig_To_kubeone_ClusterNetworkConfig(&in.ClusterNetwork, &out.ClusterNetwork, s); err != nil {
 		return err
 	}
+	if err := Convert_v1alpha1_KubeProxyConfig_To_kubeone_KubeProxyConfig(&in.KubeProxy, &out.KubeProxy, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha1_KubeletConfig_To_kubeone_KubeletConfig(&in.Kubelet, &out.Kubelet, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha1_CoreDNSConfig_To_kubeone_CoreDNSConfig(&in.CoreDNS, &out.CoreDNS, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha1_ContainerRuntimeConfig_To_kubeone_ContainerRuntimeConfig(&in.ContainerRuntime, &out.ContainerRuntime, s); err != nil {
+		return err
+	}
 	if err := Convert_v1alpha1_ProxyConfig_To_kubeone_ProxyConfig(&in.Proxy, &out.Proxy, s); err != nil {
 		return err
 	}
+	out.ImageRepository = in.ImageRepository
+	if err := Convert_v1alpha1_PackageMirrorsConfig_To_kubeone_PackageMirrorsConfig(&in.PackageMirrors, &out.PackageMirrors, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha1_OSUpgradeConfig_To_kubeone_OSUpgradeConfig(&in.OSUpgrade, &out.OSUpgrade, s); err != nil {
+		return err
+	}
 	out.Workers = *(*[]kubeone.WorkerConfig)(unsafe.Pointer(&in.Workers))
 	out.MachineController = (*kubeone.MachineControllerConfig)(unsafe.Pointer(in.MachineController))
+	out.CSI = (*kubeone.CSIConfig)(unsafe.Pointer(in.CSI))
+	out.ClusterAutoscaler = (*kubeone.ClusterAutoscalerConfig)(unsafe.Pointer(in.ClusterAutoscaler))
+	out.Velero = (*kubeone.VeleroConfig)(unsafe.Pointer(in.Velero))
+	out.NodeLocalDNS = (*kubeone.NodeLocalDNSConfig)(unsafe.Pointer(in.NodeLocalDNS))
+	out.Monitoring = (*kubeone.MonitoringConfig)(unsafe.Pointer(in.Monitoring))
 	if err := Convert_v1alpha1_Features_To_kubeone_Features(&in.Features, &out.Features, s); err != nil {
 		return err
 	}
@@ -436,11 +737,35 @@ func autoConvert_kubeone_KubeOneCluster_To_v1alpha1_KubeOneCluster(in *kubeone.K
 	if err := Convert_kubeone_ClusterNetworkConfig_To_v1alpha1_ClusterNetworkConfig(&in.ClusterNetwork, &out.ClusterNetwork, s); err != nil {
 		return err
 	}
+	if err := Convert_kubeone_KubeProxyConfig_To_v1alpha1_KubeProxyConfig(&in.KubeProxy, &out.KubeProxy, s); err != nil {
+		return err
+	}
+	if err := Convert_kubeone_KubeletConfig_To_v1alpha1_KubeletConfig(&in.Kubelet, &out.Kubelet, s); err != nil {
+		return err
+	}
+	if err := Convert_kubeone_CoreDNSConfig_To_v1alpha1_CoreDNSConfig(&in.CoreDNS, &out.CoreDNS, s); err != nil {
+		return err
+	}
+	if err := Convert_kubeone_ContainerRuntimeConfig_To_v1alpha1_ContainerRuntimeConfig(&in.ContainerRuntime, &out.ContainerRuntime, s); err != nil {
+		return err
+	}
 	if err := Convert_kubeone_ProxyConfig_To_v1alpha1_ProxyConfig(&in.Proxy, &out.Proxy, s); err != nil {
 		return err
 	}
+	out.ImageRepository = in.ImageRepository
+	if err := Convert_kubeone_PackageMirrorsConfig_To_v1alpha1_PackageMirrorsConfig(&in.PackageMirrors, &out.PackageMirrors, s); err != nil {
+		return err
+	}
+	if err := Convert_kubeone_OSUpgradeConfig_To_v1alpha1_OSUpgradeConfig(&in.OSUpgrade, &out.OSUpgrade, s); err != nil {
+		return err
+	}
 	out.Workers = *(*[]WorkerConfig)(unsafe.Pointer(&in.Workers))
 	out.MachineController = (*MachineControllerConfig)(unsafe.Pointer(in.MachineController))
+	out.CSI = (*CSIConfig)(unsafe.Pointer(in.CSI))
+	out.ClusterAutoscaler = (*ClusterAutoscalerConfig)(unsafe.Pointer(in.ClusterAutoscaler))
+	out.Velero = (*VeleroConfig)(unsafe.Pointer(in.Velero))
+	out.NodeLocalDNS = (*NodeLocalDNSConfig)(unsafe.Pointer(in.NodeLocalDNS))
+	out.Monitoring = (*MonitoringConfig)(unsafe.Pointer(in.Monitoring))
 	if err := Convert_kubeone_Features_To_v1alpha1_Features(&in.Features, &out.Features, s); err != nil {
 		return err
 	}
@@ -453,9 +778,63 @@ func Convert_kubeone_KubeOneCluster_To_v1alpha1_KubeOneCluster(in *kubeone.KubeO
 	return autoConvert_kubeone_KubeOneCluster_To_v1alpha1_KubeOneCluster(in, out, s)
 }
 
+func autoConvert_v1alpha1_KubeProxyConfig_To_kubeone_KubeProxyConfig(in *KubeProxyConfig, out *kubeone.KubeProxyConfig, s conversion.Scope) error {
+	out.Mode = kubeone.KubeProxyMode(in.Mode)
+	out.IPVSScheduler = in.IPVSScheduler
+	return nil
+}
+
+// Convert_v1alpha1_KubeProxyConfig_To_kubeone_KubeProxyConfig is an autogenerated conversion function.
+func Convert_v1alpha1_KubeProxyConfig_To_kubeone_KubeProxyConfig(in *KubeProxyConfig, out *kubeone.KubeProxyConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_KubeProxyConfig_To_kubeone_KubeProxyConfig(in, out, s)
+}
+
+func autoConvert_kubeone_KubeProxyConfig_To_v1alpha1_KubeProxyConfig(in *kubeone.KubeProxyConfig, out *KubeProxyConfig, s conversion.Scope) error {
+	out.Mode = KubeProxyMode(in.Mode)
+	out.IPVSScheduler = in.IPVSScheduler
+	return nil
+}
+
+// Convert_kubeone_KubeProxyConfig_To_v1alpha1_KubeProxyConfig is an autogenerated conversion function.
+func Convert_kubeone_KubeProxyConfig_To_v1alpha1_KubeProxyConfig(in *kubeone.KubeProxyConfig, out *KubeProxyConfig, s conversion.Scope) error {
+	return autoConvert_kubeone_KubeProxyConfig_To_v1alpha1_KubeProxyConfig(in, out, s)
+}
+
+func autoConvert_v1alpha1_KubeletConfig_To_kubeone_KubeletConfig(in *KubeletConfig, out *kubeone.KubeletConfig, s conversion.Scope) error {
+	out.DiskPressureThreshold = in.DiskPressureThreshold
+	out.InodePressureThreshold = in.InodePressureThreshold
+	return nil
+}
+
+// Convert_v1alpha1_KubeletConfig_To_kubeone_KubeletConfig is an autogenerated conversion function.
+func Convert_v1alpha1_KubeletConfig_To_kubeone_KubeletConfig(in *KubeletConfig, out *kubeone.KubeletConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_KubeletConfig_To_kubeone_KubeletConfig(in, out, s)
+}
+
+func autoConvert_kubeone_KubeletConfig_To_v1alpha1_KubeletConfig(in *kubeone.KubeletConfig, out *KubeletConfig, s conversion.Scope) error {
+	out.DiskPressureThreshold = in.DiskPressureThreshold
+	out.InodePressureThreshold = in.InodePressureThreshold
+	return nil
+}
+
+// Convert_kubeone_KubeletConfig_To_v1alpha1_KubeletConfig is an autogenerated conversion function.
+func Convert_kubeone_KubeletConfig_To_v1alpha1_KubeletConfig(in *kubeone.KubeletConfig, out *KubeletConfig, s conversion.Scope) error {
+	return autoConvert_kubeone_KubeletConfig_To_v1alpha1_KubeletConfig(in, out, s)
+}
+
 func autoConvert_v1alpha1_MachineControllerConfig_To_kubeone_MachineControllerConfig(in *MachineControllerConfig, out *kubeone.MachineControllerConfig, s conversion.Scope) error {
 	out.Deploy = in.Deploy
 	out.Provider = kubeone.CloudProviderName(in.Provider)
+	out.LeaderElectionLeaseDuration = (*metav1.Duration)(unsafe.Pointer(in.LeaderElectionLeaseDuration))
+	out.LeaderElectionRenewDeadline = (*metav1.Duration)(unsafe.Pointer(in.LeaderElectionRenewDeadline))
+	out.LeaderElectionRetryPeriod = (*metav1.Duration)(unsafe.Pointer(in.LeaderElectionRetryPeriod))
+	out.Resources = (*v1.ResourceRequirements)(unsafe.Pointer(in.Resources))
+	out.TopologySpreadConstraints = *(*[]kubeone.TopologySpreadConstraint)(unsafe.Pointer(&in.TopologySpreadConstraints))
+	out.CredentialsSecretRef = (*v1.SecretReference)(unsafe.Pointer(in.CredentialsSecretRef))
+	out.WebhookTimeoutSeconds = in.WebhookTimeoutSeconds
+	out.WorkerCount = in.WorkerCount
+	out.MetricsPort = in.MetricsPort
+	out.UpdateStrategy = (*appsv1.DeploymentStrategy)(unsafe.Pointer(in.UpdateStrategy))
 	return nil
 }
 
@@ -467,6 +846,16 @@ func Convert_v1alpha1_MachineControllerConfig_To_kubeone_MachineControllerConfig
 func autoConvert_kubeone_MachineControllerConfig_To_v1alpha1_MachineControllerConfig(in *kubeone.MachineControllerConfig, out *MachineControllerConfig, s conversion.Scope) error {
 	out.Deploy = in.Deploy
 	out.Provider = CloudProviderName(in.Provider)
+	out.LeaderElectionLeaseDuration = (*metav1.Duration)(unsafe.Pointer(in.LeaderElectionLeaseDuration))
+	out.LeaderElectionRenewDeadline = (*metav1.Duration)(unsafe.Pointer(in.LeaderElectionRenewDeadline))
+	out.LeaderElectionRetryPeriod = (*metav1.Duration)(unsafe.Pointer(in.LeaderElectionRetryPeriod))
+	out.Resources = (*v1.ResourceRequirements)(unsafe.Pointer(in.Resources))
+	out.TopologySpreadConstraints = *(*[]TopologySpreadConstraint)(unsafe.Pointer(&in.TopologySpreadConstraints))
+	out.CredentialsSecretRef = (*v1.SecretReference)(unsafe.Pointer(in.CredentialsSecretRef))
+	out.WebhookTimeoutSeconds = in.WebhookTimeoutSeconds
+	out.WorkerCount = in.WorkerCount
+	out.MetricsPort = in.MetricsPort
+	out.UpdateStrategy = (*appsv1.DeploymentStrategy)(unsafe.Pointer(in.UpdateStrategy))
 	return nil
 }
 
@@ -495,6 +884,48 @@ func Convert_kubeone_MetricsServer_To_v1alpha1_MetricsServer(in *kubeone.Metrics
 	return autoConvert_kubeone_MetricsServer_To_v1alpha1_MetricsServer(in, out, s)
 }
 
+func autoConvert_v1alpha1_MonitoringConfig_To_kubeone_MonitoringConfig(in *MonitoringConfig, out *kubeone.MonitoringConfig, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	return nil
+}
+
+// Convert_v1alpha1_MonitoringConfig_To_kubeone_MonitoringConfig is an autogenerated conversion function.
+func Convert_v1alpha1_MonitoringConfig_To_kubeone_MonitoringConfig(in *MonitoringConfig, out *kubeone.MonitoringConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_MonitoringConfig_To_kubeone_MonitoringConfig(in, out, s)
+}
+
+func autoConvert_kubeone_MonitoringConfig_To_v1alpha1_MonitoringConfig(in *kubeone.MonitoringConfig, out *MonitoringConfig, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	return nil
+}
+
+// Convert_kubeone_MonitoringConfig_To_v1alpha1_MonitoringConfig is an autogenerated conversion function.
+func Convert_kubeone_MonitoringConfig_To_v1alpha1_MonitoringConfig(in *kubeone.MonitoringConfig, out *MonitoringConfig, s conversion.Scope) error {
+	return autoConvert_kubeone_MonitoringConfig_To_v1alpha1_MonitoringConfig(in, out, s)
+}
+
+func autoConvert_v1alpha1_NodeLocalDNSConfig_To_kubeone_NodeLocalDNSConfig(in *NodeLocalDNSConfig, out *kubeone.NodeLocalDNSConfig, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.LocalAddress = in.LocalAddress
+	return nil
+}
+
+// Convert_v1alpha1_NodeLocalDNSConfig_To_kubeone_NodeLocalDNSConfig is an autogenerated conversion function.
+func Convert_v1alpha1_NodeLocalDNSConfig_To_kubeone_NodeLocalDNSConfig(in *NodeLocalDNSConfig, out *kubeone.NodeLocalDNSConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_NodeLocalDNSConfig_To_kubeone_NodeLocalDNSConfig(in, out, s)
+}
+
+func autoConvert_kubeone_NodeLocalDNSConfig_To_v1alpha1_NodeLocalDNSConfig(in *kubeone.NodeLocalDNSConfig, out *NodeLocalDNSConfig, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.LocalAddress = in.LocalAddress
+	return nil
+}
+
+// Convert_kubeone_NodeLocalDNSConfig_To_v1alpha1_NodeLocalDNSConfig is an autogenerated conversion function.
+func Convert_kubeone_NodeLocalDNSConfig_To_v1alpha1_NodeLocalDNSConfig(in *kubeone.NodeLocalDNSConfig, out *NodeLocalDNSConfig, s conversion.Scope) error {
+	return autoConvert_kubeone_NodeLocalDNSConfig_To_v1alpha1_NodeLocalDNSConfig(in, out, s)
+}
+
 func autoConvert_v1alpha1_OpenIDConnect_To_kubeone_OpenIDConnect(in *OpenIDConnect, out *kubeone.OpenIDConnect, s conversion.Scope) error {
 	out.Enable = in.Enable
 	if err := Convert_v1alpha1_OpenIDConnectConfig_To_kubeone_OpenIDConnectConfig(&in.Config, &out.Config, s); err != nil {
@@ -521,6 +952,26 @@ func Convert_kubeone_OpenIDConnect_To_v1alpha1_OpenIDConnect(in *kubeone.OpenIDC
 	return autoConvert_kubeone_OpenIDConnect_To_v1alpha1_OpenIDConnect(in, out, s)
 }
 
+func autoConvert_v1alpha1_OSUpgradeConfig_To_kubeone_OSUpgradeConfig(in *OSUpgradeConfig, out *kubeone.OSUpgradeConfig, s conversion.Scope) error {
+	out.Packages = *(*[]string)(unsafe.Pointer(&in.Packages))
+	return nil
+}
+
+// Convert_v1alpha1_OSUpgradeConfig_To_kubeone_OSUpgradeConfig is an autogenerated conversion function.
+func Convert_v1alpha1_OSUpgradeConfig_To_kubeone_OSUpgradeConfig(in *OSUpgradeConfig, out *kubeone.OSUpgradeConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_OSUpgradeConfig_To_kubeone_OSUpgradeConfig(in, out, s)
+}
+
+func autoConvert_kubeone_OSUpgradeConfig_To_v1alpha1_OSUpgradeConfig(in *kubeone.OSUpgradeConfig, out *OSUpgradeConfig, s conversion.Scope) error {
+	out.Packages = *(*[]string)(unsafe.Pointer(&in.Packages))
+	return nil
+}
+
+// Convert_kubeone_OSUpgradeConfig_To_v1alpha1_OSUpgradeConfig is an autogenerated conversion function.
+func Convert_kubeone_OSUpgradeConfig_To_v1alpha1_OSUpgradeConfig(in *kubeone.OSUpgradeConfig, out *OSUpgradeConfig, s conversion.Scope) error {
+	return autoConvert_kubeone_OSUpgradeConfig_To_v1alpha1_OSUpgradeConfig(in, out, s)
+}
+
 func autoConvert_v1alpha1_OpenIDConnectConfig_To_kubeone_OpenIDConnectConfig(in *OpenIDConnectConfig, out *kubeone.OpenIDConnectConfig, s conversion.Scope) error {
 	out.IssuerURL = in.IssuerURL
 	out.ClientID = in.ClientID
@@ -557,6 +1008,28 @@ func Convert_kubeone_OpenIDConnectConfig_To_v1alpha1_OpenIDConnectConfig(in *kub
 	return autoConvert_kubeone_OpenIDConnectConfig_To_v1alpha1_OpenIDConnectConfig(in, out, s)
 }
 
+func autoConvert_v1alpha1_PackageMirrorsConfig_To_kubeone_PackageMirrorsConfig(in *PackageMirrorsConfig, out *kubeone.PackageMirrorsConfig, s conversion.Scope) error {
+	out.AptMirror = in.AptMirror
+	out.YumMirror = in.YumMirror
+	return nil
+}
+
+// Convert_v1alpha1_PackageMirrorsConfig_To_kubeone_PackageMirrorsConfig is an autogenerated conversion function.
+func Convert_v1alpha1_PackageMirrorsConfig_To_kubeone_PackageMirrorsConfig(in *PackageMirrorsConfig, out *kubeone.PackageMirrorsConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_PackageMirrorsConfig_To_kubeone_PackageMirrorsConfig(in, out, s)
+}
+
+func autoConvert_kubeone_PackageMirrorsConfig_To_v1alpha1_PackageMirrorsConfig(in *kubeone.PackageMirrorsConfig, out *PackageMirrorsConfig, s conversion.Scope) error {
+	out.AptMirror = in.AptMirror
+	out.YumMirror = in.YumMirror
+	return nil
+}
+
+// Convert_kubeone_PackageMirrorsConfig_To_v1alpha1_PackageMirrorsConfig is an autogenerated conversion function.
+func Convert_kubeone_PackageMirrorsConfig_To_v1alpha1_PackageMirrorsConfig(in *kubeone.PackageMirrorsConfig, out *PackageMirrorsConfig, s conversion.Scope) error {
+	return autoConvert_kubeone_PackageMirrorsConfig_To_v1alpha1_PackageMirrorsConfig(in, out, s)
+}
+
 func autoConvert_v1alpha1_PodSecurityPolicy_To_kubeone_PodSecurityPolicy(in *PodSecurityPolicy, out *kubeone.PodSecurityPolicy, s conversion.Scope) error {
 	out.Enable = in.Enable
 	return nil
@@ -578,11 +1051,14 @@ func Convert_kubeone_PodSecurityPolicy_To_v1alpha1_PodSecurityPolicy(in *kubeone
 }
 
 func autoConvert_v1alpha1_ProviderSpec_To_kubeone_ProviderSpec(in *ProviderSpec, out *kubeone.ProviderSpec, s conversion.Scope) error {
+	out.CloudProvider = kubeone.CloudProviderName(in.CloudProvider)
 	out.CloudProviderSpec = *(*json.RawMessage)(unsafe.Pointer(&in.CloudProviderSpec))
 	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
 	out.SSHPublicKeys = *(*[]string)(unsafe.Pointer(&in.SSHPublicKeys))
 	out.OperatingSystem = in.OperatingSystem
 	out.OperatingSystemSpec = *(*json.RawMessage)(unsafe.Pointer(&in.OperatingSystemSpec))
+	out.DiskSize = in.DiskSize
+	out.DataDisks = *(*[]kubeone.DataDisk)(unsafe.Pointer(&in.DataDisks))
 	return nil
 }
 
@@ -592,11 +1068,14 @@ func Convert_v1alpha1_ProviderSpec_To_kubeone_ProviderSpec(in *ProviderSpec, out
 }
 
 func autoConvert_kubeone_ProviderSpec_To_v1alpha1_ProviderSpec(in *kubeone.ProviderSpec, out *ProviderSpec, s conversion.Scope) error {
+	out.CloudProvider = CloudProviderName(in.CloudProvider)
 	out.CloudProviderSpec = *(*json.RawMessage)(unsafe.Pointer(&in.CloudProviderSpec))
 	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
 	out.SSHPublicKeys = *(*[]string)(unsafe.Pointer(&in.SSHPublicKeys))
 	out.OperatingSystem = in.OperatingSystem
 	out.OperatingSystemSpec = *(*json.RawMessage)(unsafe.Pointer(&in.OperatingSystemSpec))
+	out.DiskSize = in.DiskSize
+	out.DataDisks = *(*[]DataDisk)(unsafe.Pointer(&in.DataDisks))
 	return nil
 }
 
@@ -629,6 +1108,84 @@ func Convert_kubeone_ProxyConfig_To_v1alpha1_ProxyConfig(in *kubeone.ProxyConfig
 	return autoConvert_kubeone_ProxyConfig_To_v1alpha1_ProxyConfig(in, out, s)
 }
 
+func autoConvert_v1alpha1_StorageClass_To_kubeone_StorageClass(in *StorageClass, out *kubeone.StorageClass, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Default = in.Default
+	out.ReclaimPolicy = in.ReclaimPolicy
+	out.Parameters = *(*map[string]string)(unsafe.Pointer(&in.Parameters))
+	return nil
+}
+
+// Convert_v1alpha1_StorageClass_To_kubeone_StorageClass is an autogenerated conversion function.
+func Convert_v1alpha1_StorageClass_To_kubeone_StorageClass(in *StorageClass, out *kubeone.StorageClass, s conversion.Scope) error {
+	return autoConvert_v1alpha1_StorageClass_To_kubeone_StorageClass(in, out, s)
+}
+
+func autoConvert_kubeone_StorageClass_To_v1alpha1_StorageClass(in *kubeone.StorageClass, out *StorageClass, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Default = in.Default
+	out.ReclaimPolicy = in.ReclaimPolicy
+	out.Parameters = *(*map[string]string)(unsafe.Pointer(&in.Parameters))
+	return nil
+}
+
+// Convert_kubeone_StorageClass_To_v1alpha1_StorageClass is an autogenerated conversion function.
+func Convert_kubeone_StorageClass_To_v1alpha1_StorageClass(in *kubeone.StorageClass, out *StorageClass, s conversion.Scope) error {
+	return autoConvert_kubeone_StorageClass_To_v1alpha1_StorageClass(in, out, s)
+}
+
+func autoConvert_v1alpha1_TopologySpreadConstraint_To_kubeone_TopologySpreadConstraint(in *TopologySpreadConstraint, out *kubeone.TopologySpreadConstraint, s conversion.Scope) error {
+	out.MaxSkew = in.MaxSkew
+	out.TopologyKey = in.TopologyKey
+	out.WhenUnsatisfiable = kubeone.UnsatisfiableConstraintAction(in.WhenUnsatisfiable)
+	return nil
+}
+
+// Convert_v1alpha1_TopologySpreadConstraint_To_kubeone_TopologySpreadConstraint is an autogenerated conversion function.
+func Convert_v1alpha1_TopologySpreadConstraint_To_kubeone_TopologySpreadConstraint(in *TopologySpreadConstraint, out *kubeone.TopologySpreadConstraint, s conversion.Scope) error {
+	return autoConvert_v1alpha1_TopologySpreadConstraint_To_kubeone_TopologySpreadConstraint(in, out, s)
+}
+
+func autoConvert_kubeone_TopologySpreadConstraint_To_v1alpha1_TopologySpreadConstraint(in *kubeone.TopologySpreadConstraint, out *TopologySpreadConstraint, s conversion.Scope) error {
+	out.MaxSkew = in.MaxSkew
+	out.TopologyKey = in.TopologyKey
+	out.WhenUnsatisfiable = UnsatisfiableConstraintAction(in.WhenUnsatisfiable)
+	return nil
+}
+
+// Convert_kubeone_TopologySpreadConstraint_To_v1alpha1_TopologySpreadConstraint is an autogenerated conversion function.
+func Convert_kubeone_TopologySpreadConstraint_To_v1alpha1_TopologySpreadConstraint(in *kubeone.TopologySpreadConstraint, out *TopologySpreadConstraint, s conversion.Scope) error {
+	return autoConvert_kubeone_TopologySpreadConstraint_To_v1alpha1_TopologySpreadConstraint(in, out, s)
+}
+
+func autoConvert_v1alpha1_VeleroConfig_To_kubeone_VeleroConfig(in *VeleroConfig, out *kubeone.VeleroConfig, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.Version = in.Version
+	out.BackendType = kubeone.VeleroBackend(in.BackendType)
+	out.BucketName = in.BucketName
+	out.Region = in.Region
+	return nil
+}
+
+// Convert_v1alpha1_VeleroConfig_To_kubeone_VeleroConfig is an autogenerated conversion function.
+func Convert_v1alpha1_VeleroConfig_To_kubeone_VeleroConfig(in *VeleroConfig, out *kubeone.VeleroConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_VeleroConfig_To_kubeone_VeleroConfig(in, out, s)
+}
+
+func autoConvert_kubeone_VeleroConfig_To_v1alpha1_VeleroConfig(in *kubeone.VeleroConfig, out *VeleroConfig, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.Version = in.Version
+	out.BackendType = VeleroBackend(in.BackendType)
+	out.BucketName = in.BucketName
+	out.Region = in.Region
+	return nil
+}
+
+// Convert_kubeone_VeleroConfig_To_v1alpha1_VeleroConfig is an autogenerated conversion function.
+func Convert_kubeone_VeleroConfig_To_v1alpha1_VeleroConfig(in *kubeone.VeleroConfig, out *VeleroConfig, s conversion.Scope) error {
+	return autoConvert_kubeone_VeleroConfig_To_v1alpha1_VeleroConfig(in, out, s)
+}
+
 func autoConvert_v1alpha1_VersionConfig_To_kubeone_VersionConfig(in *VersionConfig, out *kubeone.VersionConfig, s conversion.Scope) error {
 	out.Kubernetes = in.Kubernetes
 	return nil
@@ -17,14 +17,29 @@ limitations under the License.
 package validation
 
 import (
+	"fmt"
 	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/Masterminds/semver"
 	"github.com/kubermatic/kubeone/pkg/apis/kubeone"
+	"github.com/pkg/errors"
 
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
+// packageNameRegexp matches Debian and RPM package names, optionally with
+// the architecture
+var packageNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9.+_:-]*$`)
+
 // ValidateKubeOneCluster validates the KubeOneCluster object
 func ValidateKubeOneCluster(c kubeone.KubeOneCluster) field.ErrorList {
 	allErrs := field.ErrorList{}
@@ -40,15 +55,38 @@ func ValidateKubeOneCluster(c kubeone.KubeOneCluster) field.ErrorList {
 		allErrs = append(allErrs, field.Invalid(field.NewPath("hosts"), c.Hosts, "no host specified"))
 	}
 
+	allErrs = append(allErrs, ValidateAPIEndpoint(c.APIEndpoint, field.NewPath("apiEndpoint"))...)
+
 	if c.MachineController != nil && c.MachineController.Deploy {
 		allErrs = append(allErrs, ValidateMachineControllerConfig(c.MachineController, c.CloudProvider.Name, field.NewPath("machineController"))...)
 		allErrs = append(allErrs, ValidateWorkerConfig(c.Workers, field.NewPath("workers"))...)
+		allErrs = append(allErrs, ValidateWorkerDiskSize(c, field.NewPath("workers"))...)
+		allErrs = append(allErrs, ValidateWorkerDataDisks(c, field.NewPath("workers"))...)
 	} else if len(c.Workers) > 0 {
 		allErrs = append(allErrs, field.Invalid(field.NewPath("workers"), c.Workers, "machine-controller deployment is disabled, but configuration still contains worker definitions"))
 	}
 
 	allErrs = append(allErrs, ValidateVersionConfig(c.Versions, field.NewPath("versions"))...)
 	allErrs = append(allErrs, ValidateClusterNetworkConfig(c.ClusterNetwork, field.NewPath("clusterNetwork"))...)
+	allErrs = append(allErrs, ValidateKubeProxyConfig(c.KubeProxy, field.NewPath("kubeProxy"))...)
+	allErrs = append(allErrs, ValidateKubeletConfig(c.Kubelet, field.NewPath("kubelet"))...)
+	allErrs = append(allErrs, ValidateCoreDNSConfig(c.CoreDNS, field.NewPath("coreDNS"))...)
+	allErrs = append(allErrs, ValidateContainerRuntimeConfig(c.ContainerRuntime, field.NewPath("containerRuntime"))...)
+	allErrs = append(allErrs, ValidateOSUpgradeConfig(c.OSUpgrade, field.NewPath("osUpgrade"))...)
+
+	if c.CSI != nil {
+		allErrs = append(allErrs, ValidateCSIConfig(c.CSI, c.CloudProvider.Name, field.NewPath("csi"))...)
+	}
+	if c.ClusterAutoscaler != nil && c.ClusterAutoscaler.Enabled {
+		mcDeployed := c.MachineController != nil && c.MachineController.Deploy
+		allErrs = append(allErrs, ValidateClusterAutoscalerConfig(c.ClusterAutoscaler, mcDeployed, field.NewPath("clusterAutoscaler"))...)
+	}
+	if c.Velero != nil && c.Velero.Enabled {
+		allErrs = append(allErrs, ValidateVeleroConfig(c.Velero, field.NewPath("velero"))...)
+	}
+	if c.NodeLocalDNS != nil && c.NodeLocalDNS.Enabled {
+		allErrs = append(allErrs, ValidateNodeLocalDNSConfig(c.NodeLocalDNS, field.NewPath("nodeLocalDNS"))...)
+	}
 	allErrs = append(allErrs, ValidateFeatures(c.Features, field.NewPath("features"))...)
 
 	return allErrs
@@ -74,6 +112,15 @@ func ValidateCloudProviderSpec(p kubeone.CloudProviderSpec, fldPath *field.Path)
 		allErrs = append(allErrs, field.Invalid(fldPath, p.Name, "unknown provider name"))
 	}
 
+	if p.CCMVersion != "" {
+		if !p.External {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("ccmVersion"), p.CCMVersion, "`cloudProvider.ccmVersion` requires `cloudProvider.external` to be enabled"))
+		}
+		if _, err := semver.NewVersion(p.CCMVersion); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("ccmVersion"), p.CCMVersion, "invalid CCM version"))
+		}
+	}
+
 	return allErrs
 }
 
@@ -88,7 +135,7 @@ func ValidateHostConfig(hosts []kubeone.HostConfig, fldPath *field.Path) field.E
 		if len(h.PrivateAddress) == 0 {
 			allErrs = append(allErrs, field.Invalid(fldPath, h.PrivateAddress, "no private IP/address givevn"))
 		}
-		if len(h.SSHPrivateKeyFile) == 0 && len(h.SSHAgentSocket) == 0 {
+		if len(h.SSHPrivateKeyFile) == 0 && len(h.SSHPrivateKeyFiles) == 0 && len(h.SSHAgentSocket) == 0 {
 			allErrs = append(allErrs, field.Invalid(fldPath, h.SSHPrivateKeyFile, "neither SSH private key nor agent socket given, don't know how to authenticate"))
 			allErrs = append(allErrs, field.Invalid(fldPath, h.SSHAgentSocket, "neither SSH private key nor agent socket given, don't know how to authenticate"))
 		}
@@ -100,6 +147,22 @@ func ValidateHostConfig(hosts []kubeone.HostConfig, fldPath *field.Path) field.E
 	return allErrs
 }
 
+// ValidateAPIEndpoint validates the APIEndpoint structure
+func ValidateAPIEndpoint(e kubeone.APIEndpoint, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if net.ParseIP(e.Host) == nil {
+		for _, msg := range validation.IsDNS1123Subdomain(strings.ToLower(e.Host)) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("host"), e.Host, "must be a valid IP address or hostname: "+msg))
+		}
+	}
+	for _, msg := range validation.IsValidPortNum(e.Port) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("port"), e.Port, msg))
+	}
+
+	return allErrs
+}
+
 // ValidateVersionConfig validates the VersionConfig structure
 func ValidateVersionConfig(version kubeone.VersionConfig, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
@@ -132,9 +195,120 @@ func ValidateMachineControllerConfig(m *kubeone.MachineControllerConfig, cloudPr
 		allErrs = append(allErrs, field.Invalid(fldPath, m.Provider, "machine-controller deployed but no provider selected"))
 	}
 
+	leaderElection := []struct {
+		name     string
+		duration *metav1.Duration
+	}{
+		{"leaderElectionLeaseDuration", m.LeaderElectionLeaseDuration},
+		{"leaderElectionRenewDeadline", m.LeaderElectionRenewDeadline},
+		{"leaderElectionRetryPeriod", m.LeaderElectionRetryPeriod},
+	}
+	for _, le := range leaderElection {
+		if le.duration != nil && le.duration.Duration < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child(le.name), le.duration.Duration.String(), "duration must not be negative"))
+		}
+	}
+
+	if lease, renew := m.LeaderElectionLeaseDuration, m.LeaderElectionRenewDeadline; lease != nil && renew != nil &&
+		lease.Duration > 0 && renew.Duration > 0 && renew.Duration >= lease.Duration {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("leaderElectionRenewDeadline"), renew.Duration.String(), "renew deadline must be less than lease duration"))
+	}
+
+	// the API server caps webhook timeouts at 30 seconds
+	if m.WebhookTimeoutSeconds < 0 || m.WebhookTimeoutSeconds > 30 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("webhookTimeoutSeconds"), m.WebhookTimeoutSeconds, "webhook timeout must be between 1 and 30 seconds"))
+	}
+
+	if m.WorkerCount < 0 || m.WorkerCount > 50 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("workerCount"), m.WorkerCount, "worker count must be between 1 and 50"))
+	}
+
+	if m.MetricsPort < 0 || m.MetricsPort > 65535 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("metricsPort"), m.MetricsPort, "metrics port must be between 1 and 65535"))
+	}
+
+	for i, c := range m.TopologySpreadConstraints {
+		cPath := fldPath.Child("topologySpreadConstraints").Index(i)
+		if c.MaxSkew < 1 {
+			allErrs = append(allErrs, field.Invalid(cPath.Child("maxSkew"), c.MaxSkew, "maxSkew must be greater than zero"))
+		}
+		if c.TopologyKey == "" {
+			allErrs = append(allErrs, field.Required(cPath.Child("topologyKey"), "topologyKey must be specified"))
+		}
+		switch c.WhenUnsatisfiable {
+		case kubeone.DoNotSchedule, kubeone.ScheduleAnyway:
+		default:
+			allErrs = append(allErrs, field.NotSupported(cPath.Child("whenUnsatisfiable"), c.WhenUnsatisfiable, []string{string(kubeone.DoNotSchedule), string(kubeone.ScheduleAnyway)}))
+		}
+	}
+
+	if ref := m.CredentialsSecretRef; ref != nil {
+		refPath := fldPath.Child("credentialsSecretRef")
+		if ref.Name == "" {
+			allErrs = append(allErrs, field.Required(refPath.Child("name"), "secret name must be specified"))
+		}
+		// environment variables can only be loaded from Secrets in the namespace of the pod
+		if ref.Namespace != metav1.NamespaceSystem {
+			allErrs = append(allErrs, field.Invalid(refPath.Child("namespace"), ref.Namespace, "secret must be in the kube-system namespace"))
+		}
+	}
+
+	if m.UpdateStrategy != nil {
+		allErrs = append(allErrs, validateDeploymentStrategy(*m.UpdateStrategy, fldPath.Child("updateStrategy"))...)
+	}
+
 	return allErrs
 }
 
+func validateDeploymentStrategy(s appsv1.DeploymentStrategy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	switch s.Type {
+	case appsv1.RecreateDeploymentStrategyType:
+		if s.RollingUpdate != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("rollingUpdate"), "rollingUpdate may not be specified with the Recreate strategy"))
+		}
+	case appsv1.RollingUpdateDeploymentStrategyType:
+		if s.RollingUpdate == nil {
+			break
+		}
+		ruPath := fldPath.Child("rollingUpdate")
+		maxUnavailable, unavailableErrs := validateIntOrPercent(s.RollingUpdate.MaxUnavailable, ruPath.Child("maxUnavailable"))
+		maxSurge, surgeErrs := validateIntOrPercent(s.RollingUpdate.MaxSurge, ruPath.Child("maxSurge"))
+		allErrs = append(allErrs, unavailableErrs...)
+		allErrs = append(allErrs, surgeErrs...)
+		// the rollout can't make progress if no pod can be removed or added
+		if len(unavailableErrs) == 0 && len(surgeErrs) == 0 && maxUnavailable == 0 && maxSurge == 0 {
+			allErrs = append(allErrs, field.Invalid(ruPath.Child("maxUnavailable"), s.RollingUpdate.MaxUnavailable.String(), "maxUnavailable may not be 0 when maxSurge is 0"))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("type"), s.Type, []string{string(appsv1.RecreateDeploymentStrategyType), string(appsv1.RollingUpdateDeploymentStrategyType)}))
+	}
+
+	return allErrs
+}
+
+// validateIntOrPercent validates a rolling update parameter and returns its
+// value, percentages being taken out of 100. Unset parameters are 0.
+func validateIntOrPercent(v *intstr.IntOrString, fldPath *field.Path) (int, field.ErrorList) {
+	allErrs := field.ErrorList{}
+	if v == nil {
+		return 0, allErrs
+	}
+
+	value, err := intstr.GetValueFromIntOrPercent(v, 100, false)
+	switch {
+	case err != nil:
+		allErrs = append(allErrs, field.Invalid(fldPath, v.String(), "must be an integer or a percentage"))
+	case value < 0:
+		allErrs = append(allErrs, field.Invalid(fldPath, v.String(), "must not be negative"))
+	case v.Type == intstr.String && value > 100:
+		allErrs = append(allErrs, field.Invalid(fldPath, v.String(), "must not be greater than 100%"))
+	}
+
+	return value, allErrs
+}
+
 // ValidateWorkerConfig validates the WorkerConfig structure
 func ValidateWorkerConfig(workerset []kubeone.WorkerConfig, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
@@ -146,6 +320,87 @@ func ValidateWorkerConfig(workerset []kubeone.WorkerConfig, fldPath *field.Path)
 		if w.Replicas == nil || *w.Replicas < 1 {
 			allErrs = append(allErrs, field.Invalid(fldPath, w.Replicas, "replicas must be specified and >= 1"))
 		}
+		switch w.Config.CloudProvider {
+		case "":
+		case kubeone.CloudProviderNameAWS:
+		case kubeone.CloudProviderNameOpenStack:
+		case kubeone.CloudProviderNameHetzner:
+		case kubeone.CloudProviderNameDigitalOcean:
+		case kubeone.CloudProviderNamePacket:
+		case kubeone.CloudProviderNameVSphere:
+		case kubeone.CloudProviderNameGCE:
+		default:
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("providerSpec", "cloudProvider"), w.Config.CloudProvider, "unknown provider name"))
+		}
+	}
+
+	return allErrs
+}
+
+// workerDiskSizeLimits are the root disk sizes in GiB supported by the cloud
+// providers, keyed by provider
+var workerDiskSizeLimits = map[kubeone.CloudProviderName]struct{ min, max int32 }{
+	// EBS volumes
+	kubeone.CloudProviderNameAWS: {min: 1, max: 16384},
+	// persistent disks
+	kubeone.CloudProviderNameGCE: {min: 10, max: 65536},
+}
+
+// ValidateWorkerDiskSize validates the root disk size of the workersets
+// against the limits of the cloud provider they are created on
+func ValidateWorkerDiskSize(c kubeone.KubeOneCluster, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, w := range c.Workers {
+		size := w.Config.DiskSize
+		if size == 0 {
+			continue
+		}
+		sizePath := fldPath.Index(i).Child("providerSpec", "diskSize")
+
+		provider := c.WorkerCloudProvider(w)
+		limits, ok := workerDiskSizeLimits[provider]
+		if !ok {
+			allErrs = append(allErrs, field.Forbidden(sizePath, fmt.Sprintf("disk size is not supported on %q", provider)))
+			continue
+		}
+		if size < limits.min || size > limits.max {
+			allErrs = append(allErrs, field.Invalid(sizePath, size, fmt.Sprintf("disk size on %q must be between %d and %d GiB", provider, limits.min, limits.max)))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateWorkerDataDisks validates the data disks of the workersets against
+// the limits of the cloud provider they are created on
+func ValidateWorkerDataDisks(c kubeone.KubeOneCluster, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, w := range c.Workers {
+		if len(w.Config.DataDisks) == 0 {
+			continue
+		}
+		disksPath := fldPath.Index(i).Child("providerSpec", "dataDisks")
+
+		provider := c.WorkerCloudProvider(w)
+		limits, ok := workerDiskSizeLimits[provider]
+		if !ok {
+			allErrs = append(allErrs, field.Forbidden(disksPath, fmt.Sprintf("data disks are not supported on %q", provider)))
+			continue
+		}
+
+		for j, disk := range w.Config.DataDisks {
+			if disk.Size < limits.min || disk.Size > limits.max {
+				allErrs = append(allErrs, field.Invalid(disksPath.Index(j).Child("size"), disk.Size, fmt.Sprintf("disk size on %q must be between %d and %d GiB", provider, limits.min, limits.max)))
+			}
+			if disk.IOPS < 0 {
+				allErrs = append(allErrs, field.Invalid(disksPath.Index(j).Child("iops"), disk.IOPS, "iops must not be negative"))
+			}
+			if disk.IOPS > 0 && (provider != kubeone.CloudProviderNameAWS || disk.Type != "io1") {
+				allErrs = append(allErrs, field.Forbidden(disksPath.Index(j).Child("iops"), "iops are only supported for io1 disks on AWS"))
+			}
+		}
 	}
 
 	return allErrs
@@ -192,6 +447,306 @@ func ValidateCNI(c *kubeone.CNI, fldPath *field.Path) field.ErrorList {
 	return allErrs
 }
 
+// ValidateContainerRuntimeConfig validates the ContainerRuntimeConfig structure
+func ValidateContainerRuntimeConfig(c kubeone.ContainerRuntimeConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	switch c.Runtime {
+	case kubeone.ContainerRuntimeDocker:
+	case kubeone.ContainerRuntimeCRIO:
+	default:
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("runtime"), c.Runtime, "unknown container runtime"))
+	}
+
+	for registry, mirrors := range c.RegistryMirrors {
+		mirrorsPath := fldPath.Child("registryMirrors").Key(registry)
+		if registry == "" || strings.Contains(registry, "/") {
+			allErrs = append(allErrs, field.Invalid(mirrorsPath, registry, "registry must be a host name, e.g. docker.io"))
+		}
+		if c.Runtime == kubeone.ContainerRuntimeDocker && registry != kubeone.DockerHubRegistry {
+			allErrs = append(allErrs, field.Forbidden(mirrorsPath, fmt.Sprintf("docker only supports mirrors of %s", kubeone.DockerHubRegistry)))
+		}
+		for i, mirror := range mirrors {
+			u, err := url.Parse(mirror)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				allErrs = append(allErrs, field.Invalid(mirrorsPath.Index(i), mirror, "mirror must be an http or https URL"))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateOSUpgradeConfig validates the OSUpgradeConfig structure
+func ValidateOSUpgradeConfig(c kubeone.OSUpgradeConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	// package names are passed to the package manager in a shell script
+	for i, p := range c.Packages {
+		if !packageNameRegexp.MatchString(p) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("packages").Index(i), p, "invalid package name"))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateKubeletConfig validates the KubeletConfig structure
+func ValidateKubeletConfig(c kubeone.KubeletConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if c.DiskPressureThreshold != "" && !validEvictionThreshold(c.DiskPressureThreshold) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("diskPressureThreshold"), c.DiskPressureThreshold, "threshold must be a percentage, e.g. 10%, or a quantity, e.g. 5Gi"))
+	}
+	if c.InodePressureThreshold != "" && !validEvictionThreshold(c.InodePressureThreshold) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("inodePressureThreshold"), c.InodePressureThreshold, "threshold must be a percentage, e.g. 5%, or a number of inodes, e.g. 100k"))
+	}
+
+	return allErrs
+}
+
+// validEvictionThreshold returns whether the kubelet eviction threshold
+// is a percentage between 0 and 100 or a non-negative quantity
+func validEvictionThreshold(threshold string) bool {
+	if strings.HasSuffix(threshold, "%") {
+		percentage, err := strconv.ParseFloat(strings.TrimSuffix(threshold, "%"), 64)
+		return err == nil && percentage >= 0 && percentage <= 100
+	}
+
+	quantity, err := resource.ParseQuantity(threshold)
+	return err == nil && quantity.Sign() >= 0
+}
+
+// ValidateKubeProxyConfig validates the KubeProxyConfig structure
+func ValidateKubeProxyConfig(c kubeone.KubeProxyConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	switch c.Mode {
+	case "", kubeone.KubeProxyModeIPTables, kubeone.KubeProxyModeIPVS:
+	default:
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("mode"), c.Mode, "kube-proxy mode must be either iptables or ipvs"))
+	}
+
+	if c.IPVSScheduler != "" {
+		if c.Mode != kubeone.KubeProxyModeIPVS {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("ipvsScheduler"), c.IPVSScheduler, "ipvs scheduler requires the ipvs mode"))
+		}
+
+		// schedulers supported by kube-proxy, see ipvsadm(8)
+		switch c.IPVSScheduler {
+		case "rr", "wrr", "lc", "wlc", "lblc", "lblcr", "dh", "sh", "sed", "nq":
+		default:
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("ipvsScheduler"), c.IPVSScheduler, "unknown ipvs scheduler"))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateCoreDNSConfig validates the CoreDNSConfig structure
+func ValidateCoreDNSConfig(c kubeone.CoreDNSConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if c.Replicas < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("replicas"), c.Replicas, "replicas must not be negative"))
+	}
+
+	if c.CPURequest != "" {
+		if _, err := resource.ParseQuantity(c.CPURequest); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("cpuRequest"), c.CPURequest, "invalid cpu quantity"))
+		}
+	}
+	if c.MemoryRequest != "" {
+		if _, err := resource.ParseQuantity(c.MemoryRequest); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("memoryRequest"), c.MemoryRequest, "invalid memory quantity"))
+		}
+	}
+
+	if c.Corefile != "" {
+		if err := validateCorefile(c.Corefile); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("corefile"), c.Corefile, err.Error()))
+		}
+	}
+
+	return allErrs
+}
+
+// validateCorefile checks the structure of a Corefile: it must consist of
+// server blocks, each starting with the addresses it serves, with balanced
+// braces. Directives themselves are checked by CoreDNS when it loads them.
+func validateCorefile(corefile string) error {
+	var (
+		depth  int
+		blocks int
+		keys   string
+	)
+
+	for i, line := range strings.Split(corefile, "\n") {
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+
+		for _, r := range line {
+			switch {
+			case r == '{':
+				if depth == 0 {
+					if strings.TrimSpace(keys) == "" {
+						return errors.Errorf("line %d: server block without server address", i+1)
+					}
+					keys = ""
+					blocks++
+				}
+				depth++
+			case r == '}':
+				depth--
+				if depth < 0 {
+					return errors.Errorf("line %d: unexpected '}'", i+1)
+				}
+			case depth == 0:
+				keys += string(r)
+			}
+		}
+		if depth == 0 {
+			keys += " "
+		}
+	}
+
+	if depth > 0 {
+		return errors.New("unclosed server block")
+	}
+	if strings.TrimSpace(keys) != "" {
+		return errors.Errorf("%q is outside of a server block", strings.TrimSpace(keys))
+	}
+	if blocks == 0 {
+		return errors.New("no server block defined")
+	}
+
+	return nil
+}
+
+// ValidateCSIConfig validates the CSIConfig structure
+func ValidateCSIConfig(c *kubeone.CSIConfig, provider kubeone.CloudProviderName, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	switch c.Driver {
+	case kubeone.CSIDriverAWSEBS:
+		if provider != kubeone.CloudProviderNameAWS {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("driver"), c.Driver, "`aws-ebs` csi driver requires the aws cloud provider"))
+		}
+	case kubeone.CSIDriverGCPPD:
+		if provider != kubeone.CloudProviderNameGCE {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("driver"), c.Driver, "`gcp-pd` csi driver requires the gce cloud provider"))
+		}
+	default:
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("driver"), c.Driver, "unknown CSI driver"))
+	}
+
+	if c.Version != "" {
+		if _, err := semver.NewVersion(c.Version); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("version"), c.Version, "invalid CSI driver version"))
+		}
+	}
+
+	names := map[string]bool{}
+	defaults := 0
+	for i, sc := range c.StorageClasses {
+		scPath := fldPath.Child("storageClasses").Index(i)
+
+		if len(validation.IsDNS1123Subdomain(sc.Name)) > 0 {
+			allErrs = append(allErrs, field.Invalid(scPath.Child("name"), sc.Name, "storage class name must be a valid DNS-1123 subdomain"))
+		}
+		if names[sc.Name] {
+			allErrs = append(allErrs, field.Duplicate(scPath.Child("name"), sc.Name))
+		}
+		names[sc.Name] = true
+
+		switch sc.ReclaimPolicy {
+		case "", "Delete", "Retain":
+		default:
+			allErrs = append(allErrs, field.Invalid(scPath.Child("reclaimPolicy"), sc.ReclaimPolicy, "reclaim policy must be either Delete or Retain"))
+		}
+
+		if sc.Default {
+			defaults++
+		}
+	}
+	if defaults > 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("storageClasses"), defaults, "only one storage class can be the default one"))
+	}
+
+	return allErrs
+}
+
+// ValidateClusterAutoscalerConfig validates the ClusterAutoscalerConfig structure
+func ValidateClusterAutoscalerConfig(c *kubeone.ClusterAutoscalerConfig, machineControllerDeployed bool, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if !machineControllerDeployed {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("enabled"), c.Enabled, "cluster-autoscaler requires machine-controller to be deployed"))
+	}
+
+	if c.Version != "" {
+		if _, err := semver.NewVersion(c.Version); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("version"), c.Version, "invalid cluster-autoscaler version"))
+		}
+	}
+
+	// the cluster-api provider can't scale MachineDeployments from zero
+	if c.MinNodes < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("minNodes"), c.MinNodes, "minNodes must be at least 1"))
+	}
+	if c.MaxNodes < c.MinNodes {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxNodes"), c.MaxNodes, "maxNodes must not be lower than minNodes"))
+	}
+
+	if c.ScaleDownDelay.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("scaleDownDelay"), c.ScaleDownDelay.Duration.String(), "scaleDownDelay must not be negative"))
+	}
+
+	return allErrs
+}
+
+// ValidateVeleroConfig validates the VeleroConfig structure
+func ValidateVeleroConfig(v *kubeone.VeleroConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	switch v.BackendType {
+	case kubeone.VeleroBackendS3:
+		if v.Region == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("region"), "region is required for the s3 backend"))
+		}
+	case kubeone.VeleroBackendGCS, kubeone.VeleroBackendAzure:
+	default:
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("backendType"), v.BackendType, "unknown velero backend, must be one of s3, gcs or azure"))
+	}
+
+	if v.BucketName == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("bucketName"), "bucket name is required"))
+	}
+
+	if v.Version != "" {
+		if _, err := semver.NewVersion(v.Version); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("version"), v.Version, "invalid velero version"))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateNodeLocalDNSConfig validates the NodeLocalDNSConfig structure
+func ValidateNodeLocalDNSConfig(n *kubeone.NodeLocalDNSConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	// the cache must listen on an address which doesn't collide with
+	// any address in the cluster
+	ip := net.ParseIP(n.LocalAddress)
+	if ip == nil || !ip.IsLinkLocalUnicast() {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("localAddress"), n.LocalAddress, "local address must be a valid link-local IP address"))
+	}
+
+	return allErrs
+}
+
 // ValidateFeatures validates the Features structure
 func ValidateFeatures(f kubeone.Features, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
@@ -18,8 +18,15 @@ package validation
 
 import (
 	"testing"
+	"time"
 
 	"github.com/kubermatic/kubeone/pkg/apis/kubeone"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 func TestValidateCloudProviderSpec(t *testing.T) {
@@ -74,6 +81,32 @@ func TestValidateCloudProviderSpec(t *testing.T) {
 			},
 			expectedError: true,
 		},
+		{
+			name: "valid provider config with external CCM version",
+			providerConfig: kubeone.CloudProviderSpec{
+				Name:       kubeone.CloudProviderNameGCE,
+				External:   true,
+				CCMVersion: "v1.14.1",
+			},
+			expectedError: false,
+		},
+		{
+			name: "invalid provider config (CCM version without external CCM)",
+			providerConfig: kubeone.CloudProviderSpec{
+				Name:       kubeone.CloudProviderNameGCE,
+				CCMVersion: "v1.14.1",
+			},
+			expectedError: true,
+		},
+		{
+			name: "invalid provider config (invalid CCM version)",
+			providerConfig: kubeone.CloudProviderSpec{
+				Name:       kubeone.CloudProviderNameGCE,
+				External:   true,
+				CCMVersion: "latest",
+			},
+			expectedError: true,
+		},
 	}
 
 	for _, tc := range tests {
@@ -119,6 +152,18 @@ func TestValidateHostConfig(t *testing.T) {
 			},
 			expectedError: false,
 		},
+		{
+			name: "valid host config (with multiple ssh keys only)",
+			hostConfig: []kubeone.HostConfig{
+				{
+					PublicAddress:      "192.168.1.1",
+					PrivateAddress:     "192.168.0.1",
+					SSHPrivateKeyFiles: []string{"new", "old"},
+					SSHUsername:        "root",
+				},
+			},
+			expectedError: false,
+		},
 		{
 			name: "invalid host config (no public address)",
 			hostConfig: []kubeone.HostConfig{
@@ -204,6 +249,64 @@ func TestValidateHostConfig(t *testing.T) {
 	}
 }
 
+func TestValidateAPIEndpoint(t *testing.T) {
+	tests := []struct {
+		name          string
+		apiEndpoint   kubeone.APIEndpoint
+		expectedError bool
+	}{
+		{
+			name: "valid API endpoint (IP address)",
+			apiEndpoint: kubeone.APIEndpoint{
+				Host: "192.168.1.1",
+				Port: 6443,
+			},
+			expectedError: false,
+		},
+		{
+			name: "valid API endpoint (load balancer hostname)",
+			apiEndpoint: kubeone.APIEndpoint{
+				Host: "kubeone-api-lb-1234.elb.eu-west-3.amazonaws.com",
+				Port: 6443,
+			},
+			expectedError: false,
+		},
+		{
+			name: "invalid API endpoint (no host)",
+			apiEndpoint: kubeone.APIEndpoint{
+				Port: 6443,
+			},
+			expectedError: true,
+		},
+		{
+			name: "invalid API endpoint (invalid hostname)",
+			apiEndpoint: kubeone.APIEndpoint{
+				Host: "api_lb.example.com",
+				Port: 6443,
+			},
+			expectedError: true,
+		},
+		{
+			name: "invalid API endpoint (invalid port)",
+			apiEndpoint: kubeone.APIEndpoint{
+				Host: "192.168.1.1",
+				Port: 70000,
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateAPIEndpoint(tc.apiEndpoint, nil)
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
 func TestValidateVersionConfig(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -314,6 +417,178 @@ func TestValidateMachineControllerConfig(t *testing.T) {
 			},
 			expectedError: true,
 		},
+		{
+			name:          "valid machine-controller config (leader election)",
+			cloudProvider: kubeone.CloudProviderNameAWS,
+			machineControllerConfig: &kubeone.MachineControllerConfig{
+				Deploy:                      true,
+				Provider:                    kubeone.CloudProviderNameAWS,
+				LeaderElectionLeaseDuration: &metav1.Duration{Duration: 15 * time.Second},
+				LeaderElectionRenewDeadline: &metav1.Duration{Duration: 10 * time.Second},
+				LeaderElectionRetryPeriod:   &metav1.Duration{Duration: 2 * time.Second},
+			},
+			expectedError: false,
+		},
+		{
+			name:          "invalid machine-controller config (renew deadline not less than lease duration)",
+			cloudProvider: kubeone.CloudProviderNameAWS,
+			machineControllerConfig: &kubeone.MachineControllerConfig{
+				Deploy:                      true,
+				Provider:                    kubeone.CloudProviderNameAWS,
+				LeaderElectionLeaseDuration: &metav1.Duration{Duration: 10 * time.Second},
+				LeaderElectionRenewDeadline: &metav1.Duration{Duration: 10 * time.Second},
+			},
+			expectedError: true,
+		},
+		{
+			name:          "invalid machine-controller config (webhook timeout above 30 seconds)",
+			cloudProvider: kubeone.CloudProviderNameAWS,
+			machineControllerConfig: &kubeone.MachineControllerConfig{
+				Deploy:                true,
+				Provider:              kubeone.CloudProviderNameAWS,
+				WebhookTimeoutSeconds: 45,
+			},
+			expectedError: true,
+		},
+		{
+			name:          "valid machine-controller config (worker count)",
+			cloudProvider: kubeone.CloudProviderNameAWS,
+			machineControllerConfig: &kubeone.MachineControllerConfig{
+				Deploy:      true,
+				Provider:    kubeone.CloudProviderNameAWS,
+				WorkerCount: 20,
+			},
+			expectedError: false,
+		},
+		{
+			name:          "invalid machine-controller config (worker count above 50)",
+			cloudProvider: kubeone.CloudProviderNameAWS,
+			machineControllerConfig: &kubeone.MachineControllerConfig{
+				Deploy:      true,
+				Provider:    kubeone.CloudProviderNameAWS,
+				WorkerCount: 51,
+			},
+			expectedError: true,
+		},
+		{
+			name:          "invalid machine-controller config (metrics port out of range)",
+			cloudProvider: kubeone.CloudProviderNameAWS,
+			machineControllerConfig: &kubeone.MachineControllerConfig{
+				Deploy:      true,
+				Provider:    kubeone.CloudProviderNameAWS,
+				MetricsPort: 70000,
+			},
+			expectedError: true,
+		},
+		{
+			name:          "valid machine-controller config (rolling update strategy)",
+			cloudProvider: kubeone.CloudProviderNameAWS,
+			machineControllerConfig: &kubeone.MachineControllerConfig{
+				Deploy:   true,
+				Provider: kubeone.CloudProviderNameAWS,
+				UpdateStrategy: &appsv1.DeploymentStrategy{
+					Type: appsv1.RollingUpdateDeploymentStrategyType,
+					RollingUpdate: &appsv1.RollingUpdateDeployment{
+						MaxUnavailable: intOrStrPtr(intstr.FromInt(0)),
+						MaxSurge:       intOrStrPtr(intstr.FromString("50%")),
+					},
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name:          "invalid machine-controller config (rolling update without unavailable or surge pods)",
+			cloudProvider: kubeone.CloudProviderNameAWS,
+			machineControllerConfig: &kubeone.MachineControllerConfig{
+				Deploy:   true,
+				Provider: kubeone.CloudProviderNameAWS,
+				UpdateStrategy: &appsv1.DeploymentStrategy{
+					Type: appsv1.RollingUpdateDeploymentStrategyType,
+					RollingUpdate: &appsv1.RollingUpdateDeployment{
+						MaxUnavailable: intOrStrPtr(intstr.FromInt(0)),
+						MaxSurge:       intOrStrPtr(intstr.FromString("0%")),
+					},
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name:          "invalid machine-controller config (recreate strategy with rolling update)",
+			cloudProvider: kubeone.CloudProviderNameAWS,
+			machineControllerConfig: &kubeone.MachineControllerConfig{
+				Deploy:   true,
+				Provider: kubeone.CloudProviderNameAWS,
+				UpdateStrategy: &appsv1.DeploymentStrategy{
+					Type:          appsv1.RecreateDeploymentStrategyType,
+					RollingUpdate: &appsv1.RollingUpdateDeployment{},
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name:          "invalid machine-controller config (unknown update strategy)",
+			cloudProvider: kubeone.CloudProviderNameAWS,
+			machineControllerConfig: &kubeone.MachineControllerConfig{
+				Deploy:         true,
+				Provider:       kubeone.CloudProviderNameAWS,
+				UpdateStrategy: &appsv1.DeploymentStrategy{Type: "BlueGreen"},
+			},
+			expectedError: true,
+		},
+		{
+			name:          "valid machine-controller config (topology spread constraints)",
+			cloudProvider: kubeone.CloudProviderNameAWS,
+			machineControllerConfig: &kubeone.MachineControllerConfig{
+				Deploy:   true,
+				Provider: kubeone.CloudProviderNameAWS,
+				TopologySpreadConstraints: []kubeone.TopologySpreadConstraint{
+					{MaxSkew: 1, TopologyKey: "failure-domain.beta.kubernetes.io/zone", WhenUnsatisfiable: kubeone.ScheduleAnyway},
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name:          "invalid machine-controller config (topology spread constraint without topology key)",
+			cloudProvider: kubeone.CloudProviderNameAWS,
+			machineControllerConfig: &kubeone.MachineControllerConfig{
+				Deploy:   true,
+				Provider: kubeone.CloudProviderNameAWS,
+				TopologySpreadConstraints: []kubeone.TopologySpreadConstraint{
+					{MaxSkew: 1, WhenUnsatisfiable: kubeone.DoNotSchedule},
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name:          "invalid machine-controller config (negative retry period)",
+			cloudProvider: kubeone.CloudProviderNameAWS,
+			machineControllerConfig: &kubeone.MachineControllerConfig{
+				Deploy:                    true,
+				Provider:                  kubeone.CloudProviderNameAWS,
+				LeaderElectionRetryPeriod: &metav1.Duration{Duration: -2 * time.Second},
+			},
+			expectedError: true,
+		},
+		{
+			name:          "valid machine-controller config (credentials secret)",
+			cloudProvider: kubeone.CloudProviderNameAWS,
+			machineControllerConfig: &kubeone.MachineControllerConfig{
+				Deploy:               true,
+				Provider:             kubeone.CloudProviderNameAWS,
+				CredentialsSecretRef: &corev1.SecretReference{Name: "aws-credentials", Namespace: metav1.NamespaceSystem},
+			},
+			expectedError: false,
+		},
+		{
+			name:          "invalid machine-controller config (credentials secret in another namespace)",
+			cloudProvider: kubeone.CloudProviderNameAWS,
+			machineControllerConfig: &kubeone.MachineControllerConfig{
+				Deploy:               true,
+				Provider:             kubeone.CloudProviderNameAWS,
+				CredentialsSecretRef: &corev1.SecretReference{Name: "aws-credentials", Namespace: "default"},
+			},
+			expectedError: true,
+		},
 	}
 
 	for _, tc := range tests {
@@ -388,6 +663,28 @@ func TestValidateWorkerConfig(t *testing.T) {
 			},
 			expectedError: true,
 		},
+		{
+			name: "valid worker config (workers on another cloud provider)",
+			workerConfig: []kubeone.WorkerConfig{
+				{
+					Name:     "test-1",
+					Replicas: intPtr(3),
+					Config:   kubeone.ProviderSpec{CloudProvider: kubeone.CloudProviderNameDigitalOcean},
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "invalid worker config (workers on no cloud provider)",
+			workerConfig: []kubeone.WorkerConfig{
+				{
+					Name:     "test-1",
+					Replicas: intPtr(3),
+					Config:   kubeone.ProviderSpec{CloudProvider: kubeone.CloudProviderNameNone},
+				},
+			},
+			expectedError: true,
+		},
 	}
 
 	for _, tc := range tests {
@@ -401,39 +698,47 @@ func TestValidateWorkerConfig(t *testing.T) {
 	}
 }
 
-func TestValidateClusterNetworkConfig(t *testing.T) {
+func TestValidateWorkerDiskSize(t *testing.T) {
 	tests := []struct {
-		name                 string
-		clusterNetworkConfig kubeone.ClusterNetworkConfig
-		expectedError        bool
+		name          string
+		provider      kubeone.CloudProviderName
+		workerConfig  kubeone.ProviderSpec
+		expectedError bool
 	}{
 		{
-			name: "valid worker config",
-			clusterNetworkConfig: kubeone.ClusterNetworkConfig{
-				PodSubnet:     "192.168.1.0/24",
-				ServiceSubnet: "192.168.0.0/24",
-			},
+			name:          "valid disk size (unset)",
+			provider:      kubeone.CloudProviderNameHetzner,
+			workerConfig:  kubeone.ProviderSpec{},
 			expectedError: false,
 		},
 		{
-			name:                 "valid worker config (empty config)",
-			clusterNetworkConfig: kubeone.ClusterNetworkConfig{},
-			expectedError:        false,
+			name:          "valid disk size (AWS)",
+			provider:      kubeone.CloudProviderNameAWS,
+			workerConfig:  kubeone.ProviderSpec{DiskSize: 50},
+			expectedError: false,
 		},
 		{
-			name: "invalid worker config (invalid pod subnet)",
-			clusterNetworkConfig: kubeone.ClusterNetworkConfig{
-				PodSubnet:     "192.168.1.0",
-				ServiceSubnet: "192.168.0.0/24",
-			},
+			name:          "invalid disk size (GCE below minimum)",
+			provider:      kubeone.CloudProviderNameGCE,
+			workerConfig:  kubeone.ProviderSpec{DiskSize: 5},
 			expectedError: true,
 		},
 		{
-			name: "invalid worker config (invalid service subnet)",
-			clusterNetworkConfig: kubeone.ClusterNetworkConfig{
-				PodSubnet:     "192.168.1.0/24",
-				ServiceSubnet: "192.168.0.0",
-			},
+			name:          "invalid disk size (AWS above maximum)",
+			provider:      kubeone.CloudProviderNameAWS,
+			workerConfig:  kubeone.ProviderSpec{DiskSize: 20000},
+			expectedError: true,
+		},
+		{
+			name:          "valid disk size (GCE workers on AWS cluster)",
+			provider:      kubeone.CloudProviderNameAWS,
+			workerConfig:  kubeone.ProviderSpec{CloudProvider: kubeone.CloudProviderNameGCE, DiskSize: 100},
+			expectedError: false,
+		},
+		{
+			name:          "invalid disk size (unsupported provider)",
+			provider:      kubeone.CloudProviderNameDigitalOcean,
+			workerConfig:  kubeone.ProviderSpec{DiskSize: 50},
 			expectedError: true,
 		},
 	}
@@ -441,7 +746,13 @@ func TestValidateClusterNetworkConfig(t *testing.T) {
 	for _, tc := range tests {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
-			errs := ValidateClusterNetworkConfig(tc.clusterNetworkConfig, nil)
+			c := kubeone.KubeOneCluster{
+				CloudProvider: kubeone.CloudProviderSpec{Name: tc.provider},
+				Workers: []kubeone.WorkerConfig{
+					{Name: "test-1", Replicas: intPtr(1), Config: tc.workerConfig},
+				},
+			}
+			errs := ValidateWorkerDiskSize(c, field.NewPath("workers"))
 			if (len(errs) == 0) == tc.expectedError {
 				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
 			}
@@ -449,59 +760,749 @@ func TestValidateClusterNetworkConfig(t *testing.T) {
 	}
 }
 
-func TestValidateFeatures(t *testing.T) {
+func TestValidateWorkerDataDisks(t *testing.T) {
 	tests := []struct {
 		name          string
-		features      kubeone.Features
+		provider      kubeone.CloudProviderName
+		workerConfig  kubeone.ProviderSpec
 		expectedError bool
 	}{
 		{
-			name: "valid features config (psp and auditing enabled)",
-			features: kubeone.Features{
-				PodSecurityPolicy: &kubeone.PodSecurityPolicy{
-					Enable: true,
-				},
-				DynamicAuditLog: &kubeone.DynamicAuditLog{
-					Enable: true,
-				},
-			},
+			name:          "valid data disks (unset)",
+			provider:      kubeone.CloudProviderNameHetzner,
+			workerConfig:  kubeone.ProviderSpec{},
 			expectedError: false,
 		},
 		{
-			name: "valid features config (metrics server disabled)",
-			features: kubeone.Features{
-				MetricsServer: &kubeone.MetricsServer{
-					Enable: false,
-				},
-			},
+			name:          "valid data disks (AWS io1)",
+			provider:      kubeone.CloudProviderNameAWS,
+			workerConfig:  kubeone.ProviderSpec{DataDisks: []kubeone.DataDisk{{Size: 100, Type: "io1", IOPS: 1000}}},
 			expectedError: false,
 		},
 		{
-			name:          "valid features config (no feature configured)",
-			features:      kubeone.Features{},
+			name:          "valid data disks (GCE)",
+			provider:      kubeone.CloudProviderNameGCE,
+			workerConfig:  kubeone.ProviderSpec{DataDisks: []kubeone.DataDisk{{Size: 100, Type: "pd-ssd"}, {Size: 200}}},
 			expectedError: false,
 		},
 		{
-			name: "valid features config (oidc enabled)",
-			features: kubeone.Features{
-				OpenIDConnect: &kubeone.OpenIDConnect{
-					Enable: true,
-					Config: kubeone.OpenIDConnectConfig{
-						IssuerURL:     "test.cluster.local",
-						ClientID:      "123",
-						RequiredClaim: "test",
-					},
-				},
-			},
-			expectedError: false,
+			name:          "invalid data disks (GCE below minimum)",
+			provider:      kubeone.CloudProviderNameGCE,
+			workerConfig:  kubeone.ProviderSpec{DataDisks: []kubeone.DataDisk{{Size: 5}}},
+			expectedError: true,
 		},
 		{
-			name: "invalid features config (invalid oidc config)",
-			features: kubeone.Features{
-				OpenIDConnect: &kubeone.OpenIDConnect{
-					Enable: true,
-					Config: kubeone.OpenIDConnectConfig{},
-				},
+			name:          "invalid data disks (missing size)",
+			provider:      kubeone.CloudProviderNameAWS,
+			workerConfig:  kubeone.ProviderSpec{DataDisks: []kubeone.DataDisk{{Type: "gp2"}}},
+			expectedError: true,
+		},
+		{
+			name:          "invalid data disks (iops on gp2)",
+			provider:      kubeone.CloudProviderNameAWS,
+			workerConfig:  kubeone.ProviderSpec{DataDisks: []kubeone.DataDisk{{Size: 100, Type: "gp2", IOPS: 1000}}},
+			expectedError: true,
+		},
+		{
+			name:          "invalid data disks (unsupported provider)",
+			provider:      kubeone.CloudProviderNameDigitalOcean,
+			workerConfig:  kubeone.ProviderSpec{DataDisks: []kubeone.DataDisk{{Size: 100}}},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			c := kubeone.KubeOneCluster{
+				CloudProvider: kubeone.CloudProviderSpec{Name: tc.provider},
+				Workers: []kubeone.WorkerConfig{
+					{Name: "test-1", Replicas: intPtr(1), Config: tc.workerConfig},
+				},
+			}
+			errs := ValidateWorkerDataDisks(c, field.NewPath("workers"))
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
+func TestValidateClusterNetworkConfig(t *testing.T) {
+	tests := []struct {
+		name                 string
+		clusterNetworkConfig kubeone.ClusterNetworkConfig
+		expectedError        bool
+	}{
+		{
+			name: "valid worker config",
+			clusterNetworkConfig: kubeone.ClusterNetworkConfig{
+				PodSubnet:     "192.168.1.0/24",
+				ServiceSubnet: "192.168.0.0/24",
+			},
+			expectedError: false,
+		},
+		{
+			name:                 "valid worker config (empty config)",
+			clusterNetworkConfig: kubeone.ClusterNetworkConfig{},
+			expectedError:        false,
+		},
+		{
+			name: "invalid worker config (invalid pod subnet)",
+			clusterNetworkConfig: kubeone.ClusterNetworkConfig{
+				PodSubnet:     "192.168.1.0",
+				ServiceSubnet: "192.168.0.0/24",
+			},
+			expectedError: true,
+		},
+		{
+			name: "invalid worker config (invalid service subnet)",
+			clusterNetworkConfig: kubeone.ClusterNetworkConfig{
+				PodSubnet:     "192.168.1.0/24",
+				ServiceSubnet: "192.168.0.0",
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateClusterNetworkConfig(tc.clusterNetworkConfig, nil)
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
+func TestValidateContainerRuntimeConfig(t *testing.T) {
+	tests := []struct {
+		name                   string
+		containerRuntimeConfig kubeone.ContainerRuntimeConfig
+		expectedError          bool
+	}{
+		{
+			name: "valid container runtime config (docker)",
+			containerRuntimeConfig: kubeone.ContainerRuntimeConfig{
+				Runtime: kubeone.ContainerRuntimeDocker,
+			},
+			expectedError: false,
+		},
+		{
+			name: "valid container runtime config (crio)",
+			containerRuntimeConfig: kubeone.ContainerRuntimeConfig{
+				Runtime: kubeone.ContainerRuntimeCRIO,
+			},
+			expectedError: false,
+		},
+		{
+			name: "invalid container runtime config (unknown runtime)",
+			containerRuntimeConfig: kubeone.ContainerRuntimeConfig{
+				Runtime: "rkt",
+			},
+			expectedError: true,
+		},
+		{
+			name: "valid container runtime config (docker mirrors)",
+			containerRuntimeConfig: kubeone.ContainerRuntimeConfig{
+				Runtime:         kubeone.ContainerRuntimeDocker,
+				RegistryMirrors: map[string][]string{"docker.io": {"https://mirror.local:5000"}},
+			},
+			expectedError: false,
+		},
+		{
+			name: "valid container runtime config (crio mirrors)",
+			containerRuntimeConfig: kubeone.ContainerRuntimeConfig{
+				Runtime:         kubeone.ContainerRuntimeCRIO,
+				RegistryMirrors: map[string][]string{"quay.io": {"http://mirror.local/quay"}},
+			},
+			expectedError: false,
+		},
+		{
+			name: "invalid container runtime config (docker mirrors of quay.io)",
+			containerRuntimeConfig: kubeone.ContainerRuntimeConfig{
+				Runtime:         kubeone.ContainerRuntimeDocker,
+				RegistryMirrors: map[string][]string{"quay.io": {"https://mirror.local"}},
+			},
+			expectedError: true,
+		},
+		{
+			name: "invalid container runtime config (mirror without scheme)",
+			containerRuntimeConfig: kubeone.ContainerRuntimeConfig{
+				Runtime:         kubeone.ContainerRuntimeCRIO,
+				RegistryMirrors: map[string][]string{"docker.io": {"mirror.local:5000"}},
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateContainerRuntimeConfig(tc.containerRuntimeConfig, nil)
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
+func TestValidateOSUpgradeConfig(t *testing.T) {
+	tests := []struct {
+		name            string
+		osUpgradeConfig kubeone.OSUpgradeConfig
+		expectedError   bool
+	}{
+		{
+			name:            "valid os upgrade config (all packages)",
+			osUpgradeConfig: kubeone.OSUpgradeConfig{},
+			expectedError:   false,
+		},
+		{
+			name:            "valid os upgrade config (listed packages)",
+			osUpgradeConfig: kubeone.OSUpgradeConfig{Packages: []string{"openssl", "libstdc++6", "glibc.x86_64"}},
+			expectedError:   false,
+		},
+		{
+			name:            "invalid os upgrade config (shell in package name)",
+			osUpgradeConfig: kubeone.OSUpgradeConfig{Packages: []string{"openssl; reboot"}},
+			expectedError:   true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateOSUpgradeConfig(tc.osUpgradeConfig, nil)
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
+func TestValidateKubeProxyConfig(t *testing.T) {
+	tests := []struct {
+		name            string
+		kubeProxyConfig kubeone.KubeProxyConfig
+		expectedError   bool
+	}{
+		{
+			name:            "valid kube-proxy config (default mode)",
+			kubeProxyConfig: kubeone.KubeProxyConfig{},
+			expectedError:   false,
+		},
+		{
+			name:            "valid kube-proxy config (iptables)",
+			kubeProxyConfig: kubeone.KubeProxyConfig{Mode: kubeone.KubeProxyModeIPTables},
+			expectedError:   false,
+		},
+		{
+			name:            "valid kube-proxy config (ipvs with scheduler)",
+			kubeProxyConfig: kubeone.KubeProxyConfig{Mode: kubeone.KubeProxyModeIPVS, IPVSScheduler: "lc"},
+			expectedError:   false,
+		},
+		{
+			name:            "invalid kube-proxy config (unknown mode)",
+			kubeProxyConfig: kubeone.KubeProxyConfig{Mode: "userspace"},
+			expectedError:   true,
+		},
+		{
+			name:            "invalid kube-proxy config (scheduler without ipvs)",
+			kubeProxyConfig: kubeone.KubeProxyConfig{Mode: kubeone.KubeProxyModeIPTables, IPVSScheduler: "rr"},
+			expectedError:   true,
+		},
+		{
+			name:            "invalid kube-proxy config (unknown scheduler)",
+			kubeProxyConfig: kubeone.KubeProxyConfig{Mode: kubeone.KubeProxyModeIPVS, IPVSScheduler: "random"},
+			expectedError:   true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateKubeProxyConfig(tc.kubeProxyConfig, nil)
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
+func TestValidateKubeletConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		kubeletConfig kubeone.KubeletConfig
+		expectedError bool
+	}{
+		{
+			name:          "valid kubelet config (defaults)",
+			kubeletConfig: kubeone.KubeletConfig{},
+			expectedError: false,
+		},
+		{
+			name:          "valid kubelet config (percentages)",
+			kubeletConfig: kubeone.KubeletConfig{DiskPressureThreshold: "10%", InodePressureThreshold: "5%"},
+			expectedError: false,
+		},
+		{
+			name:          "valid kubelet config (quantities)",
+			kubeletConfig: kubeone.KubeletConfig{DiskPressureThreshold: "5Gi", InodePressureThreshold: "100k"},
+			expectedError: false,
+		},
+		{
+			name:          "invalid kubelet config (percentage above 100)",
+			kubeletConfig: kubeone.KubeletConfig{DiskPressureThreshold: "110%"},
+			expectedError: true,
+		},
+		{
+			name:          "invalid kubelet config (malformed quantity)",
+			kubeletConfig: kubeone.KubeletConfig{DiskPressureThreshold: "5 GB"},
+			expectedError: true,
+		},
+		{
+			name:          "invalid kubelet config (negative inodes)",
+			kubeletConfig: kubeone.KubeletConfig{InodePressureThreshold: "-1000"},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateKubeletConfig(tc.kubeletConfig, nil)
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
+func TestValidateCoreDNSConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		coreDNSConfig kubeone.CoreDNSConfig
+		expectedError bool
+	}{
+		{
+			name:          "valid coredns config (defaults)",
+			coreDNSConfig: kubeone.CoreDNSConfig{},
+			expectedError: false,
+		},
+		{
+			name: "valid coredns config",
+			coreDNSConfig: kubeone.CoreDNSConfig{
+				Replicas:      3,
+				CPURequest:    "100m",
+				MemoryRequest: "70Mi",
+				Corefile: `# cluster DNS
+.:53 {
+    errors
+    health
+    kubernetes cluster.local in-addr.arpa ip6.arpa {
+       pods insecure
+       fallthrough in-addr.arpa ip6.arpa
+    }
+    forward . /etc/resolv.conf
+    cache 30
+}
+
+example.com:53 {
+    forward . 10.0.0.2
+}
+`,
+			},
+			expectedError: false,
+		},
+		{
+			name:          "invalid coredns config (negative replicas)",
+			coreDNSConfig: kubeone.CoreDNSConfig{Replicas: -1},
+			expectedError: true,
+		},
+		{
+			name:          "invalid coredns config (invalid cpu request)",
+			coreDNSConfig: kubeone.CoreDNSConfig{CPURequest: "fast"},
+			expectedError: true,
+		},
+		{
+			name:          "invalid coredns config (invalid memory request)",
+			coreDNSConfig: kubeone.CoreDNSConfig{MemoryRequest: "70MB!"},
+			expectedError: true,
+		},
+		{
+			name:          "invalid coredns config (unclosed server block)",
+			coreDNSConfig: kubeone.CoreDNSConfig{Corefile: ".:53 {\n    errors\n"},
+			expectedError: true,
+		},
+		{
+			name:          "invalid coredns config (unexpected closing brace)",
+			coreDNSConfig: kubeone.CoreDNSConfig{Corefile: ".:53 {\n    errors\n}\n}\n"},
+			expectedError: true,
+		},
+		{
+			name:          "invalid coredns config (server block without address)",
+			coreDNSConfig: kubeone.CoreDNSConfig{Corefile: "{\n    errors\n}\n"},
+			expectedError: true,
+		},
+		{
+			name:          "invalid coredns config (directive outside of server block)",
+			coreDNSConfig: kubeone.CoreDNSConfig{Corefile: "errors\n"},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateCoreDNSConfig(tc.coreDNSConfig, nil)
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
+func TestValidateCSIConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		csiConfig     kubeone.CSIConfig
+		provider      kubeone.CloudProviderName
+		expectedError bool
+	}{
+		{
+			name: "valid csi config",
+			csiConfig: kubeone.CSIConfig{
+				Driver:  kubeone.CSIDriverAWSEBS,
+				Version: "v0.4.0",
+				StorageClasses: []kubeone.StorageClass{
+					{Name: "gp2", Default: true, Parameters: map[string]string{"type": "gp2"}},
+					{Name: "io1", ReclaimPolicy: "Retain", Parameters: map[string]string{"type": "io1"}},
+				},
+			},
+			provider:      kubeone.CloudProviderNameAWS,
+			expectedError: false,
+		},
+		{
+			name: "invalid csi config (driver not matching the provider)",
+			csiConfig: kubeone.CSIConfig{
+				Driver: kubeone.CSIDriverGCPPD,
+			},
+			provider:      kubeone.CloudProviderNameAWS,
+			expectedError: true,
+		},
+		{
+			name: "invalid csi config (unknown driver)",
+			csiConfig: kubeone.CSIConfig{
+				Driver: "azure-disk",
+			},
+			provider:      kubeone.CloudProviderNameAWS,
+			expectedError: true,
+		},
+		{
+			name: "invalid csi config (multiple default storage classes)",
+			csiConfig: kubeone.CSIConfig{
+				Driver: kubeone.CSIDriverGCPPD,
+				StorageClasses: []kubeone.StorageClass{
+					{Name: "standard", Default: true},
+					{Name: "ssd", Default: true},
+				},
+			},
+			provider:      kubeone.CloudProviderNameGCE,
+			expectedError: true,
+		},
+		{
+			name: "invalid csi config (invalid reclaim policy)",
+			csiConfig: kubeone.CSIConfig{
+				Driver: kubeone.CSIDriverGCPPD,
+				StorageClasses: []kubeone.StorageClass{
+					{Name: "standard", ReclaimPolicy: "Recycle"},
+				},
+			},
+			provider:      kubeone.CloudProviderNameGCE,
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateCSIConfig(&tc.csiConfig, tc.provider, nil)
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
+func TestValidateClusterAutoscalerConfig(t *testing.T) {
+	tests := []struct {
+		name                      string
+		autoscalerConfig          kubeone.ClusterAutoscalerConfig
+		machineControllerDeployed bool
+		expectedError             bool
+	}{
+		{
+			name: "valid cluster-autoscaler config",
+			autoscalerConfig: kubeone.ClusterAutoscalerConfig{
+				Enabled:        true,
+				Version:        "v1.16.0",
+				MinNodes:       1,
+				MaxNodes:       5,
+				ScaleDownDelay: metav1.Duration{Duration: 10 * time.Minute},
+			},
+			machineControllerDeployed: true,
+			expectedError:             false,
+		},
+		{
+			name: "valid cluster-autoscaler config (min equals max)",
+			autoscalerConfig: kubeone.ClusterAutoscalerConfig{
+				Enabled:  true,
+				MinNodes: 3,
+				MaxNodes: 3,
+			},
+			machineControllerDeployed: true,
+			expectedError:             false,
+		},
+		{
+			name: "invalid cluster-autoscaler config (machine-controller not deployed)",
+			autoscalerConfig: kubeone.ClusterAutoscalerConfig{
+				Enabled:  true,
+				MinNodes: 1,
+				MaxNodes: 5,
+			},
+			machineControllerDeployed: false,
+			expectedError:             true,
+		},
+		{
+			name: "invalid cluster-autoscaler config (invalid version)",
+			autoscalerConfig: kubeone.ClusterAutoscalerConfig{
+				Enabled:  true,
+				Version:  "latest",
+				MinNodes: 1,
+				MaxNodes: 5,
+			},
+			machineControllerDeployed: true,
+			expectedError:             true,
+		},
+		{
+			name: "invalid cluster-autoscaler config (zero minNodes)",
+			autoscalerConfig: kubeone.ClusterAutoscalerConfig{
+				Enabled:  true,
+				MinNodes: 0,
+				MaxNodes: 5,
+			},
+			machineControllerDeployed: true,
+			expectedError:             true,
+		},
+		{
+			name: "invalid cluster-autoscaler config (maxNodes lower than minNodes)",
+			autoscalerConfig: kubeone.ClusterAutoscalerConfig{
+				Enabled:  true,
+				MinNodes: 5,
+				MaxNodes: 2,
+			},
+			machineControllerDeployed: true,
+			expectedError:             true,
+		},
+		{
+			name: "invalid cluster-autoscaler config (negative scaleDownDelay)",
+			autoscalerConfig: kubeone.ClusterAutoscalerConfig{
+				Enabled:        true,
+				MinNodes:       1,
+				MaxNodes:       5,
+				ScaleDownDelay: metav1.Duration{Duration: -time.Minute},
+			},
+			machineControllerDeployed: true,
+			expectedError:             true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateClusterAutoscalerConfig(&tc.autoscalerConfig, tc.machineControllerDeployed, nil)
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
+func TestValidateVeleroConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		veleroConfig  kubeone.VeleroConfig
+		expectedError bool
+	}{
+		{
+			name: "valid velero config (s3)",
+			veleroConfig: kubeone.VeleroConfig{
+				Enabled:     true,
+				Version:     "v1.2.0",
+				BackendType: kubeone.VeleroBackendS3,
+				BucketName:  "backups",
+				Region:      "eu-west-3",
+			},
+			expectedError: false,
+		},
+		{
+			name: "valid velero config (gcs)",
+			veleroConfig: kubeone.VeleroConfig{
+				Enabled:     true,
+				BackendType: kubeone.VeleroBackendGCS,
+				BucketName:  "backups",
+			},
+			expectedError: false,
+		},
+		{
+			name: "invalid velero config (s3 without region)",
+			veleroConfig: kubeone.VeleroConfig{
+				Enabled:     true,
+				BackendType: kubeone.VeleroBackendS3,
+				BucketName:  "backups",
+			},
+			expectedError: true,
+		},
+		{
+			name: "invalid velero config (unknown backend)",
+			veleroConfig: kubeone.VeleroConfig{
+				Enabled:     true,
+				BackendType: "minio",
+				BucketName:  "backups",
+			},
+			expectedError: true,
+		},
+		{
+			name: "invalid velero config (no bucket)",
+			veleroConfig: kubeone.VeleroConfig{
+				Enabled:     true,
+				BackendType: kubeone.VeleroBackendAzure,
+			},
+			expectedError: true,
+		},
+		{
+			name: "invalid velero config (invalid version)",
+			veleroConfig: kubeone.VeleroConfig{
+				Enabled:     true,
+				Version:     "latest",
+				BackendType: kubeone.VeleroBackendGCS,
+				BucketName:  "backups",
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateVeleroConfig(&tc.veleroConfig, nil)
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
+func TestValidateNodeLocalDNSConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		localAddress  string
+		expectedError bool
+	}{
+		{
+			name:          "valid link-local IPv4 address",
+			localAddress:  "169.254.20.10",
+			expectedError: false,
+		},
+		{
+			name:          "valid link-local IPv6 address",
+			localAddress:  "fe80::a",
+			expectedError: false,
+		},
+		{
+			name:          "invalid address (not link-local)",
+			localAddress:  "10.96.0.10",
+			expectedError: true,
+		},
+		{
+			name:          "invalid address (not an IP)",
+			localAddress:  "localhost",
+			expectedError: true,
+		},
+		{
+			name:          "invalid address (empty)",
+			localAddress:  "",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &kubeone.NodeLocalDNSConfig{Enabled: true, LocalAddress: tc.localAddress}
+			errs := ValidateNodeLocalDNSConfig(cfg, nil)
+			if (len(errs) == 0) == tc.expectedError {
+				t.Errorf("test case failed: expected %v, but got %v", tc.expectedError, (len(errs) != 0))
+			}
+		})
+	}
+}
+
+func TestValidateFeatures(t *testing.T) {
+	tests := []struct {
+		name          string
+		features      kubeone.Features
+		expectedError bool
+	}{
+		{
+			name: "valid features config (psp and auditing enabled)",
+			features: kubeone.Features{
+				PodSecurityPolicy: &kubeone.PodSecurityPolicy{
+					Enable: true,
+				},
+				DynamicAuditLog: &kubeone.DynamicAuditLog{
+					Enable: true,
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "valid features config (metrics server disabled)",
+			features: kubeone.Features{
+				MetricsServer: &kubeone.MetricsServer{
+					Enable: false,
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name:          "valid features config (no feature configured)",
+			features:      kubeone.Features{},
+			expectedError: false,
+		},
+		{
+			name: "valid features config (oidc enabled)",
+			features: kubeone.Features{
+				OpenIDConnect: &kubeone.OpenIDConnect{
+					Enable: true,
+					Config: kubeone.OpenIDConnectConfig{
+						IssuerURL:     "test.cluster.local",
+						ClientID:      "123",
+						RequiredClaim: "test",
+					},
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "invalid features config (invalid oidc config)",
+			features: kubeone.Features{
+				OpenIDConnect: &kubeone.OpenIDConnect{
+					Enable: true,
+					Config: kubeone.OpenIDConnectConfig{},
+				},
 			},
 			expectedError: true,
 		},
@@ -562,3 +1563,7 @@ func TestValidateOIDCConfig(t *testing.T) {
 func intPtr(i int) *int {
 	return &i
 }
+
+func intOrStrPtr(v intstr.IntOrString) *intstr.IntOrString {
+	return &v
+}
@@ -0,0 +1,238 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
+	"github.com/kubermatic/kubeone/pkg/templates/canal"
+	"github.com/kubermatic/kubeone/pkg/templates/clusterautoscaler"
+	"github.com/kubermatic/kubeone/pkg/templates/csi"
+	"github.com/kubermatic/kubeone/pkg/templates/externalccm"
+	"github.com/kubermatic/kubeone/pkg/templates/machinecontroller"
+	"github.com/kubermatic/kubeone/pkg/templates/metricsserver"
+	"github.com/kubermatic/kubeone/pkg/templates/nodelocaldns"
+	"github.com/kubermatic/kubeone/pkg/templates/velero"
+	"github.com/kubermatic/kubeone/pkg/templates/weave"
+)
+
+type addonListOptions struct {
+	globalOptions
+	Manifest string
+	Output   string
+}
+
+// addonInfo describes a built-in addon
+type addonInfo struct {
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	Enabled     bool   `json:"enabled"`
+	Description string `json:"description"`
+}
+
+// addonCmd setups the addon command
+func addonCmd(rootFlags *pflag.FlagSet) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "addon",
+		Short: "Commands for inspecting the built-in addons",
+	}
+
+	cmd.AddCommand(
+		addonListCmd(rootFlags),
+	)
+
+	return cmd
+}
+
+// addonListCmd setups the addon list command
+func addonListCmd(rootFlags *pflag.FlagSet) *cobra.Command {
+	lopts := &addonListOptions{}
+	cmd := &cobra.Command{
+		Use:   "list <manifest>",
+		Short: "List the built-in addons",
+		Long: `List all addons KubeOne is able to deploy, along with the version that
+would be deployed and whether the addon is enabled in the given manifest.`,
+		Args:    cobra.ExactArgs(1),
+		Example: `kubeone addon list mycluster.yaml --output json`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			gopts, err := persistentGlobalOptions(rootFlags)
+			if err != nil {
+				return errors.Wrap(err, "unable to get global flags")
+			}
+
+			lopts.TerraformState = gopts.TerraformState
+			lopts.Verbose = gopts.Verbose
+			lopts.Debug = gopts.Debug
+
+			lopts.Manifest = args[0]
+			if lopts.Manifest == "" {
+				return errors.New("no cluster config file given")
+			}
+
+			return runAddonList(lopts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&lopts.Output, "output", "o", "table", "output format, one of: table, json")
+
+	return cmd
+}
+
+// runAddonList prints the built-in addons of the given cluster
+func runAddonList(opts *addonListOptions) error {
+	if opts.Output != "table" && opts.Output != "json" {
+		return errors.Errorf("unsupported output format %q", opts.Output)
+	}
+
+	cluster, err := loadClusterConfig(opts.Manifest, opts.TerraformState)
+	if err != nil {
+		return errors.Wrap(err, "failed to load cluster")
+	}
+
+	addons := listAddons(cluster)
+
+	if opts.Output == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(addons)
+	}
+
+	return printAddons(os.Stdout, addons)
+}
+
+// listAddons returns all the built-in addons with their state in the given cluster
+func listAddons(cluster *kubeoneapi.KubeOneCluster) []addonInfo {
+	return []addonInfo{
+		{
+			Name:        "canal",
+			Version:     imageTag(canal.Images()[1]),
+			Enabled:     cluster.ClusterNetwork.CNI.Provider == kubeoneapi.CNIProviderCanal,
+			Description: "CNI plugin combining Calico network policies and Flannel networking",
+		},
+		{
+			Name:        "weave-net",
+			Version:     imageTag(weave.Images()[0]),
+			Enabled:     cluster.ClusterNetwork.CNI.Provider == kubeoneapi.CNIProviderWeaveNet,
+			Description: "CNI plugin with optional encryption of the pod traffic",
+		},
+		{
+			Name:        "machine-controller",
+			Version:     machinecontroller.MachineControllerTag,
+			Enabled:     cluster.MachineController.Deploy,
+			Description: "Manages worker nodes using the Cluster API MachineDeployments",
+		},
+		{
+			Name:        "external-ccm",
+			Version:     firstImageTag(externalccm.Images(cluster)),
+			Enabled:     cluster.CloudProvider.External,
+			Description: "External cloud controller manager of the cloud provider",
+		},
+		{
+			Name:        "metrics-server",
+			Version:     imageTag(metricsserver.Images()[0]),
+			Enabled:     cluster.Features.MetricsServer != nil && cluster.Features.MetricsServer.Enable,
+			Description: "Resource metrics API used by kubectl top and autoscalers",
+		},
+		{
+			Name:        "csi-aws-ebs",
+			Version:     csiDriverVersion(cluster, kubeoneapi.CSIDriverAWSEBS),
+			Enabled:     cluster.CSI != nil && cluster.CSI.Driver == kubeoneapi.CSIDriverAWSEBS,
+			Description: "CSI driver provisioning Amazon EBS volumes",
+		},
+		{
+			Name:        "csi-gcp-pd",
+			Version:     csiDriverVersion(cluster, kubeoneapi.CSIDriverGCPPD),
+			Enabled:     cluster.CSI != nil && cluster.CSI.Driver == kubeoneapi.CSIDriverGCPPD,
+			Description: "CSI driver provisioning Google Compute Engine persistent disks",
+		},
+		{
+			Name:        "cluster-autoscaler",
+			Version:     firstImageTag(clusterautoscaler.Images(cluster)),
+			Enabled:     cluster.ClusterAutoscaler != nil && cluster.ClusterAutoscaler.Enabled,
+			Description: "Scales the MachineDeployments according to the pending pods",
+		},
+		{
+			Name:        "nodelocaldns",
+			Version:     imageTag(nodelocaldns.Images()[0]),
+			Enabled:     cluster.NodeLocalDNS != nil && cluster.NodeLocalDNS.Enabled,
+			Description: "DNS cache running on every node",
+		},
+		{
+			Name:        "velero",
+			Version:     firstImageTag(velero.Images(cluster)),
+			Enabled:     cluster.Velero != nil && cluster.Velero.Enabled,
+			Description: "Scheduled backups of the cluster resources and volumes",
+		},
+	}
+}
+
+// csiDriverVersion returns the version of the given CSI driver, taking the
+// version from the manifest into account if the driver is the configured one
+func csiDriverVersion(cluster *kubeoneapi.KubeOneCluster, driver kubeoneapi.CSIDriver) string {
+	cfg := &kubeoneapi.CSIConfig{Driver: driver}
+	if cluster.CSI != nil && cluster.CSI.Driver == driver {
+		cfg.Version = cluster.CSI.Version
+	}
+
+	// the driver image comes after the sidecars
+	images := csi.Images(&kubeoneapi.KubeOneCluster{CSI: cfg})
+	if len(images) == 0 {
+		return ""
+	}
+	return imageTag(images[len(images)-1])
+}
+
+func firstImageTag(images []string) string {
+	if len(images) == 0 {
+		return ""
+	}
+	return imageTag(images[0])
+}
+
+// imageTag returns the tag of the given image reference
+func imageTag(image string) string {
+	i := strings.LastIndex(image, ":")
+	if i < 0 || strings.Contains(image[i:], "/") {
+		return "latest"
+	}
+	return image[i+1:]
+}
+
+func printAddons(out io.Writer, addons []addonInfo) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "NAME\tVERSION\tENABLED\tDESCRIPTION")
+	for _, a := range addons {
+		ver := a.Version
+		if ver == "" {
+			ver = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%t\t%s\n", a.Name, ver, a.Enabled, a.Description)
+	}
+
+	return w.Flush()
+}
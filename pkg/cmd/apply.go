@@ -0,0 +1,185 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/kubermatic/kubeone/pkg/installer"
+	"github.com/kubermatic/kubeone/pkg/util"
+)
+
+type applyOptions struct {
+	globalOptions
+	Metrics     *util.Metrics
+	Manifests   []string
+	BackupFile  string
+	SkipWorkers bool
+	Parallelism int
+	// StrictVersion fails instead of warning about unsupported Kubernetes versions
+	StrictVersion bool
+	// OutputPlan is where the JSON plan is written before changing the cluster
+	OutputPlan string
+	// Approve shows the plan and asks for a confirmation before changing the cluster
+	Approve bool
+	// TargetHost limits apply to the host with the given address
+	TargetHost string
+	// Prune deletes the MachineDeployments created by KubeOne which are no
+	// longer in the manifest
+	Prune bool
+}
+
+// applyCmd setups apply command
+func applyCmd(rootFlags *pflag.FlagSet, metrics *util.Metrics) *cobra.Command {
+	aopts := &applyOptions{Metrics: metrics}
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Reconcile the cluster with the manifest",
+		Long: `
+Reconcile the cluster with the manifest, changing only what differs from it.
+
+The control plane, machine-controller and the worker MachineDeployments are compared with the manifest
+one by one, and only the differing ones are reconciled, e.g. changing only the workers doesn't touch
+the control plane. Missing control plane nodes are joined, while changes such as the Kubernetes version
+must be done using the upgrade command. A cluster which can't be accessed is installed from scratch.
+
+MachineDeployments created by KubeOne which are removed from the manifest are only reported, unless the
+--prune flag is given, in which case they are deleted. MachineDeployments created by other means, i.e.
+without the "app.kubernetes.io/managed-by: kubeone" label, are never deleted.
+
+The manifest flag can be repeated to apply overlays, e.g. environment-specific settings, on top of a base
+manifest. The later manifests override the earlier ones: objects are merged, lists of named items such as
+the workers are merged by name, other lists are replaced and null values remove the setting.
+The PKI backup is placed next to the first manifest by default.
+
+A warning is printed if the Kubernetes version isn't supported by this KubeOne release, unless the
+--strict-version flag is given, in which case the command fails.
+
+The --output-plan flag writes the phases about to run as JSON before changing the cluster, including the
+affected hosts, the changes and a rough duration estimate, e.g. for CI systems to require an approval.
+The "version" field of the plan is increased on incompatible changes of the format.
+
+The --approve flag shows the plan and asks for a confirmation before changing the cluster, to prevent
+accidental changes when running apply manually. Only "yes" is accepted. Without it the changes are applied
+right away.
+
+The --target-host flag runs apply only against the control plane host with the given public or private
+address, e.g. to debug its provisioning. The host is provisioned even if the cluster matches the manifest,
+while machine-controller and the workers aren't reconciled. The cluster state may be inconsistent until
+apply is run against all hosts.
+`,
+		Args: cobra.ExactArgs(0),
+		Example: `kubeone apply --manifest mycluster.yaml -t terraformoutput.json
+kubeone apply --manifest base.yaml --manifest production.yaml
+kubeone apply --manifest mycluster.yaml --approve
+kubeone apply --manifest mycluster.yaml --prune
+kubeone apply --manifest mycluster.yaml --target-host 192.0.2.3`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			gopts, err := persistentGlobalOptions(rootFlags)
+			if err != nil {
+				return errors.Wrap(err, "unable to get global flags")
+			}
+
+			logger := initLogger(gopts.Verbose, gopts.NoColor)
+			aopts.TerraformState = gopts.TerraformState
+			aopts.Verbose = gopts.Verbose
+			aopts.Debug = gopts.Debug
+			aopts.InCluster = gopts.InCluster
+
+			if len(aopts.Manifests) == 0 {
+				return errors.New("no cluster config file given")
+			}
+
+			return runApply(logger, aopts)
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&aopts.Manifests, "manifest", "m", nil, "path to the KubeOne manifest, repeat to apply overlays")
+	cmd.Flags().StringVarP(&aopts.BackupFile, "backup", "b", "", "path to where the PKI backup .tar.gz file should be placed (default: location of cluster config file)")
+	cmd.Flags().BoolVar(&aopts.SkipWorkers, "skip-workers", false, "don't reconcile the worker MachineDeployments")
+	cmd.Flags().IntVar(&aopts.Parallelism, "parallelism", 1, "number of nodes to run SSH sessions against concurrently during multi-node phases")
+	cmd.Flags().BoolVar(&aopts.StrictVersion, "strict-version", false, "fail if the Kubernetes version isn't supported by this KubeOne release")
+	cmd.Flags().StringVar(&aopts.OutputPlan, "output-plan", "", "path to write the JSON plan of the phases to run to, before changing the cluster")
+	cmd.Flags().BoolVar(&aopts.Approve, "approve", false, "show the plan and ask for a confirmation before changing the cluster")
+	cmd.Flags().StringVar(&aopts.TargetHost, "target-host", "", "public or private address of the only host to run apply against")
+	cmd.Flags().BoolVar(&aopts.Prune, "prune", false, "delete the MachineDeployments created by KubeOne which are no longer in the manifest")
+
+	return cmd
+}
+
+// runApply reconciles the cluster with the manifest
+func runApply(logger *logrus.Logger, applyOptions *applyOptions) error {
+	cluster, err := loadClusterConfigOverlays(applyOptions.Manifests, applyOptions.TerraformState)
+	if err != nil {
+		return errors.Wrap(err, "failed to load cluster")
+	}
+
+	if err := checkKubernetesVersion(logger, cluster.Versions.Kubernetes, applyOptions.StrictVersion); err != nil {
+		return err
+	}
+
+	options, err := createInstallerOptions(applyOptions.Manifests[0], cluster, &installOptions{
+		globalOptions: applyOptions.globalOptions,
+		Metrics:       applyOptions.Metrics,
+		BackupFile:    applyOptions.BackupFile,
+		SkipWorkers:   applyOptions.SkipWorkers,
+		Parallelism:   applyOptions.Parallelism,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to create installer options")
+	}
+	options.PlanFile = applyOptions.OutputPlan
+	options.Prune = applyOptions.Prune
+	if applyOptions.Approve {
+		options.Approve = confirmApply(os.Stdin, os.Stdout)
+	}
+	if applyOptions.TargetHost != "" {
+		host, err := findHost(cluster.Hosts, applyOptions.TargetHost)
+		if err != nil {
+			return err
+		}
+		logger.Warnf("Running apply only against %s, the cluster state may be inconsistent until apply is run against all hosts", host.PublicAddress)
+		options.TargetHost = host.PublicAddress
+	}
+
+	return installer.NewInstaller(cluster, logger).Apply(options)
+}
+
+// confirmApply returns a function showing the plan of apply and asking
+// whether to proceed, only "yes" approves the changes
+func confirmApply(in io.Reader, out io.Writer) func(string) (bool, error) {
+	return func(plan string) (bool, error) {
+		fmt.Fprintf(out, "The following changes will be applied:\n\n%s\n", plan)
+		fmt.Fprint(out, "Do you want to apply these changes? (yes/no) ")
+
+		line, err := bufio.NewReader(in).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return false, errors.Wrap(err, "failed to read the answer")
+		}
+
+		return strings.TrimSpace(line) == "yes", nil
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const completionLong = `
+Generate the shell completion script for KubeOne.
+
+To load completions in the current bash session, run:
+  source <(kubeone completion bash)
+To load completions for every bash session, run:
+  kubeone completion bash > /etc/bash_completion.d/kubeone
+
+To load completions for every zsh session, run:
+  kubeone completion zsh > "${fpath[1]}/_kubeone"
+
+To load completions for every fish session, run:
+  kubeone completion fish > ~/.config/fish/completions/kubeone.fish
+`
+
+// completionCmd setups the completion command
+func completionCmd(_ *pflag.FlagSet) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:       "completion <bash|zsh|fish>",
+		Short:     "Generate the shell completion script",
+		Long:      completionLong,
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: []string{"bash", "zsh", "fish"},
+		Example:   `kubeone completion bash`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCompletion(os.Stdout, cmd.Root(), args[0])
+		},
+	}
+
+	return cmd
+}
+
+// runCompletion writes the completion script for the given shell
+func runCompletion(out io.Writer, root *cobra.Command, shell string) error {
+	switch shell {
+	case "bash":
+		fmt.Fprintln(out, "# To load completions, run: source <(kubeone completion bash)")
+		return root.GenBashCompletion(out)
+	case "zsh":
+		// the #compdef line must come first, so no instructions are written
+		return root.GenZshCompletion(out)
+	case "fish":
+		fmt.Fprintln(out, "# To load completions, run: kubeone completion fish | source")
+		genFishCompletion(out, root)
+		return nil
+	default:
+		return errors.Errorf("unsupported shell %q, supported shells are bash, zsh and fish", shell)
+	}
+}
+
+// genFishCompletion writes fish completions for all commands and their flags,
+// as cobra doesn't support generating fish completions
+func genFishCompletion(out io.Writer, cmd *cobra.Command) {
+	name := cmd.Root().Name()
+
+	// condition matching the command path, e.g. "config print"
+	path := strings.Fields(cmd.CommandPath())[1:]
+	condition := "__fish_use_subcommand"
+	if len(path) > 0 {
+		condition = fmt.Sprintf("__fish_seen_subcommand_from %s", path[len(path)-1])
+	}
+
+	for _, sub := range cmd.Commands() {
+		if !sub.IsAvailableCommand() {
+			continue
+		}
+		fmt.Fprintf(out, "complete -c %s -f -n '%s' -a %s -d %s\n", name, condition, sub.Name(), fishQuote(sub.Short))
+	}
+
+	cmd.NonInheritedFlags().VisitAll(func(f *pflag.Flag) {
+		if f.Hidden {
+			return
+		}
+		line := fmt.Sprintf("complete -c %s -n '%s' -l %s", name, condition, f.Name)
+		if f.Shorthand != "" {
+			line += " -s " + f.Shorthand
+		}
+		fmt.Fprintf(out, "%s -d %s\n", line, fishQuote(f.Usage))
+	})
+
+	for _, sub := range cmd.Commands() {
+		if sub.IsAvailableCommand() {
+			genFishCompletion(out, sub)
+		}
+	}
+}
+
+func fishQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `\'`, -1) + "'"
+}
@@ -19,12 +19,14 @@ package cmd
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
 	"text/template"
 
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	yaml "gopkg.in/yaml.v2"
@@ -77,9 +79,16 @@ type printOptions struct {
 	DeployMachineController bool
 }
 
+type validateOptions struct {
+	globalOptions
+	Manifest      string
+	StrictVersion bool
+}
+
 type migrateOptions struct {
 	globalOptions
 	Manifest string
+	Output   string
 }
 
 // configCmd setups the config command
@@ -91,6 +100,7 @@ func configCmd(rootFlags *pflag.FlagSet) *cobra.Command {
 
 	cmd.AddCommand(printCmd(rootFlags))
 	cmd.AddCommand(migrateCmd(rootFlags))
+	cmd.AddCommand(validateCmd(rootFlags))
 
 	return cmd
 }
@@ -153,16 +163,22 @@ For the full reference of the configuration manifest, run the print command with
 func migrateCmd(_ *pflag.FlagSet) *cobra.Command {
 	mOpts := &migrateOptions{}
 	cmd := &cobra.Command{
-		Use:   "migrate <cluster-manifest>",
-		Short: "Migrate the pre-v0.6.0 configuration manifest to the KubeOneCluster manifest",
+		Use:   "migrate [<cluster-manifest>]",
+		Short: "Migrate the configuration manifest to the current KubeOneCluster manifest",
 		Long: `
-Migrate the pre-v0.6.0 KubeOne configuration manifest to the KubeOneCluster manifest used as of v0.6.0.
-The new manifest is printed on the standard output.
+Migrate the KubeOne configuration manifest to the current KubeOneCluster manifest.
+The transformations required by the manifest version are applied one after another.
+Both the pre-v0.6.0 manifests (including the v0.5.0 features API) and the KubeOneCluster
+manifests are supported, the latter ones being left unchanged.
+
+The new manifest is printed on the standard output, unless the --output flag is given.
 `,
-		Args:    cobra.ExactArgs(1),
-		Example: `kubeone config migrate mycluster.yaml`,
+		Args:    cobra.MaximumNArgs(1),
+		Example: `kubeone config migrate --manifest mycluster.yaml --output mycluster-migrated.yaml`,
 		RunE: func(_ *cobra.Command, args []string) error {
-			mOpts.Manifest = args[0]
+			if len(args) == 1 {
+				mOpts.Manifest = args[0]
+			}
 			if mOpts.Manifest == "" {
 				return errors.New("no cluster config file given")
 			}
@@ -171,6 +187,45 @@ The new manifest is printed on the standard output.
 		},
 	}
 
+	cmd.Flags().StringVarP(&mOpts.Manifest, "manifest", "m", "", "path to the manifest to migrate")
+	cmd.Flags().StringVarP(&mOpts.Output, "output", "o", "", "path to write the migrated manifest to, instead of the standard output")
+
+	return cmd
+}
+
+// validateCmd setups the validate command
+func validateCmd(rootFlags *pflag.FlagSet) *cobra.Command {
+	vOpts := &validateOptions{}
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate the KubeOneCluster manifest",
+		Long: `
+Validate the KubeOneCluster manifest, optionally along with the terraform output, without accessing the cluster.
+A warning is printed if the Kubernetes version isn't supported by this KubeOne release, unless the
+--strict-version flag is given, in which case validation fails.
+`,
+		Args:    cobra.ExactArgs(0),
+		Example: `kubeone config validate --manifest mycluster.yaml -t terraformoutput.json`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			gopts, err := persistentGlobalOptions(rootFlags)
+			if err != nil {
+				return errors.Wrap(err, "unable to get global flags")
+			}
+
+			logger := initLogger(gopts.Verbose, gopts.NoColor)
+			vOpts.TerraformState = gopts.TerraformState
+
+			if vOpts.Manifest == "" {
+				return errors.New("no cluster config file given")
+			}
+
+			return runValidate(logger, vOpts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&vOpts.Manifest, "manifest", "m", "", "path to the KubeOne manifest")
+	cmd.Flags().BoolVar(&vOpts.StrictVersion, "strict-version", false, "fail if the Kubernetes version isn't supported by this KubeOne release")
+
 	return cmd
 }
 
@@ -316,7 +371,7 @@ func createAndPrintManifest(printOptions *printOptions) error {
 	}
 
 	// Print the manifest
-	err := validateAndPrintConfig(cfg)
+	err := validateAndPrintConfig(cfg, os.Stdout)
 	if err != nil {
 		return errors.Wrap(err, "unable to validate and print config")
 	}
@@ -353,15 +408,39 @@ func parseHosts(cfg *yamled.Document, hostList string) error {
 	return nil
 }
 
-// runMigrate migrates the pre-v0.6.0 KubeOne API manifest to the KubeOneCluster manifest used as of v0.6.0
+// runValidate validates the manifest and the Kubernetes version
+func runValidate(logger *logrus.Logger, validateOptions *validateOptions) error {
+	cluster, err := loadClusterConfig(validateOptions.Manifest, validateOptions.TerraformState)
+	if err != nil {
+		return err
+	}
+
+	if err := checkKubernetesVersion(logger, cluster.Versions.Kubernetes, validateOptions.StrictVersion); err != nil {
+		return err
+	}
+
+	fmt.Println("The manifest is valid.")
+	return nil
+}
+
+// runMigrate migrates the KubeOne API manifest to the current KubeOneCluster manifest
 func runMigrate(migrateOptions *migrateOptions) error {
 	// Convert old config yaml to new config yaml
-	newConfigYAML, err := config.MigrateToKubeOneClusterAPI(migrateOptions.Manifest)
+	newConfigYAML, err := config.MigrateManifest(migrateOptions.Manifest)
 	if err != nil {
 		return errors.Wrap(err, "unable to migrate the provided configuration")
 	}
 
-	err = validateAndPrintConfig(newConfigYAML)
+	out := os.Stdout
+	if migrateOptions.Output != "" {
+		out, err = os.Create(migrateOptions.Output)
+		if err != nil {
+			return errors.Wrap(err, "unable to create the output file")
+		}
+		defer out.Close()
+	}
+
+	err = validateAndPrintConfig(newConfigYAML, out)
 	if err != nil {
 		return errors.Wrap(err, "unable to validate and print config")
 	}
@@ -369,7 +448,7 @@ func runMigrate(migrateOptions *migrateOptions) error {
 	return nil
 }
 
-func validateAndPrintConfig(cfgYaml interface{}) error {
+func validateAndPrintConfig(cfgYaml interface{}, out io.Writer) error {
 	// Validate new config by unmarshaling
 	var buffer bytes.Buffer
 	err := yaml.NewEncoder(&buffer).Encode(cfgYaml)
@@ -390,7 +469,7 @@ func validateAndPrintConfig(cfgYaml interface{}) error {
 	}
 
 	// Print new config yaml
-	err = yaml.NewEncoder(os.Stdout).Encode(cfgYaml)
+	err = yaml.NewEncoder(out).Encode(cfgYaml)
 	if err != nil {
 		return errors.Wrap(err, "failed to encode new config as YAML")
 	}
@@ -437,7 +516,11 @@ cloudProvider:
   # * vsphere
   name: "{{ .CloudProviderName }}"
   # Set the kubelet flag '--cloud-provider=external' and deploy the external CCM for supported providers
+  # (aws, digitalocean, gce, hetzner and packet)
   external: {{ .CloudProviderExternal }}
+  # Version of the external CCM image, defaults to the version tested with KubeOne
+  # or, for aws and gce, to the Kubernetes version
+  # ccmVersion: ""
   # Path to file that will be uploaded and used as custom '--cloud-config' file.
   cloudConfig: "{{ .CloudProviderCloudCfg }}"
 
@@ -511,8 +594,18 @@ features:
 #   # You usually want to configure either a private key OR an
 #   # agent socket, but never both. The socket value can be
 #   # prefixed with "env:" to refer to an environment variable.
+#   # RSA, ECDSA and Ed25519 keys are supported, in the PEM, PKCS #8
+#   # or OpenSSH formats. Password-protected keys are not supported.
 #   sshPrivateKeyFile: '/home/me/.ssh/id_rsa'
+#   # Additional keys tried in order until one authenticates,
+#   # e.g. while the keys are being rotated.
+#   sshPrivateKeyFiles:
+#   - '/home/me/.ssh/id_rsa_old'
 #   sshAgentSocket: 'env:SSH_AUTH_SOCK'
+#   # Verify the host key against a known_hosts file instead of
+#   # accepting any host key. The file defaults to ~/.ssh/known_hosts.
+#   sshStrictHostKeyChecking: false
+#   sshKnownHostsFile: '/home/me/.ssh/known_hosts'
 
 # The API server can also be overwritten by Terraform. Provide the
 # external address of your load balancer or the public addresses of
@@ -528,15 +621,178 @@ machineController:
   deploy: {{ .DeployMachineController }}
   # Defines for what provider the machine-controller will be configured (defaults to cloudProvider.Name)
   # provider: ""
+  # Leader election settings for machine-controller (defaults to machine-controller defaults)
+  # leaderElectionLeaseDuration: 15s
+  # leaderElectionRenewDeadline: 10s
+  # leaderElectionRetryPeriod: 2s
+  # Compute resources for the machine-controller container
+  # resources:
+  #   requests:
+  #     cpu: 50m
+  #     memory: 64Mi
+  #   limits:
+  #     cpu: 500m
+  #     memory: 512Mi
+  # Spread machine-controller pods across zones, rendered as preferred pod
+  # anti-affinity
+  # topologySpreadConstraints:
+  # - maxSkew: 1
+  #   topologyKey: failure-domain.beta.kubernetes.io/zone
+  #   whenUnsatisfiable: ScheduleAnyway
+  # Load the cloud provider credentials from an existing secret in kube-system
+  # instead of the local environment. The secret keys are the environment
+  # variables machine-controller expects, e.g. AWS_ACCESS_KEY_ID.
+  # credentialsSecretRef:
+  #   name: machine-controller-credentials
+  # How long the API server waits for the machine-controller webhook, between
+  # 1 and 30 seconds (default: 30). Requires Kubernetes 1.14 or newer.
+  # webhookTimeoutSeconds: 30
+  # Number of worker threads processing machines, between 1 and 50 (default: 5).
+  # Raise it for clusters with many machines.
+  # workerCount: 5
+  # Port of the machine-controller Service exposing the Prometheus metrics
+  # (default: 8080).
+  # metricsPort: 8080
+  # Strategy used to replace the machine-controller pods. By default a new pod
+  # is started and ready before the old one is removed.
+  # updateStrategy:
+  #   type: RollingUpdate
+  #   rollingUpdate:
+  #     maxUnavailable: 0
+  #     maxSurge: 1
+
+# NodeLocalDNS deploys the NodeLocal DNSCache, a DNS cache running on
+# every node. Pods keep using the kube-dns service IP, which the cache
+# also listens on. The local address must be a link-local IP.
+# nodeLocalDNS:
+#   enabled: false
+#   localAddress: '169.254.20.10'
+
+# Monitoring creates ServiceMonitors for the components deployed by KubeOne,
+# e.g. machine-controller. It requires the Prometheus Operator CRDs.
+# monitoring:
+#   enabled: false
+
+# ClusterAutoscaler deploys cluster-autoscaler, which scales each worker
+# MachineDeployment between minNodes and maxNodes. It requires
+# machine-controller to be deployed.
+# clusterAutoscaler:
+#   enabled: false
+#   # Version of cluster-autoscaler (defaults to the version tested with KubeOne)
+#   version: ""
+#   minNodes: 1
+#   maxNodes: 5
+#   # How long after scale up that scale down evaluation resumes
+#   scaleDownDelay: 10m
+
+# Velero backs up the cluster to object storage every night. Supported
+# backends are "s3", "gcs" and "azure". The backend credentials are read
+# from the environment: AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY for s3,
+# GOOGLE_CREDENTIALS for gcs, and AZURE_SUBSCRIPTION_ID, AZURE_TENANT_ID,
+# AZURE_CLIENT_ID, AZURE_CLIENT_SECRET, AZURE_RESOURCE_GROUP and
+# AZURE_STORAGE_ACCOUNT_ID for azure.
+# velero:
+#   enabled: false
+#   # Version of Velero (defaults to the version tested with KubeOne)
+#   version: ""
+#   backendType: 's3'
+#   bucketName: 'my-cluster-backups'
+#   # Region of the bucket, required for s3
+#   region: 'eu-west-3'
+
+# CSI deploys a CSI driver and StorageClasses provisioning volumes with it.
+# Supported drivers are "aws-ebs" on AWS and "gcp-pd" on GCE.
+# csi:
+#   driver: 'aws-ebs'
+#   # Version of the driver image (defaults to the version tested with KubeOne)
+#   version: ""
+#   storageClasses:
+#   - name: 'ebs-gp2'
+#     default: true
+#     # Delete (default) or Retain
+#     reclaimPolicy: 'Delete'
+#     parameters:
+#       type: 'gp2'
+
+# KubeProxy selects the mode kube-proxy implements services with, either
+# "iptables" or "ipvs". The ipvs mode requires ipvsadm to be installed on
+# the control plane nodes.
+# kubeProxy:
+#   mode: 'ipvs'
+#   # IPVS scheduling algorithm (defaults to rr)
+#   ipvsScheduler: 'rr'
+
+# Kubelet configures the eviction thresholds of the kubelet. Pods are
+# evicted once the free disk space or inodes drop below the threshold,
+# given either as a percentage or as a quantity.
+# kubelet:
+#   # applies to both the node and the image filesystem (defaults to 10%
+#   # and 15%)
+#   diskPressureThreshold: '5Gi'
+#   # free inodes of the node filesystem (defaults to 5%)
+#   inodePressureThreshold: '5%'
+
+# CoreDNS customizes the CoreDNS deployed by kubeadm. The Corefile, if
+# set, replaces the configuration generated by kubeadm.
+# coreDNS:
+#   replicas: 2
+#   cpuRequest: '100m'
+#   memoryRequest: '70Mi'
+#   corefile: |
+#     .:53 {
+#         errors
+#         health
+#         kubernetes cluster.local in-addr.arpa ip6.arpa {
+#            pods insecure
+#            fallthrough in-addr.arpa ip6.arpa
+#         }
+#         prometheus :9153
+#         forward . /etc/resolv.conf
+#         cache 30
+#         loop
+#         reload
+#         loadbalance
+#     }
+
+# ContainerRuntime selects the container runtime installed on the control
+# plane nodes. Supported runtimes are "docker" (default) and "crio".
+# CRI-O is supported on Ubuntu and CentOS.
+# RegistryMirrors are keyed by the registry they mirror, Docker only
+# supports mirrors of docker.io.
+# containerRuntime:
+#   runtime: 'docker'
+#   registryMirrors:
+#     docker.io:
+#     - 'https://mirror.local:5000'
 
 # Proxy is used to configure HTTP_PROXY, HTTPS_PROXY and NO_PROXY
-# for Docker daemon and kubelet, and to be used when provisioning cluster
-# (e.g. for curl, apt-get..).
+# for the container runtime and kubelet, and to be used when provisioning cluster
+# (e.g. for curl, apt-get..). The variables are also set system-wide on the
+# nodes, in /etc/environment and /etc/profile.d/proxy.sh.
 # proxy:
 #  http: '{{ .HTTPProxy }}'
 #  https: '{{ .HTTPSProxy }}'
 #  noProxy: '{{ .NoProxy }}'
 
+# ImageRepository overrides the registry all the container images are
+# pulled from, e.g. to install clusters without internet access.
+# imageRepository: 'registry.local:5000'
+
+# PackageMirrors replaces the upstream repositories kubelet, kubeadm and
+# kubectl are installed from. Mirrors must keep the layout of
+# https://packages.cloud.google.com/apt and https://packages.cloud.google.com/yum,
+# including the GPG keys under doc/.
+# packageMirrors:
+#   aptMirror: 'https://mirror.local/kubernetes/apt'
+#   yumMirror: 'https://mirror.local/kubernetes/yum'
+
+# OSUpgrade limits the packages upgraded by 'kubeone upgrade --include-os-packages'.
+# All packages except Kubernetes ones are upgraded if no package is listed.
+# osUpgrade:
+#   packages:
+#   - openssl
+#   - openssh-server
+
 # KubeOne can automatically create MachineDeployments to create
 # worker nodes in your cluster. Each element in this "workers"
 # list is a single deployment and must have a unique name.
@@ -565,6 +821,15 @@ machineController:
 #       instanceType: 't3.medium'
 #       diskSize: 50
 #       diskType: 'gp2'
+#     # Size of the root disk in GiB, only supported on AWS and GCE. It
+#     # overrides the diskSize of the cloudProviderSpec.
+#     # diskSize: 50
+#     # Additional disks attached to the workers, only supported on AWS and
+#     # GCE. The iops are only supported for io1 disks on AWS.
+#     # dataDisks:
+#     # - size: 100
+#     #   type: 'io1'
+#     #   iops: 1000
 #     operatingSystem: 'ubuntu'
 #     operatingSystemSpec:
 #       distUpgradeOnBoot: true
@@ -592,4 +857,14 @@ machineController:
 #     operatingSystem: 'ubuntu'
 #     operatingSystemSpec:
 #       distUpgradeOnBoot: true
+# # workers can be created on another cloud provider than the control
+# # plane, its credentials must be provided as well
+# - name: burst
+#   replicas: 1
+#   providerSpec:
+#     cloudProvider: 'digitalocean'
+#     cloudProviderSpec:
+#       region: 'fra1'
+#       size: 's-2vcpu-4gb'
+#     operatingSystem: 'ubuntu'
 `
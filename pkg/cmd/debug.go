@@ -0,0 +1,121 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/kubermatic/kubeone/pkg/installer"
+)
+
+type debugBundleOptions struct {
+	globalOptions
+	Manifest string
+	Output   string
+}
+
+// debugCmd setups the debug command
+func debugCmd(rootFlags *pflag.FlagSet) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "debug",
+		Short: "Commands for troubleshooting clusters",
+	}
+
+	cmd.AddCommand(debugBundleCmd(rootFlags))
+
+	return cmd
+}
+
+// debugBundleCmd setups the debug bundle command
+func debugBundleCmd(rootFlags *pflag.FlagSet) *cobra.Command {
+	dopts := &debugBundleOptions{}
+	cmd := &cobra.Command{
+		Use:   "bundle",
+		Short: "Collect diagnostic information into an archive",
+		Long: `Collect diagnostic information about the cluster into a .tar.gz archive, to be attached to bug reports.
+
+The archive contains:
+* the manifest, with the values of keys likely to hold secrets, such as passwords, tokens and the cloud config, redacted
+* the description of all nodes
+* the machine-controller logs
+* all Machine, MachineSet and MachineDeployment objects
+
+Information that can't be collected is listed in errors.txt in the archive.
+It's possible to source information about hosts from Terraform output, using the '--tfjson' flag.`,
+		Args:    cobra.ExactArgs(0),
+		Example: `kubeone debug bundle --manifest mycluster.yaml --output bundle.tar.gz`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			gopts, err := persistentGlobalOptions(rootFlags)
+			if err != nil {
+				return errors.Wrap(err, "unable to get global flags")
+			}
+
+			logger := initLogger(gopts.Verbose, gopts.NoColor)
+			dopts.TerraformState = gopts.TerraformState
+			dopts.Verbose = gopts.Verbose
+			dopts.Debug = gopts.Debug
+			dopts.InCluster = gopts.InCluster
+
+			if dopts.Manifest == "" {
+				return errors.New("no cluster config file given")
+			}
+
+			if dopts.Output == "" {
+				return errors.New("no output file given")
+			}
+
+			return runDebugBundle(logger, dopts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&dopts.Manifest, "manifest", "m", "", "path to the KubeOne manifest")
+	cmd.Flags().StringVarP(&dopts.Output, "output", "o", "kubeone-debug.tar.gz", "path of the archive to write")
+
+	return cmd
+}
+
+// runDebugBundle collects the debug bundle
+func runDebugBundle(logger *logrus.Logger, debugBundleOptions *debugBundleOptions) error {
+	cluster, err := loadClusterConfig(debugBundleOptions.Manifest, debugBundleOptions.TerraformState)
+	if err != nil {
+		return errors.Wrap(err, "failed to load cluster")
+	}
+
+	manifest, err := ioutil.ReadFile(debugBundleOptions.Manifest)
+	if err != nil {
+		return errors.Wrap(err, "unable to read manifest")
+	}
+
+	options := &installer.Options{
+		Verbose:   debugBundleOptions.Verbose,
+		Debug:     debugBundleOptions.Debug,
+		InCluster: debugBundleOptions.InCluster,
+	}
+
+	err = installer.NewInstaller(cluster, logger).DebugBundle(options, string(manifest), debugBundleOptions.Output)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Debug bundle written to %s", debugBundleOptions.Output)
+	return nil
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/kubermatic/kubeone/pkg/installer"
+)
+
+type diffOptions struct {
+	globalOptions
+	Manifest string
+}
+
+// diffCmd setups diff command
+func diffCmd(rootFlags *pflag.FlagSet) *cobra.Command {
+	dopts := &diffOptions{}
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Show the drift between the manifest and the cluster",
+		Long: `
+Compare the state of the cluster with the state described by the manifest and print the drift in the
+unified diff format. The compared state includes the cluster settings stored by kubeadm, the number
+of control plane and worker nodes, the Kubernetes version of each node, the machine-controller image
+and the replicas of the worker MachineDeployments. No changes are made.
+
+The command exits with code 2 if the cluster drifted from the manifest.
+`,
+		Args:    cobra.ExactArgs(0),
+		Example: `kubeone diff --manifest mycluster.yaml -t terraformoutput.json`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			gopts, err := persistentGlobalOptions(rootFlags)
+			if err != nil {
+				return errors.Wrap(err, "unable to get global flags")
+			}
+
+			logger := initLogger(gopts.Verbose, gopts.NoColor)
+			dopts.TerraformState = gopts.TerraformState
+			dopts.Verbose = gopts.Verbose
+			dopts.Debug = gopts.Debug
+			dopts.InCluster = gopts.InCluster
+
+			if dopts.Manifest == "" {
+				return errors.New("no cluster config file given")
+			}
+
+			drifted, err := runDrift(logger, dopts)
+			if err != nil {
+				return err
+			}
+
+			if drifted {
+				cmd.SilenceUsage = true
+				cmd.SilenceErrors = true
+				return &exitCodeError{code: exitCodePendingChanges}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&dopts.Manifest, "manifest", "m", "", "path to the KubeOne manifest")
+
+	return cmd
+}
+
+// runDrift prints the drift between the cluster and the manifest and
+// reports whether there is any
+func runDrift(logger *logrus.Logger, diffOptions *diffOptions) (bool, error) {
+	cluster, err := loadClusterConfig(diffOptions.Manifest, diffOptions.TerraformState)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to load cluster")
+	}
+
+	options := &installer.Options{
+		Verbose:   diffOptions.Verbose,
+		Debug:     diffOptions.Debug,
+		InCluster: diffOptions.InCluster,
+	}
+
+	existing, desired, err := installer.NewInstaller(cluster, logger).Drift(options)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to compare cluster with the manifest")
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(existing),
+		B:        difflib.SplitLines(desired),
+		FromFile: "cluster",
+		ToFile:   diffOptions.Manifest,
+		Context:  3,
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "failed to generate diff")
+	}
+
+	if diff == "" {
+		logger.Infoln("No drift, the cluster matches the manifest.")
+		return false, nil
+	}
+
+	fmt.Fprint(os.Stdout, diff)
+
+	return true, nil
+}
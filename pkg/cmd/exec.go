@@ -0,0 +1,93 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/kubermatic/kubeone/pkg/installer"
+)
+
+type execOptions struct {
+	globalOptions
+	Manifest   string
+	Command    string
+	AllWorkers bool
+}
+
+// execCmd setups the exec command
+func execCmd(rootFlags *pflag.FlagSet) *cobra.Command {
+	eopts := &execOptions{}
+	cmd := &cobra.Command{
+		Use:   "exec <manifest>",
+		Short: "Run a command on all cluster nodes",
+		Long: `Run a command on all control plane hosts, and optionally on all worker nodes, over SSH.
+
+The output is prefixed with the hostname of the node it comes from. The command fails if it fails on any of the nodes.
+Worker nodes are accessed using the SSH key and agent of the first control plane host.
+It's possible to source information about hosts from Terraform output, using the '--tfjson' flag.`,
+		Args:    cobra.ExactArgs(1),
+		Example: `kubeone exec mycluster.yaml --command "systemctl status kubelet"`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			gopts, err := persistentGlobalOptions(rootFlags)
+			if err != nil {
+				return errors.Wrap(err, "unable to get global flags")
+			}
+
+			logger := initLogger(gopts.Verbose, gopts.NoColor)
+			eopts.TerraformState = gopts.TerraformState
+			eopts.Verbose = gopts.Verbose
+			eopts.Debug = gopts.Debug
+			eopts.InCluster = gopts.InCluster
+
+			eopts.Manifest = args[0]
+			if eopts.Manifest == "" {
+				return errors.New("no cluster config file given")
+			}
+
+			if eopts.Command == "" {
+				return errors.New("no command given")
+			}
+
+			return runExec(logger, eopts)
+		},
+	}
+
+	cmd.Flags().StringVar(&eopts.Command, "command", "", "command to run on the nodes")
+	cmd.Flags().BoolVar(&eopts.AllWorkers, "all-workers", false, "run the command on the worker nodes as well")
+
+	return cmd
+}
+
+// runExec runs the command on the cluster nodes
+func runExec(logger *logrus.Logger, execOptions *execOptions) error {
+	cluster, err := loadClusterConfig(execOptions.Manifest, execOptions.TerraformState)
+	if err != nil {
+		return errors.Wrap(err, "failed to load cluster")
+	}
+
+	options := &installer.Options{
+		Verbose:   execOptions.Verbose,
+		Debug:     execOptions.Debug,
+		InCluster: execOptions.InCluster,
+	}
+
+	return installer.NewInstaller(cluster, logger).Exec(options, execOptions.Command, execOptions.AllWorkers)
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	yaml "gopkg.in/yaml.v2"
+
+	kubeonescheme "github.com/kubermatic/kubeone/pkg/apis/kubeone/scheme"
+	kubeonev1alpha1 "github.com/kubermatic/kubeone/pkg/apis/kubeone/v1alpha1"
+	"github.com/kubermatic/kubeone/pkg/terraform"
+
+	kyaml "sigs.k8s.io/yaml"
+)
+
+type generateConfigOptions struct {
+	globalOptions
+	TerraformOutput   string
+	CloudProviderName string
+	KubernetesVersion string
+}
+
+// generateConfigCmd setups the generate-config command
+func generateConfigCmd(_ *pflag.FlagSet) *cobra.Command {
+	gOpts := &generateConfigOptions{}
+	cmd := &cobra.Command{
+		Use:   "generate-config",
+		Short: "Generate a configuration manifest from the Terraform output",
+		Long: `
+Generate a KubeOneCluster configuration manifest from the Terraform output.
+Control plane hosts, API endpoint and worker sets are sourced from the Terraform output.
+The manifest is printed on the standard output.
+`,
+		Args:    cobra.ExactArgs(0),
+		Example: `kubeone generate-config --terraform-output tf.json --provider aws`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			if gOpts.TerraformOutput == "" {
+				return errors.New("no terraform output file given")
+			}
+
+			return runGenerateConfig(gOpts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&gOpts.TerraformOutput, "terraform-output", "", "", "path to terraform output JSON")
+	cmd.Flags().StringVarP(&gOpts.CloudProviderName, "provider", "p", defaultCloudProviderName, "cloud provider name, used if the terraform output doesn't specify one (aws, digitalocean, gce, hetzner, packet, openstack, none)")
+	cmd.Flags().StringVarP(&gOpts.KubernetesVersion, "kubernetes-version", "k", defaultKubernetesVersion, "Kubernetes version")
+
+	return cmd
+}
+
+// runGenerateConfig prints the configuration manifest sourced from the terraform output
+func runGenerateConfig(generateOptions *generateConfigOptions) error {
+	tfOutput, err := ioutil.ReadFile(generateOptions.TerraformOutput)
+	if err != nil {
+		return errors.Wrap(err, "unable to read the given terraform output file")
+	}
+
+	tfConfig, err := terraform.NewConfigFromJSON(tfOutput)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse Terraform config")
+	}
+
+	cluster := &kubeonev1alpha1.KubeOneCluster{}
+	cluster.APIVersion = kubeonev1alpha1.SchemeGroupVersion.String()
+	cluster.Kind = "KubeOneCluster"
+	cluster.Versions.Kubernetes = generateOptions.KubernetesVersion
+	cluster.CloudProvider.Name = kubeonev1alpha1.CloudProviderName(generateOptions.CloudProviderName)
+
+	if err = tfConfig.Apply(cluster); err != nil {
+		return errors.Wrap(err, "unable to source information about cluster from a given terraform output")
+	}
+
+	kubeonescheme.Scheme.Default(cluster)
+
+	switch cluster.CloudProvider.Name {
+	case kubeonev1alpha1.CloudProviderNameDigitalOcean, kubeonev1alpha1.CloudProviderNamePacket, kubeonev1alpha1.CloudProviderNameHetzner:
+		cluster.CloudProvider.External = true
+	}
+
+	// round-trip through JSON tags to get the manifest field names
+	clusterYAML, err := kyaml.Marshal(cluster)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode config as YAML")
+	}
+
+	cfg := yaml.MapSlice{}
+	if err = yaml.Unmarshal(clusterYAML, &cfg); err != nil {
+		return errors.Wrap(err, "failed to decode config")
+	}
+
+	return errors.Wrap(validateAndPrintConfig(cfg, os.Stdout), "unable to validate and print config")
+}
@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
@@ -28,17 +29,33 @@ import (
 
 	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
 	"github.com/kubermatic/kubeone/pkg/installer"
+	"github.com/kubermatic/kubeone/pkg/util"
 )
 
 type installOptions struct {
 	globalOptions
+	Metrics    *util.Metrics
 	Manifest   string
 	BackupFile string
+	Adopt      bool
+	// SkipMachineController overrides machineController.deploy from the manifest
+	SkipMachineController bool
+	// SkipWorkers prevents creating worker machines defined in the manifest
+	SkipWorkers bool
+	// Parallelism limits how many nodes are worked on concurrently
+	Parallelism int
+	// Retry continues a failed installation whose kubeadm init or join
+	// failed although the node was initialized or joined
+	Retry bool
+	// Diff prints the differences between the cluster and the manifest instead of installing
+	Diff bool
+	// IgnoreState runs all steps, including the ones completed by an interrupted installation
+	IgnoreState bool
 }
 
 // installCmd setups install command
-func installCmd(rootFlags *pflag.FlagSet) *cobra.Command {
-	iopts := &installOptions{}
+func installCmd(rootFlags *pflag.FlagSet, metrics *util.Metrics) *cobra.Command {
+	iopts := &installOptions{Metrics: metrics}
 	cmd := &cobra.Command{
 		Use:   "install <manifest>",
 		Short: "Install Kubernetes",
@@ -47,6 +64,16 @@ Install Kubernetes on pre-existing machines
 
 This command takes KubeOne manifest which contains information about hosts and how the cluster should be provisioned.
 It's possible to source information about hosts from Terraform output, using the '--tfjson' flag.
+The manifest can also be a kustomize directory, which is built using 'kustomize build', or 'kubectl kustomize'
+if kustomize isn't installed.
+Clusters provisioned by other means can be adopted using the '--adopt' flag, in which case only machine-controller
+and worker machines are reconciled.
+The '--diff' flag shows how an existing cluster differs from the manifest, without making any changes.
+Nodes which are already initialized or joined, i.e. have a kubeconfig and are reachable through the API server,
+are skipped by kubeadm init and join. If kubeadm init or join failed, e.g. because of a lost SSH connection, but
+the node was initialized or joined anyway, the installation can be continued using the '--retry' flag.
+The completed steps are recorded in .kubeone/state.json, an interrupted installation skips them when run again,
+unless the manifest changed or the '--ignore-state' flag is given. The file is removed once the installation succeeds.
 `,
 		Args:    cobra.ExactArgs(1),
 		Example: `kubeone install mycluster.yaml -t terraformoutput.json`,
@@ -56,9 +83,11 @@ It's possible to source information about hosts from Terraform output, using the
 				return errors.Wrap(err, "unable to get global flags")
 			}
 
-			logger := initLogger(gopts.Verbose)
+			logger := initLogger(gopts.Verbose, gopts.NoColor)
 			iopts.TerraformState = gopts.TerraformState
 			iopts.Verbose = gopts.Verbose
+			iopts.Debug = gopts.Debug
+			iopts.InCluster = gopts.InCluster
 
 			iopts.Manifest = args[0]
 			if iopts.Manifest == "" {
@@ -70,6 +99,13 @@ It's possible to source information about hosts from Terraform output, using the
 	}
 
 	cmd.Flags().StringVarP(&iopts.BackupFile, "backup", "b", "", "path to where the PKI backup .tar.gz file should be placed (default: location of cluster config file)")
+	cmd.Flags().BoolVar(&iopts.SkipMachineController, "skip-machine-controller", false, "skip deploying machine-controller, regardless of the manifest")
+	cmd.Flags().BoolVar(&iopts.SkipWorkers, "skip-workers", false, "skip creating worker machines defined in the manifest")
+	cmd.Flags().IntVar(&iopts.Parallelism, "parallelism", 1, "number of nodes to run SSH sessions against concurrently during multi-node phases")
+	cmd.Flags().BoolVar(&iopts.Retry, "retry", false, "continue the installation if kubeadm init or join failed but the node was initialized or joined")
+	cmd.Flags().BoolVar(&iopts.Diff, "diff", false, "show how the existing cluster differs from the manifest, without making any changes")
+	cmd.Flags().BoolVar(&iopts.IgnoreState, "ignore-state", false, "run all steps, including the ones completed by an interrupted installation")
+	cmd.Flags().BoolVar(&iopts.Adopt, "adopt", false, "adopt an existing cluster not provisioned by KubeOne, skipping control plane provisioning")
 
 	return cmd
 }
@@ -81,6 +117,14 @@ func runInstall(logger *logrus.Logger, installOptions *installOptions) error {
 		return errors.Wrap(err, "failed to load cluster")
 	}
 
+	if installOptions.SkipMachineController {
+		cluster.MachineController.Deploy = false
+	}
+
+	if installOptions.Diff {
+		return runDiff(logger, cluster, installOptions)
+	}
+
 	options, err := createInstallerOptions(installOptions.Manifest, cluster, installOptions)
 	if err != nil {
 		return errors.Wrap(err, "failed to create installer options")
@@ -89,7 +133,49 @@ func runInstall(logger *logrus.Logger, installOptions *installOptions) error {
 	return installer.NewInstaller(cluster, logger).Install(options)
 }
 
+// runDiff prints the differences between the existing cluster and the manifest
+func runDiff(logger *logrus.Logger, cluster *kubeoneapi.KubeOneCluster, installOptions *installOptions) error {
+	options := &installer.Options{
+		Verbose:     installOptions.Verbose,
+		Debug:       installOptions.Debug,
+		InCluster:   installOptions.InCluster,
+		SkipWorkers: installOptions.SkipWorkers,
+	}
+
+	diffs, err := installer.NewInstaller(cluster, logger).Diff(options)
+	if err != nil {
+		return errors.Wrap(err, "failed to compare cluster with the manifest")
+	}
+
+	printDiff(os.Stdout, diffs)
+
+	return nil
+}
+
+func printDiff(out io.Writer, diffs []util.Difference) {
+	if len(diffs) == 0 {
+		fmt.Fprintln(out, "No changes, the cluster matches the manifest.")
+		return
+	}
+
+	for _, d := range diffs {
+		if d.Removed {
+			fmt.Fprintf(out, "- %s will be removed\n", d.Resource)
+			continue
+		}
+		if d.Field == "" {
+			fmt.Fprintf(out, "+ %s will be created\n", d.Resource)
+			continue
+		}
+		fmt.Fprintf(out, "~ %s %s: %q => %q\n", d.Resource, d.Field, d.Existing, d.Desired)
+	}
+}
+
 func createInstallerOptions(clusterFile string, cluster *kubeoneapi.KubeOneCluster, options *installOptions) (*installer.Options, error) {
+	if options.Parallelism < 1 {
+		return nil, errors.Errorf("parallelism must be at least 1, got %d", options.Parallelism)
+	}
+
 	if len(options.BackupFile) == 0 {
 		fullPath, _ := filepath.Abs(clusterFile)
 		clusterName := cluster.Name
@@ -114,7 +200,16 @@ func createInstallerOptions(clusterFile string, cluster *kubeoneapi.KubeOneClust
 	defer f.Close()
 
 	return &installer.Options{
-		BackupFile: options.BackupFile,
-		Verbose:    options.Verbose,
+		BackupFile:  options.BackupFile,
+		Verbose:     options.Verbose,
+		Debug:       options.Debug,
+		InCluster:   options.InCluster,
+		Adopt:       options.Adopt,
+		SkipWorkers: options.SkipWorkers,
+		Metrics:     options.Metrics,
+		Parallelism: options.Parallelism,
+		Retry:       options.Retry,
+		StateFile:   util.DefaultStateFile,
+		IgnoreState: options.IgnoreState,
 	}, nil
 }
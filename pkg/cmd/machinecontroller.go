@@ -0,0 +1,267 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/kubermatic/kubeone/pkg/installer"
+	"github.com/kubermatic/kubeone/pkg/templates/machinecontroller"
+)
+
+type rollbackOptions struct {
+	globalOptions
+	Manifest string
+	Version  string
+}
+
+type rotateCertsOptions struct {
+	globalOptions
+	Manifest string
+}
+
+type statusOptions struct {
+	globalOptions
+	Manifest string
+}
+
+// machineControllerCmd setups the machine-controller command
+func machineControllerCmd(rootFlags *pflag.FlagSet) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "machine-controller",
+		Short: "Commands for managing machine-controller",
+	}
+
+	cmd.AddCommand(
+		rollbackCmd(rootFlags),
+		rotateCertsCmd(rootFlags),
+		statusCmd(rootFlags),
+	)
+
+	return cmd
+}
+
+// rollbackCmd setups the machine-controller rollback command
+func rollbackCmd(rootFlags *pflag.FlagSet) *cobra.Command {
+	ropts := &rollbackOptions{}
+	cmd := &cobra.Command{
+		Use:   "rollback <manifest>",
+		Short: "Rollback machine-controller to the previous version",
+		Long: `Rollback machine-controller and its webhook to the given version.
+
+This command takes KubeOne manifest which contains information about hosts.
+It's possible to source information about hosts from Terraform output, using the '--tfjson' flag.`,
+		Args:    cobra.ExactArgs(1),
+		Example: `kubeone machine-controller rollback mycluster.yaml --version v1.1.4`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			gopts, err := persistentGlobalOptions(rootFlags)
+			if err != nil {
+				return errors.Wrap(err, "unable to get global flags")
+			}
+
+			logger := initLogger(gopts.Verbose, gopts.NoColor)
+			ropts.TerraformState = gopts.TerraformState
+			ropts.Verbose = gopts.Verbose
+			ropts.Debug = gopts.Debug
+			ropts.InCluster = gopts.InCluster
+
+			ropts.Manifest = args[0]
+			if ropts.Manifest == "" {
+				return errors.New("no cluster config file given")
+			}
+
+			if ropts.Version == "" {
+				return errors.New("no machine-controller version given")
+			}
+
+			return runRollback(logger, ropts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&ropts.Version, "version", "", "", "machine-controller version to rollback to")
+
+	return cmd
+}
+
+// runRollback rolls machine-controller back to the given version
+func runRollback(logger *logrus.Logger, rollbackOptions *rollbackOptions) error {
+	cluster, err := loadClusterConfig(rollbackOptions.Manifest, rollbackOptions.TerraformState)
+	if err != nil {
+		return errors.Wrap(err, "failed to load cluster")
+	}
+
+	options := &installer.Options{
+		Verbose:   rollbackOptions.Verbose,
+		Debug:     rollbackOptions.Debug,
+		InCluster: rollbackOptions.InCluster,
+	}
+
+	return installer.NewInstaller(cluster, logger).RollbackMachineController(options, rollbackOptions.Version)
+}
+
+// rotateCertsCmd setups the machine-controller rotate-certs command
+func rotateCertsCmd(rootFlags *pflag.FlagSet) *cobra.Command {
+	ropts := &rotateCertsOptions{}
+	cmd := &cobra.Command{
+		Use:   "rotate-certs <manifest>",
+		Short: "Rotate machine-controller webhook certificates",
+		Long: `Issue a new serving certificate for the machine-controller webhook and restart the webhook to use it.
+
+This command takes KubeOne manifest which contains information about hosts.
+It's possible to source information about hosts from Terraform output, using the '--tfjson' flag.`,
+		Args:    cobra.ExactArgs(1),
+		Example: `kubeone machine-controller rotate-certs mycluster.yaml`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			gopts, err := persistentGlobalOptions(rootFlags)
+			if err != nil {
+				return errors.Wrap(err, "unable to get global flags")
+			}
+
+			logger := initLogger(gopts.Verbose, gopts.NoColor)
+			ropts.TerraformState = gopts.TerraformState
+			ropts.Verbose = gopts.Verbose
+			ropts.Debug = gopts.Debug
+			ropts.InCluster = gopts.InCluster
+
+			ropts.Manifest = args[0]
+			if ropts.Manifest == "" {
+				return errors.New("no cluster config file given")
+			}
+
+			return runRotateCerts(logger, ropts)
+		},
+	}
+
+	return cmd
+}
+
+// runRotateCerts rotates the machine-controller webhook certificates
+func runRotateCerts(logger *logrus.Logger, rotateCertsOptions *rotateCertsOptions) error {
+	cluster, err := loadClusterConfig(rotateCertsOptions.Manifest, rotateCertsOptions.TerraformState)
+	if err != nil {
+		return errors.Wrap(err, "failed to load cluster")
+	}
+
+	options := &installer.Options{
+		Verbose:   rotateCertsOptions.Verbose,
+		Debug:     rotateCertsOptions.Debug,
+		InCluster: rotateCertsOptions.InCluster,
+	}
+
+	return installer.NewInstaller(cluster, logger).RotateMachineControllerCertificates(options)
+}
+
+// statusCmd setups the machine-controller status command
+func statusCmd(rootFlags *pflag.FlagSet) *cobra.Command {
+	sopts := &statusOptions{}
+	cmd := &cobra.Command{
+		Use:   "status <manifest>",
+		Short: "Show machine-controller health",
+		Long: `Show the status of machine-controller and its webhook, including their deployments, pods and restarts.
+The command fails if machine-controller is not healthy.
+
+This command takes KubeOne manifest which contains information about hosts.
+It's possible to source information about hosts from Terraform output, using the '--tfjson' flag.`,
+		Args:    cobra.ExactArgs(1),
+		Example: `kubeone machine-controller status mycluster.yaml`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			gopts, err := persistentGlobalOptions(rootFlags)
+			if err != nil {
+				return errors.Wrap(err, "unable to get global flags")
+			}
+
+			logger := initLogger(gopts.Verbose, gopts.NoColor)
+			sopts.TerraformState = gopts.TerraformState
+			sopts.Verbose = gopts.Verbose
+			sopts.Debug = gopts.Debug
+			sopts.InCluster = gopts.InCluster
+
+			sopts.Manifest = args[0]
+			if sopts.Manifest == "" {
+				return errors.New("no cluster config file given")
+			}
+
+			return runStatus(logger, sopts)
+		},
+	}
+
+	return cmd
+}
+
+// runStatus prints the machine-controller status
+func runStatus(logger *logrus.Logger, statusOptions *statusOptions) error {
+	cluster, err := loadClusterConfig(statusOptions.Manifest, statusOptions.TerraformState)
+	if err != nil {
+		return errors.Wrap(err, "failed to load cluster")
+	}
+
+	options := &installer.Options{
+		Verbose:   statusOptions.Verbose,
+		Debug:     statusOptions.Debug,
+		InCluster: statusOptions.InCluster,
+	}
+
+	status, err := installer.NewInstaller(cluster, logger).MachineControllerStatus(options)
+	if err != nil {
+		return errors.Wrap(err, "failed to get machine-controller status")
+	}
+
+	printStatus(os.Stdout, status)
+
+	if !status.Healthy() {
+		return errors.New("machine-controller is not healthy")
+	}
+
+	return nil
+}
+
+func printStatus(out io.Writer, status *machinecontroller.Status) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "DEPLOYMENT\tREADY")
+	for _, dep := range status.Deployments {
+		ready := "not found"
+		if dep.Found {
+			ready = fmt.Sprintf("%d/%d", dep.ReadyReplicas, dep.Replicas)
+		}
+		fmt.Fprintf(w, "%s\t%s\n", dep.Name, ready)
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "POD\tPHASE\tREADY\tRESTARTS\tLAST RESTART REASON")
+	for _, pod := range status.Pods {
+		reason := pod.LastRestartReason
+		if reason == "" {
+			reason = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%t\t%d\t%s\n", pod.Name, pod.Phase, pod.Ready, pod.Restarts, reason)
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintf(w, "Webhook service endpoints:\t%d\n", status.WebhookEndpoints)
+	fmt.Fprintf(w, "Webhook configuration:\t%t\n", status.WebhookConfigured)
+
+	w.Flush()
+}
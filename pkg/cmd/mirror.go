@@ -0,0 +1,198 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
+	"github.com/kubermatic/kubeone/pkg/templates/canal"
+	"github.com/kubermatic/kubeone/pkg/templates/clusterautoscaler"
+	"github.com/kubermatic/kubeone/pkg/templates/csi"
+	"github.com/kubermatic/kubeone/pkg/templates/externalccm"
+	"github.com/kubermatic/kubeone/pkg/templates/machinecontroller"
+	"github.com/kubermatic/kubeone/pkg/templates/metricsserver"
+	"github.com/kubermatic/kubeone/pkg/templates/nodelocaldns"
+	"github.com/kubermatic/kubeone/pkg/templates/velero"
+	"github.com/kubermatic/kubeone/pkg/templates/weave"
+)
+
+type mirrorOptions struct {
+	globalOptions
+	Manifest       string
+	TargetRegistry string
+}
+
+// mirrorCmd setups the mirror command
+func mirrorCmd(rootFlags *pflag.FlagSet) *cobra.Command {
+	mopts := &mirrorOptions{}
+	cmd := &cobra.Command{
+		Use:   "mirror <manifest>",
+		Short: "Mirror the images needed by the cluster to a registry",
+		Long: `Copy all the images needed to install the cluster to the given registry, for installing clusters without internet access.
+
+The Kubernetes images are listed using kubeadm, which must be on PATH. Images are copied using crane if available,
+otherwise using docker. The target registry defaults to the imageRepository from the manifest.`,
+		Args:    cobra.ExactArgs(1),
+		Example: `kubeone mirror mycluster.yaml --target-registry registry.internal.example.com`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			gopts, err := persistentGlobalOptions(rootFlags)
+			if err != nil {
+				return errors.Wrap(err, "unable to get global flags")
+			}
+
+			logger := initLogger(gopts.Verbose, gopts.NoColor)
+			mopts.TerraformState = gopts.TerraformState
+			mopts.Verbose = gopts.Verbose
+
+			mopts.Manifest = args[0]
+			if mopts.Manifest == "" {
+				return errors.New("no cluster config file given")
+			}
+
+			return runMirror(logger, mopts)
+		},
+	}
+
+	cmd.Flags().StringVar(&mopts.TargetRegistry, "target-registry", "", "registry to copy the images to (default: imageRepository from the manifest)")
+
+	return cmd
+}
+
+// runMirror copies the images needed by the cluster to the target registry
+func runMirror(logger *logrus.Logger, mirrorOptions *mirrorOptions) error {
+	cluster, err := loadClusterConfig(mirrorOptions.Manifest, mirrorOptions.TerraformState)
+	if err != nil {
+		return errors.Wrap(err, "failed to load cluster")
+	}
+
+	if mirrorOptions.TargetRegistry != "" {
+		cluster.ImageRepository = mirrorOptions.TargetRegistry
+	}
+	if cluster.ImageRepository == "" {
+		return errors.New("no target registry given")
+	}
+
+	copyImage, err := imageCopier(mirrorOptions.Verbose)
+	if err != nil {
+		return err
+	}
+
+	images, err := requiredImages(cluster)
+	if err != nil {
+		return errors.Wrap(err, "failed to list required images")
+	}
+
+	for _, image := range images {
+		target := cluster.ImageFor(image)
+		logger.Infof("Mirroring %s to %s…", image, target)
+		if err := copyImage(image, target); err != nil {
+			return errors.Wrapf(err, "failed to mirror %s", image)
+		}
+	}
+
+	return nil
+}
+
+// requiredImages returns all the images needed to install the cluster
+func requiredImages(cluster *kubeoneapi.KubeOneCluster) ([]string, error) {
+	out, err := exec.Command("kubeadm", "config", "images", "list", "--kubernetes-version", cluster.Versions.Kubernetes).Output()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list Kubernetes images using kubeadm")
+	}
+
+	images := strings.Fields(string(out))
+
+	switch cluster.ClusterNetwork.CNI.Provider {
+	case kubeoneapi.CNIProviderWeaveNet:
+		images = append(images, weave.Images()...)
+	default:
+		images = append(images, canal.Images()...)
+	}
+
+	if cluster.MachineController.Deploy {
+		images = append(images, machinecontroller.Images()...)
+	}
+
+	if cluster.CloudProvider.External {
+		images = append(images, externalccm.Images(cluster)...)
+	}
+
+	if cluster.ClusterAutoscaler != nil && cluster.ClusterAutoscaler.Enabled {
+		images = append(images, clusterautoscaler.Images(cluster)...)
+	}
+
+	if cluster.NodeLocalDNS != nil && cluster.NodeLocalDNS.Enabled {
+		images = append(images, nodelocaldns.Images()...)
+	}
+
+	if cluster.Velero != nil && cluster.Velero.Enabled {
+		images = append(images, velero.Images(cluster)...)
+	}
+
+	if cluster.CSI != nil {
+		images = append(images, csi.Images(cluster)...)
+	}
+
+	if cluster.Features.MetricsServer != nil && cluster.Features.MetricsServer.Enable {
+		images = append(images, metricsserver.Images()...)
+	}
+
+	return images, nil
+}
+
+// imageCopier returns a function copying an image between registries,
+// using crane if available, otherwise docker
+func imageCopier(verbose bool) (func(src, dst string) error, error) {
+	run := func(name string, args ...string) error {
+		cmd := exec.Command(name, args...)
+		if verbose {
+			cmd.Stdout = os.Stdout
+			cmd.Stderr = os.Stderr
+		}
+		if err := cmd.Run(); err != nil {
+			return errors.Wrapf(err, "failed to run %s %s", name, strings.Join(args, " "))
+		}
+		return nil
+	}
+
+	if _, err := exec.LookPath("crane"); err == nil {
+		return func(src, dst string) error {
+			return run("crane", "copy", src, dst)
+		}, nil
+	}
+
+	if _, err := exec.LookPath("docker"); err == nil {
+		return func(src, dst string) error {
+			for _, args := range [][]string{{"pull", src}, {"tag", src, dst}, {"push", dst}} {
+				if err := run("docker", args...); err != nil {
+					return err
+				}
+			}
+			return nil
+		}, nil
+	}
+
+	return nil, errors.New("neither crane nor docker found on PATH")
+}
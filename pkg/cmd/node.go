@@ -0,0 +1,193 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
+)
+
+const sshAgentSocketEnvPrefix = "env:"
+
+type nodeSSHOptions struct {
+	globalOptions
+	Manifest string
+	Host     string
+}
+
+// nodeCmd setups the node command
+func nodeCmd(rootFlags *pflag.FlagSet) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "node",
+		Short: "Commands for accessing cluster nodes",
+	}
+
+	cmd.AddCommand(nodeSSHCmd(rootFlags))
+	cmd.AddCommand(nodeCordonCmd(rootFlags, true))
+	cmd.AddCommand(nodeCordonCmd(rootFlags, false))
+	cmd.AddCommand(nodeDrainCmd(rootFlags))
+
+	return cmd
+}
+
+// nodeSSHCmd setups the node ssh command
+func nodeSSHCmd(rootFlags *pflag.FlagSet) *cobra.Command {
+	nopts := &nodeSSHOptions{}
+	cmd := &cobra.Command{
+		Use:   "ssh <manifest>",
+		Short: "Open an SSH session to a cluster node",
+		Long: `Open an interactive SSH session to a cluster node, using the SSH user, port, key and agent from the manifest.
+If no host is given, available hosts are listed and one can be chosen interactively.
+
+This command requires the ssh client to be installed.
+It's possible to source information about hosts from Terraform output, using the '--tfjson' flag.`,
+		Args:    cobra.ExactArgs(1),
+		Example: `kubeone node ssh mycluster.yaml --host 192.168.1.10`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			gopts, err := persistentGlobalOptions(rootFlags)
+			if err != nil {
+				return errors.Wrap(err, "unable to get global flags")
+			}
+
+			nopts.TerraformState = gopts.TerraformState
+
+			nopts.Manifest = args[0]
+			if nopts.Manifest == "" {
+				return errors.New("no cluster config file given")
+			}
+
+			return runNodeSSH(nopts)
+		},
+	}
+
+	cmd.Flags().StringVar(&nopts.Host, "host", "", "public or private address of the host to connect to")
+
+	return cmd
+}
+
+// runNodeSSH replaces the current process with an ssh session to the chosen host
+func runNodeSSH(nodeSSHOptions *nodeSSHOptions) error {
+	cluster, err := loadClusterConfig(nodeSSHOptions.Manifest, nodeSSHOptions.TerraformState)
+	if err != nil {
+		return errors.Wrap(err, "failed to load cluster")
+	}
+
+	var host *kubeoneapi.HostConfig
+	if nodeSSHOptions.Host != "" {
+		host, err = findHost(cluster.Hosts, nodeSSHOptions.Host)
+	} else {
+		host, err = promptHost(cluster.Hosts, os.Stdin, os.Stdout)
+	}
+	if err != nil {
+		return err
+	}
+
+	sshPath, err := exec.LookPath("ssh")
+	if err != nil {
+		return errors.Wrap(err, "unable to find ssh client")
+	}
+
+	args, env := sshCommand(*host)
+
+	return errors.Wrap(syscall.Exec(sshPath, args, env), "failed to run ssh")
+}
+
+// findHost looks up a host by its public or private address
+func findHost(hosts []kubeoneapi.HostConfig, address string) (*kubeoneapi.HostConfig, error) {
+	for i := range hosts {
+		if hosts[i].PublicAddress == address || hosts[i].PrivateAddress == address {
+			return &hosts[i], nil
+		}
+	}
+
+	return nil, errors.Errorf("host %q not found in the manifest", address)
+}
+
+// promptHost lists the hosts and asks which one to connect to
+func promptHost(hosts []kubeoneapi.HostConfig, in io.Reader, out io.Writer) (*kubeoneapi.HostConfig, error) {
+	if len(hosts) == 0 {
+		return nil, errors.New("no hosts defined in the manifest")
+	}
+
+	for i, host := range hosts {
+		fmt.Fprintf(out, "[%d] %s (%s)\n", i+1, host.PublicAddress, host.PrivateAddress)
+	}
+	fmt.Fprint(out, "Choose a host: ")
+
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to read host choice")
+	}
+
+	choice, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil || choice < 1 || choice > len(hosts) {
+		return nil, errors.Errorf("invalid host choice %q", strings.TrimSpace(line))
+	}
+
+	return &hosts[choice-1], nil
+}
+
+// sshCommand returns the ssh arguments and environment to connect to the host
+func sshCommand(host kubeoneapi.HostConfig) ([]string, []string) {
+	args := []string{"ssh", "-t"}
+	env := os.Environ()
+
+	if host.SSHPort > 0 {
+		args = append(args, "-p", strconv.Itoa(host.SSHPort))
+	}
+
+	if host.SSHPrivateKeyFile != "" {
+		args = append(args, "-i", host.SSHPrivateKeyFile)
+	}
+
+	if host.SSHStrictHostKeyChecking {
+		args = append(args, "-o", "StrictHostKeyChecking=yes")
+		if host.SSHKnownHostsFile != "" {
+			args = append(args, "-o", "UserKnownHostsFile="+host.SSHKnownHostsFile)
+		}
+	}
+
+	// ssh tries the identity files in the given order
+	for _, keyFile := range host.SSHPrivateKeyFiles {
+		args = append(args, "-i", keyFile)
+	}
+
+	if socket := host.SSHAgentSocket; socket != "" {
+		if strings.HasPrefix(socket, sshAgentSocketEnvPrefix) {
+			socket = os.Getenv(strings.TrimPrefix(socket, sshAgentSocketEnvPrefix))
+		}
+		if socket != "" {
+			env = append(env, fmt.Sprintf("SSH_AUTH_SOCK=%s", socket))
+		}
+	}
+
+	args = append(args, fmt.Sprintf("%s@%s", host.SSHUsername, host.PublicAddress))
+
+	return args, env
+}
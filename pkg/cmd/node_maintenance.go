@@ -0,0 +1,354 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
+	"github.com/kubermatic/kubeone/pkg/ssh"
+)
+
+const kubectlCommand = "sudo kubectl --kubeconfig=/etc/kubernetes/admin.conf"
+
+// exitCodeDrainFailed is returned by node drain when not all pods could be evicted
+const exitCodeDrainFailed = 1
+
+type nodeCordonOptions struct {
+	globalOptions
+	Manifest string
+	Host     string
+	All      bool
+	Selector string
+}
+
+// nodeCordonCmd setups the node cordon and node uncordon commands
+func nodeCordonCmd(rootFlags *pflag.FlagSet, cordon bool) *cobra.Command {
+	verb, description := "cordon", "Mark a node as unschedulable"
+	if !cordon {
+		verb, description = "uncordon", "Mark a node as schedulable"
+	}
+
+	nopts := &nodeCordonOptions{}
+	cmd := &cobra.Command{
+		Use:   verb,
+		Short: description,
+		Long: description + `.
+
+The node is looked up by its address and kubectl is run on the leader control plane node over SSH,
+so no direct access to the API server is needed. The node doesn't have to be listed in the manifest,
+which allows worker nodes created by machine-controller to be used as well.
+With '--all', all nodes matching the '--selector' label selector are used instead, or all nodes if no selector is given.`,
+		Args: cobra.ExactArgs(0),
+		Example: fmt.Sprintf(`kubeone node %[1]s --manifest mycluster.yaml --host 192.168.1.10
+kubeone node %[1]s --manifest mycluster.yaml --all --selector node-role.kubernetes.io/master!=`, verb),
+		RunE: func(_ *cobra.Command, _ []string) error {
+			gopts, err := persistentGlobalOptions(rootFlags)
+			if err != nil {
+				return errors.Wrap(err, "unable to get global flags")
+			}
+
+			logger := initLogger(gopts.Verbose, gopts.NoColor)
+			nopts.TerraformState = gopts.TerraformState
+
+			if nopts.Manifest == "" {
+				return errors.New("no cluster config file given")
+			}
+
+			if err = validateNodeSelection(nopts.Host, nopts.All, nopts.Selector); err != nil {
+				return err
+			}
+
+			return runNodeCordon(logger, nopts, verb)
+		},
+	}
+
+	cmd.Flags().StringVarP(&nopts.Manifest, "manifest", "m", "", "path to the KubeOne manifest")
+	cmd.Flags().StringVar(&nopts.Host, "host", "", "address of the node")
+	cmd.Flags().BoolVar(&nopts.All, "all", false, "use all nodes matching the label selector")
+	cmd.Flags().StringVarP(&nopts.Selector, "selector", "l", "", "label selector used with --all, all nodes are used if empty")
+
+	return cmd
+}
+
+type nodeDrainOptions struct {
+	globalOptions
+	Manifest         string
+	Host             string
+	IgnoreDaemonSets bool
+	DeleteLocalData  bool
+	GracePeriod      int
+	Timeout          time.Duration
+}
+
+// nodeDrainCmd setups the node drain command
+func nodeDrainCmd(rootFlags *pflag.FlagSet) *cobra.Command {
+	nopts := &nodeDrainOptions{}
+	cmd := &cobra.Command{
+		Use:   "drain",
+		Short: "Drain a node in preparation for maintenance",
+		Long: `Drain a node in preparation for maintenance.
+
+The node is cordoned and its pods are evicted by running kubectl drain on the leader control plane node over SSH.
+The node is looked up by its address, as with 'kubeone node cordon'. The evicted pods are printed.
+
+The command exits with code 1 if not all pods could be evicted within the timeout. The node stays cordoned
+in that case and can be made schedulable again using 'kubeone node uncordon'.`,
+		Args:    cobra.ExactArgs(0),
+		Example: `kubeone node drain --manifest mycluster.yaml --host 192.168.1.10 --ignore-daemonsets --timeout 10m`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			gopts, err := persistentGlobalOptions(rootFlags)
+			if err != nil {
+				return errors.Wrap(err, "unable to get global flags")
+			}
+
+			logger := initLogger(gopts.Verbose, gopts.NoColor)
+			nopts.TerraformState = gopts.TerraformState
+
+			if nopts.Manifest == "" {
+				return errors.New("no cluster config file given")
+			}
+
+			if err = validateNodeSelection(nopts.Host, false, ""); err != nil {
+				return err
+			}
+
+			drained, err := runNodeDrain(logger, nopts)
+			if err != nil {
+				return err
+			}
+
+			if !drained {
+				cmd.SilenceUsage = true
+				cmd.SilenceErrors = true
+				return &exitCodeError{code: exitCodeDrainFailed}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&nopts.Manifest, "manifest", "m", "", "path to the KubeOne manifest")
+	cmd.Flags().StringVar(&nopts.Host, "host", "", "address of the node")
+	cmd.Flags().BoolVar(&nopts.IgnoreDaemonSets, "ignore-daemonsets", false, "ignore pods managed by DaemonSets")
+	cmd.Flags().BoolVar(&nopts.DeleteLocalData, "delete-local-data", false, "evict pods using emptyDir volumes, deleting their data")
+	cmd.Flags().IntVar(&nopts.GracePeriod, "grace-period", -1, "seconds given to each pod to terminate, the pod's own value is used if negative")
+	cmd.Flags().DurationVar(&nopts.Timeout, "timeout", 5*time.Minute, "time to wait for all pods to be evicted, 0 waits forever")
+
+	return cmd
+}
+
+// runNodeDrain drains the node, and reports whether all pods were evicted
+func runNodeDrain(logger *logrus.Logger, nodeDrainOptions *nodeDrainOptions) (bool, error) {
+	cluster, err := loadClusterConfig(nodeDrainOptions.Manifest, nodeDrainOptions.TerraformState)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to load cluster")
+	}
+
+	connector := ssh.NewConnector()
+	defer connector.CloseAll()
+
+	conn, err := connectLeader(connector, cluster)
+	if err != nil {
+		return false, err
+	}
+
+	target, err := nodeTarget(conn, cluster, nodeDrainOptions.Host, "")
+	if err != nil {
+		return false, err
+	}
+
+	args := []string{
+		"drain", target,
+		fmt.Sprintf("--grace-period=%d", nodeDrainOptions.GracePeriod),
+		fmt.Sprintf("--timeout=%s", nodeDrainOptions.Timeout),
+	}
+	if nodeDrainOptions.IgnoreDaemonSets {
+		args = append(args, "--ignore-daemonsets")
+	}
+	if nodeDrainOptions.DeleteLocalData {
+		args = append(args, "--delete-local-data")
+	}
+
+	logger.Infof("Draining node %s…", nodeDrainOptions.Host)
+	stdout, stderr, _, drainErr := conn.Exec(kubectlCommand + " " + strings.Join(args, " "))
+
+	evicted := evictedPods(stdout + "\n" + stderr)
+	if len(evicted) == 0 {
+		logger.Infoln("No pods were evicted")
+	}
+	for _, pod := range evicted {
+		logger.Infof("Evicted pod %s", pod)
+	}
+
+	if drainErr != nil {
+		logger.Errorf("Failed to drain node %s: %s", nodeDrainOptions.Host, stderr)
+		return false, nil
+	}
+
+	logger.Infof("Node %s drained", nodeDrainOptions.Host)
+	return true, nil
+}
+
+// evictedPods returns the pods reported as evicted in the kubectl drain output
+func evictedPods(output string) []string {
+	var pods []string
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "pod/") && strings.HasSuffix(line, " evicted") {
+			pods = append(pods, strings.TrimSuffix(strings.TrimPrefix(line, "pod/"), " evicted"))
+		}
+	}
+
+	return pods
+}
+
+// validateNodeSelection ensures the nodes are selected either by address or by label selector
+func validateNodeSelection(host string, all bool, selector string) error {
+	switch {
+	case host != "" && all:
+		return errors.New("--host and --all can't be used together")
+	case host == "" && !all:
+		return errors.New("no host given, use --host or --all")
+	case selector != "" && !all:
+		return errors.New("--selector can only be used with --all")
+	case host != "" && net.ParseIP(host) == nil:
+		return errors.Errorf("invalid host address %q", host)
+	}
+
+	return nil
+}
+
+// runNodeCordon cordons or uncordons the selected nodes
+func runNodeCordon(logger *logrus.Logger, nodeCordonOptions *nodeCordonOptions, verb string) error {
+	cluster, err := loadClusterConfig(nodeCordonOptions.Manifest, nodeCordonOptions.TerraformState)
+	if err != nil {
+		return errors.Wrap(err, "failed to load cluster")
+	}
+
+	connector := ssh.NewConnector()
+	defer connector.CloseAll()
+
+	conn, err := connectLeader(connector, cluster)
+	if err != nil {
+		return err
+	}
+
+	target, err := nodeTarget(conn, cluster, nodeCordonOptions.Host, nodeCordonOptions.Selector)
+	if err != nil {
+		return err
+	}
+
+	stdout, stderr, _, err := conn.Exec(fmt.Sprintf("%s %s %s", kubectlCommand, verb, target))
+	if err != nil {
+		return errors.Wrapf(err, "failed to %s: %s", verb, stderr)
+	}
+
+	if stdout == "" {
+		logger.Warnln("No nodes matched the selector")
+	}
+	for _, line := range strings.Split(stdout, "\n") {
+		if line != "" {
+			logger.Infoln(line)
+		}
+	}
+
+	return nil
+}
+
+// nodeTarget returns the kubectl arguments selecting the node with the given
+// address, or the nodes matching the label selector if no address is given
+func nodeTarget(conn ssh.Connection, cluster *kubeoneapi.KubeOneCluster, host, selector string) (string, error) {
+	if host != "" {
+		nodeName, err := nodeNameByAddress(conn, cluster, host)
+		if err != nil {
+			return "", err
+		}
+		return shellQuote(nodeName), nil
+	}
+
+	// kubectl requires either a node name or a selector, and all nodes
+	// have the hostname label
+	if selector == "" {
+		selector = "kubernetes.io/hostname"
+	}
+
+	return "--selector=" + shellQuote(selector), nil
+}
+
+// connectLeader connects to the leader control plane node
+func connectLeader(connector *ssh.Connector, cluster *kubeoneapi.KubeOneCluster) (ssh.Connection, error) {
+	leader, err := cluster.Leader()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := connector.Connect(leader)
+	return conn, errors.Wrapf(err, "failed to connect to %s", leader.PublicAddress)
+}
+
+// nodeNameByAddress looks up the name of the node having the given address.
+// Control plane nodes usually register their private address, so both
+// addresses of the hosts from the manifest are tried.
+func nodeNameByAddress(conn ssh.Connection, cluster *kubeoneapi.KubeOneCluster, address string) (string, error) {
+	addresses := []string{address}
+	if host, err := findHost(cluster.Hosts, address); err == nil {
+		addresses = []string{host.PublicAddress, host.PrivateAddress, host.Hostname}
+	}
+
+	stdout, stderr, _, err := conn.Exec(kubectlCommand + ` get nodes -o jsonpath='{range .items[*]}{.metadata.name}{" "}{.status.addresses[*].address}{"\n"}{end}'`)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to list nodes: %s", stderr)
+	}
+
+	return matchNodeAddress(stdout, addresses)
+}
+
+// matchNodeAddress finds the node matching any of the addresses in lines of
+// the form "<node name> <address>...", the node name is an address as well
+func matchNodeAddress(nodes string, addresses []string) (string, error) {
+	for _, line := range strings.Split(nodes, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		for _, field := range fields {
+			for _, address := range addresses {
+				if address != "" && field == address {
+					return fields[0], nil
+				}
+			}
+		}
+	}
+
+	return "", errors.Errorf("no node with address %q found", addresses[0])
+}
+
+// shellQuote quotes the string to be passed as a single shell word
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'"'"'`, -1) + "'"
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/kubermatic/kubeone/pkg/installer"
+)
+
+// exitCodePendingChanges is returned by plan when the cluster doesn't match the manifest
+const exitCodePendingChanges = 2
+
+type planOptions struct {
+	globalOptions
+	Manifest    string
+	SkipWorkers bool
+}
+
+// planCmd setups plan command
+func planCmd(rootFlags *pflag.FlagSet) *cobra.Command {
+	popts := &planOptions{}
+	cmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Show the changes install would make to the cluster",
+		Long: `
+Compare the cluster with the manifest and print the control plane nodes and worker MachineDeployments
+which would be created, updated or removed, and the components which would be upgraded.
+No changes are made. A cluster which can't be accessed is assumed not to be provisioned yet.
+
+The command exits with code 2 if there are pending changes.
+`,
+		Args:    cobra.ExactArgs(0),
+		Example: `kubeone plan --manifest mycluster.yaml -t terraformoutput.json`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			gopts, err := persistentGlobalOptions(rootFlags)
+			if err != nil {
+				return errors.Wrap(err, "unable to get global flags")
+			}
+
+			logger := initLogger(gopts.Verbose, gopts.NoColor)
+			popts.TerraformState = gopts.TerraformState
+			popts.Verbose = gopts.Verbose
+			popts.Debug = gopts.Debug
+			popts.InCluster = gopts.InCluster
+
+			if popts.Manifest == "" {
+				return errors.New("no cluster config file given")
+			}
+
+			pending, err := runPlan(logger, popts)
+			if err != nil {
+				return err
+			}
+
+			if pending {
+				cmd.SilenceUsage = true
+				cmd.SilenceErrors = true
+				return &exitCodeError{code: exitCodePendingChanges}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&popts.Manifest, "manifest", "m", "", "path to the KubeOne manifest")
+	cmd.Flags().BoolVar(&popts.SkipWorkers, "skip-workers", false, "don't compare the worker MachineDeployments")
+
+	return cmd
+}
+
+// runPlan prints the changes install would make and reports whether there are any
+func runPlan(logger *logrus.Logger, planOptions *planOptions) (bool, error) {
+	cluster, err := loadClusterConfig(planOptions.Manifest, planOptions.TerraformState)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to load cluster")
+	}
+
+	options := &installer.Options{
+		Verbose:     planOptions.Verbose,
+		Debug:       planOptions.Debug,
+		InCluster:   planOptions.InCluster,
+		SkipWorkers: planOptions.SkipWorkers,
+	}
+
+	diffs, err := installer.NewInstaller(cluster, logger).Plan(options)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to plan changes")
+	}
+
+	printDiff(os.Stdout, diffs)
+
+	return len(diffs) > 0, nil
+}
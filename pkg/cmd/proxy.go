@@ -0,0 +1,150 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/kubermatic/kubeone/pkg/ssh"
+	"github.com/kubermatic/kubeone/pkg/util"
+)
+
+// apiServerAddress is where the API server is reached from the control plane nodes
+const apiServerAddress = "127.0.0.1:6443"
+
+type proxyOptions struct {
+	globalOptions
+	Manifest         string
+	Port             int
+	KubeconfigOutput string
+}
+
+// proxyCmd setups the proxy command
+func proxyCmd(rootFlags *pflag.FlagSet) *cobra.Command {
+	popts := &proxyOptions{}
+	cmd := &cobra.Command{
+		Use:   "proxy",
+		Short: "Proxy a local port to the API server over SSH",
+		Long: `Proxy a local port to the API server over SSH.
+
+The API server is reached through an SSH tunnel to the leader control plane node, so it doesn't have to be
+accessible directly. The proxy runs until it's interrupted.
+
+With '--kubeconfig-output', the admin kubeconfig pointing to the local port is written to the given file.
+The API server certificate is valid for 127.0.0.1 on clusters provisioned by this release of KubeOne or later.`,
+		Args:    cobra.ExactArgs(0),
+		Example: `kubeone proxy --manifest mycluster.yaml --port 6443 --kubeconfig-output kubeconfig`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			gopts, err := persistentGlobalOptions(rootFlags)
+			if err != nil {
+				return errors.Wrap(err, "unable to get global flags")
+			}
+
+			logger := initLogger(gopts.Verbose, gopts.NoColor)
+			popts.TerraformState = gopts.TerraformState
+			popts.Verbose = gopts.Verbose
+			popts.Debug = gopts.Debug
+
+			if popts.Manifest == "" {
+				return errors.New("no cluster config file given")
+			}
+
+			return runProxy(logger, popts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&popts.Manifest, "manifest", "m", "", "path to the KubeOne manifest")
+	cmd.Flags().IntVarP(&popts.Port, "port", "p", 6443, "local port the API server is proxied on")
+	cmd.Flags().StringVar(&popts.KubeconfigOutput, "kubeconfig-output", "", "write a kubeconfig using the proxy to the given file")
+
+	return cmd
+}
+
+// runProxy proxies the local port to the API server until it's interrupted
+func runProxy(logger *logrus.Logger, proxyOptions *proxyOptions) error {
+	cluster, err := loadClusterConfig(proxyOptions.Manifest, proxyOptions.TerraformState)
+	if err != nil {
+		return errors.Wrap(err, "failed to load cluster")
+	}
+
+	leader, err := cluster.Leader()
+	if err != nil {
+		return err
+	}
+
+	if proxyOptions.KubeconfigOutput != "" {
+		kubeconfig, kerr := util.DownloadKubeconfig(cluster)
+		if kerr != nil {
+			return errors.Wrap(kerr, "unable to download kubeconfig")
+		}
+
+		kubeconfig, kerr = util.LocalKubeconfig(kubeconfig, proxyOptions.Port)
+		if kerr != nil {
+			return kerr
+		}
+
+		if kerr = ioutil.WriteFile(proxyOptions.KubeconfigOutput, kubeconfig, 0600); kerr != nil {
+			return errors.Wrap(kerr, "unable to write kubeconfig")
+		}
+		logger.Infof("Kubeconfig written to %s", proxyOptions.KubeconfigOutput)
+	}
+
+	connector := ssh.NewConnector()
+	defer connector.CloseAll()
+
+	conn, err := connector.Connect(leader)
+	if err != nil {
+		return errors.Wrapf(err, "failed to connect to %s", leader.PublicAddress)
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", proxyOptions.Port))
+	if err != nil {
+		return errors.Wrap(err, "unable to listen on the local port")
+	}
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	stopped := make(chan struct{})
+	go func() {
+		<-stop
+		close(stopped)
+		listener.Close()
+	}()
+
+	logger.Infof("Proxying 127.0.0.1:%d to the API server on %s…", proxyOptions.Port, leader.PublicAddress)
+	err = ssh.Forward(listener, conn, apiServerAddress, func(err error) {
+		logger.Errorf("Proxying connection failed: %v", err)
+	})
+
+	select {
+	case <-stopped:
+		logger.Infoln("Proxy stopped")
+		return nil
+	default:
+		return errors.Wrap(err, "proxy failed")
+	}
+}
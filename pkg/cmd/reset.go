@@ -29,6 +29,7 @@ type resetOptions struct {
 	globalOptions
 	Manifest       string
 	DestroyWorkers bool
+	ForceDrain     bool
 }
 
 // resetCmd setups reset command
@@ -49,9 +50,11 @@ It's possible to source information about hosts from Terraform output, using the
 				return errors.Wrap(err, "unable to get global flags")
 			}
 
-			logger := initLogger(gopts.Verbose)
+			logger := initLogger(gopts.Verbose, gopts.NoColor)
 			ropts.TerraformState = gopts.TerraformState
 			ropts.Verbose = gopts.Verbose
+			ropts.Debug = gopts.Debug
+			ropts.InCluster = gopts.InCluster
 
 			ropts.Manifest = args[0]
 			if ropts.Manifest == "" {
@@ -63,6 +66,7 @@ It's possible to source information about hosts from Terraform output, using the
 	}
 
 	cmd.Flags().BoolVarP(&ropts.DestroyWorkers, "destroy-workers", "", true, "destroy all worker machines before resetting the cluster")
+	cmd.Flags().BoolVarP(&ropts.ForceDrain, "force-drain", "", false, "ignore PodDisruptionBudgets when draining worker nodes")
 
 	return cmd
 }
@@ -80,7 +84,10 @@ func runReset(logger *logrus.Logger, resetOptions *resetOptions) error {
 
 	options := &installer.Options{
 		Verbose:        resetOptions.Verbose,
+		Debug:          resetOptions.Debug,
+		InCluster:      resetOptions.InCluster,
 		DestroyWorkers: resetOptions.DestroyWorkers,
+		ForceDrain:     resetOptions.ForceDrain,
 	}
 
 	return installer.NewInstaller(cluster, logger).Reset(options)
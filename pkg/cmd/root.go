@@ -19,15 +19,27 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/kubermatic/kubeone/pkg/util"
+
 	apiextensionsscheme "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/scheme"
 	"k8s.io/client-go/kubernetes/scheme"
 	apiregscheme "k8s.io/kube-aggregator/pkg/client/clientset_generated/clientset/scheme"
 	clusterscheme "sigs.k8s.io/cluster-api/pkg/client/clientset_generated/clientset/scheme"
 )
 
+// exitCodeError makes Execute exit with the given code without printing anything
+type exitCodeError struct {
+	code int
+}
+
+func (e *exitCodeError) Error() string {
+	return fmt.Sprintf("exit code %d", e.code)
+}
+
 // rootCmd is the KubeOne base command
 
 // Execute is the root command entry function
@@ -46,9 +58,23 @@ func Execute() {
 		panic(err)
 	}
 
-	rootCmd := newRoot()
+	metrics := util.NewMetrics()
+	rootCmd := newRoot(metrics)
+
+	cmd, err := rootCmd.ExecuteC()
+	exitErr, isExitErr := err.(*exitCodeError)
+	if metricsFile, _ := rootCmd.PersistentFlags().GetString(globalMetricsFileFlagName); metricsFile != "" && cmd != rootCmd {
+		operation := strings.TrimPrefix(cmd.CommandPath(), rootCmd.Name()+" ")
+		if werr := metrics.WriteFile(metricsFile, operation, err == nil || isExitErr); werr != nil {
+			fmt.Println(werr)
+		}
+	}
+
+	if isExitErr {
+		os.Exit(exitErr.code)
+	}
 
-	if err := rootCmd.Execute(); err != nil {
+	if err != nil {
 		debug, _ := rootCmd.PersistentFlags().GetBool(globalDebugFlagName)
 		if debug {
 			fmt.Printf("%+v\n", err)
@@ -59,7 +85,7 @@ func Execute() {
 	}
 }
 
-func newRoot() *cobra.Command {
+func newRoot(metrics *util.Metrics) *cobra.Command {
 	opts := &globalOptions{}
 	rootCmd := &cobra.Command{
 		Use:   "kubeone",
@@ -73,16 +99,31 @@ func newRoot() *cobra.Command {
 	fs := rootCmd.PersistentFlags()
 
 	fs.StringVarP(&opts.TerraformState, globalTerraformFlagName, "t", "", "path to terraform output JSON or - for stdin")
-	fs.BoolVarP(&opts.Verbose, globalVerboseFlagName, "v", false, "verbose")
-	fs.BoolVarP(&opts.Debug, globalDebugFlagName, "d", false, "debug")
+	fs.BoolVarP(&opts.Verbose, globalVerboseFlagName, "v", false, "verbose, streams the output of the commands run over SSH")
+	fs.BoolVarP(&opts.Debug, globalDebugFlagName, "d", false, "debug, implies verbose and prints the commands run over SSH")
+	fs.StringVar(&opts.MetricsFile, globalMetricsFileFlagName, "", "path to write Prometheus metrics about the command duration and result to")
+	fs.BoolVar(&opts.NoColor, globalNoColorFlagName, false, "disable colored output, also disabled by setting the NO_COLOR environment variable")
+	fs.BoolVar(&opts.InCluster, globalInClusterFlagName, false, "access the Kubernetes API with the service account of the pod kubeone runs in, instead of the kubeconfig downloaded from the control plane")
 
 	rootCmd.AddCommand(
-		installCmd(fs),
+		installCmd(fs, metrics),
+		applyCmd(fs, metrics),
+		planCmd(fs),
+		diffCmd(fs),
 		upgradeCmd(fs),
 		resetCmd(fs),
 		kubeconfigCmd(fs),
 		configCmd(fs),
+		generateConfigCmd(fs),
+		machineControllerCmd(fs),
+		addonCmd(fs),
+		nodeCmd(fs),
+		execCmd(fs),
+		proxyCmd(fs),
+		debugCmd(fs),
+		mirrorCmd(fs),
 		versionCmd(fs),
+		completionCmd(fs),
 	)
 
 	return rootCmd
@@ -17,6 +17,8 @@ limitations under the License.
 package cmd
 
 import (
+	"os"
+
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/pflag"
@@ -26,9 +28,12 @@ import (
 )
 
 const (
-	globalTerraformFlagName = "tfjson"
-	globalVerboseFlagName   = "verbose"
-	globalDebugFlagName     = "debug"
+	globalTerraformFlagName   = "tfjson"
+	globalVerboseFlagName     = "verbose"
+	globalDebugFlagName       = "debug"
+	globalMetricsFileFlagName = "metrics-file"
+	globalNoColorFlagName     = "no-color"
+	globalInClusterFlagName   = "in-cluster"
 )
 
 // globalOptions are global globalOptions same for all commands
@@ -36,6 +41,9 @@ type globalOptions struct {
 	TerraformState string
 	Verbose        bool
 	Debug          bool
+	MetricsFile    string
+	NoColor        bool
+	InCluster      bool
 }
 
 func persistentGlobalOptions(fs *pflag.FlagSet) (*globalOptions, error) {
@@ -44,22 +52,42 @@ func persistentGlobalOptions(fs *pflag.FlagSet) (*globalOptions, error) {
 		return nil, errors.WithStack(err)
 	}
 
+	debug, err := fs.GetBool(globalDebugFlagName)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
 	tfjson, err := fs.GetString(globalTerraformFlagName)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
 
+	noColor, err := fs.GetBool(globalNoColorFlagName)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	inCluster, err := fs.GetBool(globalInClusterFlagName)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
 	return &globalOptions{
-		Verbose:        verbose,
+		Verbose:        verbose || debug,
+		Debug:          debug,
 		TerraformState: tfjson,
+		// NO_COLOR disables colors when set to any value, see https://no-color.org
+		NoColor:   noColor || os.Getenv("NO_COLOR") != "",
+		InCluster: inCluster,
 	}, nil
 }
 
-func initLogger(verbose bool) *logrus.Logger {
+func initLogger(verbose, noColor bool) *logrus.Logger {
 	logger := logrus.New()
 	logger.Formatter = &logrus.TextFormatter{
 		FullTimestamp:   true,
 		TimestampFormat: "15:04:05 MST",
+		DisableColors:   noColor,
 	}
 
 	if verbose {
@@ -77,3 +105,14 @@ func loadClusterConfig(filename, terraformOutputPath string) (*kubeoneapi.KubeOn
 
 	return a, nil
 }
+
+// loadClusterConfigOverlays loads the cluster from a base manifest and the
+// overlays overriding it
+func loadClusterConfigOverlays(filenames []string, terraformOutputPath string) (*kubeoneapi.KubeOneCluster, error) {
+	a, err := config.LoadKubeOneClusterOverlays(filenames, terraformOutputPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to load a given KubeOneCluster object")
+	}
+
+	return a, nil
+}
@@ -31,6 +31,7 @@ type upgradeOptions struct {
 	ForceUpgrade              bool
 	Manifest                  string
 	UpgradeMachineDeployments bool
+	IncludeOSPackages         bool
 }
 
 func upgradeCmd(rootFlags *pflag.FlagSet) *cobra.Command {
@@ -41,7 +42,13 @@ func upgradeCmd(rootFlags *pflag.FlagSet) *cobra.Command {
 		Long: `Upgrade Kubernetes
 
 This command takes KubeOne manifest which contains information about hosts and how the cluster should be provisioned.
-It's possible to source information about hosts from Terraform output, using the '--tfjson' flag.`,
+It's possible to source information about hosts from Terraform output, using the '--tfjson' flag.
+
+Before upgrading, the cluster must be healthy: all nodes must be ready, etcd members healthy and no
+PodDisruptionBudget violated. The requested version must not skip a minor version.
+
+With '--include-os-packages', the operating system packages of the control plane nodes are upgraded
+while the nodes are drained. The upgrade can be limited to the packages listed in 'osUpgrade.packages'.`,
 		Args:    cobra.ExactArgs(1),
 		Example: `kubeone upgrade mycluster.yaml -t terraformoutput.json`,
 		RunE: func(_ *cobra.Command, args []string) error {
@@ -50,9 +57,11 @@ It's possible to source information about hosts from Terraform output, using the
 				return errors.Wrap(err, "unable to get global flags")
 			}
 
-			logger := initLogger(gopts.Verbose)
+			logger := initLogger(gopts.Verbose, gopts.NoColor)
 			uopts.TerraformState = gopts.TerraformState
 			uopts.Verbose = gopts.Verbose
+			uopts.Debug = gopts.Debug
+			uopts.InCluster = gopts.InCluster
 
 			uopts.Manifest = args[0]
 			if uopts.Manifest == "" {
@@ -65,6 +74,7 @@ It's possible to source information about hosts from Terraform output, using the
 
 	cmd.Flags().BoolVarP(&uopts.ForceUpgrade, "force", "f", false, "force start upgrade process")
 	cmd.Flags().BoolVarP(&uopts.UpgradeMachineDeployments, "upgrade-machine-deployments", "", false, "upgrade MachineDeployments objects")
+	cmd.Flags().BoolVarP(&uopts.IncludeOSPackages, "include-os-packages", "", false, "upgrade operating system packages of the control plane nodes")
 
 	return cmd
 }
@@ -84,6 +94,9 @@ func createUpgradeOptions(options *upgradeOptions) *upgrader.Options {
 	return &upgrader.Options{
 		ForceUpgrade:              options.ForceUpgrade,
 		Verbose:                   options.Verbose,
+		Debug:                     options.Debug,
+		InCluster:                 options.InCluster,
 		UpgradeMachineDeployments: options.UpgradeMachineDeployments,
+		UpgradeOSPackages:         options.IncludeOSPackages,
 	}
 }
@@ -22,8 +22,11 @@ import (
 	"os"
 	"runtime"
 	"strconv"
+	"strings"
 
 	"github.com/Masterminds/semver"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 
@@ -36,8 +39,17 @@ var (
 	commit  = "none"
 	date    = "unknown"
 	version = "dev"
+
+	// supportedKubernetesVersions is the comma separated list of the
+	// Kubernetes versions KubeOne is compatible with, either minor versions
+	// matching all their patch releases or exact versions
+	supportedKubernetesVersions = "1.13,1.14"
 )
 
+// SupportedKubernetesVersions lists the Kubernetes versions KubeOne is
+// compatible with, as embedded by the build system
+var SupportedKubernetesVersions = strings.Split(supportedKubernetesVersions, ",")
+
 type kubeoneVersions struct {
 	Kubeone           k8sversion.Info `json:"kubeone"`
 	MachineController k8sversion.Info `json:"machine_controller"`
@@ -89,3 +101,50 @@ func versionCmd(_ *pflag.FlagSet) *cobra.Command {
 
 	return cmd
 }
+
+// kubernetesVersionSupported returns whether the Kubernetes version matches
+// any of the supported ones
+func kubernetesVersionSupported(kubernetesVersion string, supported []string) bool {
+	v, err := semver.NewVersion(kubernetesVersion)
+	if err != nil {
+		return false
+	}
+
+	for _, s := range supported {
+		sv, err := semver.NewVersion(strings.TrimSpace(s))
+		if err != nil {
+			continue
+		}
+
+		// minor versions, e.g. 1.14, match all their patch releases
+		if strings.Count(strings.TrimSpace(s), ".") == 1 {
+			if v.Major() == sv.Major() && v.Minor() == sv.Minor() {
+				return true
+			}
+			continue
+		}
+
+		if v.Equal(sv) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkKubernetesVersion warns about Kubernetes versions KubeOne isn't
+// compatible with, or fails if strict is set
+func checkKubernetesVersion(logger *logrus.Logger, kubernetesVersion string, strict bool) error {
+	if kubernetesVersionSupported(kubernetesVersion, SupportedKubernetesVersions) {
+		return nil
+	}
+
+	msg := fmt.Sprintf("Kubernetes %s is not supported by this KubeOne release, supported versions are %s",
+		kubernetesVersion, strings.Join(SupportedKubernetesVersions, ", "))
+	if strict {
+		return errors.New(msg)
+	}
+
+	logger.Warnln(msg)
+	return nil
+}
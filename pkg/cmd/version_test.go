@@ -0,0 +1,112 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+)
+
+func TestKubernetesVersionSupported(t *testing.T) {
+	tests := []struct {
+		name              string
+		kubernetesVersion string
+		supported         []string
+		expected          bool
+	}{
+		{
+			name:              "minor version",
+			kubernetesVersion: "1.14",
+			supported:         []string{"1.13", "1.14"},
+			expected:          true,
+		},
+		{
+			name:              "patch release of a supported minor version",
+			kubernetesVersion: "1.14.1",
+			supported:         []string{"1.13", "1.14"},
+			expected:          true,
+		},
+		{
+			name:              "unsupported minor version",
+			kubernetesVersion: "1.15.0",
+			supported:         []string{"1.13", "1.14"},
+			expected:          false,
+		},
+		{
+			name:              "version with v prefix",
+			kubernetesVersion: "v1.14.1",
+			supported:         []string{"1.14"},
+			expected:          true,
+		},
+		{
+			name:              "supported version with v prefix",
+			kubernetesVersion: "1.14.1",
+			supported:         []string{"v1.14"},
+			expected:          true,
+		},
+		{
+			name:              "exact version",
+			kubernetesVersion: "1.14.1",
+			supported:         []string{"1.14.1"},
+			expected:          true,
+		},
+		{
+			name:              "other patch release of an exact version",
+			kubernetesVersion: "1.14.2",
+			supported:         []string{"1.14.1"},
+			expected:          false,
+		},
+		{
+			name:              "supported versions with spaces",
+			kubernetesVersion: "1.14.1",
+			supported:         []string{"1.13", " 1.14 "},
+			expected:          true,
+		},
+		{
+			name:              "malformed supported versions are skipped",
+			kubernetesVersion: "1.14.1",
+			supported:         []string{"", "1.x", "latest", "1.14"},
+			expected:          true,
+		},
+		{
+			name:              "only malformed supported versions",
+			kubernetesVersion: "1.14.1",
+			supported:         []string{"", "1.x", "latest"},
+			expected:          false,
+		},
+		{
+			name:              "malformed kubernetes version",
+			kubernetesVersion: "1.14.x",
+			supported:         []string{"1.14"},
+			expected:          false,
+		},
+		{
+			name:              "empty kubernetes version",
+			kubernetesVersion: "",
+			supported:         []string{"1.14"},
+			expected:          false,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if got := kubernetesVersionSupported(tc.kubernetesVersion, tc.supported); got != tc.expected {
+				t.Errorf("kubernetesVersionSupported(%q, %q) = %v, expected %v", tc.kubernetesVersion, tc.supported, got, tc.expected)
+			}
+		})
+	}
+}
@@ -23,11 +23,46 @@ import (
 	"strconv"
 
 	"github.com/pkg/errors"
-	yaml "gopkg.in/yaml.v2"
 
 	"github.com/kubermatic/kubeone/pkg/util/yamled"
 )
 
+// currentAPIVersion is the apiVersion manifests are migrated to
+const currentAPIVersion = "kubeone.io/v1alpha1"
+
+// manifestMigrations maps the apiVersion of a manifest to the function
+// migrating it to the next apiVersion. Pre-v0.6.0 manifests don't have
+// the apiVersion field.
+var manifestMigrations = map[string]func(*yamled.Document) error{
+	"": migrateLegacyManifest,
+}
+
+// MigrateManifest migrates the manifest of any supported version to the
+// current KubeOneCluster API. Manifests already using the current API are
+// returned unchanged.
+func MigrateManifest(configPath string) (interface{}, error) {
+	doc, err := loadClusterConfig(configPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse the config")
+	}
+
+	for {
+		apiVersion, _ := doc.GetString(yamled.Path{"apiVersion"})
+		if apiVersion == currentAPIVersion {
+			return doc.Root(), nil
+		}
+
+		migrate, ok := manifestMigrations[apiVersion]
+		if !ok {
+			return nil, errors.Errorf("unable to migrate manifests with apiVersion %q", apiVersion)
+		}
+
+		if err := migrate(doc); err != nil {
+			return nil, errors.Wrapf(err, "unable to migrate the manifest from apiVersion %q", apiVersion)
+		}
+	}
+}
+
 // MigrateToKubeOneClusterAPI migrates the old API manifest to the new KubeOneCluster API
 func MigrateToKubeOneClusterAPI(oldConfigPath string) (interface{}, error) {
 	oldConfig, err := loadClusterConfig(oldConfigPath)
@@ -35,6 +70,16 @@ func MigrateToKubeOneClusterAPI(oldConfigPath string) (interface{}, error) {
 		return nil, errors.Wrap(err, "unable to parse the old config")
 	}
 
+	if err := migrateLegacyManifest(oldConfig); err != nil {
+		return nil, err
+	}
+
+	return oldConfig.Root(), nil
+}
+
+// migrateLegacyManifest migrates the pre-v0.6.0 manifest, including the
+// v0.5.0 features API, to the KubeOneCluster API
+func migrateLegacyManifest(oldConfig *yamled.Document) error {
 	oldConfig.Set(yamled.Path{"apiVersion"}, "kubeone.io/v1alpha1")
 	oldConfig.Set(yamled.Path{"kind"}, "KubeOneCluster")
 
@@ -72,7 +117,7 @@ func MigrateToKubeOneClusterAPI(oldConfigPath string) (interface{}, error) {
 
 		port, err := strconv.Atoi(sport)
 		if err != nil {
-			return yaml.MapSlice{}, fmt.Errorf("invalid port specified for API server: %d", port)
+			return fmt.Errorf("invalid port specified for API server: %d", port)
 		}
 
 		oldConfig.Remove(yamled.Path{"apiEndpoint"})
@@ -142,7 +187,7 @@ func MigrateToKubeOneClusterAPI(oldConfigPath string) (interface{}, error) {
 		}
 	}
 
-	return oldConfig.Root(), nil
+	return nil
 }
 
 // loadClusterConfig takes path to the Cluster Config (old API) and returns yamled.Document
@@ -99,6 +99,52 @@ func TestMigrateToKubeOneClusterAPI(t *testing.T) {
 	}
 }
 
+func TestMigrateManifest(t *testing.T) {
+	testcases := []struct {
+		name          string
+		manifest      string
+		golden        string
+		expectedError bool
+	}{
+		{
+			name:     "pre-v0.6.0 manifest",
+			manifest: "config-features-0.5.0.yaml",
+			golden:   "config-features-0.5.0",
+		},
+		{
+			name:     "current manifest is left unchanged",
+			manifest: "config-features-0.5.0.yaml.golden",
+			golden:   "config-features-0.5.0",
+		},
+		{
+			name:          "unknown apiVersion",
+			manifest:      "config-unknown-version.yaml",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			newConfigYAML, err := MigrateManifest(filepath.Join("testdata", tc.manifest))
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("expected error = %v, but got %v", tc.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+
+			var buffer bytes.Buffer
+			err = yaml.NewEncoder(&buffer).Encode(newConfigYAML)
+			if err != nil {
+				t.Fatalf("unable to encode yaml: %v", err)
+			}
+
+			compareOutput(t, tc.golden, buffer.Bytes(), false)
+		})
+	}
+}
+
 func compareOutput(t *testing.T, name string, output []byte, update bool) {
 	golden, err := filepath.Abs(filepath.Join("testdata", name+".yaml.golden"))
 	if err != nil {
@@ -0,0 +1,136 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package installation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	kubeadmv1beta1 "github.com/kubermatic/kubeone/pkg/apis/kubeadm/v1beta1"
+	"github.com/kubermatic/kubeone/pkg/certificate"
+	"github.com/kubermatic/kubeone/pkg/task"
+	"github.com/kubermatic/kubeone/pkg/templates/machinecontroller"
+	"github.com/kubermatic/kubeone/pkg/util"
+	"github.com/kubermatic/kubeone/pkg/util/credentials"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	kubeadmConfigMapName    = "kubeadm-config"
+	kubeadmClusterConfigKey = "ClusterConfiguration"
+)
+
+// Adopt performs all the steps required to manage an already running
+// cluster, which was not provisioned by KubeOne. Control plane is left
+// untouched, only machine-controller and worker machines are reconciled.
+func Adopt(ctx *util.Context) error {
+	adoptSteps := []task.Task{
+		{Fn: util.BuildKubernetesClientset, ErrMsg: "unable to build kubernetes clientset", Retries: 3},
+		{Fn: compareClusterConfig, ErrMsg: "unable to compare cluster configuration"},
+		{Fn: saveKubeconfig, ErrMsg: "unable to save kubeconfig to the local machine", Retries: 3},
+		{Fn: certificate.DownloadCA, ErrMsg: "unable to download ca from leader", Retries: 3},
+		{Fn: credentials.Ensure, ErrMsg: "unable to ensure credentials secret"},
+		{Fn: machinecontroller.Ensure, ErrMsg: "failed to install machine-controller", Retries: 3},
+		{Fn: machinecontroller.WaitReady, ErrMsg: "failed to wait for machine-controller", Retries: 3},
+		{Fn: createWorkerMachines, ErrMsg: "failed to create worker machines", Retries: 3},
+	}
+
+	for _, step := range adoptSteps {
+		if err := step.Run(ctx); err != nil {
+			return errors.Wrap(err, step.ErrMsg)
+		}
+	}
+
+	return nil
+}
+
+// compareClusterConfig compares the configuration stored by kubeadm in the
+// existing cluster with the manifest and warns about any differences
+func compareClusterConfig(ctx *util.Context) error {
+	ctx.Logger.Infoln("Comparing existing cluster configuration with the manifest…")
+
+	diffs, err := clusterConfigDiff(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range diffs {
+		ctx.Logger.Warnf("Existing cluster %s %q differs from the manifest %q", d.Field, d.Existing, d.Desired)
+	}
+
+	return nil
+}
+
+// clusterConfigDiff returns the differences between the configuration
+// stored by kubeadm in the existing cluster and the manifest
+func clusterConfigDiff(ctx *util.Context) ([]util.Difference, error) {
+	clusterConfig, err := existingClusterConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster := ctx.Cluster
+	controlPlaneEndpoint := fmt.Sprintf("%s:%d", cluster.APIEndpoint.Host, cluster.APIEndpoint.Port)
+
+	diffs := []struct {
+		field    string
+		existing string
+		desired  string
+	}{
+		{"kubernetes version", strings.TrimPrefix(clusterConfig.KubernetesVersion, "v"), strings.TrimPrefix(cluster.Versions.Kubernetes, "v")},
+		{"control plane endpoint", clusterConfig.ControlPlaneEndpoint, controlPlaneEndpoint},
+		{"pod subnet", clusterConfig.Networking.PodSubnet, cluster.ClusterNetwork.PodSubnet},
+		{"service subnet", clusterConfig.Networking.ServiceSubnet, cluster.ClusterNetwork.ServiceSubnet},
+	}
+
+	var result []util.Difference
+	for _, d := range diffs {
+		if d.existing != d.desired {
+			result = append(result, util.Difference{
+				Resource: "kubeadm ClusterConfiguration",
+				Field:    d.field,
+				Existing: d.existing,
+				Desired:  d.desired,
+			})
+		}
+	}
+
+	return result, nil
+}
+
+// existingClusterConfig returns the configuration stored by kubeadm in the
+// existing cluster
+func existingClusterConfig(ctx *util.Context) (*kubeadmv1beta1.ClusterConfiguration, error) {
+	cm := &corev1.ConfigMap{}
+	key := types.NamespacedName{Namespace: "kube-system", Name: kubeadmConfigMapName}
+	if err := ctx.DynamicClient.Get(context.Background(), key, cm); err != nil {
+		return nil, errors.Wrap(err, "unable to get kubeadm-config ConfigMap")
+	}
+
+	clusterConfig := &kubeadmv1beta1.ClusterConfiguration{}
+	if err := yaml.Unmarshal([]byte(cm.Data[kubeadmClusterConfigKey]), clusterConfig); err != nil {
+		return nil, errors.Wrap(err, "unable to parse kubeadm ClusterConfiguration")
+	}
+
+	return clusterConfig, nil
+}
@@ -0,0 +1,254 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package installation
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
+	"github.com/kubermatic/kubeone/pkg/certificate"
+	"github.com/kubermatic/kubeone/pkg/ssh"
+	"github.com/kubermatic/kubeone/pkg/task"
+	"github.com/kubermatic/kubeone/pkg/templates/machinecontroller"
+	"github.com/kubermatic/kubeone/pkg/util"
+	"github.com/kubermatic/kubeone/pkg/util/credentials"
+)
+
+// names of the subsystems reconciled by apply
+const (
+	subsystemControlPlane      = "control plane"
+	subsystemMachineController = "machine-controller"
+	subsystemWorkers           = "workers"
+)
+
+// subsystem is a part of the cluster compared with the manifest and
+// reconciled independently of the other ones
+type subsystem struct {
+	name      string
+	diff      func(*util.Context) ([]util.Difference, error)
+	reconcile func(*util.Context, []util.Difference) error
+}
+
+// Apply reconciles the cluster with the manifest. Every subsystem is
+// compared with the manifest first and only the differing ones are
+// reconciled, e.g. changing only the workers doesn't touch the control
+// plane. A cluster that can't be reached is installed from scratch.
+//
+// With a target host, only the control plane is reconciled, on the target
+// host, even if it matches the manifest.
+func Apply(ctx *util.Context) error {
+	if err := util.BuildKubernetesClientset(ctx); err != nil {
+		if ctx.TargetHost != "" && !isLeader(ctx.Cluster, ctx.TargetHost) {
+			return errors.Wrap(err, "unable to access the cluster, it can only be installed on the first host")
+		}
+		ctx.Logger.Warnf("Unable to access the cluster, installing it: %v", err)
+		if err = approveApplyPlan(ctx, installPlan(ctx)); err != nil {
+			return err
+		}
+		return Install(ctx)
+	}
+
+	subsystems := []subsystem{
+		{name: subsystemControlPlane, diff: controlPlaneDiff, reconcile: reconcileControlPlane},
+		{name: subsystemMachineController, diff: machinecontroller.ControllerDiff, reconcile: reconcileMachineController},
+		{name: subsystemWorkers, diff: workersDiff, reconcile: reconcileWorkers},
+	}
+	if ctx.TargetHost != "" {
+		ctx.Logger.Infof("Only reconciling the %s on %s", subsystemControlPlane, ctx.TargetHost)
+		subsystems = subsystems[:1]
+	}
+
+	// compare everything before changing anything, so a subsystem that
+	// can't be compared doesn't leave the cluster partially reconciled
+	diffs := make([][]util.Difference, len(subsystems))
+	for i, s := range subsystems {
+		d, err := s.diff(ctx)
+		if err != nil {
+			return errors.Wrapf(err, "unable to compare %s", s.name)
+		}
+		diffs[i] = d
+	}
+
+	if err := approveApplyPlan(ctx, reconcilePlan(subsystems, diffs)); err != nil {
+		return err
+	}
+
+	for i, s := range subsystems {
+		if len(diffs[i]) == 0 && ctx.TargetHost == "" {
+			ctx.Logger.Infof("The %s matches the manifest, skipping it", s.name)
+			continue
+		}
+
+		ctx.Logger.Infof("Reconciling the %s…", s.name)
+		if err := s.reconcile(ctx, diffs[i]); err != nil {
+			return errors.Wrapf(err, "failed to reconcile %s", s.name)
+		}
+	}
+
+	return nil
+}
+
+// isLeader returns whether the host with the given public address is the
+// leader of the cluster
+func isLeader(cluster *kubeoneapi.KubeOneCluster, address string) bool {
+	leader, err := cluster.Leader()
+	return err == nil && leader.PublicAddress == address
+}
+
+func controlPlaneDiff(ctx *util.Context) ([]util.Difference, error) {
+	diffs, err := clusterConfigDiff(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to compare cluster configuration")
+	}
+
+	nodeDiffs, err := controlPlaneNodesDiff(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to compare control plane nodes")
+	}
+
+	return append(diffs, nodeDiffs...), nil
+}
+
+func workersDiff(ctx *util.Context) ([]util.Difference, error) {
+	diffs, err := machinecontroller.MachineDeploymentsDiff(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ctx.Cluster.MachineController.Deploy || ctx.SkipWorkers {
+		return diffs, nil
+	}
+
+	removed, err := removedMachineDeployments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(diffs, removed...), nil
+}
+
+// reconcileControlPlane joins the control plane nodes missing from the
+// cluster. Changed fields, such as the Kubernetes version, can't be
+// reconciled by apply and removed nodes are left for the operator.
+func reconcileControlPlane(ctx *util.Context, diffs []util.Difference) error {
+	// the target host is provisioned even if it's already joined
+	join := ctx.TargetHost != ""
+	for _, d := range diffs {
+		switch {
+		case d.Removed:
+			ctx.Logger.Warnf("%s is not in the manifest, it must be removed manually", d.Resource)
+		case d.Field != "":
+			return errors.Errorf("%s %s %q differs from the manifest %q, use the upgrade command to change it",
+				d.Resource, d.Field, d.Existing, d.Desired)
+		default:
+			join = true
+		}
+	}
+
+	if !join {
+		return nil
+	}
+
+	// the steps are safe to run on the existing nodes, kubeadm join is
+	// skipped on the already joined ones
+	steps := []task.Task{
+		{Fn: util.DetermineOS, ErrMsg: "failed to determine operating systems"},
+		{Fn: installPrerequisites, ErrMsg: "failed to install prerequisites"},
+		{Fn: generateKubeadm, ErrMsg: "failed to generate kubeadm config files"},
+		{Fn: createJoinToken, ErrMsg: "failed to create bootstrap token", Retries: 3},
+		{Fn: certificate.DownloadCA, ErrMsg: "unable to download ca from leader", Retries: 3},
+		{Fn: deployCA, ErrMsg: "unable to deploy ca on nodes", Retries: 3},
+		{Fn: kubeadmCertsOnFollower, ErrMsg: "failed to provision certs and etcd on followers"},
+		{Fn: joinControlplaneNode, ErrMsg: "unable to join other masters a cluster"},
+		{Fn: copyKubeconfig, ErrMsg: "unable to copy kubeconfig to home directory", Retries: 3},
+	}
+
+	for _, step := range steps {
+		if err := step.Run(ctx); err != nil {
+			return errors.Wrap(err, step.ErrMsg)
+		}
+	}
+
+	return nil
+}
+
+// createJoinToken registers the bootstrap token of the generated kubeadm
+// configs in the running cluster, so new nodes can join it. The token may
+// already exist if a previous apply failed after creating it.
+func createJoinToken(ctx *util.Context) error {
+	return ctx.RunTaskOnLeader(func(ctx *util.Context, _ *kubeoneapi.HostConfig, _ ssh.Connection) error {
+		ctx.Logger.Infoln("Creating bootstrap token…")
+
+		_, _, err := ctx.Runner.Run(`
+if sudo kubeadm token list | grep -q "{{ .TOKEN }}"; then exit 0; fi
+sudo kubeadm token create "{{ .TOKEN }}" --ttl 1h
+`, util.TemplateVariables{
+			"TOKEN": ctx.JoinToken,
+		})
+
+		return err
+	})
+}
+
+func reconcileMachineController(ctx *util.Context, _ []util.Difference) error {
+	steps := []task.Task{
+		{Fn: certificate.DownloadCA, ErrMsg: "unable to download ca from leader", Retries: 3},
+		{Fn: credentials.Ensure, ErrMsg: "unable to ensure credentials secret"},
+		{Fn: machinecontroller.Ensure, ErrMsg: "failed to install machine-controller", Retries: 3},
+		{Fn: machinecontroller.WaitReady, ErrMsg: "failed to wait for machine-controller", Retries: 3},
+	}
+
+	for _, step := range steps {
+		if err := step.Run(ctx); err != nil {
+			return errors.Wrap(err, step.ErrMsg)
+		}
+	}
+
+	return nil
+}
+
+// reconcileWorkers creates or updates the MachineDeployments of the
+// manifest, the ones created by KubeOne and removed from it are deleted
+// only if pruning is enabled
+func reconcileWorkers(ctx *util.Context, _ []util.Difference) error {
+	if err := createWorkerMachines(ctx); err != nil {
+		return err
+	}
+
+	mds, err := unmanagedMachineDeployments(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !ctx.Prune {
+		for _, md := range mds {
+			ctx.Logger.Warnf("MachineDeployment %s/%s is not in the manifest, use --prune to delete it", md.Namespace, md.Name)
+		}
+		return nil
+	}
+
+	for i := range mds {
+		ctx.Logger.Infof("Deleting MachineDeployment %s/%s…", mds[i].Namespace, mds[i].Name)
+		if err := ctx.DynamicClient.Delete(context.Background(), &mds[i]); err != nil {
+			return errors.Wrapf(err, "unable to delete MachineDeployment %s/%s", mds[i].Namespace, mds[i].Name)
+		}
+	}
+
+	return nil
+}
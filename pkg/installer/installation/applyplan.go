@@ -0,0 +1,209 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package installation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/kubermatic/kubeone/pkg/util"
+)
+
+// ApplyPlanVersion is the version of the apply plan format, increased on
+// incompatible changes
+const ApplyPlanVersion = "v1"
+
+// rough durations of the operations, used to estimate the duration of the
+// apply phases
+const (
+	estimatedInstallPerHost      = 5 * time.Minute
+	estimatedJoinPerHost         = 5 * time.Minute
+	estimatedMachineController   = 3 * time.Minute
+	estimatedWorkersPerResource  = 10 * time.Minute
+	estimatedInstallMachineSetup = estimatedMachineController + estimatedWorkersPerResource
+)
+
+// ApplyPlan describes the phases apply is about to run, written as JSON
+// before changing the cluster, e.g. for CI systems to require an approval
+type ApplyPlan struct {
+	Version string           `json:"version"`
+	Phases  []ApplyPlanPhase `json:"phases"`
+}
+
+// ApplyPlanPhase is a single phase of apply
+type ApplyPlanPhase struct {
+	Name string `json:"name"`
+	// Hosts are the addresses of the affected hosts of the manifest
+	Hosts []string `json:"hosts,omitempty"`
+	// Changes describe the differences between the cluster and the manifest
+	// reconciled by the phase
+	Changes []ApplyPlanChange `json:"changes,omitempty"`
+	// EstimatedDuration is a rough estimate, e.g. "10m0s"
+	EstimatedDuration string `json:"estimatedDuration"`
+}
+
+// ApplyPlanChange is a single difference reconciled by a phase
+type ApplyPlanChange struct {
+	Resource string `json:"resource"`
+	Action   string `json:"action"`
+	Field    string `json:"field,omitempty"`
+	Existing string `json:"existing,omitempty"`
+	Desired  string `json:"desired,omitempty"`
+}
+
+// installPlan is the plan of installing a cluster which can't be accessed
+func installPlan(ctx *util.Context) *ApplyPlan {
+	var hosts []string
+	for _, host := range ctx.Cluster.Hosts {
+		hosts = append(hosts, host.PublicAddress)
+	}
+
+	duration := time.Duration(len(hosts)) * estimatedInstallPerHost
+	if ctx.Cluster.MachineController.Deploy {
+		duration += estimatedInstallMachineSetup
+	}
+
+	return &ApplyPlan{
+		Version: ApplyPlanVersion,
+		Phases: []ApplyPlanPhase{{
+			Name:              "install",
+			Hosts:             hosts,
+			EstimatedDuration: duration.String(),
+		}},
+	}
+}
+
+// reconcilePlan is the plan of reconciling the differing subsystems
+func reconcilePlan(subsystems []subsystem, diffs [][]util.Difference) *ApplyPlan {
+	plan := &ApplyPlan{Version: ApplyPlanVersion, Phases: []ApplyPlanPhase{}}
+
+	for i, s := range subsystems {
+		if len(diffs[i]) == 0 {
+			continue
+		}
+
+		phase := ApplyPlanPhase{Name: s.name}
+		var duration time.Duration
+		for _, d := range diffs[i] {
+			phase.Changes = append(phase.Changes, planChange(d))
+
+			switch s.name {
+			case subsystemControlPlane:
+				// only missing nodes are joined
+				if d.Field == "" && !d.Removed {
+					phase.Hosts = append(phase.Hosts, strings.TrimPrefix(d.Resource, "Node "))
+					duration += estimatedJoinPerHost
+				}
+			case subsystemWorkers:
+				duration += estimatedWorkersPerResource
+			}
+		}
+		if s.name == subsystemMachineController {
+			duration = estimatedMachineController
+		}
+
+		phase.EstimatedDuration = duration.String()
+		plan.Phases = append(plan.Phases, phase)
+	}
+
+	return plan
+}
+
+func planChange(d util.Difference) ApplyPlanChange {
+	change := ApplyPlanChange{
+		Resource: d.Resource,
+		Field:    d.Field,
+		Existing: d.Existing,
+		Desired:  d.Desired,
+	}
+
+	switch {
+	case d.Removed:
+		change.Action = "delete"
+	case d.Field == "":
+		change.Action = "create"
+	default:
+		change.Action = "update"
+	}
+
+	return change
+}
+
+// approveApplyPlan writes the plan if a plan file is given and asks for
+// its approval if required. Plans without any phase are always approved.
+func approveApplyPlan(ctx *util.Context, plan *ApplyPlan) error {
+	if ctx.PlanFile != "" {
+		if err := writeApplyPlan(ctx.PlanFile, plan); err != nil {
+			return err
+		}
+	}
+
+	if ctx.Approve == nil || len(plan.Phases) == 0 {
+		return nil
+	}
+
+	approved, err := ctx.Approve(formatApplyPlan(plan))
+	if err != nil {
+		return errors.Wrap(err, "unable to approve the changes")
+	}
+	if !approved {
+		return errors.New("apply canceled, the changes were not approved")
+	}
+
+	return nil
+}
+
+// formatApplyPlan renders the plan for humans, creations are prefixed with
+// "+", deletions with "-" and updates with "~"
+func formatApplyPlan(plan *ApplyPlan) string {
+	var buf bytes.Buffer
+
+	for _, phase := range plan.Phases {
+		fmt.Fprintf(&buf, "%s (estimated duration %s):\n", phase.Name, phase.EstimatedDuration)
+		if len(phase.Hosts) > 0 {
+			fmt.Fprintf(&buf, "  hosts: %s\n", strings.Join(phase.Hosts, ", "))
+		}
+		for _, c := range phase.Changes {
+			switch c.Action {
+			case "create":
+				fmt.Fprintf(&buf, "  + %s\n", c.Resource)
+			case "delete":
+				fmt.Fprintf(&buf, "  - %s\n", c.Resource)
+			default:
+				fmt.Fprintf(&buf, "  ~ %s %s: %q -> %q\n", c.Resource, c.Field, c.Existing, c.Desired)
+			}
+		}
+	}
+
+	return buf.String()
+}
+
+// writeApplyPlan writes the plan as JSON to the given file
+func writeApplyPlan(path string, plan *ApplyPlan) error {
+	buf, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "unable to encode apply plan")
+	}
+
+	return errors.Wrap(ioutil.WriteFile(path, append(buf, '\n'), 0644), "unable to write apply plan")
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package installation
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kubermatic/kubeone/pkg/util"
+)
+
+func TestReconcilePlan(t *testing.T) {
+	subsystems := []subsystem{
+		{name: subsystemControlPlane},
+		{name: subsystemMachineController},
+		{name: subsystemWorkers},
+	}
+	diffs := [][]util.Difference{
+		{
+			{Resource: "Node 192.0.2.3"},
+			{Resource: "Node old", Removed: true},
+		},
+		nil,
+		{
+			{Resource: "MachineDeployment kube-system/pool1", Field: "replicas", Existing: "1", Desired: "3"},
+		},
+	}
+
+	expected := &ApplyPlan{
+		Version: ApplyPlanVersion,
+		Phases: []ApplyPlanPhase{
+			{
+				Name:  subsystemControlPlane,
+				Hosts: []string{"192.0.2.3"},
+				Changes: []ApplyPlanChange{
+					{Resource: "Node 192.0.2.3", Action: "create"},
+					{Resource: "Node old", Action: "delete"},
+				},
+				EstimatedDuration: estimatedJoinPerHost.String(),
+			},
+			{
+				Name: subsystemWorkers,
+				Changes: []ApplyPlanChange{
+					{Resource: "MachineDeployment kube-system/pool1", Action: "update", Field: "replicas", Existing: "1", Desired: "3"},
+				},
+				EstimatedDuration: estimatedWorkersPerResource.String(),
+			},
+		},
+	}
+
+	plan := reconcilePlan(subsystems, diffs)
+	if !reflect.DeepEqual(plan, expected) {
+		t.Errorf("expected plan %+v, got %+v", expected, plan)
+	}
+}
+
+func TestFormatApplyPlan(t *testing.T) {
+	plan := &ApplyPlan{
+		Version: ApplyPlanVersion,
+		Phases: []ApplyPlanPhase{
+			{
+				Name:  subsystemControlPlane,
+				Hosts: []string{"192.0.2.3"},
+				Changes: []ApplyPlanChange{
+					{Resource: "Node 192.0.2.3", Action: "create"},
+					{Resource: "Node old", Action: "delete"},
+				},
+				EstimatedDuration: "5m0s",
+			},
+			{
+				Name: subsystemWorkers,
+				Changes: []ApplyPlanChange{
+					{Resource: "MachineDeployment kube-system/pool1", Action: "update", Field: "replicas", Existing: "1", Desired: "3"},
+				},
+				EstimatedDuration: "10m0s",
+			},
+		},
+	}
+
+	expected := `control plane (estimated duration 5m0s):
+  hosts: 192.0.2.3
+  + Node 192.0.2.3
+  - Node old
+workers (estimated duration 10m0s):
+  ~ MachineDeployment kube-system/pool1 replicas: "1" -> "3"
+`
+
+	if got := formatApplyPlan(plan); got != expected {
+		t.Errorf("expected plan:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestApproveApplyPlan(t *testing.T) {
+	plan := &ApplyPlan{
+		Version: ApplyPlanVersion,
+		Phases:  []ApplyPlanPhase{{Name: subsystemWorkers, EstimatedDuration: "10m0s"}},
+	}
+
+	tests := []struct {
+		name          string
+		approve       func(string) (bool, error)
+		plan          *ApplyPlan
+		expectedError bool
+	}{
+		{
+			name: "no approval required",
+			plan: plan,
+		},
+		{
+			name:    "approved",
+			approve: func(string) (bool, error) { return true, nil },
+			plan:    plan,
+		},
+		{
+			name:          "declined",
+			approve:       func(string) (bool, error) { return false, nil },
+			plan:          plan,
+			expectedError: true,
+		},
+		{
+			name:    "nothing to approve",
+			approve: func(string) (bool, error) { return false, nil },
+			plan:    &ApplyPlan{Version: ApplyPlanVersion, Phases: []ApplyPlanPhase{}},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := approveApplyPlan(&util.Context{Approve: tc.approve}, tc.plan)
+			if (err != nil) != tc.expectedError {
+				t.Errorf("expected error %v, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
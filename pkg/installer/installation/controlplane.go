@@ -27,6 +27,8 @@ import (
 
 func joinControlplaneNode(ctx *util.Context) error {
 	ctx.Logger.Infoln("Joining controlplane node…")
+	// nodes are joined one by one regardless of --parallelism, because
+	// each join adds an etcd member and must not endanger the quorum
 	return ctx.RunTaskOnFollowers(joinControlPlaneNodeInternal, false)
 }
 
@@ -34,17 +36,37 @@ func joinControlPlaneNodeInternal(ctx *util.Context, node *kubeoneapi.HostConfig
 	sleepTime := 30 * time.Second
 
 	logger := ctx.Logger.WithField("node", node.PublicAddress)
+	if nodeJoined(ctx, node) {
+		logger.Infoln("Node is already joined, skipping kubeadm join")
+		return nil
+	}
+
 	logger.Infof("Waiting %s to ensure main control plane components are up…", sleepTime)
 	time.Sleep(sleepTime)
 
 	_, _, err := ctx.Runner.Run(`
-if [[ -f /etc/kubernetes/kubelet.conf ]]; then exit 0; fi
-
 sudo kubeadm join \
 	--config=./{{ .WORK_DIR }}/cfg/master_{{ .NODE_ID }}.yaml
 `, util.TemplateVariables{
 		"WORK_DIR": ctx.WorkDir,
 		"NODE_ID":  strconv.Itoa(node.ID),
 	})
+	if err != nil && ctx.Retry && nodeJoined(ctx, node) {
+		logger.Warnf("kubeadm join failed, but the node is joined, continuing: %v", err)
+		return nil
+	}
+
 	return err
 }
+
+// nodeJoined returns whether kubeadm join already ran on the node, i.e. the
+// node has the kubelet kubeconfig and is registered in the cluster with it
+func nodeJoined(ctx *util.Context, node *kubeoneapi.HostConfig) bool {
+	_, _, err := ctx.Runner.Run(`
+sudo test -f /etc/kubernetes/kubelet.conf
+sudo kubectl --kubeconfig=/etc/kubernetes/kubelet.conf get node "{{ .NODE_NAME }}" >/dev/null
+`, util.TemplateVariables{
+		"NODE_NAME": node.Hostname,
+	})
+	return err == nil
+}
@@ -25,22 +25,49 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func patchCoreDNS(ctx *util.Context) error {
-	if !ctx.Cluster.CloudProvider.External {
+	cfg := ctx.Cluster.CoreDNS
+	customized := cfg.Replicas > 0 || cfg.CPURequest != "" || cfg.MemoryRequest != ""
+
+	if !ctx.Cluster.CloudProvider.External && !customized && cfg.Corefile == "" {
 		return nil
 	}
 
-	ctx.Logger.Infoln("Patching coreDNS with uninitialized toleration…")
-
 	if ctx.DynamicClient == nil {
 		return errors.New("kubernetes client not initialized")
 	}
 
 	bgCtx := context.Background()
+
+	if cfg.Corefile != "" {
+		ctx.Logger.Infoln("Patching coreDNS with custom Corefile…")
+
+		cm := &corev1.ConfigMap{}
+		key := client.ObjectKey{
+			Name:      "coredns",
+			Namespace: metav1.NamespaceSystem,
+		}
+
+		if err := ctx.DynamicClient.Get(bgCtx, key, cm); err != nil {
+			return errors.Wrap(err, "failed to get coredns configmap")
+		}
+
+		// CoreDNS reloads the Corefile once it's updated
+		cm.Data["Corefile"] = cfg.Corefile
+		if err := ctx.DynamicClient.Update(bgCtx, cm); err != nil {
+			return errors.Wrap(err, "failed to update coredns configmap")
+		}
+	}
+
+	if !ctx.Cluster.CloudProvider.External && !customized {
+		return nil
+	}
+
 	dep := &appsv1.Deployment{}
 	key := client.ObjectKey{
 		Name:      "coredns",
@@ -52,13 +79,47 @@ func patchCoreDNS(ctx *util.Context) error {
 		return errors.Wrap(err, "failed to get coredns deployment")
 	}
 
-	dep.Spec.Template.Spec.Tolerations = append(dep.Spec.Template.Spec.Tolerations,
-		corev1.Toleration{
-			Key:    "node.cloudprovider.kubernetes.io/uninitialized",
-			Value:  "true",
-			Effect: corev1.TaintEffectNoSchedule,
-		},
-	)
+	if ctx.Cluster.CloudProvider.External {
+		ctx.Logger.Infoln("Patching coreDNS with uninitialized toleration…")
+
+		dep.Spec.Template.Spec.Tolerations = append(dep.Spec.Template.Spec.Tolerations,
+			corev1.Toleration{
+				Key:    "node.cloudprovider.kubernetes.io/uninitialized",
+				Value:  "true",
+				Effect: corev1.TaintEffectNoSchedule,
+			},
+		)
+	}
+
+	if customized {
+		ctx.Logger.Infoln("Patching coreDNS with replicas and resources…")
+
+		if cfg.Replicas > 0 {
+			replicas := cfg.Replicas
+			dep.Spec.Replicas = &replicas
+		}
+
+		for i := range dep.Spec.Template.Spec.Containers {
+			c := &dep.Spec.Template.Spec.Containers[i]
+			if c.Name != "coredns" {
+				continue
+			}
+			if c.Resources.Requests == nil {
+				c.Resources.Requests = corev1.ResourceList{}
+			}
+			if cfg.CPURequest != "" {
+				c.Resources.Requests[corev1.ResourceCPU] = resource.MustParse(cfg.CPURequest)
+			}
+			if cfg.MemoryRequest != "" {
+				req := resource.MustParse(cfg.MemoryRequest)
+				c.Resources.Requests[corev1.ResourceMemory] = req
+				// kubeadm sets a memory limit, which must not be lower than the request
+				if limit, ok := c.Resources.Limits[corev1.ResourceMemory]; ok && limit.Cmp(req) < 0 {
+					c.Resources.Limits[corev1.ResourceMemory] = req
+				}
+			}
+		}
+	}
 
 	return errors.Wrap(ctx.DynamicClient.Update(bgCtx, dep), "failed to update coredns deployment")
 }
@@ -0,0 +1,182 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package installation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+
+	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
+	"github.com/kubermatic/kubeone/pkg/ssh"
+	"github.com/kubermatic/kubeone/pkg/templates"
+	"github.com/kubermatic/kubeone/pkg/templates/machinecontroller"
+	"github.com/kubermatic/kubeone/pkg/util"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1alpha1 "sigs.k8s.io/cluster-api/pkg/apis/cluster/v1alpha1"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// debugBundleLogLines is the number of machine-controller log lines collected
+	debugBundleLogLines = 10000
+
+	redactedValue = "[REDACTED]"
+)
+
+// sensitiveKeys are substrings of the manifest keys holding secrets
+var sensitiveKeys = []string{"password", "token", "secret", "apikey", "accesskey", "cloudconfig", "serviceaccount"}
+
+// DebugBundle collects diagnostic information about the cluster into a .tar.gz
+// archive. The collection is best-effort: failures are recorded in errors.txt,
+// so a bundle is written even if the cluster is only partially reachable.
+func DebugBundle(ctx *util.Context, manifest, output string) error {
+	bundle := util.NewConfiguration()
+	var failures []string
+
+	collect := func(name string, fn func(*util.Context, *util.Configuration) error) {
+		ctx.Logger.Infof("Collecting %s…", name)
+		if err := fn(ctx, bundle); err != nil {
+			ctx.Logger.Warnf("Failed to collect %s: %v", name, err)
+			failures = append(failures, fmt.Sprintf("%s: %v", name, err))
+		}
+	}
+
+	collect("manifest", func(_ *util.Context, bundle *util.Configuration) error {
+		redacted, err := redactManifest(manifest)
+		if err != nil {
+			return err
+		}
+		bundle.AddFile("manifest.yaml", redacted)
+		return nil
+	})
+	collect("node descriptions", collectNodeDescriptions)
+
+	if err := util.BuildKubernetesClientset(ctx); err != nil {
+		ctx.Logger.Warnf("Unable to access the cluster: %v", err)
+		failures = append(failures, fmt.Sprintf("kubernetes clientset: %v", err))
+	} else {
+		collect("machine-controller logs", collectMachineControllerLogs)
+		collect("machines", collectMachines)
+	}
+
+	if len(failures) > 0 {
+		bundle.AddFile("errors.txt", strings.Join(failures, "\n"))
+	}
+
+	// the logs and the node descriptions may contain credentials, e.g. in
+	// error messages or the cloud provider flags
+	bundle.Redact(ctx.Redactor)
+
+	return errors.Wrap(bundle.Backup(output), "failed to write debug bundle")
+}
+
+// collectNodeDescriptions describes all nodes using kubectl on the leader
+func collectNodeDescriptions(ctx *util.Context, bundle *util.Configuration) error {
+	return ctx.RunTaskOnLeader(func(_ *util.Context, _ *kubeoneapi.HostConfig, conn ssh.Connection) error {
+		stdout, stderr, _, err := conn.Exec("sudo kubectl --kubeconfig=/etc/kubernetes/admin.conf describe nodes")
+		if err != nil {
+			return errors.Wrap(err, stderr)
+		}
+		bundle.AddFile("nodes.txt", stdout)
+		return nil
+	})
+}
+
+// collectMachineControllerLogs collects the machine-controller pod logs
+func collectMachineControllerLogs(ctx *util.Context, bundle *util.Configuration) error {
+	logs, err := machinecontroller.GetMachineControllerLogs(context.Background(), ctx.RESTConfig, debugBundleLogLines)
+	if err != nil {
+		return err
+	}
+	bundle.AddFile("machine-controller.log", logs)
+	return nil
+}
+
+// collectMachines dumps the Machine, MachineSet and MachineDeployment objects
+func collectMachines(ctx *util.Context, bundle *util.Configuration) error {
+	lists := []struct {
+		filename string
+		list     runtime.Object
+	}{
+		{"machines.yaml", &clusterv1alpha1.MachineList{}},
+		{"machinesets.yaml", &clusterv1alpha1.MachineSetList{}},
+		{"machinedeployments.yaml", &clusterv1alpha1.MachineDeploymentList{}},
+	}
+
+	listOpts := dynclient.ListOptions{Namespace: metav1.NamespaceSystem}
+	for _, l := range lists {
+		if err := ctx.DynamicClient.List(context.Background(), &listOpts, l.list); err != nil {
+			return errors.Wrapf(err, "unable to list objects for %s", l.filename)
+		}
+
+		content, err := templates.KubernetesToYAML([]interface{}{l.list})
+		if err != nil {
+			return err
+		}
+		bundle.AddFile(l.filename, content)
+	}
+
+	return nil
+}
+
+// redactManifest replaces the values of the manifest keys likely to hold
+// secrets, such as the cloud config and the worker cloud provider credentials
+func redactManifest(manifest string) (string, error) {
+	var doc yaml.MapSlice
+	if err := yaml.Unmarshal([]byte(manifest), &doc); err != nil {
+		return "", errors.Wrap(err, "unable to parse manifest")
+	}
+
+	out, err := yaml.Marshal(redactValue(doc))
+	return string(out), errors.Wrap(err, "unable to encode manifest")
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case yaml.MapSlice:
+		for i := range v {
+			key, _ := v[i].Key.(string)
+			if _, scalar := v[i].Value.(string); scalar && isSensitiveKey(key) {
+				v[i].Value = redactedValue
+				continue
+			}
+			v[i].Value = redactValue(v[i].Value)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redactValue(v[i])
+		}
+	}
+
+	return value
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range sensitiveKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
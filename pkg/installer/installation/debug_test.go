@@ -0,0 +1,60 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package installation
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedactManifest(t *testing.T) {
+	const manifest = `apiVersion: kubeone.io/v1alpha1
+kind: KubeOneCluster
+name: demo
+cloudProvider:
+  name: openstack
+  cloudConfig: |
+    [Global]
+    password = hunter2
+workers:
+- name: pool1
+  replicas: 1
+  providerSpec:
+    cloudProviderSpec:
+      flavor: m1.small
+      password: hunter2
+      applicationCredentialSecret: hunter2
+machineController:
+  credentialsSecretRef:
+    name: machine-controller-credentials
+`
+
+	redacted, err := redactManifest(manifest)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Contains(redacted, "hunter2") {
+		t.Errorf("secrets were not redacted:\n%s", redacted)
+	}
+
+	for _, kept := range []string{"name: demo", "flavor: m1.small", "name: machine-controller-credentials"} {
+		if !strings.Contains(redacted, kept) {
+			t.Errorf("expected %q to be kept:\n%s", kept, redacted)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package installation
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/kubermatic/kubeone/pkg/templates/machinecontroller"
+	"github.com/kubermatic/kubeone/pkg/util"
+)
+
+// Diff compares the state of the existing cluster with the manifest and
+// returns the differences install would reconcile, without changing anything
+func Diff(ctx *util.Context) ([]util.Difference, error) {
+	if err := util.BuildKubernetesClientset(ctx); err != nil {
+		return nil, errors.Wrap(err, "unable to build kubernetes clientset")
+	}
+
+	return diff(ctx)
+}
+
+func diff(ctx *util.Context) ([]util.Difference, error) {
+	diffs, err := clusterConfigDiff(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to compare cluster configuration")
+	}
+
+	mcDiffs, err := machinecontroller.Diff(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to compare machine-controller and worker machines")
+	}
+
+	return append(diffs, mcDiffs...), nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package installation
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/kubermatic/kubeone/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/rest"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const labelControlPlaneNode = "node-role.kubernetes.io/master"
+
+// drainWorkerNodes cordons all worker nodes and evicts their pods using
+// the eviction API, so PodDisruptionBudgets are honored. With ForceDrain
+// the pods are deleted instead, ignoring PodDisruptionBudgets.
+func drainWorkerNodes(ctx *util.Context) error {
+	if ctx.DynamicClient == nil {
+		return errors.New("kubernetes dynamic client is not initialized")
+	}
+
+	var (
+		evictionClient rest.Interface
+		err            error
+	)
+	if !ctx.ForceDrain {
+		evictionClient, err = util.NewEvictionClient(ctx.RESTConfig)
+		if err != nil {
+			return errors.Wrap(err, "unable to build eviction client")
+		}
+	}
+
+	bgCtx := context.Background()
+
+	nodes := corev1.NodeList{}
+	nodeListOpts := dynclient.ListOptions{}
+	if err = nodeListOpts.SetLabelSelector("!" + labelControlPlaneNode); err != nil {
+		return errors.Wrap(err, "failed to set node selector labels")
+	}
+
+	if err = ctx.DynamicClient.List(bgCtx, &nodeListOpts, &nodes); err != nil {
+		return errors.Wrap(err, "unable to list nodes")
+	}
+
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		ctx.Logger.Infof("Draining node %s…", node.Name)
+
+		if !node.Spec.Unschedulable {
+			node.Spec.Unschedulable = true
+			if err = ctx.DynamicClient.Update(bgCtx, node); err != nil {
+				return errors.Wrapf(err, "unable to cordon node %s", node.Name)
+			}
+		}
+
+		if ctx.ForceDrain {
+			if err = util.DeleteNodePods(bgCtx, ctx.DynamicClient, node.Name); err != nil {
+				return errors.Wrapf(err, "unable to drain node %s", node.Name)
+			}
+			continue
+		}
+
+		if err = util.EvictNodePods(bgCtx, ctx.DynamicClient, evictionClient, node.Name); err != nil {
+			return errors.Wrapf(err, "unable to drain node %s, use --force-drain to ignore PodDisruptionBudgets", node.Name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package installation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kubermatic/kubeone/pkg/internal/testclient"
+	"github.com/kubermatic/kubeone/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestForceDrainWorkerNodes(t *testing.T) {
+	controller := true
+	pod := func(name, nodeName string, owners ...metav1.OwnerReference) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, OwnerReferences: owners},
+			Spec:       corev1.PodSpec{NodeName: nodeName},
+		}
+	}
+
+	client := testclient.NewMemoryClient(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker"}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "master", Labels: map[string]string{labelControlPlaneNode: ""}}},
+		pod("app", "worker"),
+		pod("daemon", "worker", metav1.OwnerReference{Kind: "DaemonSet", Name: "daemon", Controller: &controller}),
+		pod("etcd", "master"),
+	)
+
+	ctx := &util.Context{
+		DynamicClient: client,
+		Logger:        logrus.New(),
+		ForceDrain:    true,
+	}
+	if err := drainWorkerNodes(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	node := &corev1.Node{}
+	if err := client.Get(context.Background(), dynclient.ObjectKey{Name: "worker"}, node); err != nil {
+		t.Fatal(err)
+	}
+	if !node.Spec.Unschedulable {
+		t.Error("expected the worker node to be cordoned")
+	}
+
+	for name, kept := range map[string]bool{"app": false, "daemon": true, "etcd": true} {
+		err := client.Get(context.Background(), dynclient.ObjectKey{Namespace: "default", Name: name}, &corev1.Pod{})
+		if kept && err != nil {
+			t.Errorf("expected pod %s to be kept, got %v", name, err)
+		}
+		if !kept && !k8serrors.IsNotFound(err) {
+			t.Errorf("expected pod %s to be deleted", name)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package installation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/kubermatic/kubeone/pkg/templates/machinecontroller"
+	"github.com/kubermatic/kubeone/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1alpha1 "sigs.k8s.io/cluster-api/pkg/apis/cluster/v1alpha1"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// clusterState is the part of the cluster state compared by Drift, either
+// read from the cluster or generated from the manifest
+type clusterState struct {
+	kubernetesVersion      string
+	controlPlaneEndpoint   string
+	podSubnet              string
+	serviceSubnet          string
+	controlPlaneNodes      int
+	workerNodes            int
+	nodeVersions           map[string]string
+	machineControllerImage string
+	machineDeployments     map[string]string
+}
+
+// Drift returns the state of the existing cluster and the state the manifest
+// describes, rendered the same way so they can be compared line by line
+func Drift(ctx *util.Context) (string, string, error) {
+	if err := util.BuildKubernetesClientset(ctx); err != nil {
+		return "", "", errors.Wrap(err, "unable to build kubernetes clientset")
+	}
+
+	existing, err := existingState(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	desired := desiredState(ctx, existing)
+
+	if ctx.Cluster.MachineController.Deploy {
+		existing.machineControllerImage, desired.machineControllerImage, err = machinecontroller.DeployedImages(ctx)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	return existing.render(), desired.render(), nil
+}
+
+func existingState(ctx *util.Context) (*clusterState, error) {
+	clusterConfig, err := existingClusterConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &clusterState{
+		kubernetesVersion:    strings.TrimPrefix(clusterConfig.KubernetesVersion, "v"),
+		controlPlaneEndpoint: clusterConfig.ControlPlaneEndpoint,
+		podSubnet:            clusterConfig.Networking.PodSubnet,
+		serviceSubnet:        clusterConfig.Networking.ServiceSubnet,
+		nodeVersions:         map[string]string{},
+		machineDeployments:   map[string]string{},
+	}
+
+	nodes := corev1.NodeList{}
+	if err := ctx.DynamicClient.List(context.Background(), &dynclient.ListOptions{}, &nodes); err != nil {
+		return nil, errors.Wrap(err, "unable to list nodes")
+	}
+	for _, node := range nodes.Items {
+		if _, ok := node.Labels[labelControlPlaneNode]; ok {
+			state.controlPlaneNodes++
+		} else {
+			state.workerNodes++
+		}
+		state.nodeVersions[node.Name] = strings.TrimPrefix(node.Status.NodeInfo.KubeletVersion, "v")
+	}
+
+	if !ctx.Cluster.MachineController.Deploy {
+		return state, nil
+	}
+
+	mds := clusterv1alpha1.MachineDeploymentList{}
+	listOpts := dynclient.ListOptions{Namespace: metav1.NamespaceSystem}
+	if err := ctx.DynamicClient.List(context.Background(), &listOpts, &mds); err != nil {
+		return nil, errors.Wrap(err, "unable to list MachineDeployments")
+	}
+	for _, md := range mds.Items {
+		replicas := "unset"
+		if md.Spec.Replicas != nil {
+			replicas = fmt.Sprint(*md.Spec.Replicas)
+		}
+		state.machineDeployments[fmt.Sprintf("%s/%s", md.Namespace, md.Name)] = replicas
+	}
+
+	return state, nil
+}
+
+// desiredState generates the state from the manifest. The nodes of the
+// existing cluster are expected to run the Kubernetes version of the manifest,
+// missing or extra nodes show up in the node count.
+func desiredState(ctx *util.Context, existing *clusterState) *clusterState {
+	cluster := ctx.Cluster
+	version := strings.TrimPrefix(cluster.Versions.Kubernetes, "v")
+
+	state := &clusterState{
+		kubernetesVersion:    version,
+		controlPlaneEndpoint: fmt.Sprintf("%s:%d", cluster.APIEndpoint.Host, cluster.APIEndpoint.Port),
+		podSubnet:            cluster.ClusterNetwork.PodSubnet,
+		serviceSubnet:        cluster.ClusterNetwork.ServiceSubnet,
+		controlPlaneNodes:    len(cluster.Hosts),
+		nodeVersions:         map[string]string{},
+		machineDeployments:   map[string]string{},
+	}
+
+	for name := range existing.nodeVersions {
+		state.nodeVersions[name] = version
+	}
+
+	if !cluster.MachineController.Deploy {
+		state.workerNodes = existing.workerNodes
+		return state
+	}
+
+	for _, workerset := range cluster.Workers {
+		replicas := "unset"
+		if workerset.Replicas != nil {
+			replicas = fmt.Sprint(*workerset.Replicas)
+			state.workerNodes += *workerset.Replicas
+		}
+		name := fmt.Sprintf("%s/%s", metav1.NamespaceSystem, machinecontroller.MachineDeploymentName(workerset.Name))
+		state.machineDeployments[name] = replicas
+	}
+
+	return state
+}
+
+func (s *clusterState) render() string {
+	var b strings.Builder
+
+	fmt.Fprintln(&b, "cluster:")
+	fmt.Fprintf(&b, "  kubernetes version: %s\n", s.kubernetesVersion)
+	fmt.Fprintf(&b, "  control plane endpoint: %s\n", s.controlPlaneEndpoint)
+	fmt.Fprintf(&b, "  pod subnet: %s\n", s.podSubnet)
+	fmt.Fprintf(&b, "  service subnet: %s\n", s.serviceSubnet)
+
+	fmt.Fprintln(&b, "nodes:")
+	fmt.Fprintf(&b, "  control plane: %d\n", s.controlPlaneNodes)
+	fmt.Fprintf(&b, "  workers: %d\n", s.workerNodes)
+
+	fmt.Fprintln(&b, "node versions:")
+	for _, name := range sortedKeys(s.nodeVersions) {
+		fmt.Fprintf(&b, "  %s: %s\n", name, s.nodeVersions[name])
+	}
+
+	if s.machineControllerImage != "" {
+		fmt.Fprintln(&b, "machine-controller:")
+		fmt.Fprintf(&b, "  image: %s\n", s.machineControllerImage)
+	}
+
+	if len(s.machineDeployments) > 0 {
+		fmt.Fprintln(&b, "machinedeployment replicas:")
+		for _, name := range sortedKeys(s.machineDeployments) {
+			fmt.Fprintf(&b, "  %s: %s\n", name, s.machineDeployments[name])
+		}
+	}
+
+	return b.String()
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package installation
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
+	"github.com/kubermatic/kubeone/pkg/ssh"
+	"github.com/kubermatic/kubeone/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Exec runs the command on all control plane hosts, and optionally on
+// all worker nodes, printing the output prefixed with the hostname
+func Exec(ctx *util.Context, command string, allWorkers bool) error {
+	hosts := ctx.Cluster.Hosts
+
+	if allWorkers {
+		if err := util.BuildKubernetesClientset(ctx); err != nil {
+			return errors.Wrap(err, "unable to build kubernetes clientset")
+		}
+
+		workers, err := workerHosts(ctx)
+		if err != nil {
+			return errors.Wrap(err, "unable to list worker nodes")
+		}
+
+		hosts = append(append([]kubeoneapi.HostConfig{}, hosts...), workers...)
+	}
+
+	return ctx.RunTaskOnNodes(hosts, func(ctx *util.Context, node *kubeoneapi.HostConfig, conn ssh.Connection) error {
+		return execOnNode(node, conn, command)
+	}, true)
+}
+
+func execOnNode(node *kubeoneapi.HostConfig, conn ssh.Connection, command string) error {
+	hostname := node.Hostname
+	if hostname == "" {
+		stdout, _, _, err := conn.Exec("hostname -f")
+		if err != nil {
+			return errors.Wrap(err, "unable to determine hostname")
+		}
+		hostname = stdout
+	}
+
+	prefix := fmt.Sprintf("[%s] ", hostname)
+	stdout := util.NewPrefixedWriter(os.Stdout, prefix)
+	stderr := util.NewPrefixedWriter(os.Stderr, prefix)
+	defer stdout.Close()
+	defer stderr.Close()
+
+	_, err := conn.Stream(command, stdout, stderr)
+
+	return err
+}
+
+// workerHosts returns the SSH configuration of the worker nodes, which
+// are accessed using the SSH key and agent of the leader
+func workerHosts(ctx *util.Context) ([]kubeoneapi.HostConfig, error) {
+	leader, err := ctx.Cluster.Leader()
+	if err != nil {
+		return nil, err
+	}
+
+	controlPlane := map[string]bool{}
+	for _, host := range ctx.Cluster.Hosts {
+		controlPlane[host.PublicAddress] = true
+		controlPlane[host.PrivateAddress] = true
+	}
+
+	nodes := corev1.NodeList{}
+	if err = ctx.DynamicClient.List(context.Background(), &dynclient.ListOptions{}, &nodes); err != nil {
+		return nil, err
+	}
+
+	var workers []kubeoneapi.HostConfig
+	for _, node := range nodes.Items {
+		address := nodeAddress(node)
+		if address == "" || controlPlane[address] || controlPlane[nodeInternalAddress(node)] {
+			continue
+		}
+
+		workers = append(workers, kubeoneapi.HostConfig{
+			PublicAddress:            address,
+			PrivateAddress:           nodeInternalAddress(node),
+			SSHPort:                  22,
+			SSHUsername:              sshUsername(node.Status.NodeInfo.OSImage),
+			SSHPrivateKeyFile:        leader.SSHPrivateKeyFile,
+			SSHPrivateKeyFiles:       leader.SSHPrivateKeyFiles,
+			SSHAgentSocket:           leader.SSHAgentSocket,
+			SSHStrictHostKeyChecking: leader.SSHStrictHostKeyChecking,
+			SSHKnownHostsFile:        leader.SSHKnownHostsFile,
+			Hostname:                 node.Name,
+		})
+	}
+
+	return workers, nil
+}
+
+// nodeAddress returns the external address of the node, falling back
+// to the internal one for nodes without an external address
+func nodeAddress(node corev1.Node) string {
+	for _, addr := range node.Status.Addresses {
+		if addr.Type == corev1.NodeExternalIP {
+			return addr.Address
+		}
+	}
+
+	return nodeInternalAddress(node)
+}
+
+func nodeInternalAddress(node corev1.Node) string {
+	for _, addr := range node.Status.Addresses {
+		if addr.Type == corev1.NodeInternalIP {
+			return addr.Address
+		}
+	}
+
+	return ""
+}
+
+// sshUsername returns the default SSH user of the machine-controller
+// provisioned operating systems
+func sshUsername(osImage string) string {
+	osImage = strings.ToLower(osImage)
+
+	switch {
+	case strings.Contains(osImage, "centos"):
+		return "centos"
+	case strings.Contains(osImage, "container linux"):
+		return "core"
+	case strings.Contains(osImage, "amazon linux"):
+		return "ec2-user"
+	default:
+		return "ubuntu"
+	}
+}
@@ -22,8 +22,12 @@ import (
 	"github.com/kubermatic/kubeone/pkg/certificate"
 	"github.com/kubermatic/kubeone/pkg/features"
 	"github.com/kubermatic/kubeone/pkg/task"
+	"github.com/kubermatic/kubeone/pkg/templates/clusterautoscaler"
+	"github.com/kubermatic/kubeone/pkg/templates/csi"
 	"github.com/kubermatic/kubeone/pkg/templates/externalccm"
 	"github.com/kubermatic/kubeone/pkg/templates/machinecontroller"
+	"github.com/kubermatic/kubeone/pkg/templates/nodelocaldns"
+	"github.com/kubermatic/kubeone/pkg/templates/velero"
 	"github.com/kubermatic/kubeone/pkg/util"
 	"github.com/kubermatic/kubeone/pkg/util/credentials"
 )
@@ -32,31 +36,62 @@ import (
 // an empty, pristine machine.
 func Install(ctx *util.Context) error {
 	installSteps := []task.Task{
-		{Fn: installPrerequisites, ErrMsg: "failed to install prerequisites"},
-		{Fn: generateKubeadm, ErrMsg: "failed to generate kubeadm config files"},
-		{Fn: kubeadmCertsOnLeader, ErrMsg: "failed to provision certs and etcd on leader"},
-		{Fn: certificate.DownloadCA, ErrMsg: "unable to download ca from leader", Retries: 3},
-		{Fn: deployCA, ErrMsg: "unable to deploy ca on nodes", Retries: 3},
-		{Fn: kubeadmCertsOnFollower, ErrMsg: "failed to provision certs and etcd on followers"},
-		{Fn: initKubernetesLeader, ErrMsg: "failed to init kubernetes on leader"},
-		{Fn: joinControlplaneNode, ErrMsg: "unable to join other masters a cluster"},
-		{Fn: copyKubeconfig, ErrMsg: "unable to copy kubeconfig to home directory", Retries: 3},
-		{Fn: saveKubeconfig, ErrMsg: "unable to save kubeconfig to the local machine", Retries: 3},
-		{Fn: util.BuildKubernetesClientset, ErrMsg: "unable to build kubernetes clientset", Retries: 3},
-		{Fn: features.Activate, ErrMsg: "unable to activate features"},
-		{Fn: credentials.Ensure, ErrMsg: "unable to ensure credentials secret"},
-		{Fn: externalccm.Ensure, ErrMsg: "failed to install external CCM"},
-		{Fn: patchCoreDNS, ErrMsg: "failed to patch CoreDNS", Retries: 3},
-		{Fn: ensureCNI, ErrMsg: "failed to install cni plugin", Retries: 3},
-		{Fn: machinecontroller.Ensure, ErrMsg: "failed to install machine-controller", Retries: 3},
-		{Fn: machinecontroller.WaitReady, ErrMsg: "failed to wait for machine-controller", Retries: 3},
-		{Fn: createWorkerMachines, ErrMsg: "failed to create worker machines", Retries: 3},
+		{Fn: util.DetermineOS, Name: "Determining operating systems", ErrMsg: "failed to determine operating systems", AlwaysRun: true},
+		{Fn: runPreflightChecks, Name: "Running preflight checks", ErrMsg: "preflight checks failed"},
+		{Fn: installPrerequisites, Name: "Installing prerequisites", ErrMsg: "failed to install prerequisites", AlwaysRun: true},
+		{Fn: verifySwapDisabled, Name: "Verifying swap is disabled", ErrMsg: "failed to verify swap is disabled"},
+		{Fn: checkIPVS, Name: "Verifying IPVS prerequisites", ErrMsg: "failed to verify IPVS prerequisites"},
+		{Fn: generateKubeadm, Name: "Generating kubeadm config files", ErrMsg: "failed to generate kubeadm config files", AlwaysRun: true},
+		{Fn: kubeadmCertsOnLeader, Name: "Provisioning certs and etcd on leader", ErrMsg: "failed to provision certs and etcd on leader"},
+		{Fn: certificate.DownloadCA, Name: "Downloading CA from leader", ErrMsg: "unable to download ca from leader", Retries: 3, AlwaysRun: true},
+		{Fn: deployCA, Name: "Deploying CA on nodes", ErrMsg: "unable to deploy ca on nodes", Retries: 3},
+		{Fn: kubeadmCertsOnFollower, Name: "Provisioning certs and etcd on followers", ErrMsg: "failed to provision certs and etcd on followers"},
+		{Fn: initKubernetesLeader, Name: "Initializing Kubernetes on leader", ErrMsg: "failed to init kubernetes on leader"},
+		{Fn: joinControlplaneNode, Name: "Joining control plane nodes", ErrMsg: "unable to join other masters a cluster"},
+		{Fn: copyKubeconfig, Name: "Copying kubeconfig to home directory", ErrMsg: "unable to copy kubeconfig to home directory", Retries: 3},
+		{Fn: saveKubeconfig, Name: "Saving kubeconfig to the local machine", ErrMsg: "unable to save kubeconfig to the local machine", Retries: 3},
+		{Fn: util.BuildKubernetesClientset, Name: "Building Kubernetes clientset", ErrMsg: "unable to build kubernetes clientset", Retries: 3, AlwaysRun: true},
+		{Fn: features.Activate, Name: "Activating features", ErrMsg: "unable to activate features"},
+		{Fn: credentials.Ensure, Name: "Ensuring credentials secret", ErrMsg: "unable to ensure credentials secret"},
+		{Fn: externalccm.Ensure, Name: "Installing external CCM", ErrMsg: "failed to install external CCM"},
+		{Fn: patchCoreDNS, Name: "Patching CoreDNS", ErrMsg: "failed to patch CoreDNS", Retries: 3},
+		{Fn: nodelocaldns.Ensure, Name: "Installing NodeLocal DNSCache", ErrMsg: "failed to install NodeLocal DNSCache", Retries: 3},
+		{Fn: ensureCNI, Name: "Installing CNI plugin", ErrMsg: "failed to install cni plugin", Retries: 3},
+		{Fn: csi.Ensure, Name: "Installing CSI driver", ErrMsg: "failed to install CSI driver", Retries: 3},
+		{Fn: machinecontroller.Ensure, Name: "Installing machine-controller", ErrMsg: "failed to install machine-controller", Retries: 3},
+		{Fn: machinecontroller.WaitReady, Name: "Waiting for machine-controller", ErrMsg: "failed to wait for machine-controller", Retries: 3},
+		{Fn: createWorkerMachines, Name: "Creating worker machines", ErrMsg: "failed to create worker machines", Retries: 3},
+		{Fn: clusterautoscaler.Ensure, Name: "Installing cluster-autoscaler", ErrMsg: "failed to install cluster-autoscaler", Retries: 3},
+		{Fn: velero.Ensure, Name: "Installing Velero", ErrMsg: "failed to install Velero", Retries: 3},
 	}
 
+	// keep the bootstrap token of an interrupted run, the leader may
+	// already be initialized with it
+	if ctx.State != nil && ctx.JoinToken == "" {
+		ctx.JoinToken = ctx.State.JoinToken
+	}
+
+	ctx.Events.RecordApplyStart("Installation")
+	ctx.Progress.Start(len(installSteps))
 	for _, step := range installSteps {
+		ctx.Progress.Step(step.Name)
+		if !step.AlwaysRun && ctx.State.Completed(step.Name) {
+			ctx.Logger.Infof("Skipping %q, completed by a previous run", step.Name)
+			continue
+		}
 		if err := step.Run(ctx); err != nil {
+			ctx.Events.RecordStepFailed(step.Name, err)
 			return errors.Wrap(err, step.ErrMsg)
 		}
+		if err := ctx.State.Complete(step.Name, ctx.JoinToken); err != nil {
+			ctx.Logger.Warnf("Unable to record the step in the state file: %v", err)
+		}
+	}
+	ctx.Progress.Finish()
+	ctx.Events.RecordApplyComplete("Installation")
+
+	if err := ctx.State.Clear(); err != nil {
+		ctx.Logger.Warnf("Unable to remove the state file: %v", err)
 	}
 
 	return nil
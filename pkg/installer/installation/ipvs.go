@@ -0,0 +1,50 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package installation
+
+import (
+	"github.com/pkg/errors"
+
+	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
+	"github.com/kubermatic/kubeone/pkg/ssh"
+	"github.com/kubermatic/kubeone/pkg/util"
+)
+
+// checkIPVS verifies the nodes can run kube-proxy in the ipvs mode.
+// kube-proxy silently falls back to iptables otherwise.
+func checkIPVS(ctx *util.Context) error {
+	if ctx.Cluster.KubeProxy.Mode != kubeoneapi.KubeProxyModeIPVS {
+		return nil
+	}
+
+	return ctx.RunTaskOnAllNodes(checkIPVSOnNode, true)
+}
+
+func checkIPVSOnNode(ctx *util.Context, node *kubeoneapi.HostConfig, _ ssh.Connection) error {
+	ctx.Logger.Infoln("Checking is ipvsadm installed…")
+
+	_, _, err := ctx.Runner.Run(checkIPVSCommand, util.TemplateVariables{})
+	if err != nil {
+		return errors.Errorf("ipvsadm is not installed on node %s, but is required by the ipvs kube-proxy mode", node.PublicAddress)
+	}
+
+	return nil
+}
+
+const checkIPVSCommand = `
+type ipvsadm &>/dev/null
+`
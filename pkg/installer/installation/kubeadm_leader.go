@@ -33,8 +33,13 @@ fi
 sudo kubeadm init phase certs all --config=./{{ .WORK_DIR }}/cfg/master_{{ .NODE_ID }}.yaml
 `
 	kubeadmInitCommand = `
-if [[ -f /etc/kubernetes/admin.conf ]]; then exit 0; fi
 sudo kubeadm init --config=./{{ .WORK_DIR }}/cfg/master_{{ .NODE_ID }}.yaml
+`
+	// kubeadmInitializedCommand succeeds only if kubeadm init already ran
+	// on the node and its API server is healthy
+	kubeadmInitializedCommand = `
+sudo test -f /etc/kubernetes/admin.conf
+sudo kubectl --kubeconfig=/etc/kubernetes/admin.conf get --raw=/healthz >/dev/null
 `
 )
 
@@ -62,13 +67,30 @@ func initKubernetesLeader(ctx *util.Context) error {
 	ctx.Logger.Infoln("Initializing Kubernetes on leader…")
 
 	return ctx.RunTaskOnLeader(func(ctx *util.Context, node *kubeoneapi.HostConfig, conn ssh.Connection) error {
+		if kubeadmInitialized(ctx) {
+			ctx.Logger.Infoln("Kubernetes is already initialized on leader, skipping kubeadm init")
+			return nil
+		}
+
 		ctx.Logger.Infoln("Running kubeadm…")
 
 		_, _, err := ctx.Runner.Run(kubeadmInitCommand, util.TemplateVariables{
 			"WORK_DIR": ctx.WorkDir,
 			"NODE_ID":  strconv.Itoa(node.ID),
 		})
+		if err != nil && ctx.Retry && kubeadmInitialized(ctx) {
+			ctx.Logger.Warnf("kubeadm init failed, but Kubernetes is initialized on leader, continuing: %v", err)
+			return nil
+		}
 
 		return err
 	})
 }
+
+// kubeadmInitialized returns whether kubeadm init already ran on the node
+// of the context, i.e. the node has the admin kubeconfig and a healthy API
+// server
+func kubeadmInitialized(ctx *util.Context) bool {
+	_, _, err := ctx.Runner.Run(kubeadmInitializedCommand, nil)
+	return err == nil
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package installation
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/kubermatic/kubeone/pkg/certificate"
+	"github.com/kubermatic/kubeone/pkg/task"
+	"github.com/kubermatic/kubeone/pkg/templates/machinecontroller"
+	"github.com/kubermatic/kubeone/pkg/util"
+)
+
+// RollbackMachineController rolls machine-controller back to the given version
+func RollbackMachineController(ctx *util.Context, version string) error {
+	rollbackSteps := []task.Task{
+		{Fn: util.BuildKubernetesClientset, ErrMsg: "unable to build kubernetes clientset", Retries: 3},
+		{
+			Fn: func(ctx *util.Context) error {
+				return machinecontroller.RollbackMachineController(ctx, version)
+			},
+			ErrMsg: "failed to rollback machine-controller",
+		},
+	}
+
+	for _, step := range rollbackSteps {
+		if err := step.Run(ctx); err != nil {
+			return errors.Wrap(err, step.ErrMsg)
+		}
+	}
+
+	return nil
+}
+
+// RotateMachineControllerCertificates rotates the machine-controller webhook
+// serving certificate
+func RotateMachineControllerCertificates(ctx *util.Context) error {
+	rotateSteps := []task.Task{
+		{Fn: util.BuildKubernetesClientset, ErrMsg: "unable to build kubernetes clientset", Retries: 3},
+		{Fn: certificate.DownloadCA, ErrMsg: "unable to download ca from leader", Retries: 3},
+		{Fn: machinecontroller.RotateWebhookCertificates, ErrMsg: "failed to rotate machine-controller webhook certificates"},
+	}
+
+	for _, step := range rotateSteps {
+		if err := step.Run(ctx); err != nil {
+			return errors.Wrap(err, step.ErrMsg)
+		}
+	}
+
+	return nil
+}
+
+// MachineControllerStatus returns the status of machine-controller and its webhook
+func MachineControllerStatus(ctx *util.Context) (*machinecontroller.Status, error) {
+	if err := util.BuildKubernetesClientset(ctx); err != nil {
+		return nil, errors.Wrap(err, "unable to build kubernetes clientset")
+	}
+
+	return machinecontroller.GetStatus(context.Background(), ctx.DynamicClient)
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package installation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
+	"github.com/kubermatic/kubeone/pkg/templates/machinecontroller"
+	"github.com/kubermatic/kubeone/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1alpha1 "sigs.k8s.io/cluster-api/pkg/apis/cluster/v1alpha1"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Plan compares the cluster with the manifest and returns the changes
+// install would make, without changing anything. A cluster that can't be
+// reached is assumed not to be provisioned yet.
+func Plan(ctx *util.Context) ([]util.Difference, error) {
+	if err := util.BuildKubernetesClientset(ctx); err != nil {
+		ctx.Logger.Warnf("Unable to access the cluster, assuming it is not provisioned yet: %v", err)
+		return provisioningPlan(ctx), nil
+	}
+
+	return existingClusterPlan(ctx)
+}
+
+// existingClusterPlan returns the changes install would make to the
+// accessible cluster
+func existingClusterPlan(ctx *util.Context) ([]util.Difference, error) {
+	diffs, err := diff(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeDiffs, err := controlPlaneNodesDiff(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to compare control plane nodes")
+	}
+	diffs = append(diffs, nodeDiffs...)
+
+	if ctx.Cluster.MachineController.Deploy && !ctx.SkipWorkers {
+		mdDiffs, err := removedMachineDeployments(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to compare worker MachineDeployments")
+		}
+		diffs = append(diffs, mdDiffs...)
+	}
+
+	return diffs, nil
+}
+
+// provisioningPlan lists everything install creates on a new cluster
+func provisioningPlan(ctx *util.Context) []util.Difference {
+	var diffs []util.Difference
+
+	for _, host := range ctx.Cluster.Hosts {
+		diffs = append(diffs, util.Difference{Resource: "Node " + host.PublicAddress})
+	}
+
+	if !ctx.Cluster.MachineController.Deploy {
+		return diffs
+	}
+
+	diffs = append(diffs, util.Difference{
+		Resource: fmt.Sprintf("Deployment %s/machine-controller", machinecontroller.MachineControllerNamespace),
+	})
+
+	if ctx.SkipWorkers {
+		return diffs
+	}
+
+	for _, workerset := range ctx.Cluster.Workers {
+		diffs = append(diffs, util.Difference{
+			Resource: fmt.Sprintf("MachineDeployment %s/%s", metav1.NamespaceSystem, machinecontroller.MachineDeploymentName(workerset.Name)),
+		})
+	}
+
+	return diffs
+}
+
+// controlPlaneNodesDiff matches the control plane nodes of the cluster with
+// the hosts of the manifest and compares their kubelet version
+func controlPlaneNodesDiff(ctx *util.Context) ([]util.Difference, error) {
+	nodes := corev1.NodeList{}
+	nodeListOpts := dynclient.ListOptions{}
+	if err := nodeListOpts.SetLabelSelector(labelControlPlaneNode); err != nil {
+		return nil, errors.Wrap(err, "failed to set node selector labels")
+	}
+
+	if err := ctx.DynamicClient.List(context.Background(), &nodeListOpts, &nodes); err != nil {
+		return nil, errors.Wrap(err, "unable to list nodes")
+	}
+
+	desiredVersion := strings.TrimPrefix(ctx.Cluster.Versions.Kubernetes, "v")
+	matched := make(map[string]bool)
+
+	var diffs []util.Difference
+	for _, host := range ctx.Cluster.Hosts {
+		node := nodeForHost(nodes.Items, host)
+		if node == nil {
+			diffs = append(diffs, util.Difference{Resource: "Node " + host.PublicAddress})
+			continue
+		}
+		matched[node.Name] = true
+
+		existingVersion := strings.TrimPrefix(node.Status.NodeInfo.KubeletVersion, "v")
+		if existingVersion != desiredVersion {
+			diffs = append(diffs, util.Difference{
+				Resource: "Node " + node.Name,
+				Field:    "kubelet version",
+				Existing: existingVersion,
+				Desired:  desiredVersion,
+			})
+		}
+	}
+
+	for _, node := range nodes.Items {
+		if !matched[node.Name] {
+			diffs = append(diffs, util.Difference{Resource: "Node " + node.Name, Removed: true})
+		}
+	}
+
+	return diffs, nil
+}
+
+// nodeForHost returns the node running on the given host, matched by
+// hostname or address
+func nodeForHost(nodes []corev1.Node, host kubeoneapi.HostConfig) *corev1.Node {
+	for i := range nodes {
+		if host.Hostname != "" && nodes[i].Name == host.Hostname {
+			return &nodes[i]
+		}
+		for _, addr := range nodes[i].Status.Addresses {
+			if addr.Address == host.PrivateAddress || addr.Address == host.PublicAddress {
+				return &nodes[i]
+			}
+		}
+	}
+
+	return nil
+}
+
+// removedMachineDeployments returns the MachineDeployments created by KubeOne
+// which are no longer in the manifest
+func removedMachineDeployments(ctx *util.Context) ([]util.Difference, error) {
+	mds, err := unmanagedMachineDeployments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs []util.Difference
+	for _, md := range mds {
+		diffs = append(diffs, util.Difference{
+			Resource: fmt.Sprintf("MachineDeployment %s/%s", md.Namespace, md.Name),
+			Removed:  true,
+		})
+	}
+
+	return diffs, nil
+}
+
+// unmanagedMachineDeployments lists the MachineDeployments created by KubeOne
+// without a workerset in the manifest, MachineDeployments created by other
+// means are never listed
+func unmanagedMachineDeployments(ctx *util.Context) ([]clusterv1alpha1.MachineDeployment, error) {
+	mds := clusterv1alpha1.MachineDeploymentList{}
+	listOpts := dynclient.ListOptions{Namespace: metav1.NamespaceSystem}
+	if err := listOpts.SetLabelSelector(fmt.Sprintf("%s=%s", machinecontroller.ManagedByLabel, machinecontroller.ManagedByLabelValue)); err != nil {
+		return nil, errors.Wrap(err, "failed to parse the label selector")
+	}
+	if err := ctx.DynamicClient.List(context.Background(), &listOpts, &mds); err != nil {
+		return nil, errors.Wrap(err, "unable to list MachineDeployments")
+	}
+
+	return machineDeploymentsWithoutWorkerset(mds.Items, ctx.Cluster.Workers), nil
+}
+
+// machineDeploymentsWithoutWorkerset returns the MachineDeployments not
+// created for any of the workersets
+func machineDeploymentsWithoutWorkerset(mds []clusterv1alpha1.MachineDeployment, workers []kubeoneapi.WorkerConfig) []clusterv1alpha1.MachineDeployment {
+	desired := make(map[string]bool)
+	for _, workerset := range workers {
+		desired[machinecontroller.MachineDeploymentName(workerset.Name)] = true
+	}
+
+	var unmanaged []clusterv1alpha1.MachineDeployment
+	for _, md := range mds {
+		if !desired[md.Name] {
+			unmanaged = append(unmanaged, md)
+		}
+	}
+
+	return unmanaged
+}
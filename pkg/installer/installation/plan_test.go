@@ -0,0 +1,132 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package installation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
+	"github.com/kubermatic/kubeone/pkg/internal/testclient"
+	"github.com/kubermatic/kubeone/pkg/templates/machinecontroller"
+	"github.com/kubermatic/kubeone/pkg/util"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1alpha1 "sigs.k8s.io/cluster-api/pkg/apis/cluster/v1alpha1"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestMachineDeploymentsWithoutWorkerset(t *testing.T) {
+	machineDeployment := func(name string) clusterv1alpha1.MachineDeployment {
+		return clusterv1alpha1.MachineDeployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceSystem, Name: name},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		mds      []clusterv1alpha1.MachineDeployment
+		workers  []kubeoneapi.WorkerConfig
+		expected []string
+	}{
+		{
+			name:    "unchanged workerset",
+			mds:     []clusterv1alpha1.MachineDeployment{machineDeployment(machinecontroller.MachineDeploymentName("pool1"))},
+			workers: []kubeoneapi.WorkerConfig{{Name: "pool1"}},
+		},
+		{
+			name:     "removed workerset",
+			mds:      []clusterv1alpha1.MachineDeployment{machineDeployment(machinecontroller.MachineDeploymentName("pool1")), machineDeployment(machinecontroller.MachineDeploymentName("pool2"))},
+			workers:  []kubeoneapi.WorkerConfig{{Name: "pool1"}},
+			expected: []string{machinecontroller.MachineDeploymentName("pool2")},
+		},
+		{
+			name:    "new workerset",
+			workers: []kubeoneapi.WorkerConfig{{Name: "pool1"}},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got := machineDeploymentsWithoutWorkerset(tc.mds, tc.workers)
+			if len(got) != len(tc.expected) {
+				t.Fatalf("expected %v, got %d MachineDeployments", tc.expected, len(got))
+			}
+			for i := range got {
+				if got[i].Name != tc.expected[i] {
+					t.Errorf("expected %v, got %s at index %d", tc.expected, got[i].Name, i)
+				}
+			}
+		})
+	}
+}
+
+func TestReconcileWorkersPrune(t *testing.T) {
+	managed := &clusterv1alpha1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceSystem,
+			Name:      machinecontroller.MachineDeploymentName("removed"),
+			Labels:    map[string]string{machinecontroller.ManagedByLabel: machinecontroller.ManagedByLabelValue},
+		},
+	}
+	foreign := &clusterv1alpha1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceSystem, Name: "foreign"},
+	}
+
+	tests := []struct {
+		name    string
+		prune   bool
+		deleted bool
+	}{
+		{
+			name: "without prune",
+		},
+		{
+			name:    "with prune",
+			prune:   true,
+			deleted: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			client := testclient.NewMemoryClient(managed, foreign)
+			ctx := &util.Context{
+				Cluster:       &kubeoneapi.KubeOneCluster{},
+				DynamicClient: client,
+				Logger:        logrus.New(),
+				Prune:         tc.prune,
+			}
+			if err := reconcileWorkers(ctx, nil); err != nil {
+				t.Fatal(err)
+			}
+
+			err := client.Get(context.Background(), dynclient.ObjectKey{Namespace: managed.Namespace, Name: managed.Name}, &clusterv1alpha1.MachineDeployment{})
+			if tc.deleted != k8serrors.IsNotFound(err) {
+				t.Errorf("expected the KubeOne MachineDeployment to be deleted: %t, got error: %v", tc.deleted, err)
+			}
+			if err := client.Get(context.Background(), dynclient.ObjectKey{Namespace: foreign.Namespace, Name: foreign.Name}, &clusterv1alpha1.MachineDeployment{}); err != nil {
+				t.Errorf("expected the foreign MachineDeployment to be kept, got error: %v", err)
+			}
+		})
+	}
+}
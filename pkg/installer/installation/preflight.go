@@ -0,0 +1,318 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package installation
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+
+	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
+	"github.com/kubermatic/kubeone/pkg/ssh"
+	"github.com/kubermatic/kubeone/pkg/util"
+)
+
+const (
+	// minKernelMajor and minKernelMinor are the oldest kernel supported
+	// by kubeadm
+	minKernelMajor = 3
+	minKernelMinor = 10
+
+	// minDiskSpaceKiB is the space needed in /var/lib for the images and
+	// the etcd data
+	minDiskSpaceKiB = 4 * 1024 * 1024
+)
+
+// controlPlanePorts must be free before kube-apiserver, etcd, kubelet,
+// kube-scheduler and kube-controller-manager are started
+var controlPlanePorts = []int{6443, 2379, 2380, 10250, 10251, 10252}
+
+// PreflightStatus is the outcome of a preflight check
+type PreflightStatus string
+
+// List of preflight check outcomes
+const (
+	PreflightPassed  PreflightStatus = "passed"
+	PreflightWarning PreflightStatus = "warning"
+	PreflightFailed  PreflightStatus = "failed"
+)
+
+// PreflightResult is the outcome of a single preflight check on a host
+type PreflightResult struct {
+	Host    string
+	Check   string
+	Status  PreflightStatus
+	Message string
+}
+
+// runPreflightChecks runs the preflight checks on all hosts and prints
+// the results as a table, failing if any of the checks failed
+func runPreflightChecks(ctx *util.Context) error {
+	var (
+		lock    sync.Mutex
+		results []PreflightResult
+	)
+
+	err := ctx.RunTaskOnAllNodes(func(ctx *util.Context, node *kubeoneapi.HostConfig, _ ssh.Connection) error {
+		ctx.Logger.Infoln("Running preflight checks…")
+
+		nodeResults, err := RunPreflightChecks(ctx, *node)
+		if err != nil {
+			return err
+		}
+
+		lock.Lock()
+		results = append(results, nodeResults...)
+		lock.Unlock()
+
+		return nil
+	}, true)
+	if err != nil {
+		return err
+	}
+
+	// keep the hosts in the order of the manifest
+	order := map[string]int{}
+	for i, host := range ctx.Cluster.Hosts {
+		order[host.PublicAddress] = i
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return order[results[i].Host] < order[results[j].Host]
+	})
+
+	if err = printPreflightResults(os.Stdout, results); err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, r := range results {
+		if r.Status == PreflightFailed {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return errors.Errorf("%d preflight checks failed", failed)
+	}
+
+	return nil
+}
+
+// RunPreflightChecks verifies the host the context's runner is connected
+// to can be bootstrapped. It returns the outcome of every check, an error
+// is only returned if the checks couldn't be run at all.
+func RunPreflightChecks(ctx *util.Context, host kubeoneapi.HostConfig) ([]PreflightResult, error) {
+	runtime := ctx.Cluster.ContainerRuntime.Service()
+	ping := "sudo docker info"
+	if ctx.Cluster.ContainerRuntime.Runtime == kubeoneapi.ContainerRuntimeCRIO {
+		ping = "sudo test -S /var/run/crio/crio.sock"
+	}
+
+	stdout, _, err := ctx.Runner.Run(preflightCommand, util.TemplateVariables{
+		"MODULES":      requiredKernelModules(ctx.Cluster),
+		"RUNTIME":      runtime,
+		"RUNTIME_PING": ping,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to gather preflight facts")
+	}
+
+	return evaluatePreflightChecks(host.PublicAddress, parsePreflightFacts(stdout), ctx.Cluster), nil
+}
+
+// preflightCommand prints the facts the checks are evaluated against,
+// one key=value pair per line
+const preflightCommand = `
+echo "kernel=$(uname -r)"
+{{- range .MODULES }}
+if [ -d /sys/module/{{ . }} ] || sudo modprobe -n {{ . }} &>/dev/null; then
+	echo "module.{{ . }}=available"
+else
+	echo "module.{{ . }}=missing"
+fi
+{{- end }}
+
+if ! type {{ .RUNTIME }} &>/dev/null; then
+	echo "runtime=absent"
+elif {{ .RUNTIME_PING }} &>/dev/null; then
+	echo "runtime=reachable"
+else
+	echo "runtime=unreachable"
+fi
+
+if [ -f /etc/kubernetes/kubelet.conf ]; then
+	echo "bootstrapped=true"
+else
+	echo "bootstrapped=false"
+fi
+echo "listening=$(sudo ss -tln | tail -n +2 | awk '{print $4}' | sed 's/.*://' | sort -un | tr '\n' ' ')"
+
+echo "swap=$(tail -n +2 /proc/swaps | wc -l)"
+echo "disk=$(df -Pk /var/lib | tail -n 1 | awk '{print $4}')"
+`
+
+func requiredKernelModules(cluster *kubeoneapi.KubeOneCluster) []string {
+	modules := []string{"br_netfilter", "overlay"}
+	if cluster.KubeProxy.Mode == kubeoneapi.KubeProxyModeIPVS {
+		modules = append(modules, "ip_vs", "ip_vs_rr", "ip_vs_wrr", "ip_vs_sh")
+	}
+
+	return modules
+}
+
+func parsePreflightFacts(stdout string) map[string]string {
+	facts := map[string]string{}
+
+	for _, line := range strings.Split(stdout, "\n") {
+		parts := strings.SplitN(strings.TrimSpace(line), "=", 2)
+		if len(parts) == 2 {
+			facts[parts[0]] = strings.TrimSpace(parts[1])
+		}
+	}
+
+	return facts
+}
+
+var kernelVersionRegex = regexp.MustCompile(`^(\d+)\.(\d+)`)
+
+func evaluatePreflightChecks(host string, facts map[string]string, cluster *kubeoneapi.KubeOneCluster) []PreflightResult {
+	var results []PreflightResult
+
+	add := func(check string, status PreflightStatus, format string, args ...interface{}) {
+		results = append(results, PreflightResult{
+			Host:    host,
+			Check:   check,
+			Status:  status,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	// kernel version
+	kernel := facts["kernel"]
+	if m := kernelVersionRegex.FindStringSubmatch(kernel); m == nil {
+		add("kernel version", PreflightFailed, "unable to parse kernel version %q", kernel)
+	} else {
+		major, _ := strconv.Atoi(m[1])
+		minor, _ := strconv.Atoi(m[2])
+		if major < minKernelMajor || (major == minKernelMajor && minor < minKernelMinor) {
+			add("kernel version", PreflightFailed, "%s is older than %d.%d", kernel, minKernelMajor, minKernelMinor)
+		} else {
+			add("kernel version", PreflightPassed, "%s", kernel)
+		}
+	}
+
+	// kernel modules
+	var missing []string
+	for _, module := range requiredKernelModules(cluster) {
+		if facts["module."+module] != "available" {
+			missing = append(missing, module)
+		}
+	}
+	if len(missing) > 0 {
+		add("kernel modules", PreflightFailed, "missing %s", strings.Join(missing, ", "))
+	} else {
+		add("kernel modules", PreflightPassed, "available")
+	}
+
+	// container runtime
+	switch facts["runtime"] {
+	case "reachable":
+		add("container runtime", PreflightPassed, "%s is reachable", cluster.ContainerRuntime.Service())
+	case "absent":
+		add("container runtime", PreflightPassed, "%s will be installed", cluster.ContainerRuntime.Service())
+	default:
+		add("container runtime", PreflightWarning, "%s is installed but not reachable", cluster.ContainerRuntime.Service())
+	}
+
+	// ports, in use by Kubernetes itself once the host is bootstrapped
+	if facts["bootstrapped"] == "true" {
+		add("ports", PreflightPassed, "host is already bootstrapped")
+	} else {
+		listening := map[string]bool{}
+		for _, port := range strings.Fields(facts["listening"]) {
+			listening[port] = true
+		}
+
+		var used []string
+		for _, port := range controlPlanePorts {
+			if listening[strconv.Itoa(port)] {
+				used = append(used, strconv.Itoa(port))
+			}
+		}
+		if len(used) > 0 {
+			add("ports", PreflightFailed, "%s already in use", strings.Join(used, ", "))
+		} else {
+			add("ports", PreflightPassed, "available")
+		}
+	}
+
+	// swap, disabled by the installation scripts
+	if swaps, _ := strconv.Atoi(facts["swap"]); swaps > 0 {
+		add("swap", PreflightWarning, "enabled, will be disabled")
+	} else {
+		add("swap", PreflightPassed, "disabled")
+	}
+
+	// disk space
+	disk, err := strconv.ParseInt(facts["disk"], 10, 64)
+	switch {
+	case err != nil:
+		add("disk space", PreflightFailed, "unable to parse available disk space %q", facts["disk"])
+	case disk < minDiskSpaceKiB:
+		add("disk space", PreflightFailed, "%d MiB available in /var/lib, %d MiB required", disk/1024, minDiskSpaceKiB/1024)
+	default:
+		add("disk space", PreflightPassed, "%d MiB available in /var/lib", disk/1024)
+	}
+
+	return results
+}
+
+// verifySwapDisabled ensures swap is off on all hosts before kubeadm runs
+func verifySwapDisabled(ctx *util.Context) error {
+	return ctx.RunTaskOnAllNodes(func(ctx *util.Context, node *kubeoneapi.HostConfig, _ ssh.Connection) error {
+		ctx.Logger.Infoln("Verifying swap is disabled…")
+
+		stdout, _, err := ctx.Runner.Run(`tail -n +2 /proc/swaps | wc -l`, util.TemplateVariables{})
+		if err != nil {
+			return err
+		}
+
+		if swaps, _ := strconv.Atoi(strings.TrimSpace(stdout)); swaps > 0 {
+			return errors.Errorf("swap is enabled on node %s, but kubelet requires it to be disabled", node.PublicAddress)
+		}
+
+		return nil
+	}, true)
+}
+
+func printPreflightResults(out io.Writer, results []PreflightResult) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "HOST\tCHECK\tSTATUS\tMESSAGE")
+	for _, r := range results {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Host, r.Check, r.Status, r.Message)
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package installation
+
+import (
+	"testing"
+
+	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
+)
+
+func TestEvaluatePreflightChecks(t *testing.T) {
+	const healthy = `kernel=4.15.0-1044-aws
+module.br_netfilter=available
+module.overlay=available
+runtime=absent
+bootstrapped=false
+listening=22 53
+swap=0
+disk=20000000`
+
+	testcases := []struct {
+		name     string
+		facts    string
+		expected map[string]PreflightStatus
+	}{
+		{
+			name:  "pristine host",
+			facts: healthy,
+			expected: map[string]PreflightStatus{
+				"kernel version":    PreflightPassed,
+				"kernel modules":    PreflightPassed,
+				"container runtime": PreflightPassed,
+				"ports":             PreflightPassed,
+				"swap":              PreflightPassed,
+				"disk space":        PreflightPassed,
+			},
+		},
+		{
+			name: "unsuitable host",
+			facts: `kernel=3.2.0-4-amd64
+module.br_netfilter=missing
+module.overlay=available
+runtime=unreachable
+bootstrapped=false
+listening=22 2379 6443
+swap=1
+disk=1000000`,
+			expected: map[string]PreflightStatus{
+				"kernel version":    PreflightFailed,
+				"kernel modules":    PreflightFailed,
+				"container runtime": PreflightWarning,
+				"ports":             PreflightFailed,
+				"swap":              PreflightWarning,
+				"disk space":        PreflightFailed,
+			},
+		},
+		{
+			name: "bootstrapped host uses the ports itself",
+			facts: healthy + `
+bootstrapped=true
+listening=22 2379 2380 6443 10250`,
+			expected: map[string]PreflightStatus{
+				"ports": PreflightPassed,
+			},
+		},
+	}
+
+	cluster := &kubeoneapi.KubeOneCluster{}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			results := evaluatePreflightChecks("10.0.0.1", parsePreflightFacts(tc.facts), cluster)
+
+			for _, r := range results {
+				expected, ok := tc.expected[r.Check]
+				if ok && r.Status != expected {
+					t.Errorf("check %q: expected %s, got %s (%s)", r.Check, expected, r.Status, r.Message)
+				}
+			}
+		})
+	}
+}
@@ -17,17 +17,31 @@ limitations under the License.
 package installation
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 
 	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
 	"github.com/kubermatic/kubeone/pkg/ssh"
 	"github.com/kubermatic/kubeone/pkg/util"
+
+	"k8s.io/apimachinery/pkg/util/wait"
 )
 
 const dockerVersion = "18.09.2"
 
+// ubuntuDockerVersions are the Docker releases installed on the Ubuntu
+// releases dockerVersion isn't published for
+var ubuntuDockerVersions = map[string]string{
+	"20.04": "19.03",
+	"22.04": "20.10",
+}
+
 func installPrerequisites(ctx *util.Context) error {
 	ctx.Logger.Infoln("Installing prerequisites…")
 
@@ -41,14 +55,6 @@ func generateConfigurationFiles(ctx *util.Context) {
 }
 
 func installPrerequisitesOnNode(ctx *util.Context, node *kubeoneapi.HostConfig, conn ssh.Connection) error {
-	ctx.Logger.Infoln("Determine operating system…")
-	os, err := determineOS(ctx)
-	if err != nil {
-		return errors.Wrap(err, "failed to determine operating system")
-	}
-
-	node.SetOperatingSystem(os)
-
 	ctx.Logger.Infoln("Determine hostname…")
 	hostname, err := determineHostname(ctx, *node)
 	if err != nil {
@@ -63,7 +69,18 @@ func installPrerequisitesOnNode(ctx *util.Context, node *kubeoneapi.HostConfig,
 
 	node.SetHostname(hostname)
 
-	logger := ctx.Logger.WithField("os", os)
+	logger := ctx.Logger.WithField("os", node.OperatingSystem)
+
+	logger.Infoln("Disabling swap…")
+	err = disableSwap(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to disable swap")
+	}
+
+	err = forceCgroupV1(ctx, *node)
+	if err != nil {
+		return errors.Wrap(err, "failed to switch to cgroup v1")
+	}
 
 	logger.Infoln("Installing kubeadm…")
 	err = installKubeadm(ctx, *node)
@@ -71,9 +88,27 @@ func installPrerequisitesOnNode(ctx *util.Context, node *kubeoneapi.HostConfig,
 		return errors.Wrap(err, "failed to install kubeadm")
 	}
 
-	err = configureDockerDaemonProxy(ctx)
+	if ctx.Cluster.ContainerRuntime.Runtime == kubeoneapi.ContainerRuntimeCRIO {
+		logger.Infoln("Installing CRI-O…")
+		err = installCRIO(ctx, *node)
+		if err != nil {
+			return errors.Wrap(err, "failed to install CRI-O")
+		}
+	}
+
+	err = configureDockerMirrors(ctx, *node)
+	if err != nil {
+		return errors.Wrap(err, "failed to configure docker registry mirrors")
+	}
+
+	err = configureContainerRuntimeProxy(ctx)
 	if err != nil {
-		return errors.Wrap(err, "failed to configure proxy for docker daemon")
+		return errors.Wrap(err, "failed to configure proxy for container runtime")
+	}
+
+	err = configureNodeProxy(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to configure proxy for node")
 	}
 
 	logger.Infoln("Deploying configuration files…")
@@ -85,11 +120,6 @@ func installPrerequisitesOnNode(ctx *util.Context, node *kubeoneapi.HostConfig,
 	return nil
 }
 
-func determineOS(ctx *util.Context) (string, error) {
-	osID, _, err := ctx.Runner.Run("source /etc/os-release && echo -n $ID", nil)
-	return osID, err
-}
-
 const hostnameCommand = `
 fqdn=$(hostname -f)
 [ "$fqdn" = localhost ] && fqdn=$(hostname)
@@ -130,18 +160,99 @@ no_proxy="{{ .NO_PROXY }}"
 EOF
 `
 
+// disableSwap turns swap off, as kubelet refuses to start otherwise, and
+// keeps it from being turned on again on boot
+func disableSwap(ctx *util.Context) error {
+	_, _, err := ctx.Runner.Run(disableSwapCommand, util.TemplateVariables{})
+	return err
+}
+
+const disableSwapCommand = `
+# swap configured by systemd units instead of /etc/fstab, e.g. on Flatcar
+for unit in $(systemctl list-units --type swap --plain --no-legend | awk '{print $1}'); do
+	sudo systemctl mask "${unit}"
+done
+
+sudo swapoff -a
+sudo sed -i '/^[^#]\S*\s\+\S\+\s\+swap\s/d' /etc/fstab
+`
+
+// timeoutNodeReboot is how long we wait for a node to come back after
+// rebooting it
+const timeoutNodeReboot = 5 * time.Minute
+
+// forceCgroupV1 boots Ubuntu and Debian nodes using the unified cgroup v2
+// hierarchy, e.g. Ubuntu 22.04, with cgroup v1 when kubelet doesn't support
+// cgroup v2. The kernel command line is changed and the node is rebooted.
+func forceCgroupV1(ctx *util.Context, node kubeoneapi.HostConfig) error {
+	if ctx.Cluster.Versions.CgroupV2Supported() {
+		return nil
+	}
+
+	switch node.OperatingSystem {
+	case kubeoneapi.OperatingSystemNameUbuntu, kubeoneapi.OperatingSystemNameDebian:
+	default:
+		return nil
+	}
+
+	stdout, _, err := ctx.Runner.Run(forceCgroupV1Command, util.TemplateVariables{})
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(stdout) != "reboot" {
+		return nil
+	}
+
+	ctx.Logger.Infoln("Rebooting to switch to cgroup v1…")
+
+	// the stale connection is replaced once the node is back up
+	return wait.Poll(5*time.Second, timeoutNodeReboot, func() (bool, error) {
+		conn, err := ctx.Connector.Connect(node)
+		if err != nil {
+			return false, nil
+		}
+		ctx.Runner.Conn = conn
+
+		stdout, _, err := ctx.Runner.Run(cgroupFSCommand, util.TemplateVariables{})
+		if err != nil {
+			return false, nil
+		}
+
+		return strings.TrimSpace(stdout) != "cgroup2fs", nil
+	})
+}
+
+const cgroupFSCommand = `stat -fc %T /sys/fs/cgroup`
+
+const forceCgroupV1Command = `
+if [[ "$(` + cgroupFSCommand + `)" != "cgroup2fs" ]]; then exit 0; fi
+
+sudo mkdir -p /etc/default/grub.d
+cat <<EOF |sudo tee /etc/default/grub.d/99-kubeone-cgroup-v1.cfg
+GRUB_CMDLINE_LINUX="\${GRUB_CMDLINE_LINUX} systemd.unified_cgroup_hierarchy=0"
+EOF
+sudo update-grub
+
+# reboot once the SSH session is closed
+sudo systemd-run --on-active=5 /bin/systemctl reboot
+echo reboot
+`
+
 func installKubeadm(ctx *util.Context, node kubeoneapi.HostConfig) error {
 	var err error
 
 	switch node.OperatingSystem {
-	case "ubuntu", "debian":
-		err = installKubeadmDebian(ctx)
+	case kubeoneapi.OperatingSystemNameUbuntu, kubeoneapi.OperatingSystemNameDebian:
+		err = installKubeadmDebian(ctx, node)
 
-	case "coreos":
+	case kubeoneapi.OperatingSystemNameCoreOS:
 		err = installKubeadmCoreOS(ctx)
 
-	case "centos":
-		err = installKubeadmCentOS(ctx)
+	case kubeoneapi.OperatingSystemNameFlatcar:
+		err = installKubeadmFlatcar(ctx)
+
+	case kubeoneapi.OperatingSystemNameCentOS, kubeoneapi.OperatingSystemNameAmazonLinux:
+		err = installKubeadmCentOS(ctx, node)
 
 	default:
 		err = errors.Errorf("'%s' is not a supported operating system", node.OperatingSystem)
@@ -150,31 +261,40 @@ func installKubeadm(ctx *util.Context, node kubeoneapi.HostConfig) error {
 	return err
 }
 
-func installKubeadmDebian(ctx *util.Context) error {
+func installKubeadmDebian(ctx *util.Context, node kubeoneapi.HostConfig) error {
+	docker := dockerVersion
+	if node.OperatingSystem == kubeoneapi.OperatingSystemNameUbuntu {
+		if v, ok := ubuntuDockerVersions[node.OperatingSystemVersion]; ok {
+			docker = v
+		}
+	}
+
 	_, _, err := ctx.Runner.Run(kubeadmDebianCommand, util.TemplateVariables{
 		"KUBERNETES_VERSION": ctx.Cluster.Versions.Kubernetes,
-		"DOCKER_VERSION":     dockerVersion,
+		"DOCKER_VERSION":     docker,
 		"CNI_VERSION":        ctx.Cluster.Versions.KubernetesCNIVersion(),
+		"APT_MIRROR":         ctx.Cluster.PackageMirrors.Apt(),
+		"CRIO":               ctx.Cluster.ContainerRuntime.Runtime == kubeoneapi.ContainerRuntimeCRIO,
 	})
 
 	return errors.WithStack(err)
 }
 
 const kubeadmDebianCommand = `
-sudo swapoff -a
-sudo sed -i '/.*swap.*/d' /etc/fstab
 
 source /etc/os-release
 source /etc/kubeone/proxy-env
 
 # Short-Circuit the installation if it was arleady executed
-if type docker &>/dev/null && type kubelet &>/dev/null; then exit 0; fi
+if {{ if not .CRIO }}type docker &>/dev/null && {{ end }}type kubelet &>/dev/null; then exit 0; fi
 
+{{ if not .CRIO -}}
 sudo mkdir -p /etc/docker
 cat <<EOF |sudo tee /etc/docker/daemon.json
 {"storage-driver": "overlay2"}
 EOF
 
+{{ end -}}
 sudo apt-get update
 sudo apt-get install -y --no-install-recommends \
      apt-transport-https \
@@ -185,42 +305,47 @@ sudo apt-get install -y --no-install-recommends \
      rsync \
      tree
 
-curl -fsSL https://packages.cloud.google.com/apt/doc/apt-key.gpg | sudo apt-key add -
+curl -fsSL {{ .APT_MIRROR }}/doc/apt-key.gpg | sudo apt-key add -
+{{- if not .CRIO }}
 curl -fsSL https://download.docker.com/linux/${ID}/gpg | sudo apt-key add -
 
 echo "deb [arch=amd64] https://download.docker.com/linux/${ID} $(lsb_release -sc) stable" | \
      sudo tee /etc/apt/sources.list.d/docker.list
+{{- end }}
 
 # You'd think that kubernetes-$(lsb_release -sc) belongs there instead, but the debian repo
 # contains neither kubeadm nor kubelet, and the docs themselves suggest using xenial repo.
-echo "deb http://apt.kubernetes.io/ kubernetes-xenial main" | \
+# The same holds for every later Ubuntu release up to jammy (22.04).
+echo "deb {{ .APT_MIRROR }} kubernetes-xenial main" | \
      sudo tee /etc/apt/sources.list.d/kubernetes.list
 sudo apt-get update
 
+{{ if not .CRIO -}}
 docker_ver=$(apt-cache madison docker-ce | grep "{{ .DOCKER_VERSION }}" | head -1 | awk '{print $3}')
+{{ end -}}
 kube_ver=$(apt-cache madison kubelet | grep "{{ .KUBERNETES_VERSION }}" | head -1 | awk '{print $3}')
 cni_ver=$(apt-cache madison kubernetes-cni | grep "{{ .CNI_VERSION }}" | head -1 | awk '{print $3}')
 
-sudo apt-mark unhold docker-ce kubelet kubeadm kubectl kubernetes-cni
+sudo apt-mark unhold {{ if not .CRIO }}docker-ce {{ end }}kubelet kubeadm kubectl kubernetes-cni
 sudo apt-get install -y --no-install-recommends \
+{{- if not .CRIO }}
      docker-ce=${docker_ver} \
+{{- end }}
      kubeadm=${kube_ver} \
      kubectl=${kube_ver} \
      kubelet=${kube_ver} \
      kubernetes-cni=${cni_ver}
-sudo apt-mark hold docker-ce kubelet kubeadm kubectl kubernetes-cni
+sudo apt-mark hold {{ if not .CRIO }}docker-ce {{ end }}kubelet kubeadm kubectl kubernetes-cni
 `
 
 const kubeadmCentOSCommand = `
-sudo swapoff -a
-sudo sed -i '/.*swap.*/d' /etc/fstab
 sudo setenforce 0 || true
 sudo sed -i s/SELINUX=enforcing/SELINUX=permissive/g /etc/sysconfig/selinux
 
 source /etc/kubeone/proxy-env
 
 # Short-Circuit the installation if it was arleady executed
-if type docker &>/dev/null && type kubelet &>/dev/null; then exit 0; fi
+if {{ if not .CRIO }}type docker &>/dev/null && {{ end }}type kubelet &>/dev/null; then exit 0; fi
 
 cat <<EOF |sudo tee  /etc/sysctl.d/k8s.conf
 net.bridge.bridge-nf-call-ip6tables = 1
@@ -231,26 +356,36 @@ sudo sysctl --system
 cat <<EOF |sudo tee /etc/yum.repos.d/kubernetes.repo
 [kubernetes]
 name=Kubernetes
-baseurl=https://packages.cloud.google.com/yum/repos/kubernetes-el7-x86_64
+baseurl={{ .YUM_MIRROR }}/repos/kubernetes-el7-x86_64
 enabled=1
 gpgcheck=1
 repo_gpgcheck=1
-gpgkey=https://packages.cloud.google.com/yum/doc/yum-key.gpg https://packages.cloud.google.com/yum/doc/rpm-package-key.gpg
+gpgkey={{ .YUM_MIRROR }}/doc/yum-key.gpg {{ .YUM_MIRROR }}/doc/rpm-package-key.gpg
 exclude=kube*
 EOF
+{{- if and .AMAZON_LINUX (not .CRIO) }}
+
+# Docker is shipped in the docker topic of Amazon Linux Extras
+sudo amazon-linux-extras enable docker
+{{- end }}
 
 sudo yum install -y --disableexcludes=kubernetes \
-			docker kubelet-{{ .KUBERNETES_VERSION }}-0\
+			{{ if not .CRIO }}docker {{ end }}kubelet-{{ .KUBERNETES_VERSION }}-0\
 			kubeadm-{{ .KUBERNETES_VERSION }}-0 \
 			kubectl-{{ .KUBERNETES_VERSION }}-0 \
 			kubernetes-cni-{{ .CNI_VERSION }}-0
+{{- if not .CRIO }}
 sudo systemctl enable --now docker
+{{- end }}
 `
 
-func installKubeadmCentOS(ctx *util.Context) error {
+func installKubeadmCentOS(ctx *util.Context, node kubeoneapi.HostConfig) error {
 	_, _, err := ctx.Runner.Run(kubeadmCentOSCommand, util.TemplateVariables{
+		"AMAZON_LINUX":       node.OperatingSystem == kubeoneapi.OperatingSystemNameAmazonLinux,
 		"KUBERNETES_VERSION": ctx.Cluster.Versions.Kubernetes,
 		"CNI_VERSION":        ctx.Cluster.Versions.KubernetesCNIVersion(),
+		"YUM_MIRROR":         ctx.Cluster.PackageMirrors.Yum(),
+		"CRIO":               ctx.Cluster.ContainerRuntime.Runtime == kubeoneapi.ContainerRuntimeCRIO,
 	})
 	return err
 }
@@ -293,6 +428,225 @@ sudo systemctl enable docker.service kubelet.service
 sudo systemctl start docker.service kubelet.service
 `
 
+func installKubeadmFlatcar(ctx *util.Context) error {
+	_, _, err := ctx.Runner.Run(kubeadmFlatcarCommand, util.TemplateVariables{
+		"KUBERNETES_VERSION": ctx.Cluster.Versions.Kubernetes,
+		"CNI_VERSION":        fmt.Sprintf("v%s", ctx.Cluster.Versions.KubernetesCNIVersion()),
+	})
+
+	return err
+}
+
+// kubeadmFlatcarCommand installs the Kubernetes binaries to /opt/bin, as
+// Flatcar has no package manager and a read-only /usr, and sets kubelet
+// up with systemd units of its own
+const kubeadmFlatcarCommand = `
+source /etc/kubeone/proxy-env
+
+sudo mkdir -p /opt/cni/bin /opt/bin
+curl -fsSL "https://github.com/containernetworking/plugins/releases/download/{{ .CNI_VERSION }}/cni-plugins-amd64-{{ .CNI_VERSION }}.tgz" | \
+     sudo tar -C /opt/cni/bin -xz
+
+RELEASE="v{{ .KUBERNETES_VERSION }}"
+
+cd /opt/bin
+sudo curl -fsSL --remote-name-all \
+     https://storage.googleapis.com/kubernetes-release/release/${RELEASE}/bin/linux/amd64/{kubeadm,kubelet,kubectl}
+sudo chmod +x {kubeadm,kubelet,kubectl}
+
+sudo mkdir -p /etc/kubernetes/pki /etc/kubernetes/manifests /var/lib/kubelet/volumeplugins
+
+cat <<EOF |sudo tee /etc/systemd/system/kubelet.service
+[Unit]
+Description=kubelet: The Kubernetes Node Agent
+Documentation=https://kubernetes.io/docs/home/
+Requires=docker.service
+After=docker.service
+
+[Service]
+ExecStart=/opt/bin/kubelet
+Restart=always
+StartLimitInterval=0
+RestartSec=10
+
+[Install]
+WantedBy=multi-user.target
+EOF
+
+# kubeadm writes the kubelet flags to /var/lib/kubelet/kubeadm-flags.env,
+# the drop-in passes them along with the configuration it generates
+sudo mkdir -p /etc/systemd/system/kubelet.service.d
+cat <<EOF |sudo tee /etc/systemd/system/kubelet.service.d/10-kubeadm.conf
+[Service]
+Environment="KUBELET_KUBECONFIG_ARGS=--bootstrap-kubeconfig=/etc/kubernetes/bootstrap-kubelet.conf --kubeconfig=/etc/kubernetes/kubelet.conf"
+Environment="KUBELET_CONFIG_ARGS=--config=/var/lib/kubelet/config.yaml"
+EnvironmentFile=-/var/lib/kubelet/kubeadm-flags.env
+EnvironmentFile=-/etc/default/kubelet
+ExecStart=
+ExecStart=/opt/bin/kubelet \$KUBELET_KUBECONFIG_ARGS \$KUBELET_CONFIG_ARGS \$KUBELET_KUBEADM_ARGS \$KUBELET_EXTRA_ARGS
+EOF
+
+sudo systemctl daemon-reload
+sudo systemctl enable --now docker.service
+sudo systemctl enable kubelet.service
+`
+
+func installCRIO(ctx *util.Context, node kubeoneapi.HostConfig) error {
+	var cmd string
+
+	// CRI-O packages are only published for Ubuntu and CentOS
+	switch node.OperatingSystem {
+	case kubeoneapi.OperatingSystemNameUbuntu:
+		cmd = crioUbuntuCommand
+
+	case kubeoneapi.OperatingSystemNameCentOS:
+		cmd = crioCentOSCommand
+
+	default:
+		return errors.Errorf("CRI-O is not supported on '%s'", node.OperatingSystem)
+	}
+
+	crioVersion := ctx.Cluster.Versions.CRIOVersion()
+
+	_, _, err := ctx.Runner.Run(cmd, util.TemplateVariables{
+		"CRIO_VERSION":     crioVersion,
+		"CRIO_CBS_VERSION": strings.Replace(crioVersion, ".", "", -1),
+	})
+	if err != nil {
+		return err
+	}
+
+	// short image names are resolved against these registries
+	registries := []string{"docker.io", "quay.io", "k8s.gcr.io"}
+	if ctx.Cluster.ImageRepository != "" {
+		registries = append([]string{strings.TrimSuffix(ctx.Cluster.ImageRepository, "/")}, registries...)
+	}
+
+	_, _, err = ctx.Runner.Run(crioConfigCommand, util.TemplateVariables{
+		"REGISTRIES": registries,
+		"MIRRORS":    crioMirrors(ctx.Cluster.ContainerRuntime.RegistryMirrors),
+	})
+
+	return err
+}
+
+type crioMirror struct {
+	Location string
+	Insecure bool
+}
+
+type crioRegistry struct {
+	Prefix  string
+	Mirrors []crioMirror
+}
+
+// crioMirrors converts the registry mirrors to the [[registry]] entries of
+// registries.conf, sorted by registry so the rendered file is stable
+func crioMirrors(registryMirrors map[string][]string) []crioRegistry {
+	registries := make([]crioRegistry, 0, len(registryMirrors))
+	for prefix, mirrors := range registryMirrors {
+		registry := crioRegistry{Prefix: prefix}
+		for _, mirror := range mirrors {
+			u, err := url.Parse(mirror)
+			if err != nil {
+				continue
+			}
+			registry.Mirrors = append(registry.Mirrors, crioMirror{
+				Location: u.Host + strings.TrimSuffix(u.Path, "/"),
+				Insecure: u.Scheme == "http",
+			})
+		}
+		registries = append(registries, registry)
+	}
+
+	sort.Slice(registries, func(i, j int) bool {
+		return registries[i].Prefix < registries[j].Prefix
+	})
+
+	return registries
+}
+
+const crioPrerequisitesCommand = `
+sudo modprobe overlay
+sudo modprobe br_netfilter
+cat <<EOF |sudo tee /etc/modules-load.d/crio.conf
+overlay
+br_netfilter
+EOF
+
+cat <<EOF |sudo tee /etc/sysctl.d/99-kubernetes-cri.conf
+net.bridge.bridge-nf-call-iptables  = 1
+net.bridge.bridge-nf-call-ip6tables = 1
+net.ipv4.ip_forward                 = 1
+EOF
+sudo sysctl --system
+`
+
+const crioUbuntuCommand = `
+source /etc/kubeone/proxy-env
+
+# Short-Circuit the installation if it was arleady executed
+if type crio &>/dev/null; then exit 0; fi
+` + crioPrerequisitesCommand + `
+sudo apt-get update
+sudo apt-get install -y --no-install-recommends software-properties-common
+sudo add-apt-repository -y ppa:projectatomic/ppa
+sudo apt-get update
+sudo apt-get install -y --no-install-recommends cri-o-{{ .CRIO_VERSION }}
+`
+
+const crioCentOSCommand = `
+source /etc/kubeone/proxy-env
+
+# Short-Circuit the installation if it was arleady executed
+if type crio &>/dev/null; then exit 0; fi
+` + crioPrerequisitesCommand + `
+cat <<EOF |sudo tee /etc/yum.repos.d/crio.repo
+[crio]
+name=CRI-O
+baseurl=https://cbs.centos.org/repos/paas7-crio-{{ .CRIO_CBS_VERSION }}-candidate/x86_64/os/
+enabled=1
+gpgcheck=0
+EOF
+
+sudo yum install -y cri-o
+`
+
+const crioConfigCommand = `
+sudo mkdir -p /etc/containers
+cat <<EOF |sudo tee /etc/containers/registries.conf
+{{ if .MIRRORS -}}
+unqualified-search-registries = [{{ range $i, $r := .REGISTRIES }}{{ if $i }}, {{ end }}'{{ $r }}'{{ end }}]
+{{ range .MIRRORS }}
+[[registry]]
+prefix = '{{ .Prefix }}'
+location = '{{ .Prefix }}'
+{{ range .Mirrors }}
+[[registry.mirror]]
+location = '{{ .Location }}'
+insecure = {{ .Insecure }}
+{{ end -}}
+{{ end -}}
+{{ else -}}
+[registries.search]
+registries = [{{ range $i, $r := .REGISTRIES }}{{ if $i }}, {{ end }}'{{ $r }}'{{ end }}]
+
+[registries.insecure]
+registries = []
+
+[registries.block]
+registries = []
+{{ end -}}
+EOF
+
+# kubelet uses the cgroupfs driver by default
+sudo sed -i 's/^#\?\s*cgroup_manager = .*/cgroup_manager = "cgroupfs"/' /etc/crio/crio.conf
+
+sudo systemctl daemon-reload
+sudo systemctl enable crio.service
+sudo systemctl restart crio.service
+`
+
 func deployConfigurationFiles(ctx *util.Context) error {
 	err := ctx.Configuration.UploadTo(ctx.Runner.Conn, ctx.WorkDir)
 	if err != nil {
@@ -312,24 +666,100 @@ sudo chmod 600 /etc/kubernetes/cloud-config
 	return err
 }
 
-func configureDockerDaemonProxy(ctx *util.Context) error {
+// configureDockerMirrors adds the docker.io mirrors to the docker daemon
+// configuration, docker is only restarted if the configuration changed
+func configureDockerMirrors(ctx *util.Context, node kubeoneapi.HostConfig) error {
+	containerRuntime := ctx.Cluster.ContainerRuntime
+	mirrors := containerRuntime.RegistryMirrors[kubeoneapi.DockerHubRegistry]
+	if containerRuntime.Runtime != kubeoneapi.ContainerRuntimeDocker || len(mirrors) == 0 {
+		return nil
+	}
+
+	daemonConfig := map[string]interface{}{
+		"registry-mirrors": mirrors,
+	}
+	switch node.OperatingSystem {
+	case kubeoneapi.OperatingSystemNameUbuntu, kubeoneapi.OperatingSystemNameDebian:
+		// keep the storage driver set when installing docker
+		daemonConfig["storage-driver"] = "overlay2"
+	}
+
+	config, err := json.Marshal(daemonConfig)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal docker daemon configuration")
+	}
+
+	ctx.Logger.Infoln("Configuring docker registry mirrors…")
+	_, _, err = ctx.Runner.Run(dockerMirrorsCommand, util.TemplateVariables{
+		"CONFIG": string(config),
+	})
+
+	return err
+}
+
+const dockerMirrorsCommand = `
+config='{{ .CONFIG }}'
+if [[ "$(sudo cat /etc/docker/daemon.json 2>/dev/null)" == "$config" ]]; then exit 0; fi
+
+sudo mkdir -p /etc/docker
+echo "$config" |sudo tee /etc/docker/daemon.json
+sudo systemctl restart docker.service
+`
+
+func configureContainerRuntimeProxy(ctx *util.Context) error {
 	if ctx.Cluster.Proxy.HTTP == "" && ctx.Cluster.Proxy.HTTPS == "" && ctx.Cluster.Proxy.NoProxy == "" {
 		return nil
 	}
 
-	ctx.Logger.Infoln("Configuring docker proxy…")
-	_, _, err := ctx.Runner.Run(dockerDaemonProxy, util.TemplateVariables{})
+	ctx.Logger.Infoln("Configuring container runtime proxy…")
+	_, _, err := ctx.Runner.Run(containerRuntimeProxy, util.TemplateVariables{
+		"SERVICE": ctx.Cluster.ContainerRuntime.Service(),
+	})
 
 	return err
 }
 
-const dockerDaemonProxy = `
-# Configure HTTP/HTTPS proxy for Docker
-sudo mkdir -p /etc/systemd/system/docker.service.d
-cat <<EOF |sudo tee /etc/systemd/system/docker.service.d/http-proxy.conf
+// configureNodeProxy sets the proxy environment variables system-wide, for
+// login shells and for kubelet
+func configureNodeProxy(ctx *util.Context) error {
+	if ctx.Cluster.Proxy.HTTP == "" && ctx.Cluster.Proxy.HTTPS == "" && ctx.Cluster.Proxy.NoProxy == "" {
+		return nil
+	}
+
+	ctx.Logger.Infoln("Configuring node proxy…")
+	_, _, err := ctx.Runner.Run(nodeProxyCommand, util.TemplateVariables{})
+
+	return err
+}
+
+const nodeProxyCommand = `
+# /etc/environment isn't a shell script, replace the proxy variables instead
+# of sourcing the kubeone environment file
+sudo sed -i '/^\(HTTP_PROXY\|http_proxy\|HTTPS_PROXY\|https_proxy\|NO_PROXY\|no_proxy\)=/d' /etc/environment
+cat /etc/kubeone/proxy-env |sudo tee -a /etc/environment
+
+cat <<EOF |sudo tee /etc/profile.d/proxy.sh
+set -a
+. /etc/kubeone/proxy-env
+set +a
+EOF
+
+sudo mkdir -p /etc/systemd/system/kubelet.service.d
+cat <<EOF |sudo tee /etc/systemd/system/kubelet.service.d/http-proxy.conf
+[Service]
+EnvironmentFile=/etc/kubeone/proxy-env
+EOF
+sudo systemctl daemon-reload
+if sudo systemctl status kubelet &>/dev/null; then sudo systemctl restart kubelet; fi
+`
+
+const containerRuntimeProxy = `
+# Configure HTTP/HTTPS proxy for the container runtime
+sudo mkdir -p /etc/systemd/system/{{ .SERVICE }}.service.d
+cat <<EOF |sudo tee /etc/systemd/system/{{ .SERVICE }}.service.d/http-proxy.conf
 [Service]
 EnvironmentFile=/etc/kubeone/proxy-env
 EOF
 sudo systemctl daemon-reload
-if sudo systemctl status docker  &>/dev/null; then sudo systemctl restart docker; fi
+if sudo systemctl status {{ .SERVICE }}  &>/dev/null; then sudo systemctl restart {{ .SERVICE }}; fi
 `
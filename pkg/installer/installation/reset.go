@@ -17,6 +17,8 @@ limitations under the License.
 package installation
 
 import (
+	"github.com/pkg/errors"
+
 	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
 	"github.com/kubermatic/kubeone/pkg/ssh"
 	"github.com/kubermatic/kubeone/pkg/templates/machinecontroller"
@@ -28,6 +30,14 @@ func Reset(ctx *util.Context) error {
 	ctx.Logger.Infoln("Resetting kubeadm…")
 
 	if ctx.DestroyWorkers {
+		if err := util.BuildKubernetesClientset(ctx); err != nil {
+			return errors.Wrap(err, "unable to build kubernetes clientset")
+		}
+
+		if err := drainWorkerNodes(ctx); err != nil {
+			return errors.Wrap(err, "unable to drain worker nodes")
+		}
+
 		if err := ctx.RunTaskOnLeader(destroyWorkers); err != nil {
 			return err
 		}
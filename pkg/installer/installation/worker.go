@@ -28,6 +28,16 @@ func createWorkerMachines(ctx *util.Context) error {
 		return nil
 	}
 
+	if ctx.SkipWorkers {
+		ctx.Logger.Info("Skipping creating worker machines as requested.")
+		return nil
+	}
+
+	if !ctx.Cluster.MachineController.Deploy {
+		ctx.Logger.Info("Skipping creating worker machines because machine-controller is not deployed.")
+		return nil
+	}
+
 	ctx.Logger.Infoln("Creating worker machines…")
 	return errors.Wrap(machinecontroller.DeployMachineDeployments(ctx), "failed to deploy Machines")
 }
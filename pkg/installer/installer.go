@@ -17,20 +17,50 @@ limitations under the License.
 package installer
 
 import (
+	"os"
+
 	"github.com/sirupsen/logrus"
 
 	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
 	"github.com/kubermatic/kubeone/pkg/installer/installation"
 	"github.com/kubermatic/kubeone/pkg/ssh"
+	"github.com/kubermatic/kubeone/pkg/templates/machinecontroller"
 	"github.com/kubermatic/kubeone/pkg/util"
+	"github.com/kubermatic/kubeone/pkg/util/credentials"
 )
 
 // Options groups the various possible options for running
 // the Kubernetes installation.
 type Options struct {
 	Verbose        bool
+	Debug          bool
 	BackupFile     string
 	DestroyWorkers bool
+	Adopt          bool
+	ForceDrain     bool
+	SkipWorkers    bool
+	Parallelism    int
+	Retry          bool
+	Metrics        *util.Metrics
+	// StateFile records the completed steps of the installation, the
+	// steps aren't recorded if it's empty
+	StateFile string
+	// IgnoreState runs all steps regardless of the recorded state
+	IgnoreState bool
+	// InCluster accesses the Kubernetes API with the service account of the
+	// pod kubeone runs in
+	InCluster bool
+	// PlanFile is where apply writes the JSON plan of the phases it runs,
+	// the plan isn't written if it's empty
+	PlanFile string
+	// Approve is asked to confirm the plan of apply before the cluster is
+	// changed, the changes are applied without confirmation if it's nil
+	Approve func(plan string) (bool, error)
+	// TargetHost is the public address of the only host apply provisions
+	TargetHost string
+	// Prune deletes the MachineDeployments created by KubeOne which are no
+	// longer in the manifest
+	Prune bool
 }
 
 // Installer is entrypoint for installation process
@@ -50,7 +80,48 @@ func NewInstaller(cluster *kubeoneapi.KubeOneCluster, logger *logrus.Logger) *In
 
 // Install run the installation process
 func (i *Installer) Install(options *Options) error {
-	return installation.Install(i.createContext(options))
+	if options.Adopt {
+		return installation.Adopt(i.createContext(options))
+	}
+
+	ctx := i.createContext(options)
+	if options.StateFile != "" {
+		manifestHash, err := util.ManifestHash(i.cluster)
+		if err != nil {
+			return err
+		}
+
+		state, err := util.LoadState(options.StateFile, i.cluster.Name, manifestHash, options.IgnoreState)
+		if err != nil {
+			return err
+		}
+		ctx.State = state
+	}
+
+	return installation.Install(ctx)
+}
+
+// Apply reconciles the cluster with the manifest, changing only the
+// subsystems which differ from it
+func (i *Installer) Apply(options *Options) error {
+	return installation.Apply(i.createContext(options))
+}
+
+// Diff returns the differences between the existing cluster and the manifest
+func (i *Installer) Diff(options *Options) ([]util.Difference, error) {
+	return installation.Diff(i.createContext(options))
+}
+
+// Drift returns the state of the existing cluster and the state described by
+// the manifest, rendered to be compared line by line
+func (i *Installer) Drift(options *Options) (string, string, error) {
+	return installation.Drift(i.createContext(options))
+}
+
+// Plan returns the changes install would make to the cluster, which doesn't
+// need to be provisioned yet
+func (i *Installer) Plan(options *Options) ([]util.Difference, error) {
+	return installation.Plan(i.createContext(options))
 }
 
 // Reset resets cluster:
@@ -60,19 +131,64 @@ func (i *Installer) Reset(options *Options) error {
 	return installation.Reset(i.createContext(options))
 }
 
+// RollbackMachineController rolls machine-controller back to the given version
+func (i *Installer) RollbackMachineController(options *Options, version string) error {
+	return installation.RollbackMachineController(i.createContext(options), version)
+}
+
+// RotateMachineControllerCertificates rotates the machine-controller webhook certificates
+func (i *Installer) RotateMachineControllerCertificates(options *Options) error {
+	return installation.RotateMachineControllerCertificates(i.createContext(options))
+}
+
+// MachineControllerStatus returns the status of machine-controller and its webhook
+func (i *Installer) MachineControllerStatus(options *Options) (*machinecontroller.Status, error) {
+	return installation.MachineControllerStatus(i.createContext(options))
+}
+
+// Exec runs the command on the cluster nodes
+func (i *Installer) Exec(options *Options, command string, allWorkers bool) error {
+	return installation.Exec(i.createContext(options), command, allWorkers)
+}
+
+// DebugBundle collects diagnostic information about the cluster into the output archive
+func (i *Installer) DebugBundle(options *Options, manifest, output string) error {
+	return installation.DebugBundle(i.createContext(options), manifest, output)
+}
+
 // createContext creates a basic, non-host bound context with
 // all relevant information, but *no* Runner yet. The various
 // task helper functions will take care of setting up Runner
 // structs for each task individually.
 func (i *Installer) createContext(options *Options) *util.Context {
-	return &util.Context{
+	progress := util.NewProgressReporter(os.Stdout)
+	if options.Metrics != nil {
+		progress = util.MultiProgressReporter{progress, options.Metrics}
+	}
+
+	ctx := &util.Context{
 		Cluster:        i.cluster,
 		Connector:      ssh.NewConnector(),
 		Configuration:  util.NewConfiguration(),
 		WorkDir:        "kubeone",
 		Logger:         i.logger,
 		Verbose:        options.Verbose,
+		Debug:          options.Debug,
 		BackupFile:     options.BackupFile,
 		DestroyWorkers: options.DestroyWorkers,
+		ForceDrain:     options.ForceDrain,
+		SkipWorkers:    options.SkipWorkers,
+		Parallelism:    options.Parallelism,
+		Retry:          options.Retry,
+		InCluster:      options.InCluster,
+		PlanFile:       options.PlanFile,
+		Approve:        options.Approve,
+		TargetHost:     options.TargetHost,
+		Prune:          options.Prune,
+		Progress:       progress,
+		Redactor:       util.NewRedactor(credentials.SensitiveValues(i.cluster)...),
 	}
+	ctx.Events = util.NewClusterEventRecorder(ctx)
+
+	return ctx
 }
@@ -0,0 +1,116 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package testclient provides an in-memory client for the unit tests of
+// code working with the dynamic client.
+package testclient
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MemoryClient is a minimal in-memory client storing objects by their type,
+// namespace and name
+type MemoryClient struct {
+	objects map[string]runtime.Object
+}
+
+// NewMemoryClient returns a client storing copies of the given objects
+func NewMemoryClient(objs ...runtime.Object) *MemoryClient {
+	c := &MemoryClient{objects: map[string]runtime.Object{}}
+	for _, obj := range objs {
+		c.objects[ObjectKey(obj)] = obj.DeepCopyObject()
+	}
+	return c
+}
+
+// ObjectKey returns the key the object is stored with
+func ObjectKey(obj runtime.Object) string {
+	m := obj.(metav1.Object)
+	return fmt.Sprintf("%T/%s/%s", obj, m.GetNamespace(), m.GetName())
+}
+
+// Get copies the stored object into obj
+func (c *MemoryClient) Get(_ context.Context, key dynclient.ObjectKey, obj runtime.Object) error {
+	stored, ok := c.objects[fmt.Sprintf("%T/%s/%s", obj, key.Namespace, key.Name)]
+	if !ok {
+		return k8serrors.NewNotFound(schema.GroupResource{}, key.Name)
+	}
+	reflect.ValueOf(obj).Elem().Set(reflect.ValueOf(stored.DeepCopyObject()).Elem())
+	return nil
+}
+
+// List returns the objects matching the label selector. The field selector
+// is only supported for pods and spec.nodeName.
+func (c *MemoryClient) List(_ context.Context, opts *dynclient.ListOptions, list runtime.Object) error {
+	var items []runtime.Object
+	for _, obj := range c.objects {
+		if fmt.Sprintf("%TList", obj) != fmt.Sprintf("%T", list) {
+			continue
+		}
+		if opts != nil && opts.LabelSelector != nil && !opts.LabelSelector.Matches(labels.Set(obj.(metav1.Object).GetLabels())) {
+			continue
+		}
+		if pod, ok := obj.(*corev1.Pod); ok && opts != nil && opts.FieldSelector != nil &&
+			!opts.FieldSelector.Matches(fields.Set{"spec.nodeName": pod.Spec.NodeName}) {
+			continue
+		}
+		items = append(items, obj.DeepCopyObject())
+	}
+
+	return meta.SetList(list, items)
+}
+
+// Create stores a copy of the object
+func (c *MemoryClient) Create(_ context.Context, obj runtime.Object) error {
+	c.objects[ObjectKey(obj)] = obj.DeepCopyObject()
+	return nil
+}
+
+// Delete removes the stored object
+func (c *MemoryClient) Delete(_ context.Context, obj runtime.Object, _ ...dynclient.DeleteOptionFunc) error {
+	if _, ok := c.objects[ObjectKey(obj)]; !ok {
+		return k8serrors.NewNotFound(schema.GroupResource{}, obj.(metav1.Object).GetName())
+	}
+	delete(c.objects, ObjectKey(obj))
+	return nil
+}
+
+// Update replaces the stored object with a copy of obj
+func (c *MemoryClient) Update(_ context.Context, obj runtime.Object) error {
+	if _, ok := c.objects[ObjectKey(obj)]; !ok {
+		return k8serrors.NewNotFound(schema.GroupResource{}, obj.(metav1.Object).GetName())
+	}
+	c.objects[ObjectKey(obj)] = obj.DeepCopyObject()
+	return nil
+}
+
+// Status returns the client itself, the status isn't stored separately
+func (c *MemoryClient) Status() dynclient.StatusWriter {
+	return c
+}
@@ -33,27 +33,44 @@ import (
 	"golang.org/x/crypto/ssh/agent"
 )
 
-const socketEnvPrefix = "env:"
+const (
+	socketEnvPrefix = "env:"
+
+	// keepaliveRequest is a global request OpenSSH servers reply to, used to
+	// check whether a connection is still alive
+	keepaliveRequest = "keepalive@openssh.com"
+	// keepaliveTimeout is how long to wait for the keepalive reply before
+	// considering the connection stale
+	keepaliveTimeout = 5 * time.Second
+)
 
 // Connection represents an established connection to an SSH server.
 type Connection interface {
 	Exec(cmd string) (stdout string, stderr string, exitCode int, err error)
 	File(filename string, flags int) (io.ReadWriteCloser, error)
 	Stream(cmd string, stdout io.Writer, stderr io.Writer) (exitCode int, err error)
+	// Tunnel opens a connection to addr, dialed from the remote host
+	Tunnel(network, addr string) (net.Conn, error)
 	io.Closer
 }
 
 // Opts represents all the possible options for connecting to
 // a remote server via SSH.
 type Opts struct {
-	Username    string
-	Password    string
-	Hostname    string
-	Port        int
-	PrivateKey  string
-	KeyFile     string
+	Username   string
+	Password   string
+	Hostname   string
+	Port       int
+	PrivateKey string
+	KeyFile    string
+	// KeyFiles are tried in order after PrivateKey and KeyFile
+	KeyFiles    []string
 	AgentSocket string
 	Timeout     time.Duration
+	// StrictHostKeyChecking verifies the host key against KnownHostsFile,
+	// ~/.ssh/known_hosts by default, instead of accepting any host key
+	StrictHostKeyChecking bool
+	KnownHostsFile        string
 }
 
 func validateOptions(o Opts) (Opts, error) {
@@ -65,7 +82,7 @@ func validateOptions(o Opts) (Opts, error) {
 		return o, errors.New("no hostname specified for SSH connection")
 	}
 
-	if len(o.Password) == 0 && len(o.PrivateKey) == 0 && len(o.KeyFile) == 0 && len(o.AgentSocket) == 0 {
+	if len(o.Password) == 0 && len(o.PrivateKey) == 0 && len(o.KeyFile) == 0 && len(o.KeyFiles) == 0 && len(o.AgentSocket) == 0 {
 		return o, errors.New("must specify at least one of password, private key, keyfile or agent socket")
 	}
 
@@ -110,13 +127,33 @@ func NewConnection(o Opts) (Connection, error) {
 		authMethods = append(authMethods, ssh.Password(o.Password))
 	}
 
+	var signers []ssh.Signer
 	if len(o.PrivateKey) > 0 {
-		signer, parseErr := ssh.ParsePrivateKey([]byte(o.PrivateKey))
+		signer, parseErr := parsePrivateKey([]byte(o.PrivateKey))
 		if parseErr != nil {
 			return nil, errors.Wrap(parseErr, "the given SSH key could not be parsed (note that password-protected keys are not supported)")
 		}
 
-		authMethods = append(authMethods, ssh.PublicKeys(signer))
+		signers = append(signers, signer)
+	}
+
+	for _, keyFile := range o.KeyFiles {
+		content, readErr := ioutil.ReadFile(keyFile)
+		if readErr != nil {
+			return nil, errors.Wrapf(readErr, "failed to read keyfile %q", keyFile)
+		}
+
+		signer, parseErr := parsePrivateKey(content)
+		if parseErr != nil {
+			return nil, errors.Wrapf(parseErr, "the SSH key %q could not be parsed (note that password-protected keys are not supported)", keyFile)
+		}
+
+		signers = append(signers, signer)
+	}
+
+	// the keys are offered in order until the server accepts one
+	if len(signers) > 0 {
+		authMethods = append(authMethods, ssh.PublicKeys(signers...))
 	}
 
 	if len(o.AgentSocket) > 0 {
@@ -146,11 +183,19 @@ func NewConnection(o Opts) (Connection, error) {
 		authMethods = append(authMethods, ssh.PublicKeys(signers...))
 	}
 
+	hostKeyCallback := ssh.InsecureIgnoreHostKey()
+	if o.StrictHostKeyChecking {
+		hostKeyCallback, err = knownHostsCallback(o.KnownHostsFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	sshConfig := &ssh.ClientConfig{
 		User:            o.Username,
 		Timeout:         o.Timeout,
 		Auth:            authMethods,
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		HostKeyCallback: hostKeyCallback,
 	}
 
 	// do not use fmt.Sprintf() to allow proper IPv6 handling if hostname is an IP address
@@ -217,6 +262,44 @@ func (c *connection) Exec(cmd string) (string, string, int, error) {
 	return strings.TrimSpace(stdoutBuf.String()), strings.TrimSpace(stderrBuf.String()), exitCode, err
 }
 
+func (c *connection) Tunnel(network, addr string) (net.Conn, error) {
+	c.mu.Lock()
+	client := c.sshclient
+	c.mu.Unlock()
+
+	if client == nil {
+		return nil, errors.New("connection closed")
+	}
+
+	conn, err := client.Dial(network, addr)
+	return conn, errors.Wrapf(err, "failed to dial %s through SSH", addr)
+}
+
+// alive reports whether the server still replies on the connection
+func (c *connection) alive() bool {
+	c.mu.Lock()
+	client := c.sshclient
+	c.mu.Unlock()
+
+	if client == nil {
+		return false
+	}
+
+	// a dead TCP connection may block until the OS gives up on it
+	reply := make(chan error, 1)
+	go func() {
+		_, _, err := client.SendRequest(keepaliveRequest, true, nil)
+		reply <- err
+	}()
+
+	select {
+	case err := <-reply:
+		return err == nil
+	case <-time.After(keepaliveTimeout):
+		return false
+	}
+}
+
 func (c *connection) session() (*ssh.Session, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
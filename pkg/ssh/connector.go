@@ -23,34 +23,63 @@ import (
 	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
 )
 
-// Connector holds a map of Connections
+// healthChecker is implemented by connections able to check whether they are
+// still alive
+type healthChecker interface {
+	alive() bool
+}
+
+// Connector holds a map of Connections, reusing a single connection per host
+// across tasks. Stale connections, e.g. after a node reboot or a network
+// interruption, are detected and replaced by new ones.
 type Connector struct {
+	// lock guards the maps, hostLocks serialize the health check and
+	// dialing per host, so a slow host doesn't block the other ones
 	lock        sync.Mutex
 	connections map[string]Connection
+	hostLocks   map[string]*sync.Mutex
 }
 
 // NewConnector constructor
 func NewConnector() *Connector {
 	return &Connector{
 		connections: make(map[string]Connection),
+		hostLocks:   make(map[string]*sync.Mutex),
 	}
 }
 
 // Connect to the node
 func (c *Connector) Connect(node kubeoneapi.HostConfig) (Connection, error) {
-	c.lock.Lock()
-	defer c.lock.Unlock()
+	hostLock := c.hostLock(node.PublicAddress)
+	hostLock.Lock()
+	defer hostLock.Unlock()
 	var err error
 
+	c.lock.Lock()
 	conn, found := c.connections[node.PublicAddress]
+	c.lock.Unlock()
+
+	if found {
+		if hc, ok := conn.(healthChecker); ok && !hc.alive() {
+			conn.Close()
+			c.lock.Lock()
+			delete(c.connections, node.PublicAddress)
+			c.lock.Unlock()
+			found = false
+		}
+	}
+
 	if !found {
 		opts := Opts{
-			Username:    node.SSHUsername,
-			Port:        node.SSHPort,
-			Hostname:    node.PublicAddress,
-			KeyFile:     node.SSHPrivateKeyFile,
-			AgentSocket: node.SSHAgentSocket,
-			Timeout:     10 * time.Second,
+			Username:              node.SSHUsername,
+			Port:                  node.SSHPort,
+			Hostname:              node.PublicAddress,
+			KeyFile:               node.SSHPrivateKeyFile,
+			KeyFiles:              node.SSHPrivateKeyFiles,
+			AgentSocket:           node.SSHAgentSocket,
+			Timeout:               10 * time.Second,
+			StrictHostKeyChecking: node.SSHStrictHostKeyChecking,
+			KnownHostsFile:        node.SSHKnownHostsFile,
 		}
 
 		conn, err = NewConnection(opts)
@@ -58,12 +87,28 @@ func (c *Connector) Connect(node kubeoneapi.HostConfig) (Connection, error) {
 			return nil, err
 		}
 
+		c.lock.Lock()
 		c.connections[node.PublicAddress] = conn
+		c.lock.Unlock()
 	}
 
 	return conn, nil
 }
 
+// hostLock returns the lock serializing the connection attempts to the host
+func (c *Connector) hostLock(host string) *sync.Mutex {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	l, found := c.hostLocks[host]
+	if !found {
+		l = &sync.Mutex{}
+		c.hostLocks[host] = l
+	}
+
+	return l
+}
+
 // CloseAll closes all connections
 func (c *Connector) CloseAll() {
 	c.lock.Lock()
@@ -0,0 +1,110 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssh
+
+import (
+	"testing"
+	"time"
+
+	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
+)
+
+// fakeConnection reports a fixed health and records whether it was closed.
+// If block is set, the health check waits until it's closed.
+type fakeConnection struct {
+	Connection
+	healthy bool
+	closed  bool
+	block   chan struct{}
+}
+
+func (f *fakeConnection) alive() bool {
+	if f.block != nil {
+		<-f.block
+	}
+	return f.healthy
+}
+
+func (f *fakeConnection) Close() error {
+	f.closed = true
+	return nil
+}
+
+func TestConnectorReusesHealthyConnections(t *testing.T) {
+	node := kubeoneapi.HostConfig{PublicAddress: "192.0.2.10"}
+	healthy := &fakeConnection{healthy: true}
+
+	c := NewConnector()
+	c.connections[node.PublicAddress] = healthy
+
+	conn, err := c.Connect(node)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if conn != healthy {
+		t.Error("expected the healthy connection to be reused")
+	}
+	if healthy.closed {
+		t.Error("expected the healthy connection not to be closed")
+	}
+}
+
+func TestConnectorReplacesStaleConnections(t *testing.T) {
+	// no SSH server listens on port 1, so reconnecting fails
+	node := kubeoneapi.HostConfig{PublicAddress: "127.0.0.1", SSHPort: 1, SSHUsername: "root"}
+	stale := &fakeConnection{healthy: false}
+
+	c := NewConnector()
+	c.connections[node.PublicAddress] = stale
+
+	if _, err := c.Connect(node); err == nil {
+		t.Fatal("expected reconnecting to fail")
+	}
+	if !stale.closed {
+		t.Error("expected the stale connection to be closed")
+	}
+	if _, found := c.connections[node.PublicAddress]; found {
+		t.Error("expected the stale connection to be removed")
+	}
+}
+
+func TestConnectorDoesNotBlockOtherHosts(t *testing.T) {
+	slowNode := kubeoneapi.HostConfig{PublicAddress: "192.0.2.10"}
+	node := kubeoneapi.HostConfig{PublicAddress: "192.0.2.11"}
+	slow := &fakeConnection{healthy: true, block: make(chan struct{})}
+	defer close(slow.block)
+
+	c := NewConnector()
+	c.connections[slowNode.PublicAddress] = slow
+	c.connections[node.PublicAddress] = &fakeConnection{healthy: true}
+
+	go func() {
+		_, _ = c.Connect(slowNode)
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		_, _ = c.Connect(node)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the health check of another host not to block the connection")
+	}
+}
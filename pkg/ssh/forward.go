@@ -0,0 +1,64 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssh
+
+import (
+	"io"
+	"net"
+)
+
+// Forward accepts connections on the listener and forwards each of them to
+// addr, dialed from the remote host of conn. It returns once the listener is
+// closed. Errors of single connections are passed to onError.
+func Forward(listener net.Listener, conn Connection, addr string, onError func(error)) error {
+	for {
+		local, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+
+		go func() {
+			if err := forward(local, conn, addr); err != nil {
+				onError(err)
+			}
+		}()
+	}
+}
+
+func forward(local net.Conn, conn Connection, addr string) error {
+	defer local.Close()
+
+	remote, err := conn.Tunnel("tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer remote.Close()
+
+	// the connection is done as soon as either side closes it
+	done := make(chan struct{}, 2)
+	go func() {
+		_, _ = io.Copy(remote, local)
+		done <- struct{}{}
+	}()
+	go func() {
+		_, _ = io.Copy(local, remote)
+		done <- struct{}{}
+	}()
+	<-done
+
+	return nil
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssh
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"testing"
+)
+
+// tunnelConnection dials the tunneled address locally instead of through SSH
+type tunnelConnection struct {
+	Connection
+}
+
+func (tunnelConnection) Tunnel(network, addr string) (net.Conn, error) {
+	return net.Dial(network, addr)
+}
+
+func TestForward(t *testing.T) {
+	echo, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer echo.Close()
+
+	go func() {
+		for {
+			c, err := echo.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer c.Close()
+				_, _ = io.Copy(c, c)
+			}()
+		}
+	}()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	forwardErr := make(chan error, 1)
+	go func() {
+		forwardErr <- Forward(listener, tunnelConnection{}, echo.Addr().String(), func(err error) {
+			t.Errorf("forwarding failed: %v", err)
+		})
+	}()
+
+	c, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if _, err = c.Write([]byte("ping\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	reply, err := bufio.NewReader(c).ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply != "ping\n" {
+		t.Errorf("expected the forwarded reply to be %q, got %q", "ping\n", reply)
+	}
+
+	listener.Close()
+	if err = <-forwardErr; err == nil {
+		t.Error("expected Forward to return once the listener is closed")
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssh
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"math/big"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ed25519"
+	"golang.org/x/crypto/ssh"
+)
+
+const openSSHKeyMagic = "openssh-key-v1\x00"
+
+// oidEd25519 identifies Ed25519 keys, see RFC 8410
+var oidEd25519 = asn1.ObjectIdentifier{1, 3, 101, 112}
+
+// parsePrivateKey parses a PEM encoded RSA, ECDSA, Ed25519 or DSA private key.
+// On top of the formats supported by the ssh package, it handles ECDSA keys in
+// the OpenSSH format, which ssh-keygen writes by default, and Ed25519 keys in
+// the PKCS #8 format, as written by openssl.
+func parsePrivateKey(pemBytes []byte) (ssh.Signer, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errors.New("no PEM encoded private key found")
+	}
+
+	switch block.Type {
+	case "OPENSSH PRIVATE KEY":
+		key, err := parseOpenSSHECDSAKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		if key != nil {
+			return ssh.NewSignerFromKey(key)
+		}
+	case "PRIVATE KEY":
+		if key := parsePKCS8Ed25519Key(block.Bytes); key != nil {
+			return ssh.NewSignerFromKey(key)
+		}
+	}
+
+	return ssh.ParsePrivateKey(pemBytes)
+}
+
+// parseOpenSSHECDSAKey parses an unencrypted ECDSA key in the OpenSSH format,
+// see https://github.com/openssh/openssh-portable/blob/master/PROTOCOL.key.
+// It returns nil if the key is of another type or is encrypted.
+func parseOpenSSHECDSAKey(der []byte) (*ecdsa.PrivateKey, error) {
+	if len(der) < len(openSSHKeyMagic) || string(der[:len(openSSHKeyMagic)]) != openSSHKeyMagic {
+		return nil, nil
+	}
+
+	var w struct {
+		CipherName   string
+		KdfName      string
+		KdfOpts      string
+		NumKeys      uint32
+		PubKey       []byte
+		PrivKeyBlock []byte
+	}
+	if err := ssh.Unmarshal(der[len(openSSHKeyMagic):], &w); err != nil {
+		return nil, nil
+	}
+	if w.CipherName != "none" || w.KdfName != "none" {
+		return nil, nil
+	}
+
+	var pk struct {
+		Check1  uint32
+		Check2  uint32
+		Keytype string
+		Rest    []byte `ssh:"rest"`
+	}
+	if err := ssh.Unmarshal(w.PrivKeyBlock, &pk); err != nil {
+		return nil, nil
+	}
+
+	var curve elliptic.Curve
+	switch pk.Keytype {
+	case ssh.KeyAlgoECDSA256:
+		curve = elliptic.P256()
+	case ssh.KeyAlgoECDSA384:
+		curve = elliptic.P384()
+	case ssh.KeyAlgoECDSA521:
+		curve = elliptic.P521()
+	default:
+		return nil, nil
+	}
+
+	if pk.Check1 != pk.Check2 {
+		return nil, errors.New("ssh: checkint mismatch")
+	}
+
+	var key struct {
+		Curve   string
+		Pub     []byte
+		D       *big.Int
+		Comment string
+		Pad     []byte `ssh:"rest"`
+	}
+	if err := ssh.Unmarshal(pk.Rest, &key); err != nil {
+		return nil, errors.Wrap(err, "failed to parse ECDSA key")
+	}
+
+	x, y := elliptic.Unmarshal(curve, key.Pub)
+	if x == nil {
+		return nil, errors.New("invalid ECDSA public key")
+	}
+
+	// make sure the private key matches the public one
+	if cx, cy := curve.ScalarBaseMult(key.D.Bytes()); cx.Cmp(x) != 0 || cy.Cmp(y) != 0 {
+		return nil, errors.New("ECDSA private key doesn't match the public key")
+	}
+
+	return &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{Curve: curve, X: x, Y: y},
+		D:         key.D,
+	}, nil
+}
+
+// parsePKCS8Ed25519Key parses an Ed25519 key in the PKCS #8 format. It
+// returns nil if the key is not an Ed25519 key.
+func parsePKCS8Ed25519Key(der []byte) ed25519.PrivateKey {
+	var pkcs8 struct {
+		Version    int
+		Algo       pkix.AlgorithmIdentifier
+		PrivateKey []byte
+	}
+	if _, err := asn1.Unmarshal(der, &pkcs8); err != nil || !pkcs8.Algo.Algorithm.Equal(oidEd25519) {
+		return nil
+	}
+
+	var seed []byte
+	if _, err := asn1.Unmarshal(pkcs8.PrivateKey, &seed); err != nil || len(seed) != ed25519.SeedSize {
+		return nil
+	}
+
+	return ed25519.NewKeyFromSeed(seed)
+}
@@ -0,0 +1,229 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssh
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"testing"
+
+	"golang.org/x/crypto/ed25519"
+	"golang.org/x/crypto/ssh"
+)
+
+// marshalOpenSSHKey encodes an unencrypted private key in the OpenSSH format
+func marshalOpenSSHKey(t *testing.T, pub ssh.PublicKey, keytype string, rest []byte) []byte {
+	t.Helper()
+
+	pk := struct {
+		Check1  uint32
+		Check2  uint32
+		Keytype string
+		Rest    []byte `ssh:"rest"`
+	}{Check1: 42, Check2: 42, Keytype: keytype, Rest: rest}
+
+	w := struct {
+		CipherName   string
+		KdfName      string
+		KdfOpts      string
+		NumKeys      uint32
+		PubKey       []byte
+		PrivKeyBlock []byte
+	}{
+		CipherName:   "none",
+		KdfName:      "none",
+		NumKeys:      1,
+		PubKey:       pub.Marshal(),
+		PrivKeyBlock: ssh.Marshal(pk),
+	}
+
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "OPENSSH PRIVATE KEY",
+		Bytes: append([]byte(openSSHKeyMagic), ssh.Marshal(w)...),
+	})
+}
+
+func ed25519OpenSSHKey(t *testing.T) (ssh.PublicKey, []byte) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rest := ssh.Marshal(struct {
+		Pub     []byte
+		Priv    []byte
+		Comment string
+	}{Pub: pub, Priv: priv})
+
+	return sshPub, marshalOpenSSHKey(t, sshPub, ssh.KeyAlgoED25519, rest)
+}
+
+func ecdsaOpenSSHKey(t *testing.T) (ssh.PublicKey, []byte) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sshPub, err := ssh.NewPublicKey(&priv.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rest := ssh.Marshal(struct {
+		Curve   string
+		Pub     []byte
+		D       *big.Int
+		Comment string
+	}{Curve: "nistp256", Pub: elliptic.Marshal(priv.Curve, priv.X, priv.Y), D: priv.D})
+
+	return sshPub, marshalOpenSSHKey(t, sshPub, ssh.KeyAlgoECDSA256, rest)
+}
+
+func ed25519PKCS8Key(t *testing.T) (ssh.PublicKey, []byte) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seed, err := asn1.Marshal(priv.Seed())
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := asn1.Marshal(struct {
+		Version    int
+		Algo       pkix.AlgorithmIdentifier
+		PrivateKey []byte
+	}{Algo: pkix.AlgorithmIdentifier{Algorithm: oidEd25519}, PrivateKey: seed})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return sshPub, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func rsaPKCS1Key(t *testing.T) (ssh.PublicKey, []byte) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sshPub, err := ssh.NewPublicKey(&priv.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return sshPub, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(priv)})
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	tests := []struct {
+		name string
+		key  func(*testing.T) (ssh.PublicKey, []byte)
+	}{
+		{name: "ed25519 openssh", key: ed25519OpenSSHKey},
+		{name: "ecdsa openssh", key: ecdsaOpenSSHKey},
+		{name: "ed25519 pkcs8", key: ed25519PKCS8Key},
+		{name: "rsa pkcs1", key: rsaPKCS1Key},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			pub, pemBytes := tc.key(t)
+
+			signer, err := parsePrivateKey(pemBytes)
+			if err != nil {
+				t.Fatalf("failed to parse key: %v", err)
+			}
+
+			if !bytes.Equal(signer.PublicKey().Marshal(), pub.Marshal()) {
+				t.Errorf("parsed key doesn't match the generated one")
+			}
+		})
+	}
+}
+
+func TestNewConnectionEd25519(t *testing.T) {
+	pub, pemBytes := ed25519OpenSSHKey(t)
+
+	_, hostKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hostSigner, err := ssh.NewSignerFromKey(hostKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if bytes.Equal(key.Marshal(), pub.Marshal()) {
+				return nil, nil
+			}
+			return nil, ssh.ErrNoAuth
+		},
+	}
+	config.AddHostKey(hostSigner)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		_, chans, reqs, err := ssh.NewServerConn(conn, config)
+		if err != nil {
+			return
+		}
+		go ssh.DiscardRequests(reqs)
+		for ch := range chans {
+			_ = ch.Reject(ssh.Prohibited, "not supported")
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	conn, err := NewConnection(Opts{
+		Username:   "root",
+		Hostname:   addr.IP.String(),
+		Port:       addr.Port,
+		PrivateKey: string(pemBytes),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect using the ed25519 key: %v", err)
+	}
+	conn.Close()
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssh
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	markerRevoked       = "@revoked"
+	hashedHostPrefix    = "|1|"
+	defaultKnownHostsFn = ".ssh/known_hosts"
+)
+
+type knownHost struct {
+	marker   string
+	patterns []string
+	key      ssh.PublicKey
+}
+
+// knownHostsCallback returns a host key callback verifying the host keys
+// against the given known_hosts file, by default ~/.ssh/known_hosts.
+// Unknown hosts are rejected, just like OpenSSH with StrictHostKeyChecking.
+func knownHostsCallback(file string) (ssh.HostKeyCallback, error) {
+	if file == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to determine the home directory")
+		}
+		file = filepath.Join(home, defaultKnownHostsFn)
+	}
+
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read known hosts file %q", file)
+	}
+
+	var hosts []knownHost
+	for rest := content; len(rest) > 0; {
+		var h knownHost
+		h.marker, h.patterns, h.key, _, rest, err = ssh.ParseKnownHosts(rest)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse known hosts file %q", file)
+		}
+		hosts = append(hosts, h)
+	}
+
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		address := knownHostsAddress(hostname)
+		known := false
+
+		for _, h := range hosts {
+			if !matchHost(h.patterns, address) {
+				continue
+			}
+
+			keyMatches := h.key.Type() == key.Type() && bytes.Equal(h.key.Marshal(), key.Marshal())
+			if h.marker == markerRevoked {
+				if keyMatches {
+					return errors.Errorf("host key of %s is marked as revoked in %s", hostname, file)
+				}
+				continue
+			}
+			if h.marker != "" {
+				// certificate authorities are not supported
+				continue
+			}
+
+			if keyMatches {
+				return nil
+			}
+			if h.key.Type() == key.Type() {
+				known = true
+			}
+		}
+
+		if known {
+			return errors.Errorf("host key of %s doesn't match the one in %s, someone could be eavesdropping", hostname, file)
+		}
+
+		return errors.Errorf("no %s host key for %s found in %s", key.Type(), hostname, file)
+	}, nil
+}
+
+// knownHostsAddress formats the address the way it's written in known_hosts
+// files, in which the port is only given if it's not the default one
+func knownHostsAddress(hostport string) string {
+	host, port, err := net.SplitHostPort(hostport)
+	if err != nil {
+		return hostport
+	}
+	if port == "22" {
+		return host
+	}
+	return "[" + host + "]:" + port
+}
+
+// matchHost reports whether the address matches the patterns of a
+// known_hosts line, which may be hashed, contain wildcards or be negated
+func matchHost(patterns []string, address string) bool {
+	matched := false
+	for _, pattern := range patterns {
+		negated := strings.HasPrefix(pattern, "!")
+		pattern = strings.TrimPrefix(pattern, "!")
+
+		if !matchPattern(pattern, address) {
+			continue
+		}
+		if negated {
+			return false
+		}
+		matched = true
+	}
+
+	return matched
+}
+
+func matchPattern(pattern, address string) bool {
+	if strings.HasPrefix(pattern, hashedHostPrefix) {
+		parts := strings.Split(strings.TrimPrefix(pattern, hashedHostPrefix), "|")
+		if len(parts) != 2 {
+			return false
+		}
+		salt, err := base64.StdEncoding.DecodeString(parts[0])
+		if err != nil {
+			return false
+		}
+		hash, err := base64.StdEncoding.DecodeString(parts[1])
+		if err != nil {
+			return false
+		}
+
+		mac := hmac.New(sha1.New, salt)
+		_, _ = mac.Write([]byte(address))
+		return hmac.Equal(mac.Sum(nil), hash)
+	}
+
+	// brackets enclose addresses with a port, not character classes
+	pattern = strings.NewReplacer("[", `\[`, "]", `\]`).Replace(pattern)
+	matched, err := path.Match(pattern, address)
+	return err == nil && matched
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssh
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/crypto/ed25519"
+	"golang.org/x/crypto/ssh"
+)
+
+func newHostKey(t *testing.T) ssh.PublicKey {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func hashHost(host string) string {
+	salt := []byte("0123456789abcdefghij")
+	mac := hmac.New(sha1.New, salt)
+	_, _ = mac.Write([]byte(host))
+	return "|1|" + base64.StdEncoding.EncodeToString(salt) + "|" + base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestKnownHostsCallback(t *testing.T) {
+	known := newHostKey(t)
+	other := newHostKey(t)
+	line := func(hosts string, key ssh.PublicKey) string {
+		return hosts + " " + string(ssh.MarshalAuthorizedKey(key))
+	}
+
+	dir, err := ioutil.TempDir("", "knownhosts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "known_hosts")
+	content := "# comment\n" +
+		line("10.0.0.1,node-1", known) +
+		line("[10.0.0.2]:2222", known) +
+		line(hashHost("10.0.0.3"), known) +
+		line("*.example.com,!bad.example.com", known) +
+		line("10.0.0.4", other)
+	if err = ioutil.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	callback, err := knownHostsCallback(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		hostport      string
+		key           ssh.PublicKey
+		expectedError bool
+	}{
+		{name: "known host", hostport: "10.0.0.1:22", key: known},
+		{name: "known host by name", hostport: "node-1:22", key: known},
+		{name: "known host with port", hostport: "10.0.0.2:2222", key: known},
+		{name: "known hashed host", hostport: "10.0.0.3:22", key: known},
+		{name: "wildcard", hostport: "node.example.com:22", key: known},
+		{name: "negated wildcard", hostport: "bad.example.com:22", key: known, expectedError: true},
+		{name: "port mismatch", hostport: "10.0.0.2:22", key: known, expectedError: true},
+		{name: "key mismatch", hostport: "10.0.0.4:22", key: known, expectedError: true},
+		{name: "unknown host", hostport: "10.0.0.5:22", key: known, expectedError: true},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := callback(tc.hostport, nil, tc.key)
+			if (err != nil) != tc.expectedError {
+				t.Errorf("expected error = %v, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
@@ -36,8 +36,12 @@ func defaultRetryBackoff(retries int) wait.Backoff {
 // Task is a runnable task
 type Task struct {
 	Fn      func(*util.Context) error
+	Name    string
 	ErrMsg  string
 	Retries int
+	// AlwaysRun tasks set up the context for the following tasks, so
+	// they aren't skipped when resuming an installation
+	AlwaysRun bool
 }
 
 // Run runs a task
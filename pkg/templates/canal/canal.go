@@ -106,7 +106,7 @@ func Deploy(ctx *util.Context) error {
 	}
 
 	// DaemonSet
-	ds := daemonSet()
+	ds := daemonSet(ctx.Cluster)
 	if err = simpleCreateOrUpdate(bgCtx, ctx.DynamicClient, ds); err != nil {
 		return errors.Wrap(err, "failed to ensure canal DaemonSet")
 	}
@@ -167,3 +167,8 @@ func Deploy(ctx *util.Context) error {
 
 	return nil
 }
+
+// Images returns the images used by canal
+func Images() []string {
+	return []string{installCNIImage, calicoImage, flannelImage}
+}
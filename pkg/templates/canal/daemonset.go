@@ -17,6 +17,8 @@ limitations under the License.
 package canal
 
 import (
+	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
+
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -26,7 +28,7 @@ import (
 
 // daemonSet installs the calico/node container, as well as the Calico CNI plugins and network config on each
 // master and worker node in a Kubernetes cluster
-func daemonSet() *appsv1.DaemonSet {
+func daemonSet(cluster *kubeoneapi.KubeOneCluster) *appsv1.DaemonSet {
 	maxUnavailable := intstr.FromInt(1)
 	terminationGracePeriodSeconds := int64(0)
 	privileged := true
@@ -94,7 +96,7 @@ func daemonSet() *appsv1.DaemonSet {
 							// This container installs the Calico CNI binaries
 							// and CNI network config file on each node
 							Name:  "install-cni",
-							Image: installCNIImage,
+							Image: cluster.ImageFor(installCNIImage),
 							Command: []string{
 								"/install-cni.sh",
 							},
@@ -143,7 +145,7 @@ func daemonSet() *appsv1.DaemonSet {
 					Containers: []corev1.Container{
 						{
 							Name:  "calico-node",
-							Image: calicoImage,
+							Image: cluster.ImageFor(calicoImage),
 							Env: []corev1.EnvVar{
 								{
 									// Use Kubernetes API as the backing datastore
@@ -261,7 +263,7 @@ func daemonSet() *appsv1.DaemonSet {
 							// This container runs flannel using the kube-subnet-mgr backend
 							// for allocating subnets.
 							Name:  "kube-flannel",
-							Image: flannelImage,
+							Image: cluster.ImageFor(flannelImage),
 							Command: []string{
 								"/opt/bin/flanneld",
 								"--ip-masq",
@@ -0,0 +1,291 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterautoscaler
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
+	"github.com/kubermatic/kubeone/pkg/util"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	clusterAutoscalerImage   = "k8s.gcr.io/cluster-autoscaler:"
+	clusterAutoscalerVersion = "v1.16.0"
+	clusterAutoscalerName    = "cluster-autoscaler"
+)
+
+// Ensure deploys cluster-autoscaler, scaling the worker MachineDeployments
+// between the configured number of nodes
+func Ensure(ctx *util.Context) error {
+	ca := ctx.Cluster.ClusterAutoscaler
+	if ca == nil || !ca.Enabled {
+		return nil
+	}
+
+	if ctx.DynamicClient == nil {
+		return errors.New("kubernetes client not initialized")
+	}
+
+	ctx.Logger.Info("Ensure cluster-autoscaler is up to date")
+
+	objs := []runtime.Object{
+		serviceAccount(),
+		clusterRole(),
+		clusterRoleBinding(),
+		deployment(ctx.Cluster),
+	}
+
+	bgCtx := context.Background()
+	for _, o := range objs {
+		if err := simpleCreateOrUpdate(bgCtx, ctx.DynamicClient, o); err != nil {
+			return errors.Wrapf(err, "failed to ensure cluster-autoscaler %T", o)
+		}
+	}
+
+	return nil
+}
+
+// Images returns the images used by cluster-autoscaler
+func Images(cluster *kubeoneapi.KubeOneCluster) []string {
+	return []string{clusterAutoscalerImage + version(cluster)}
+}
+
+// version returns the cluster-autoscaler version configured in the
+// manifest, if any, or the version tested with KubeOne
+func version(cluster *kubeoneapi.KubeOneCluster) string {
+	if cluster.ClusterAutoscaler != nil && cluster.ClusterAutoscaler.Version != "" {
+		return cluster.ClusterAutoscaler.Version
+	}
+	return clusterAutoscalerVersion
+}
+
+func serviceAccount() *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ServiceAccount",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      clusterAutoscalerName,
+			Namespace: metav1.NamespaceSystem,
+		},
+	}
+}
+
+func clusterRole() *rbacv1.ClusterRole {
+	return &rbacv1.ClusterRole{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "rbac.authorization.k8s.io/v1",
+			Kind:       "ClusterRole",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "system:" + clusterAutoscalerName,
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{""},
+				Resources: []string{"events", "endpoints"},
+				Verbs:     []string{"create", "patch"},
+			},
+			{
+				APIGroups: []string{""},
+				Resources: []string{"pods/eviction"},
+				Verbs:     []string{"create"},
+			},
+			{
+				APIGroups: []string{""},
+				Resources: []string{"pods/status"},
+				Verbs:     []string{"update"},
+			},
+			{
+				APIGroups:     []string{""},
+				Resources:     []string{"endpoints"},
+				ResourceNames: []string{clusterAutoscalerName},
+				Verbs:         []string{"get", "update"},
+			},
+			{
+				APIGroups: []string{""},
+				Resources: []string{"nodes"},
+				Verbs:     []string{"get", "list", "watch", "update"},
+			},
+			{
+				APIGroups: []string{""},
+				Resources: []string{"pods", "services", "replicationcontrollers", "persistentvolumeclaims", "persistentvolumes", "namespaces"},
+				Verbs:     []string{"get", "list", "watch"},
+			},
+			{
+				// cluster-autoscaler stores its status in a ConfigMap
+				APIGroups: []string{""},
+				Resources: []string{"configmaps"},
+				Verbs:     []string{"create", "get", "list", "watch", "update", "delete"},
+			},
+			{
+				APIGroups: []string{"extensions"},
+				Resources: []string{"replicasets", "daemonsets"},
+				Verbs:     []string{"get", "list", "watch"},
+			},
+			{
+				APIGroups: []string{"apps"},
+				Resources: []string{"replicasets", "statefulsets", "daemonsets"},
+				Verbs:     []string{"get", "list", "watch"},
+			},
+			{
+				APIGroups: []string{"policy"},
+				Resources: []string{"poddisruptionbudgets"},
+				Verbs:     []string{"get", "list", "watch"},
+			},
+			{
+				APIGroups: []string{"batch"},
+				Resources: []string{"jobs", "cronjobs"},
+				Verbs:     []string{"get", "list", "watch"},
+			},
+			{
+				APIGroups: []string{"storage.k8s.io"},
+				Resources: []string{"storageclasses", "csinodes"},
+				Verbs:     []string{"get", "list", "watch"},
+			},
+			{
+				APIGroups: []string{"coordination.k8s.io"},
+				Resources: []string{"leases"},
+				Verbs:     []string{"create", "get", "update"},
+			},
+			{
+				// node groups are the MachineDeployments managed by machine-controller
+				APIGroups: []string{"cluster.k8s.io"},
+				Resources: []string{"machinedeployments", "machinedeployments/scale", "machinesets", "machines"},
+				Verbs:     []string{"get", "list", "watch", "update", "patch"},
+			},
+		},
+	}
+}
+
+func clusterRoleBinding() *rbacv1.ClusterRoleBinding {
+	return &rbacv1.ClusterRoleBinding{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "rbac.authorization.k8s.io/v1",
+			Kind:       "ClusterRoleBinding",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "system:" + clusterAutoscalerName,
+		},
+		RoleRef: rbacv1.RoleRef{
+			Name:     "system:" + clusterAutoscalerName,
+			Kind:     "ClusterRole",
+			APIGroup: rbacv1.GroupName,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Name:      clusterAutoscalerName,
+				Namespace: metav1.NamespaceSystem,
+			},
+		},
+	}
+}
+
+func deployment(cluster *kubeoneapi.KubeOneCluster) *appsv1.Deployment {
+	var (
+		replicas  int32 = 1
+		revisions int32 = 2
+	)
+
+	labels := map[string]string{
+		"app": clusterAutoscalerName,
+	}
+
+	command := []string{
+		"./cluster-autoscaler",
+		"--cloud-provider=clusterapi",
+		"--logtostderr",
+		"--v=4",
+		"--stderrthreshold=info",
+	}
+	if delay := cluster.ClusterAutoscaler.ScaleDownDelay.Duration; delay > 0 {
+		command = append(command, "--scale-down-delay-after-add="+delay.String())
+	}
+
+	return &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      clusterAutoscalerName,
+			Namespace: metav1.NamespaceSystem,
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas:             &replicas,
+			RevisionHistoryLimit: &revisions,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: clusterAutoscalerName,
+					PriorityClassName:  "system-cluster-critical",
+					// run on the control plane, so cluster-autoscaler
+					// never scales down the node it's running on
+					NodeSelector: map[string]string{
+						"node-role.kubernetes.io/master": "",
+					},
+					Tolerations: []corev1.Toleration{
+						{
+							Key:      "node-role.kubernetes.io/master",
+							Operator: corev1.TolerationOpExists,
+							Effect:   corev1.TaintEffectNoSchedule,
+						},
+						{
+							Key:      "CriticalAddonsOnly",
+							Operator: corev1.TolerationOpExists,
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:    clusterAutoscalerName,
+							Image:   cluster.ImageFor(clusterAutoscalerImage + version(cluster)),
+							Command: command,
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("100m"),
+									corev1.ResourceMemory: resource.MustParse("300Mi"),
+								},
+								Limits: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("100m"),
+									corev1.ResourceMemory: resource.MustParse("300Mi"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,262 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package csi
+
+import (
+	"context"
+	"encoding/base64"
+
+	"github.com/pkg/errors"
+
+	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
+	"github.com/kubermatic/kubeone/pkg/util"
+	"github.com/kubermatic/kubeone/pkg/util/credentials"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// Sidecar containers maintained by the Kubernetes storage SIG
+	provisionerImage = "quay.io/k8scsi/csi-provisioner:v1.0.1"
+	attacherImage    = "quay.io/k8scsi/csi-attacher:v1.0.1"
+	registrarImage   = "quay.io/k8scsi/csi-node-driver-registrar:v1.0.2"
+
+	defaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+)
+
+// Ensure deploys the CSI driver and the StorageClasses using it
+func Ensure(ctx *util.Context) error {
+	if ctx.Cluster.CSI == nil {
+		return nil
+	}
+
+	if ctx.DynamicClient == nil {
+		return errors.New("kubernetes client not initialized")
+	}
+
+	d, err := driverFor(ctx.Cluster.CSI.Driver)
+	if err != nil {
+		return err
+	}
+
+	ctx.Logger.Infof("Ensure %s CSI driver is up to date", d.name)
+
+	bgctx := context.Background()
+	version := driverVersion(ctx.Cluster, d)
+
+	if d.credentialsSecret != nil {
+		secret, err := d.credentialsSecret()
+		if err != nil {
+			return errors.Wrap(err, "failed to build CSI driver credentials")
+		}
+		if err := simpleCreateOrUpdate(bgctx, ctx.DynamicClient, secret); err != nil {
+			return errors.Wrap(err, "failed to ensure CSI driver credentials")
+		}
+	}
+
+	objs := []runtime.Object{
+		serviceAccount(d),
+		clusterRole(d),
+		clusterRoleBinding(d),
+		controllerDeployment(ctx.Cluster, d, version),
+		nodeDaemonSet(ctx.Cluster, d, version),
+	}
+	for _, sc := range ctx.Cluster.CSI.StorageClasses {
+		objs = append(objs, storageClass(d, sc))
+	}
+
+	for _, obj := range objs {
+		if err := simpleCreateOrUpdate(bgctx, ctx.DynamicClient, obj); err != nil {
+			return errors.Wrapf(err, "failed to ensure CSI %T", obj)
+		}
+	}
+
+	return nil
+}
+
+// Images returns the images used by the CSI driver of the cluster
+func Images(cluster *kubeoneapi.KubeOneCluster) []string {
+	if cluster.CSI == nil {
+		return nil
+	}
+
+	d, err := driverFor(cluster.CSI.Driver)
+	if err != nil {
+		return nil
+	}
+
+	return []string{
+		provisionerImage,
+		attacherImage,
+		registrarImage,
+		d.image + ":" + driverVersion(cluster, d),
+	}
+}
+
+// driverVersion returns the driver version configured in the manifest,
+// if any, or the version tested with KubeOne
+func driverVersion(cluster *kubeoneapi.KubeOneCluster, d *driver) string {
+	if cluster.CSI.Version != "" {
+		return cluster.CSI.Version
+	}
+	return d.version
+}
+
+func serviceAccount(d *driver) *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ServiceAccount",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      d.resourceName,
+			Namespace: metav1.NamespaceSystem,
+		},
+	}
+}
+
+func clusterRole(d *driver) *rbacv1.ClusterRole {
+	return &rbacv1.ClusterRole{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "rbac.authorization.k8s.io/v1",
+			Kind:       "ClusterRole",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "system:" + d.resourceName,
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{""},
+				Resources: []string{"persistentvolumes"},
+				Verbs:     []string{"get", "list", "watch", "create", "delete", "update", "patch"},
+			},
+			{
+				APIGroups: []string{""},
+				Resources: []string{"persistentvolumeclaims"},
+				Verbs:     []string{"get", "list", "watch", "update"},
+			},
+			{
+				APIGroups: []string{""},
+				Resources: []string{"events"},
+				Verbs:     []string{"get", "list", "watch", "create", "update", "patch"},
+			},
+			{
+				APIGroups: []string{""},
+				Resources: []string{"nodes"},
+				Verbs:     []string{"get", "list", "watch"},
+			},
+			{
+				APIGroups: []string{"storage.k8s.io"},
+				Resources: []string{"storageclasses", "csinodes"},
+				Verbs:     []string{"get", "list", "watch"},
+			},
+			{
+				APIGroups: []string{"storage.k8s.io"},
+				Resources: []string{"volumeattachments"},
+				Verbs:     []string{"get", "list", "watch", "update", "patch"},
+			},
+		},
+	}
+}
+
+func clusterRoleBinding(d *driver) *rbacv1.ClusterRoleBinding {
+	return &rbacv1.ClusterRoleBinding{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "rbac.authorization.k8s.io/v1",
+			Kind:       "ClusterRoleBinding",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "system:" + d.resourceName,
+		},
+		RoleRef: rbacv1.RoleRef{
+			Name:     "system:" + d.resourceName,
+			Kind:     "ClusterRole",
+			APIGroup: rbacv1.GroupName,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Name:      d.resourceName,
+				Namespace: metav1.NamespaceSystem,
+			},
+		},
+	}
+}
+
+func storageClass(d *driver, sc kubeoneapi.StorageClass) *storagev1.StorageClass {
+	bindingMode := storagev1.VolumeBindingWaitForFirstConsumer
+
+	obj := &storagev1.StorageClass{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "storage.k8s.io/v1",
+			Kind:       "StorageClass",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: sc.Name,
+		},
+		Provisioner: d.name,
+		Parameters:  sc.Parameters,
+		// volumes are created in the zone of the pod using them
+		VolumeBindingMode: &bindingMode,
+	}
+
+	if sc.ReclaimPolicy != "" {
+		policy := corev1.PersistentVolumeReclaimPolicy(sc.ReclaimPolicy)
+		obj.ReclaimPolicy = &policy
+	}
+
+	if sc.Default {
+		obj.Annotations = map[string]string{
+			defaultStorageClassAnnotation: "true",
+		}
+	}
+
+	return obj
+}
+
+// gcpServiceAccountSecret provides the service account of the cluster
+// to the GCP PD driver as a JSON key file
+func gcpServiceAccountSecret() (*corev1.Secret, error) {
+	creds, err := credentials.ProviderCredentials(kubeoneapi.CloudProviderNameGCE)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := base64.StdEncoding.DecodeString(creds[credentials.GoogleServiceAccountKey])
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode google service account")
+	}
+
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      gcpSecretName,
+			Namespace: metav1.NamespaceSystem,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			gcpSecretKey: key,
+		},
+	}, nil
+}
@@ -0,0 +1,437 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package csi
+
+import (
+	"github.com/pkg/errors"
+
+	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
+	"github.com/kubermatic/kubeone/pkg/util/credentials"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	awsEBSImage   = "amazon/aws-ebs-csi-driver"
+	awsEBSVersion = "v0.4.0"
+
+	gcpPDImage   = "gcr.io/gke-release/gcp-compute-persistent-disk-csi-driver"
+	gcpPDVersion = "v0.4.0-gke.0"
+
+	gcpSecretName = "cloud-sa"
+	gcpSecretKey  = "cloud-sa.json"
+	gcpSecretPath = "/etc/cloud-sa"
+
+	socketDir           = "/csi"
+	controllerSocketDir = "/var/lib/csi/sockets/pluginproxy/"
+	kubeletDir          = "/var/lib/kubelet"
+	registrationDir     = "/var/lib/kubelet/plugins_registry/"
+)
+
+// driver holds what differs between the supported CSI drivers
+type driver struct {
+	// name the driver registers with, used as StorageClass provisioner
+	name string
+	// resourceName is used for the objects deployed in kube-system
+	resourceName string
+	image        string
+	version      string
+	args         []string
+
+	// controllerEnv is passed to the driver in the controller Deployment
+	controllerEnv []corev1.EnvVar
+	// controllerVolumes are mounted into the driver in the controller Deployment
+	controllerVolumes []volume
+	// nodeVolumes are mounted into the driver in the node DaemonSet
+	nodeVolumes []volume
+
+	// credentialsSecret, if set, builds a Secret the driver needs
+	credentialsSecret func() (*corev1.Secret, error)
+}
+
+type volume struct {
+	volume corev1.Volume
+	mount  corev1.VolumeMount
+}
+
+func driverFor(name kubeoneapi.CSIDriver) (*driver, error) {
+	switch name {
+	case kubeoneapi.CSIDriverAWSEBS:
+		return awsEBSDriver(), nil
+	case kubeoneapi.CSIDriverGCPPD:
+		return gcpPDDriver(), nil
+	default:
+		return nil, errors.Errorf("CSI driver %q not supported", name)
+	}
+}
+
+func awsEBSDriver() *driver {
+	return &driver{
+		name:         "ebs.csi.aws.com",
+		resourceName: "ebs-csi",
+		image:        awsEBSImage,
+		version:      awsEBSVersion,
+		args: []string{
+			"--endpoint=$(CSI_ENDPOINT)",
+			"--logtostderr",
+			"--v=5",
+		},
+		controllerEnv: []corev1.EnvVar{
+			secretEnvVar("AWS_ACCESS_KEY_ID", credentials.SecretName, credentials.AWSAccessKeyID),
+			secretEnvVar("AWS_SECRET_ACCESS_KEY", credentials.SecretName, credentials.AWSSecretAccessKey),
+		},
+	}
+}
+
+func gcpPDDriver() *driver {
+	hostPathDir := corev1.HostPathDirectory
+
+	return &driver{
+		name:         "pd.csi.storage.gke.io",
+		resourceName: "pd-csi",
+		image:        gcpPDImage,
+		version:      gcpPDVersion,
+		args: []string{
+			"--endpoint=$(CSI_ENDPOINT)",
+			"--logtostderr",
+			"--v=5",
+		},
+		controllerEnv: []corev1.EnvVar{
+			{
+				Name:  "GOOGLE_APPLICATION_CREDENTIALS",
+				Value: gcpSecretPath + "/" + gcpSecretKey,
+			},
+		},
+		controllerVolumes: []volume{
+			{
+				volume: corev1.Volume{
+					Name: gcpSecretName,
+					VolumeSource: corev1.VolumeSource{
+						Secret: &corev1.SecretVolumeSource{
+							SecretName: gcpSecretName,
+						},
+					},
+				},
+				mount: corev1.VolumeMount{
+					Name:      gcpSecretName,
+					MountPath: gcpSecretPath,
+					ReadOnly:  true,
+				},
+			},
+		},
+		// the driver looks up the attached disks by their udev id
+		nodeVolumes: []volume{
+			hostPathVolume("udev-rules-etc", "/etc/udev", hostPathDir),
+			hostPathVolume("udev-rules-lib", "/lib/udev", hostPathDir),
+			hostPathVolume("udev-socket", "/run/udev", hostPathDir),
+			hostPathVolume("sys", "/sys", hostPathDir),
+		},
+		credentialsSecret: gcpServiceAccountSecret,
+	}
+}
+
+func secretEnvVar(name, secret, key string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: secret,
+				},
+				Key: key,
+			},
+		},
+	}
+}
+
+func hostPathVolume(name, path string, hostPathType corev1.HostPathType) volume {
+	return volume{
+		volume: corev1.Volume{
+			Name: name,
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: path,
+					Type: &hostPathType,
+				},
+			},
+		},
+		mount: corev1.VolumeMount{
+			Name:      name,
+			MountPath: path,
+		},
+	}
+}
+
+func splitVolumes(vols []volume) ([]corev1.Volume, []corev1.VolumeMount) {
+	var (
+		volumes []corev1.Volume
+		mounts  []corev1.VolumeMount
+	)
+	for _, v := range vols {
+		volumes = append(volumes, v.volume)
+		mounts = append(mounts, v.mount)
+	}
+	return volumes, mounts
+}
+
+func controllerDeployment(cluster *kubeoneapi.KubeOneCluster, d *driver, version string) *appsv1.Deployment {
+	var (
+		replicas  int32 = 1
+		revisions int32 = 2
+	)
+
+	name := d.resourceName + "-controller"
+	labels := map[string]string{
+		"app": name,
+	}
+
+	volumes, driverMounts := splitVolumes(d.controllerVolumes)
+	volumes = append(volumes, corev1.Volume{
+		Name: "socket-dir",
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	})
+	socketMount := corev1.VolumeMount{
+		Name:      "socket-dir",
+		MountPath: controllerSocketDir,
+	}
+	driverMounts = append(driverMounts, socketMount)
+
+	sidecarArgs := []string{
+		"--csi-address=$(ADDRESS)",
+		"--v=5",
+	}
+	sidecarEnv := []corev1.EnvVar{
+		{
+			Name:  "ADDRESS",
+			Value: controllerSocketDir + "csi.sock",
+		},
+	}
+
+	return &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: metav1.NamespaceSystem,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas:             &replicas,
+			RevisionHistoryLimit: &revisions,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: d.resourceName,
+					PriorityClassName:  "system-cluster-critical",
+					Tolerations: []corev1.Toleration{
+						{
+							Key:      "CriticalAddonsOnly",
+							Operator: corev1.TolerationOpExists,
+						},
+					},
+					Volumes: volumes,
+					Containers: []corev1.Container{
+						{
+							Name:  "csi-driver",
+							Image: cluster.ImageFor(d.image + ":" + version),
+							Args:  d.args,
+							Env: append([]corev1.EnvVar{
+								{
+									Name:  "CSI_ENDPOINT",
+									Value: "unix://" + controllerSocketDir + "csi.sock",
+								},
+							}, d.controllerEnv...),
+							VolumeMounts: driverMounts,
+						},
+						{
+							Name:         "csi-provisioner",
+							Image:        cluster.ImageFor(provisionerImage),
+							Args:         append([]string{"--provisioner=" + d.name, "--feature-gates=Topology=true"}, sidecarArgs...),
+							Env:          sidecarEnv,
+							VolumeMounts: []corev1.VolumeMount{socketMount},
+						},
+						{
+							Name:         "csi-attacher",
+							Image:        cluster.ImageFor(attacherImage),
+							Args:         sidecarArgs,
+							Env:          sidecarEnv,
+							VolumeMounts: []corev1.VolumeMount{socketMount},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func nodeDaemonSet(cluster *kubeoneapi.KubeOneCluster, d *driver, version string) *appsv1.DaemonSet {
+	var (
+		privileged        = true
+		bidirectional     = corev1.MountPropagationBidirectional
+		hostPathDir       = corev1.HostPathDirectory
+		hostPathDirCreate = corev1.HostPathDirectoryOrCreate
+	)
+
+	name := d.resourceName + "-node"
+	labels := map[string]string{
+		"app": name,
+	}
+	pluginDir := kubeletDir + "/plugins/" + d.name + "/"
+
+	volumes, driverMounts := splitVolumes(d.nodeVolumes)
+	volumes = append(volumes,
+		corev1.Volume{
+			Name: "kubelet-dir",
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: kubeletDir,
+					Type: &hostPathDir,
+				},
+			},
+		},
+		corev1.Volume{
+			Name: "plugin-dir",
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: pluginDir,
+					Type: &hostPathDirCreate,
+				},
+			},
+		},
+		corev1.Volume{
+			Name: "registration-dir",
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: registrationDir,
+					Type: &hostPathDir,
+				},
+			},
+		},
+		corev1.Volume{
+			Name: "device-dir",
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: "/dev",
+					Type: &hostPathDir,
+				},
+			},
+		},
+	)
+	driverMounts = append(driverMounts,
+		corev1.VolumeMount{
+			Name:             "kubelet-dir",
+			MountPath:        kubeletDir,
+			MountPropagation: &bidirectional,
+		},
+		corev1.VolumeMount{
+			Name:      "plugin-dir",
+			MountPath: socketDir,
+		},
+		corev1.VolumeMount{
+			Name:      "device-dir",
+			MountPath: "/dev",
+		},
+	)
+
+	return &appsv1.DaemonSet{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apps/v1",
+			Kind:       "DaemonSet",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: metav1.NamespaceSystem,
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: d.resourceName,
+					PriorityClassName:  "system-node-critical",
+					HostNetwork:        true,
+					Tolerations: []corev1.Toleration{
+						{
+							Operator: corev1.TolerationOpExists,
+						},
+					},
+					Volumes: volumes,
+					Containers: []corev1.Container{
+						{
+							Name:  "csi-driver",
+							Image: cluster.ImageFor(d.image + ":" + version),
+							Args:  d.args,
+							Env: []corev1.EnvVar{
+								{
+									Name:  "CSI_ENDPOINT",
+									Value: "unix:" + socketDir + "/csi.sock",
+								},
+							},
+							SecurityContext: &corev1.SecurityContext{
+								Privileged: &privileged,
+							},
+							VolumeMounts: driverMounts,
+						},
+						{
+							Name:  "csi-node-driver-registrar",
+							Image: cluster.ImageFor(registrarImage),
+							Args: []string{
+								"--csi-address=$(ADDRESS)",
+								"--kubelet-registration-path=$(DRIVER_REG_SOCK_PATH)",
+								"--v=5",
+							},
+							Env: []corev1.EnvVar{
+								{
+									Name:  "ADDRESS",
+									Value: socketDir + "/csi.sock",
+								},
+								{
+									Name:  "DRIVER_REG_SOCK_PATH",
+									Value: pluginDir + "csi.sock",
+								},
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "plugin-dir",
+									MountPath: socketDir,
+								},
+								{
+									Name:      "registration-dir",
+									MountPath: "/registration",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
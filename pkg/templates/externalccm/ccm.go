@@ -47,12 +47,39 @@ func Ensure(ctx *util.Context) error {
 		return ensureDigitalOcean(ctx)
 	case kubeoneapi.CloudProviderNamePacket:
 		return ensurePacket(ctx)
+	case kubeoneapi.CloudProviderNameAWS, kubeoneapi.CloudProviderNameGCE:
+		return ensureUpstream(ctx)
 	default:
 		ctx.Logger.Infof("External CCM for %q not yet supported, skipping", ctx.Cluster.CloudProvider.Name)
 		return nil
 	}
 }
 
+// Images returns the images used by the external CCM of the cluster's provider
+func Images(cluster *kubeoneapi.KubeOneCluster) []string {
+	switch cluster.CloudProvider.Name {
+	case kubeoneapi.CloudProviderNameHetzner:
+		return []string{hetznerCCMImage + ccmVersion(cluster, hetznerCCMVersion)}
+	case kubeoneapi.CloudProviderNameDigitalOcean:
+		return []string{digitaloceanCCMImage + ccmVersion(cluster, digitaloceanCCMVersion)}
+	case kubeoneapi.CloudProviderNamePacket:
+		return []string{packetCCMImage + ccmVersion(cluster, packetCCMVersion)}
+	case kubeoneapi.CloudProviderNameAWS, kubeoneapi.CloudProviderNameGCE:
+		return []string{upstreamCCMImage + ccmVersion(cluster, upstreamCCMVersion(cluster))}
+	default:
+		return nil
+	}
+}
+
+// ccmVersion returns the CCM version configured in the manifest, if
+// any, or the given default version
+func ccmVersion(cluster *kubeoneapi.KubeOneCluster, defaultVersion string) string {
+	if cluster.CloudProvider.CCMVersion != "" {
+		return cluster.CloudProvider.CCMVersion
+	}
+	return defaultVersion
+}
+
 func simpleCreateOrUpdate(ctx context.Context, client dynclient.Client, obj runtime.Object) error {
 	okFunc := func(runtime.Object) error { return nil }
 	_, err := controllerutil.CreateOrUpdate(ctx, client, obj, okFunc)
@@ -75,7 +102,9 @@ func mutateDeploymentWithVersionCheck(want *semver.Constraints) func(obj runtime
 			return errors.New("unable to choose a CCM container, as number of containers > 1")
 		}
 
-		imageSpec := strings.SplitN(dep.Spec.Template.Spec.Containers[0].Image, ":", 2)
+		// the registry may contain a port, so only look for the tag after the last slash
+		image := dep.Spec.Template.Spec.Containers[0].Image
+		imageSpec := strings.SplitN(image[strings.LastIndex(image, "/")+1:], ":", 2)
 		if len(imageSpec) != 2 {
 			return errors.New("unable to grab CCM image version")
 		}
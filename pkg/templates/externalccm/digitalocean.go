@@ -22,6 +22,7 @@ import (
 	"github.com/Masterminds/semver"
 	"github.com/pkg/errors"
 
+	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
 	"github.com/kubermatic/kubeone/pkg/util"
 	"github.com/kubermatic/kubeone/pkg/util/credentials"
 
@@ -34,6 +35,7 @@ import (
 )
 
 const (
+	digitaloceanCCMImage       = "digitalocean/digitalocean-cloud-controller-manager:"
 	digitaloceanCCMVersion     = "v0.1.9"
 	digitaloceanSAName         = "cloud-controller-manager"
 	digitaloceanDeploymentName = "digitalocean-cloud-controller-manager"
@@ -61,8 +63,9 @@ func ensureDigitalOcean(ctx *util.Context) error {
 		return errors.Wrap(err, "failed to ensure digitalocean CCM ClusterRoleBinding")
 	}
 
-	dep := doDeployment()
-	want, err := semver.NewConstraint("<= " + digitaloceanCCMVersion)
+	version := ccmVersion(ctx.Cluster, digitaloceanCCMVersion)
+	dep := doDeployment(ctx.Cluster, version)
+	want, err := semver.NewConstraint("<= " + version)
 	if err != nil {
 		return errors.Wrap(err, "failed to parse digitalocean CCM version constraint")
 	}
@@ -171,7 +174,7 @@ func doClusterRoleBinding() *rbacv1.ClusterRoleBinding {
 	}
 }
 
-func doDeployment() *appsv1.Deployment {
+func doDeployment(cluster *kubeoneapi.KubeOneCluster, version string) *appsv1.Deployment {
 	var (
 		replicas  int32 = 1
 		revisions int32 = 2
@@ -227,7 +230,7 @@ func doDeployment() *appsv1.Deployment {
 					Containers: []corev1.Container{
 						{
 							Name:  "digitalocean-cloud-controller-manager",
-							Image: "digitalocean/digitalocean-cloud-controller-manager:" + digitaloceanCCMVersion,
+							Image: cluster.ImageFor(digitaloceanCCMImage + version),
 							Command: []string{
 								"/bin/digitalocean-cloud-controller-manager",
 								"--cloud-provider=digitalocean",
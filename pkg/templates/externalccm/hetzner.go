@@ -22,6 +22,7 @@ import (
 	"github.com/Masterminds/semver"
 	"github.com/pkg/errors"
 
+	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
 	"github.com/kubermatic/kubeone/pkg/util"
 	"github.com/kubermatic/kubeone/pkg/util/credentials"
 
@@ -34,6 +35,7 @@ import (
 )
 
 const (
+	hetznerCCMImage       = "hetznercloud/hcloud-cloud-controller-manager:"
 	hetznerCCMVersion     = "v1.3.0"
 	hetznerSAName         = "cloud-controller-manager"
 	hetznerDeploymentName = "hcloud-cloud-controller-manager"
@@ -56,8 +58,9 @@ func ensureHetzner(ctx *util.Context) error {
 		return errors.Wrap(err, "failed to ensure hetzner CCM ClusterRoleBinding")
 	}
 
-	dep := hetznerDeployment()
-	want, err := semver.NewConstraint("<= " + hetznerCCMVersion)
+	version := ccmVersion(ctx.Cluster, hetznerCCMVersion)
+	dep := hetznerDeployment(ctx.Cluster, version)
+	want, err := semver.NewConstraint("<= " + version)
 	if err != nil {
 		return errors.Wrap(err, "failed to parse hetzner CCM version constraint")
 	}
@@ -110,7 +113,7 @@ func hetznerClusterRoleBinding() *rbacv1.ClusterRoleBinding {
 	}
 }
 
-func hetznerDeployment() *appsv1.Deployment {
+func hetznerDeployment(cluster *kubeoneapi.KubeOneCluster, version string) *appsv1.Deployment {
 	var (
 		replicas  int32 = 1
 		revisions int32 = 2
@@ -166,7 +169,7 @@ func hetznerDeployment() *appsv1.Deployment {
 					Containers: []corev1.Container{
 						{
 							Name:  "hcloud-cloud-controller-manager",
-							Image: "hetznercloud/hcloud-cloud-controller-manager:" + hetznerCCMVersion,
+							Image: cluster.ImageFor(hetznerCCMImage + version),
 							Command: []string{
 								"/bin/hcloud-cloud-controller-manager",
 								"--cloud-provider=hcloud",
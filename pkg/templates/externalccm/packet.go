@@ -22,6 +22,7 @@ import (
 	"github.com/Masterminds/semver"
 	"github.com/pkg/errors"
 
+	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
 	"github.com/kubermatic/kubeone/pkg/util"
 	"github.com/kubermatic/kubeone/pkg/util/credentials"
 
@@ -34,6 +35,7 @@ import (
 )
 
 const (
+	packetCCMImage       = "packethost/packet-ccm:"
 	packetCCMVersion     = "v0.0.4"
 	packetSAName         = "cloud-controller-manager"
 	packetDeploymentName = "packet-cloud-controller-manager"
@@ -61,8 +63,9 @@ func ensurePacket(ctx *util.Context) error {
 		return errors.Wrap(err, "failed to ensure packet CCM ClusterRoleBinding")
 	}
 
-	dep := packetDeployment()
-	want, err := semver.NewConstraint("<= " + packetCCMVersion)
+	version := ccmVersion(ctx.Cluster, packetCCMVersion)
+	dep := packetDeployment(ctx.Cluster, version)
+	want, err := semver.NewConstraint("<= " + version)
 	if err != nil {
 		return errors.Wrap(err, "failed to parse packet CCM version constraint")
 	}
@@ -172,7 +175,7 @@ func packetClusterRoleBinding() *rbacv1.ClusterRoleBinding {
 	}
 }
 
-func packetDeployment() *appsv1.Deployment {
+func packetDeployment(cluster *kubeoneapi.KubeOneCluster, version string) *appsv1.Deployment {
 	var (
 		replicas int32 = 1
 	)
@@ -226,7 +229,7 @@ func packetDeployment() *appsv1.Deployment {
 					Containers: []corev1.Container{
 						{
 							Name:  "packet-cloud-controller-manager",
-							Image: "packethost/packet-ccm:" + packetCCMVersion,
+							Image: cluster.ImageFor(packetCCMImage + version),
 							Command: []string{
 								"./packet-cloud-controller-manager",
 								"--cloud-provider=packet",
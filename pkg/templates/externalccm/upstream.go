@@ -0,0 +1,243 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalccm
+
+import (
+	"context"
+
+	"github.com/Masterminds/semver"
+	"github.com/pkg/errors"
+
+	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
+	"github.com/kubermatic/kubeone/pkg/util"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// The upstream cloud-controller-manager runs the cloud providers still
+// maintained in the Kubernetes tree, such as AWS and GCE, out of tree.
+const (
+	upstreamCCMImage       = "k8s.gcr.io/cloud-controller-manager:"
+	upstreamSAName         = "cloud-controller-manager"
+	upstreamDeploymentName = "cloud-controller-manager"
+	upstreamCloudConfig    = "/etc/kubernetes/cloud-config"
+)
+
+// upstreamCCMVersion returns the default upstream CCM version, which
+// is released together with Kubernetes
+func upstreamCCMVersion(cluster *kubeoneapi.KubeOneCluster) string {
+	return "v" + cluster.Versions.Kubernetes
+}
+
+func ensureUpstream(ctx *util.Context) error {
+	if ctx.DynamicClient == nil {
+		return errors.New("kubernetes client not initialized")
+	}
+
+	bgctx := context.Background()
+	provider := ctx.Cluster.CloudProvider.Name
+
+	sa := upstreamServiceAccount()
+	if err := simpleCreateOrUpdate(bgctx, ctx.DynamicClient, sa); err != nil {
+		return errors.Wrapf(err, "failed to ensure %s CCM ServiceAccount", provider)
+	}
+
+	crb := upstreamClusterRoleBinding()
+	if err := simpleCreateOrUpdate(bgctx, ctx.DynamicClient, crb); err != nil {
+		return errors.Wrapf(err, "failed to ensure %s CCM ClusterRoleBinding", provider)
+	}
+
+	version := ccmVersion(ctx.Cluster, upstreamCCMVersion(ctx.Cluster))
+	dep := upstreamDeployment(ctx.Cluster, version)
+	want, err := semver.NewConstraint("<= " + version)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse %s CCM version constraint", provider)
+	}
+
+	_, err = controllerutil.CreateOrUpdate(bgctx,
+		ctx.DynamicClient,
+		dep,
+		mutateDeploymentWithVersionCheck(want))
+	if err != nil {
+		ctx.Logger.Warnf("unable to ensure %s CCM Deployment: %v, skipping", provider, err)
+	}
+
+	return nil
+}
+
+func upstreamServiceAccount() *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ServiceAccount",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      upstreamSAName,
+			Namespace: metav1.NamespaceSystem,
+		},
+	}
+}
+
+func upstreamClusterRoleBinding() *rbacv1.ClusterRoleBinding {
+	return &rbacv1.ClusterRoleBinding{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "rbac.authorization.k8s.io/v1",
+			Kind:       "ClusterRoleBinding",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "system:cloud-controller-manager",
+		},
+		RoleRef: rbacv1.RoleRef{
+			Name:     "cluster-admin",
+			Kind:     "ClusterRole",
+			APIGroup: rbacv1.GroupName,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Name:      upstreamSAName,
+				Namespace: metav1.NamespaceSystem,
+			},
+		},
+	}
+}
+
+func upstreamDeployment(cluster *kubeoneapi.KubeOneCluster, version string) *appsv1.Deployment {
+	var (
+		replicas  int32 = 1
+		revisions int32 = 2
+	)
+
+	command := []string{
+		"/usr/local/bin/cloud-controller-manager",
+		"--cloud-provider=" + string(cluster.CloudProvider.Name),
+		"--cluster-name=" + cluster.Name,
+		"--leader-elect=true",
+		"--use-service-account-credentials=true",
+		"--allocate-node-cidrs=false",
+		"--configure-cloud-routes=false",
+	}
+
+	var (
+		volumes      []corev1.Volume
+		volumeMounts []corev1.VolumeMount
+	)
+
+	// the cloud-config is deployed on all control plane nodes, which
+	// the CCM is scheduled on
+	if cluster.CloudProvider.CloudConfig != "" {
+		hostPathFile := corev1.HostPathFile
+		command = append(command, "--cloud-config="+upstreamCloudConfig)
+		volumes = append(volumes, corev1.Volume{
+			Name: "cloud-config",
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: upstreamCloudConfig,
+					Type: &hostPathFile,
+				},
+			},
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "cloud-config",
+			MountPath: upstreamCloudConfig,
+			ReadOnly:  true,
+		})
+	}
+
+	return &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      upstreamDeploymentName,
+			Namespace: metav1.NamespaceSystem,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas:             &replicas,
+			RevisionHistoryLimit: &revisions,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"app": "cloud-controller-manager",
+				},
+			},
+			Strategy: appsv1.DeploymentStrategy{
+				Type: appsv1.RecreateDeploymentStrategyType,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						"scheduler.alpha.kubernetes.io/critical-pod": "",
+					},
+					Labels: map[string]string{
+						"app": "cloud-controller-manager",
+					},
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: upstreamSAName,
+					// the CCM is deployed before the CNI plugin, and is
+					// needed to initialize the nodes
+					HostNetwork: true,
+					NodeSelector: map[string]string{
+						"node-role.kubernetes.io/master": "",
+					},
+					Tolerations: []corev1.Toleration{
+						{
+							Key:      "node-role.kubernetes.io/master",
+							Operator: corev1.TolerationOpExists,
+							Effect:   corev1.TaintEffectNoSchedule,
+						},
+						{
+							Key:    "node.cloudprovider.kubernetes.io/uninitialized",
+							Value:  "true",
+							Effect: corev1.TaintEffectNoSchedule,
+						},
+						{
+							Key:      "node.kubernetes.io/not-ready",
+							Operator: corev1.TolerationOpExists,
+							Effect:   corev1.TaintEffectNoSchedule,
+						},
+						{
+							Key:      "CriticalAddonsOnly",
+							Operator: corev1.TolerationOpExists,
+						},
+					},
+					Volumes: volumes,
+					Containers: []corev1.Container{
+						{
+							Name:         "cloud-controller-manager",
+							Image:        cluster.ImageFor(upstreamCCMImage + version),
+							Command:      command,
+							VolumeMounts: volumeMounts,
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("100m"),
+									corev1.ResourceMemory: resource.MustParse("50Mi"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -22,6 +22,7 @@ import (
 
 	kubeadmv1beta1 "github.com/kubermatic/kubeone/pkg/apis/kubeadm/v1beta1"
 	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
+	kubeproxyv1alpha1 "github.com/kubermatic/kubeone/pkg/apis/kubeproxy/v1alpha1"
 	"github.com/kubermatic/kubeone/pkg/features"
 	"github.com/kubermatic/kubeone/pkg/util"
 
@@ -31,6 +32,14 @@ import (
 	bootstraputil "k8s.io/cluster-bootstrap/token/util"
 )
 
+const (
+	crioSocket = "/var/run/crio/crio.sock"
+
+	// flexVolumePluginDir replaces /usr/libexec/kubernetes/kubelet-plugins/volume/exec
+	// on hosts with a read-only /usr
+	flexVolumePluginDir = "/var/lib/kubelet/volumeplugins"
+)
+
 // NewConfig returns all required configs to init a cluster via a set of v1beta1 configs
 func NewConfig(ctx *util.Context, host kubeoneapi.HostConfig) ([]runtime.Object, error) {
 	cluster := ctx.Cluster
@@ -115,13 +124,15 @@ func NewConfig(ctx *util.Context, host kubeoneapi.HostConfig) ([]runtime.Object,
 				},
 				ExtraVolumes: []kubeadmv1beta1.HostPathMount{},
 			},
-			CertSANs: []string{strings.ToLower(cluster.APIEndpoint.Host)},
+			// 127.0.0.1 is used to reach the API server through 'kubeone proxy'
+			CertSANs: []string{strings.ToLower(cluster.APIEndpoint.Host), "127.0.0.1"},
 		},
 		ControllerManager: kubeadmv1beta1.ControlPlaneComponent{
 			ExtraArgs:    map[string]string{},
 			ExtraVolumes: []kubeadmv1beta1.HostPathMount{},
 		},
-		ClusterName: cluster.Name,
+		ClusterName:     cluster.Name,
+		ImageRepository: cluster.ImageRepository,
 	}
 
 	// TODO(kron4eg): figure out working way to provide `bind-address` to
@@ -159,10 +170,43 @@ func NewConfig(ctx *util.Context, host kubeoneapi.HostConfig) ([]runtime.Object,
 		nodeRegistration.KubeletExtraArgs["cloud-provider"] = "external"
 	}
 
+	if cluster.ContainerRuntime.Runtime == kubeoneapi.ContainerRuntimeCRIO {
+		nodeRegistration.CRISocket = crioSocket
+		nodeRegistration.KubeletExtraArgs["container-runtime"] = "remote"
+		nodeRegistration.KubeletExtraArgs["container-runtime-endpoint"] = "unix://" + crioSocket
+		nodeRegistration.KubeletExtraArgs["cgroup-driver"] = "cgroupfs"
+	}
+
+	if evictionHard := cluster.Kubelet.EvictionHard(); evictionHard != "" {
+		nodeRegistration.KubeletExtraArgs["eviction-hard"] = evictionHard
+	}
+
+	switch host.OperatingSystem {
+	case kubeoneapi.OperatingSystemNameCoreOS, kubeoneapi.OperatingSystemNameFlatcar:
+		nodeRegistration.KubeletExtraArgs["volume-plugin-dir"] = flexVolumePluginDir
+		clusterConfig.ControllerManager.ExtraArgs["flex-volume-plugin-dir"] = flexVolumePluginDir
+	}
+
 	features.UpdateKubeadmClusterConfiguration(cluster.Features, clusterConfig)
 
 	initConfig.NodeRegistration = nodeRegistration
 	joinConfig.NodeRegistration = nodeRegistration
 
-	return []runtime.Object{initConfig, joinConfig, clusterConfig}, nil
+	configs := []runtime.Object{initConfig, joinConfig, clusterConfig}
+
+	// kube-proxy defaults are kept unless a mode is configured
+	if cluster.KubeProxy.Mode != "" {
+		configs = append(configs, &kubeproxyv1alpha1.KubeProxyConfiguration{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "kubeproxy.config.k8s.io/v1alpha1",
+				Kind:       "KubeProxyConfiguration",
+			},
+			Mode: kubeproxyv1alpha1.ProxyMode(cluster.KubeProxy.Mode),
+			IPVS: kubeproxyv1alpha1.KubeProxyIPVSConfiguration{
+				Scheduler: cluster.KubeProxy.IPVSScheduler,
+			},
+		})
+	}
+
+	return configs, nil
 }
@@ -0,0 +1,109 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinecontroller
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/kubermatic/kubeone/pkg/certificate"
+	"github.com/kubermatic/kubeone/pkg/util"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// restartedAtAnnotation is set on the webhook pod template to roll its pods
+const restartedAtAnnotation = "kubeone.io/restartedAt"
+
+// RotateWebhookCertificates issues a new serving certificate for the
+// machine-controller webhook and restarts the webhook to pick it up
+func RotateWebhookCertificates(ctx *util.Context) error {
+	if ctx.DynamicClient == nil {
+		return errors.New("kubernetes client not initialized")
+	}
+
+	caPrivateKey, caCert, err := certificate.CAKeyPair(ctx.Configuration)
+	if err != nil {
+		return errors.Wrap(err, "failed to load CA keypair")
+	}
+
+	servingCert, err := tlsServingCertificate(caPrivateKey, caCert)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate machine-controller webhook TLS secret")
+	}
+
+	bgCtx := context.Background()
+
+	ctx.Logger.Infoln("Rotating machine-controller webhook certificates…")
+	err = replaceSecretData(bgCtx, ctx.DynamicClient, servingCert)
+	if err != nil {
+		return errors.Wrap(err, "failed to update machine-controller webhook secret")
+	}
+
+	err = ensureMutatingWebhookConfiguration(bgCtx, ctx.DynamicClient, caCert, ctx.Cluster.MachineController.WebhookTimeoutSeconds)
+	if err != nil {
+		return errors.Wrap(err, "failed to update machine-controller mutating webhook")
+	}
+
+	dep := &appsv1.Deployment{}
+	key := types.NamespacedName{Namespace: WebhookNamespace, Name: WebhookName}
+	if err = ctx.DynamicClient.Get(bgCtx, key, dep); err != nil {
+		return errors.Wrap(err, "failed to get machine-controller webhook deployment")
+	}
+
+	if dep.Spec.Template.Annotations == nil {
+		dep.Spec.Template.Annotations = map[string]string{}
+	}
+	dep.Spec.Template.Annotations[restartedAtAnnotation] = time.Now().Format(time.RFC3339)
+
+	if err = ctx.DynamicClient.Update(bgCtx, dep); err != nil {
+		return errors.Wrap(err, "failed to restart machine-controller webhook deployment")
+	}
+
+	// Wait a bit to let the deployment controller to replace the pods
+	time.Sleep(10 * time.Second)
+
+	if err = WaitForWebhookReady(ctx.DynamicClient); err != nil {
+		return errors.Wrap(err, "machine-controller-webhook did not come up")
+	}
+
+	return nil
+}
+
+// replaceSecretData creates the secret, or replaces the data of the existing
+// one with the data of the given secret
+func replaceSecretData(ctx context.Context, client dynclient.Client, secret *corev1.Secret) error {
+	data := secret.Data
+	_, err := controllerutil.CreateOrUpdate(ctx, client, secret, func(obj runtime.Object) error {
+		existing, ok := obj.(*corev1.Secret)
+		if !ok {
+			return errors.Errorf("unknown object type %T passed", obj)
+		}
+
+		existing.Data = data
+		return nil
+	})
+
+	return err
+}
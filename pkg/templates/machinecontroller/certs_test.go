@@ -0,0 +1,69 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinecontroller
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/kubermatic/kubeone/pkg/internal/testclient"
+
+	corev1 "k8s.io/api/core/v1"
+	certutil "k8s.io/client-go/util/cert"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestReplaceSecretDataRotatesCertificate(t *testing.T) {
+	caKey, err := certutil.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, err := certutil.NewSelfSignedCACert(certutil.Config{CommonName: "kubernetes"}, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldSecret, err := tlsServingCertificate(caKey, caCert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newSecret, err := tlsServingCertificate(caKey, caCert)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := testclient.NewMemoryClient(oldSecret)
+	if err = replaceSecretData(context.Background(), client, newSecret.DeepCopy()); err != nil {
+		t.Fatal(err)
+	}
+
+	got := &corev1.Secret{}
+	key := dynclient.ObjectKey{Namespace: oldSecret.Namespace, Name: oldSecret.Name}
+	if err = client.Get(context.Background(), key, got); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, k := range []string{"cert.pem", "key.pem"} {
+		if bytes.Equal(got.Data[k], oldSecret.Data[k]) {
+			t.Errorf("expected %s to be rotated", k)
+		}
+		if !bytes.Equal(got.Data[k], newSecret.Data[k]) {
+			t.Errorf("expected %s to match the new certificate", k)
+		}
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"strconv"
 	"time"
 
 	"github.com/pkg/errors"
@@ -35,6 +36,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
 	"k8s.io/kubernetes/pkg/registry/core/service/ipallocator"
 	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -45,8 +47,18 @@ const (
 	MachineControllerAppLabelKey   = "app"
 	MachineControllerAppLabelValue = "machine-controller"
 	MachineControllerTag           = "v1.1.5"
+
+	machineControllerImage = "docker.io/kubermatic/machine-controller:"
+
+	// machineControllerLogLines is number of log lines shown when machine-controller fails to come up
+	machineControllerLogLines = 50
 )
 
+// Images returns the images used by machine-controller and its webhook
+func Images() []string {
+	return []string{machineControllerImage + MachineControllerTag}
+}
+
 // Deploy deploys MachineController deployment with RBAC on the cluster
 func Deploy(ctx *util.Context) error {
 	if ctx.DynamicClient == nil {
@@ -107,15 +119,19 @@ func Deploy(ctx *util.Context) error {
 	}
 
 	// Deployments
-	deployment, err := machineControllerDeployment(ctx.Cluster)
+	deployment, err := machineControllerDeployment(ctx.Cluster, MachineControllerTag)
 	if err != nil {
 		return errors.Wrap(err, "failed to generate machine-controller deployment")
 	}
 
-	if err = simpleCreateOrUpdate(bgCtx, ctx.DynamicClient, deployment); err != nil {
+	if err = ensureDeployment(bgCtx, ctx.DynamicClient, deployment); err != nil {
 		return errors.Wrap(err, "failed to ensure machine-controller deployment")
 	}
 
+	if err = deployMetrics(bgCtx, ctx.DynamicClient, ctx.Cluster); err != nil {
+		return err
+	}
+
 	// CRDs
 	crdGenerators := []func() *apiextensions.CustomResourceDefinition{
 		machineControllerMachineCRD,
@@ -137,14 +153,14 @@ func Deploy(ctx *util.Context) error {
 
 // WaitForMachineController waits for machine-controller-webhook to become running
 // func WaitForMachineController(corev1Client corev1types.CoreV1Interface) error {
-func WaitForMachineController(client dynclient.Client) error {
+func WaitForMachineController(client dynclient.Client, restConfig *rest.Config) error {
 	listOpts := dynclient.ListOptions{Namespace: WebhookNamespace}
 	err := listOpts.SetLabelSelector(fmt.Sprintf("%s=%s", MachineControllerAppLabelKey, MachineControllerAppLabelValue))
 	if err != nil {
 		return errors.Wrap(err, "failed to parse machine-controller labels")
 	}
 
-	return wait.Poll(5*time.Second, 3*time.Minute, func() (bool, error) {
+	err = wait.Poll(5*time.Second, 3*time.Minute, func() (bool, error) {
 		machineControllerPods := corev1.PodList{}
 		err = client.List(context.Background(), &listOpts, &machineControllerPods)
 		if err != nil {
@@ -167,6 +183,16 @@ func WaitForMachineController(client dynclient.Client) error {
 
 		return false, nil
 	})
+
+	if err == wait.ErrWaitTimeout {
+		logs, logsErr := GetMachineControllerLogs(context.Background(), restConfig, machineControllerLogLines)
+		if logsErr != nil {
+			return errors.Wrapf(err, "unable to fetch machine-controller logs (%v)", logsErr)
+		}
+		return errors.Wrapf(err, "machine-controller logs:\n%s", logs)
+	}
+
+	return err
 }
 
 func machineControllerServiceAccount() *corev1.ServiceAccount {
@@ -765,7 +791,7 @@ func machineControllerMachineDeploymentCRD() *apiextensions.CustomResourceDefini
 	}
 }
 
-func machineControllerDeployment(cluster *kubeoneapi.KubeOneCluster) (*appsv1.Deployment, error) {
+func machineControllerDeployment(cluster *kubeoneapi.KubeOneCluster, version string) (*appsv1.Deployment, error) {
 	var replicas int32 = 1
 
 	clusterDNS, err := clusterDNSIP(cluster)
@@ -784,6 +810,47 @@ func machineControllerDeployment(cluster *kubeoneapi.KubeOneCluster) (*appsv1.De
 		args = append(args, "-external-cloud-provider")
 	}
 
+	if mc := cluster.MachineController; mc != nil {
+		if d := mc.LeaderElectionLeaseDuration; d != nil && d.Duration > 0 {
+			args = append(args, "-leader-elect-lease-duration", d.Duration.String())
+		}
+		if d := mc.LeaderElectionRenewDeadline; d != nil && d.Duration > 0 {
+			args = append(args, "-leader-elect-renew-deadline", d.Duration.String())
+		}
+		if d := mc.LeaderElectionRetryPeriod; d != nil && d.Duration > 0 {
+			args = append(args, "-leader-elect-retry-period", d.Duration.String())
+		}
+		if mc.WorkerCount > 0 {
+			args = append(args, "-worker-count", strconv.Itoa(int(mc.WorkerCount)))
+		}
+	}
+
+	strategy := appsv1.DeploymentStrategy{
+		Type: appsv1.RollingUpdateDeploymentStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateDeployment{
+			MaxSurge: &intstr.IntOrString{
+				Type:   intstr.Int,
+				IntVal: 1,
+			},
+			MaxUnavailable: &intstr.IntOrString{
+				Type:   intstr.Int,
+				IntVal: 0,
+			},
+		},
+	}
+
+	var resources corev1.ResourceRequirements
+	var affinity *corev1.Affinity
+	if mc := cluster.MachineController; mc != nil {
+		if mc.Resources != nil {
+			resources = *mc.Resources
+		}
+		if mc.UpdateStrategy != nil {
+			strategy = *mc.UpdateStrategy
+		}
+		affinity = topologySpreadAffinity(mc.TopologySpreadConstraints)
+	}
+
 	return &appsv1.Deployment{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "apps/v1",
@@ -803,19 +870,7 @@ func machineControllerDeployment(cluster *kubeoneapi.KubeOneCluster) (*appsv1.De
 					MachineControllerAppLabelKey: MachineControllerAppLabelValue,
 				},
 			},
-			Strategy: appsv1.DeploymentStrategy{
-				Type: appsv1.RollingUpdateDeploymentStrategyType,
-				RollingUpdate: &appsv1.RollingUpdateDeployment{
-					MaxSurge: &intstr.IntOrString{
-						Type:   intstr.Int,
-						IntVal: 1,
-					},
-					MaxUnavailable: &intstr.IntOrString{
-						Type:   intstr.Int,
-						IntVal: 0,
-					},
-				},
-			},
+			Strategy: strategy,
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Annotations: map[string]string{
@@ -829,6 +884,7 @@ func machineControllerDeployment(cluster *kubeoneapi.KubeOneCluster) (*appsv1.De
 				},
 				Spec: corev1.PodSpec{
 					ServiceAccountName: "machine-controller",
+					Affinity:           affinity,
 					Tolerations: []corev1.Toleration{
 						{
 							Key:      "node-role.kubernetes.io/master",
@@ -848,11 +904,13 @@ func machineControllerDeployment(cluster *kubeoneapi.KubeOneCluster) (*appsv1.De
 					Containers: []corev1.Container{
 						{
 							Name:                     "machine-controller",
-							Image:                    "docker.io/kubermatic/machine-controller:" + MachineControllerTag,
+							Image:                    cluster.ImageFor(machineControllerImage + version),
 							ImagePullPolicy:          corev1.PullIfNotPresent,
 							Command:                  []string{"/usr/local/bin/machine-controller"},
 							Args:                     args,
 							Env:                      getEnvVarCredentials(cluster),
+							EnvFrom:                  getEnvFromCredentials(cluster),
+							Resources:                resources,
 							TerminationMessagePath:   corev1.TerminationMessagePathDefault,
 							TerminationMessagePolicy: corev1.TerminationMessageReadFile,
 							ReadinessProbe: &corev1.Probe{
@@ -891,6 +949,11 @@ func machineControllerDeployment(cluster *kubeoneapi.KubeOneCluster) (*appsv1.De
 func getEnvVarCredentials(cluster *kubeoneapi.KubeOneCluster) []corev1.EnvVar {
 	env := make([]corev1.EnvVar, 0)
 
+	// credentials are loaded from the referenced secret instead
+	if cluster.MachineController.CredentialsSecretRef != nil {
+		return env
+	}
+
 	for k := range cluster.Credentials {
 		env = append(env, corev1.EnvVar{
 			Name: k,
@@ -908,6 +971,25 @@ func getEnvVarCredentials(cluster *kubeoneapi.KubeOneCluster) []corev1.EnvVar {
 	return env
 }
 
+// getEnvFromCredentials loads the whole credentials secret referenced in the
+// machine-controller config, if any, as environment variables
+func getEnvFromCredentials(cluster *kubeoneapi.KubeOneCluster) []corev1.EnvFromSource {
+	ref := cluster.MachineController.CredentialsSecretRef
+	if ref == nil {
+		return nil
+	}
+
+	return []corev1.EnvFromSource{
+		{
+			SecretRef: &corev1.SecretEnvSource{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: ref.Name,
+				},
+			},
+		},
+	}
+}
+
 // clusterDNSIP returns the IP address of ClusterDNS Service,
 // which is 10th IP of the Services CIDR.
 func clusterDNSIP(cluster *kubeoneapi.KubeOneCluster) (*net.IP, error) {
@@ -0,0 +1,224 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinecontroller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/kubermatic/kubeone/pkg/util"
+
+	appsv1 "k8s.io/api/apps/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1alpha1 "sigs.k8s.io/cluster-api/pkg/apis/cluster/v1alpha1"
+)
+
+// Diff compares machine-controller and the worker MachineDeployments
+// running in the cluster with the ones generated from the manifest
+func Diff(ctx *util.Context) ([]util.Difference, error) {
+	if ctx.DynamicClient == nil {
+		return nil, errors.New("kubernetes client not initialized")
+	}
+
+	if !ctx.Cluster.MachineController.Deploy {
+		return nil, nil
+	}
+
+	diffs, err := machineControllerDiff(context.Background(), ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mdDiffs, err := MachineDeploymentsDiff(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(diffs, mdDiffs...), nil
+}
+
+// ControllerDiff compares only the machine-controller deployment running in
+// the cluster with the one generated from the manifest
+func ControllerDiff(ctx *util.Context) ([]util.Difference, error) {
+	if ctx.DynamicClient == nil {
+		return nil, errors.New("kubernetes client not initialized")
+	}
+
+	if !ctx.Cluster.MachineController.Deploy {
+		return nil, nil
+	}
+
+	return machineControllerDiff(context.Background(), ctx)
+}
+
+// MachineDeploymentsDiff compares only the worker MachineDeployments running
+// in the cluster with the ones generated from the manifest
+func MachineDeploymentsDiff(ctx *util.Context) ([]util.Difference, error) {
+	if ctx.DynamicClient == nil {
+		return nil, errors.New("kubernetes client not initialized")
+	}
+
+	if !ctx.Cluster.MachineController.Deploy || ctx.SkipWorkers {
+		return nil, nil
+	}
+
+	var diffs []util.Difference
+	for _, workerset := range ctx.Cluster.Workers {
+		desired, err := createMachineDeployment(ctx.Cluster, workerset)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to generate MachineDeployment")
+		}
+
+		mdDiffs, err := machineDeploymentDiff(context.Background(), ctx, desired)
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, mdDiffs...)
+	}
+
+	return diffs, nil
+}
+
+// DeployedImages returns the image of the machine-controller deployment running in
+// the cluster, empty if it isn't deployed, and the one generated from the manifest
+func DeployedImages(ctx *util.Context) (string, string, error) {
+	if ctx.DynamicClient == nil {
+		return "", "", errors.New("kubernetes client not initialized")
+	}
+
+	desired, err := machineControllerDeployment(ctx.Cluster, MachineControllerTag)
+	if err != nil {
+		return "", "", errors.Wrap(err, "failed to generate machine-controller deployment")
+	}
+	desiredImage := desired.Spec.Template.Spec.Containers[0].Image
+
+	existing := &appsv1.Deployment{}
+	key := types.NamespacedName{Namespace: desired.Namespace, Name: desired.Name}
+	err = ctx.DynamicClient.Get(context.Background(), key, existing)
+	if k8serrors.IsNotFound(err) {
+		return "", desiredImage, nil
+	}
+	if err != nil {
+		return "", "", errors.Wrap(err, "failed to get machine-controller deployment")
+	}
+
+	existingImage := ""
+	if containers := existing.Spec.Template.Spec.Containers; len(containers) > 0 {
+		existingImage = containers[0].Image
+	}
+
+	return existingImage, desiredImage, nil
+}
+
+func machineControllerDiff(ctx context.Context, kctx *util.Context) ([]util.Difference, error) {
+	desired, err := machineControllerDeployment(kctx.Cluster, MachineControllerTag)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate machine-controller deployment")
+	}
+
+	resource := fmt.Sprintf("Deployment %s/%s", desired.Namespace, desired.Name)
+
+	existing := &appsv1.Deployment{}
+	key := types.NamespacedName{Namespace: desired.Namespace, Name: desired.Name}
+	err = kctx.DynamicClient.Get(ctx, key, existing)
+	if k8serrors.IsNotFound(err) {
+		return []util.Difference{{Resource: resource}}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get machine-controller deployment")
+	}
+
+	desiredImage := desired.Spec.Template.Spec.Containers[0].Image
+	existingImage := ""
+	if containers := existing.Spec.Template.Spec.Containers; len(containers) > 0 {
+		existingImage = containers[0].Image
+	}
+
+	if existingImage == desiredImage {
+		return nil, nil
+	}
+
+	return []util.Difference{{
+		Resource: resource,
+		Field:    "image",
+		Existing: existingImage,
+		Desired:  desiredImage,
+	}}, nil
+}
+
+func machineDeploymentDiff(ctx context.Context, kctx *util.Context, desired *clusterv1alpha1.MachineDeployment) ([]util.Difference, error) {
+	resource := fmt.Sprintf("MachineDeployment %s/%s", desired.Namespace, desired.Name)
+
+	existing := &clusterv1alpha1.MachineDeployment{}
+	key := types.NamespacedName{Namespace: desired.Namespace, Name: desired.Name}
+	err := kctx.DynamicClient.Get(ctx, key, existing)
+	if k8serrors.IsNotFound(err) {
+		return []util.Difference{{Resource: resource}}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get MachineDeployment %s", key)
+	}
+
+	var diffs []util.Difference
+	add := func(field, existing, desired string) {
+		if existing != desired {
+			diffs = append(diffs, util.Difference{Resource: resource, Field: field, Existing: existing, Desired: desired})
+		}
+	}
+
+	add("replicas", replicasString(existing.Spec.Replicas), replicasString(desired.Spec.Replicas))
+	add("kubelet version", existing.Spec.Template.Spec.Versions.Kubelet, desired.Spec.Template.Spec.Versions.Kubelet)
+
+	existingSpec, desiredSpec := providerSpecString(existing), providerSpecString(desired)
+	if !jsonEqual(existingSpec, desiredSpec) {
+		add("providerSpec", existingSpec, desiredSpec)
+	}
+
+	return diffs, nil
+}
+
+func replicasString(replicas *int32) string {
+	if replicas == nil {
+		return ""
+	}
+
+	return strconv.Itoa(int(*replicas))
+}
+
+func providerSpecString(md *clusterv1alpha1.MachineDeployment) string {
+	if value := md.Spec.Template.Spec.ProviderSpec.Value; value != nil {
+		return string(value.Raw)
+	}
+
+	return ""
+}
+
+// jsonEqual compares two JSON documents regardless of their formatting
+func jsonEqual(a, b string) bool {
+	var aObj, bObj interface{}
+	if json.Unmarshal([]byte(a), &aObj) != nil || json.Unmarshal([]byte(b), &bObj) != nil {
+		return a == b
+	}
+
+	return reflect.DeepEqual(aObj, bObj)
+}
@@ -18,13 +18,19 @@ package machinecontroller
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 
+	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
 	"github.com/kubermatic/kubeone/pkg/util"
+	"github.com/kubermatic/kubeone/pkg/util/credentials"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1alpha1 "sigs.k8s.io/cluster-api/pkg/apis/cluster/v1alpha1"
 	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
@@ -35,6 +41,64 @@ func simpleCreateOrUpdate(ctx context.Context, client dynclient.Client, obj runt
 	return err
 }
 
+// ensureDeployment creates the Deployment, or updates the labels and the spec
+// of the existing one
+func ensureDeployment(ctx context.Context, client dynclient.Client, dep *appsv1.Deployment) error {
+	desired := dep.DeepCopy()
+	_, err := controllerutil.CreateOrUpdate(ctx, client, dep, func(obj runtime.Object) error {
+		existing, ok := obj.(*appsv1.Deployment)
+		if !ok {
+			return errors.Errorf("unknown object type %T passed", obj)
+		}
+
+		existing.Labels = mergeStringMaps(existing.Labels, desired.Labels)
+		existing.Spec = desired.Spec
+		return nil
+	})
+
+	return err
+}
+
+// ensureMachineDeployment creates the MachineDeployment, or updates the labels,
+// the annotations and the spec of the existing one. The replicas are kept when
+// the MachineDeployment is scaled by cluster-autoscaler.
+func ensureMachineDeployment(ctx context.Context, client dynclient.Client, md *clusterv1alpha1.MachineDeployment) error {
+	desired := md.DeepCopy()
+	_, err := controllerutil.CreateOrUpdate(ctx, client, md, func(obj runtime.Object) error {
+		existing, ok := obj.(*clusterv1alpha1.MachineDeployment)
+		if !ok {
+			return errors.Errorf("unknown object type %T passed", obj)
+		}
+
+		replicas := existing.Spec.Replicas
+		existing.Labels = mergeStringMaps(existing.Labels, desired.Labels)
+		existing.Annotations = mergeStringMaps(existing.Annotations, desired.Annotations)
+		existing.Spec = desired.Spec
+		if _, autoscaled := desired.Annotations[autoscalerMinSizeAnnotation]; autoscaled && replicas != nil {
+			existing.Spec.Replicas = replicas
+		}
+		return nil
+	})
+
+	return err
+}
+
+// mergeStringMaps sets the desired keys on the existing map, keeping the keys
+// added by others
+func mergeStringMaps(existing, desired map[string]string) map[string]string {
+	if len(desired) == 0 {
+		return existing
+	}
+	if existing == nil {
+		existing = make(map[string]string, len(desired))
+	}
+	for k, v := range desired {
+		existing[k] = v
+	}
+
+	return existing
+}
+
 // Ensure install/update machine-controller
 func Ensure(ctx *util.Context) error {
 	if !ctx.Cluster.MachineController.Deploy {
@@ -42,11 +106,20 @@ func Ensure(ctx *util.Context) error {
 		return nil
 	}
 
+	if err := validateCredentials(ctx); err != nil {
+		return err
+	}
+
 	ctx.Logger.Infoln("Installing machine-controller…")
 	if err := Deploy(ctx); err != nil {
 		return errors.Wrap(err, "failed to deploy machine-controller")
 	}
 
+	ctx.Logger.Infoln("Verifying machine-controller permissions…")
+	if err := waitForMachineControllerRBAC(context.Background(), ctx.DynamicClient); err != nil {
+		return errors.Wrap(err, "machine-controller permissions are not granted")
+	}
+
 	ctx.Logger.Infoln("Installing machine-controller webhooks…")
 	if err := DeployWebhookConfiguration(ctx); err != nil {
 		return errors.Wrap(err, "failed to deploy machine-controller webhook configuration")
@@ -55,6 +128,76 @@ func Ensure(ctx *util.Context) error {
 	return nil
 }
 
+// validateCredentials makes sure the cloud providers accept the credentials
+// before machine-controller is deployed, as machine-controller only reports
+// bad credentials in its logs. The credentials of every cloud provider the
+// workers are created on are validated.
+func validateCredentials(ctx *util.Context) error {
+	creds, err := machineControllerCredentials(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to fetch cloud provider credentials")
+	}
+
+	providers := []kubeoneapi.CloudProviderName{ctx.Cluster.CloudProvider.Name}
+	for _, p := range ctx.Cluster.WorkerCloudProviders() {
+		if p != ctx.Cluster.CloudProvider.Name {
+			providers = append(providers, p)
+		}
+	}
+
+	ctx.Logger.Infoln("Validating cloud provider credentials…")
+	for _, p := range providers {
+		if missing := credentials.MissingKeys(p, creds); len(missing) > 0 {
+			return errors.Errorf("machine-controller credentials are missing %s required by the %s cloud provider", strings.Join(missing, ", "), p)
+		}
+
+		err = credentials.Validate(p, creds)
+		switch {
+		case err == nil:
+		case err == credentials.ErrValidationUnsupported:
+			ctx.Logger.Debugf("Skipping %s credentials validation: %v", p, err)
+		case isUnreachable(err):
+			// the API may not be reachable from air-gapped setups
+			ctx.Logger.Warnf("Unable to validate %s credentials: %v", p, err)
+		default:
+			return errors.Wrapf(err, "invalid %s credentials", p)
+		}
+	}
+
+	return nil
+}
+
+func isUnreachable(err error) bool {
+	_, ok := err.(*credentials.UnreachableError)
+	return ok
+}
+
+// machineControllerCredentials returns the credentials machine-controller will
+// use, either from the referenced secret or from the local environment
+func machineControllerCredentials(ctx *util.Context) (map[string]string, error) {
+	ref := ctx.Cluster.MachineController.CredentialsSecretRef
+	if ref == nil {
+		return credentials.ClusterCredentials(ctx.Cluster)
+	}
+
+	if ctx.DynamicClient == nil {
+		return nil, errors.New("kubernetes client not initialized")
+	}
+
+	secret := &corev1.Secret{}
+	key := dynclient.ObjectKey{Name: ref.Name, Namespace: ref.Namespace}
+	if err := ctx.DynamicClient.Get(context.Background(), key, secret); err != nil {
+		return nil, errors.Wrapf(err, "failed to get credentials secret %s/%s", ref.Namespace, ref.Name)
+	}
+
+	creds := make(map[string]string, len(secret.Data))
+	for k, v := range secret.Data {
+		creds[k] = string(v)
+	}
+
+	return creds, nil
+}
+
 // WaitReady waits for machine-controller and its webhook to became ready
 func WaitReady(ctx *util.Context) error {
 	if !ctx.Cluster.MachineController.Deploy {
@@ -66,11 +209,11 @@ func WaitReady(ctx *util.Context) error {
 	// Wait a bit to let scheduler to react
 	time.Sleep(10 * time.Second)
 
-	if err := WaitForWebhook(ctx.DynamicClient); err != nil {
+	if err := WaitForWebhookReady(ctx.DynamicClient); err != nil {
 		return errors.Wrap(err, "machine-controller-webhook did not come up")
 	}
 
-	if err := WaitForMachineController(ctx.DynamicClient); err != nil {
+	if err := WaitForMachineController(ctx.DynamicClient, ctx.RESTConfig); err != nil {
 		return errors.Wrap(err, "machine-controller did not come up")
 	}
 	return nil
@@ -0,0 +1,131 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinecontroller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/kubermatic/kubeone/pkg/internal/testclient"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1alpha1 "sigs.k8s.io/cluster-api/pkg/apis/cluster/v1alpha1"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestEnsureDeploymentUpdatesExisting(t *testing.T) {
+	existing := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceSystem,
+			Name:      "machine-controller",
+			Labels:    map[string]string{"app": "machine-controller", "owner": "someone"},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "machine-controller", Image: "machine-controller:v1.0.0"}},
+				},
+			},
+		},
+	}
+	client := testclient.NewMemoryClient(existing)
+
+	desired := existing.DeepCopy()
+	desired.Labels = map[string]string{"app": "machine-controller", "version": "v1.1.0"}
+	desired.Spec.Template.Spec.Containers[0].Image = "machine-controller:v1.1.0"
+
+	if err := ensureDeployment(context.Background(), client, desired.DeepCopy()); err != nil {
+		t.Fatal(err)
+	}
+
+	got := &appsv1.Deployment{}
+	if err := client.Get(context.Background(), dynclient.ObjectKey{Namespace: metav1.NamespaceSystem, Name: "machine-controller"}, got); err != nil {
+		t.Fatal(err)
+	}
+	if image := got.Spec.Template.Spec.Containers[0].Image; image != "machine-controller:v1.1.0" {
+		t.Errorf("expected the image to be updated, got %s", image)
+	}
+	expectedLabels := map[string]string{"app": "machine-controller", "owner": "someone", "version": "v1.1.0"}
+	if !reflect.DeepEqual(got.Labels, expectedLabels) {
+		t.Errorf("expected labels %v, got %v", expectedLabels, got.Labels)
+	}
+}
+
+func TestEnsureMachineDeploymentUpdatesExisting(t *testing.T) {
+	machineDeployment := func(replicas int32, kubelet string, annotations map[string]string) *clusterv1alpha1.MachineDeployment {
+		return &clusterv1alpha1.MachineDeployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   metav1.NamespaceSystem,
+				Name:        MachineDeploymentName("pool1"),
+				Annotations: annotations,
+			},
+			Spec: clusterv1alpha1.MachineDeploymentSpec{
+				Replicas: &replicas,
+				Template: clusterv1alpha1.MachineTemplateSpec{
+					Spec: clusterv1alpha1.MachineSpec{
+						Versions: clusterv1alpha1.MachineVersionInfo{Kubelet: kubelet},
+					},
+				},
+			},
+		}
+	}
+	autoscaled := map[string]string{autoscalerMinSizeAnnotation: "1", autoscalerMaxSizeAnnotation: "5"}
+
+	tests := []struct {
+		name             string
+		existing         *clusterv1alpha1.MachineDeployment
+		desired          *clusterv1alpha1.MachineDeployment
+		expectedReplicas int32
+	}{
+		{
+			name:             "replicas and version updated",
+			existing:         machineDeployment(1, "1.14.1", nil),
+			desired:          machineDeployment(3, "1.14.2", nil),
+			expectedReplicas: 3,
+		},
+		{
+			name:             "autoscaled replicas kept",
+			existing:         machineDeployment(4, "1.14.1", autoscaled),
+			desired:          machineDeployment(3, "1.14.2", autoscaled),
+			expectedReplicas: 4,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			client := testclient.NewMemoryClient(tc.existing)
+			if err := ensureMachineDeployment(context.Background(), client, tc.desired); err != nil {
+				t.Fatal(err)
+			}
+
+			got := &clusterv1alpha1.MachineDeployment{}
+			if err := client.Get(context.Background(), dynclient.ObjectKey{Namespace: metav1.NamespaceSystem, Name: MachineDeploymentName("pool1")}, got); err != nil {
+				t.Fatal(err)
+			}
+			if kubelet := got.Spec.Template.Spec.Versions.Kubelet; kubelet != "1.14.2" {
+				t.Errorf("expected the kubelet version to be updated, got %s", kubelet)
+			}
+			if *got.Spec.Replicas != tc.expectedReplicas {
+				t.Errorf("expected %d replicas, got %d", tc.expectedReplicas, *got.Spec.Replicas)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinecontroller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+)
+
+// GetMachineControllerLogs returns the last lines of the machine-controller pod logs
+func GetMachineControllerLogs(ctx context.Context, restConfig *rest.Config, lines int64) (string, error) {
+	client, err := coreV1RESTClient(restConfig)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to build REST client")
+	}
+
+	pods := corev1.PodList{}
+	err = client.Get().
+		Context(ctx).
+		Namespace(MachineControllerNamespace).
+		Resource("pods").
+		Param("labelSelector", fmt.Sprintf("%s=%s", MachineControllerAppLabelKey, MachineControllerAppLabelValue)).
+		Do().
+		Into(&pods)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to list machine-controller pods")
+	}
+
+	if len(pods.Items) == 0 {
+		return "", errors.New("no machine-controller pods found")
+	}
+
+	logs, err := client.Get().
+		Context(ctx).
+		Namespace(MachineControllerNamespace).
+		Resource("pods").
+		Name(pods.Items[0].Name).
+		SubResource("log").
+		Param("tailLines", strconv.FormatInt(lines, 10)).
+		DoRaw()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get machine-controller logs")
+	}
+
+	return string(logs), nil
+}
+
+// coreV1RESTClient returns a REST client for the core/v1 API group
+func coreV1RESTClient(config *rest.Config) (rest.Interface, error) {
+	if config == nil {
+		return nil, errors.New("rest config is not initialized")
+	}
+
+	cfg := rest.CopyConfig(config)
+	cfg.GroupVersion = &corev1.SchemeGroupVersion
+	cfg.APIPath = "/api"
+	cfg.NegotiatedSerializer = serializer.DirectCodecFactory{CodecFactory: scheme.Codecs}
+
+	return rest.RESTClientFor(cfg)
+}
@@ -20,6 +20,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -29,9 +31,25 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/wait"
 	clustercommon "sigs.k8s.io/cluster-api/pkg/apis/cluster/common"
 	clusterv1alpha1 "sigs.k8s.io/cluster-api/pkg/apis/cluster/v1alpha1"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Annotations read by the cluster-api provider of cluster-autoscaler
+const (
+	autoscalerMinSizeAnnotation = "cluster.k8s.io/cluster-api-autoscaler-node-group-min-size"
+	autoscalerMaxSizeAnnotation = "cluster.k8s.io/cluster-api-autoscaler-node-group-max-size"
+)
+
+// Label marking the MachineDeployments created by KubeOne, only those can be
+// pruned by apply
+const (
+	ManagedByLabel      = "app.kubernetes.io/managed-by"
+	ManagedByLabelValue = "kubeone"
 )
 
 type providerSpec struct {
@@ -57,7 +75,7 @@ func DeployMachineDeployments(ctx *util.Context) error {
 			return errors.Wrap(err, "failed to generate MachineDeployment")
 		}
 
-		err = simpleCreateOrUpdate(bgCtx, ctx.DynamicClient, machinedeployment)
+		err = ensureMachineDeployment(bgCtx, ctx.DynamicClient, machinedeployment)
 		if err != nil {
 			return errors.Wrap(err, "failed to ensure MachineDeployment")
 		}
@@ -66,8 +84,33 @@ func DeployMachineDeployments(ctx *util.Context) error {
 	return nil
 }
 
+// WaitForMachineSetReady waits until all replicas of the MachineSet are ready
+func WaitForMachineSetReady(ctx context.Context, client dynclient.Client, namespace, name string, timeout time.Duration) error {
+	key := types.NamespacedName{Namespace: namespace, Name: name}
+
+	return wait.Poll(5*time.Second, timeout, func() (bool, error) {
+		machineSet := &clusterv1alpha1.MachineSet{}
+		if err := client.Get(ctx, key, machineSet); err != nil {
+			return false, errors.Wrapf(err, "failed to get MachineSet %s", key)
+		}
+
+		var replicas int32 = 1
+		if machineSet.Spec.Replicas != nil {
+			replicas = *machineSet.Spec.Replicas
+		}
+
+		return machineSet.Status.ReadyReplicas == replicas, nil
+	})
+}
+
+// MachineDeploymentName returns the name of the MachineDeployment created
+// for the given workerset
+func MachineDeploymentName(workerset string) string {
+	return fmt.Sprintf("%s-deployment", workerset)
+}
+
 func createMachineDeployment(cluster *kubeoneapi.KubeOneCluster, workerset kubeoneapi.WorkerConfig) (*clusterv1alpha1.MachineDeployment, error) {
-	provider := cluster.CloudProvider.Name
+	provider := cluster.WorkerCloudProvider(workerset)
 
 	cloudProviderSpec, err := machineSpec(cluster, workerset, provider)
 	if err != nil {
@@ -101,8 +144,10 @@ func createMachineDeployment(cluster *kubeoneapi.KubeOneCluster, workerset kubeo
 			Kind:       "MachineDeployment",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace: metav1.NamespaceSystem,
-			Name:      fmt.Sprintf("%s-deployment", workerset.Name),
+			Namespace:   metav1.NamespaceSystem,
+			Name:        MachineDeploymentName(workerset.Name),
+			Labels:      map[string]string{ManagedByLabel: ManagedByLabelValue},
+			Annotations: autoscalerAnnotations(cluster),
 		},
 		Spec: clusterv1alpha1.MachineDeploymentSpec{
 			Paused:   false,
@@ -139,6 +184,20 @@ func createMachineDeployment(cluster *kubeoneapi.KubeOneCluster, workerset kubeo
 	}, nil
 }
 
+// autoscalerAnnotations returns the annotations marking the MachineDeployments
+// as node groups scaled by cluster-autoscaler, if it's enabled
+func autoscalerAnnotations(cluster *kubeoneapi.KubeOneCluster) map[string]string {
+	ca := cluster.ClusterAutoscaler
+	if ca == nil || !ca.Enabled {
+		return nil
+	}
+
+	return map[string]string{
+		autoscalerMinSizeAnnotation: strconv.Itoa(ca.MinNodes),
+		autoscalerMaxSizeAnnotation: strconv.Itoa(ca.MaxNodes),
+	}
+}
+
 func machineSpec(cluster *kubeoneapi.KubeOneCluster, workerset kubeoneapi.WorkerConfig, provider kubeoneapi.CloudProviderName) (map[string]interface{}, error) {
 	var err error
 
@@ -152,6 +211,14 @@ func machineSpec(cluster *kubeoneapi.KubeOneCluster, workerset kubeoneapi.Worker
 		return nil, errors.Wrap(err, "unable to parse the workerset spec")
 	}
 
+	if workerset.Config.DiskSize > 0 {
+		spec["diskSize"] = workerset.Config.DiskSize
+	}
+
+	if len(workerset.Config.DataDisks) > 0 {
+		spec["dataDisks"] = workerset.Config.DataDisks
+	}
+
 	// We only need this tag for AWS because it is used to coordinate nodes in ASG
 	if provider == kubeoneapi.CloudProviderNameAWS {
 		tagName := fmt.Sprintf("kubernetes.io/cluster/%s", cluster.Name)
@@ -0,0 +1,131 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinecontroller
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// machineControllerMetricsPortName is the name of the metrics port of the
+	// machine-controller Service
+	machineControllerMetricsPortName = "metrics"
+	// machineControllerInternalPort is the port of the machine-controller
+	// internal listen address serving the metrics and health checks
+	machineControllerInternalPort = 8085
+)
+
+// deployMetrics exposes the machine-controller metrics with a Service and,
+// when monitoring is enabled, creates a ServiceMonitor scraping it
+func deployMetrics(ctx context.Context, client dynclient.Client, cluster *kubeoneapi.KubeOneCluster) error {
+	if err := simpleCreateOrUpdate(ctx, client, machineControllerService(cluster)); err != nil {
+		return errors.Wrap(err, "failed to ensure machine-controller service")
+	}
+
+	if cluster.Monitoring == nil || !cluster.Monitoring.Enabled {
+		return nil
+	}
+
+	if err := simpleCreateOrUpdate(ctx, client, machineControllerServiceMonitor()); err != nil {
+		return errors.Wrap(err, "failed to ensure machine-controller ServiceMonitor, are the Prometheus Operator CRDs installed?")
+	}
+
+	return nil
+}
+
+// machineControllerService returns the Service exposing the machine-controller metrics
+func machineControllerService(cluster *kubeoneapi.KubeOneCluster) *corev1.Service {
+	port := int32(8080)
+	if mc := cluster.MachineController; mc != nil && mc.MetricsPort > 0 {
+		port = mc.MetricsPort
+	}
+
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Service",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "machine-controller",
+			Namespace: MachineControllerNamespace,
+			Labels: map[string]string{
+				MachineControllerAppLabelKey: MachineControllerAppLabelValue,
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeClusterIP,
+			Selector: map[string]string{
+				MachineControllerAppLabelKey: MachineControllerAppLabelValue,
+			},
+			Ports: []corev1.ServicePort{
+				{
+					Name:       machineControllerMetricsPortName,
+					Port:       port,
+					Protocol:   corev1.ProtocolTCP,
+					TargetPort: intstr.FromInt(machineControllerInternalPort),
+				},
+			},
+		},
+	}
+}
+
+// machineControllerServiceMonitor returns the Prometheus Operator
+// ServiceMonitor scraping the machine-controller Service. The Prometheus
+// Operator types aren't vendored, so it's built as an unstructured object.
+func machineControllerServiceMonitor() *unstructured.Unstructured {
+	sm := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "monitoring.coreos.com/v1",
+			"kind":       "ServiceMonitor",
+			"metadata": map[string]interface{}{
+				"name":      "machine-controller",
+				"namespace": MachineControllerNamespace,
+				"labels": map[string]interface{}{
+					MachineControllerAppLabelKey: MachineControllerAppLabelValue,
+				},
+			},
+			"spec": map[string]interface{}{
+				"selector": map[string]interface{}{
+					"matchLabels": map[string]interface{}{
+						MachineControllerAppLabelKey: MachineControllerAppLabelValue,
+					},
+				},
+				"namespaceSelector": map[string]interface{}{
+					"matchNames": []interface{}{MachineControllerNamespace},
+				},
+				"endpoints": []interface{}{
+					map[string]interface{}{
+						"port": machineControllerMetricsPortName,
+						"path": "/metrics",
+					},
+				},
+			},
+		},
+	}
+
+	return sm
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinecontroller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// rbacPropagationTimeout is how long newly created roles are given to
+// become effective before missing permissions are reported
+const rbacPropagationTimeout = 30 * time.Second
+
+// VerifyMachineControllerRBAC checks that the machine-controller ServiceAccount
+// is granted all permissions of the machine-controller ClusterRole and Roles.
+// The permissions are checked using SubjectAccessReviews, so permissions denied
+// by other authorizers are reported as well. The returned error lists exactly
+// which permissions are missing.
+func VerifyMachineControllerRBAC(ctx context.Context, client dynclient.Client) error {
+	var missing []string
+
+	for _, attrs := range requiredPermissions() {
+		sar := &authorizationv1.SubjectAccessReview{
+			Spec: authorizationv1.SubjectAccessReviewSpec{
+				User:               fmt.Sprintf("system:serviceaccount:%s:machine-controller", MachineControllerNamespace),
+				Groups:             []string{"system:serviceaccounts", "system:serviceaccounts:" + MachineControllerNamespace, "system:authenticated"},
+				ResourceAttributes: attrs.DeepCopy(),
+			},
+		}
+
+		if err := client.Create(ctx, sar); err != nil {
+			return errors.Wrap(err, "failed to review machine-controller permissions")
+		}
+
+		if !sar.Status.Allowed {
+			missing = append(missing, formatPermission(attrs))
+		}
+	}
+
+	if len(missing) > 0 {
+		return errors.Errorf("machine-controller service account is missing permissions: %s", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// waitForMachineControllerRBAC verifies the machine-controller permissions,
+// giving newly created roles time to become effective
+func waitForMachineControllerRBAC(ctx context.Context, client dynclient.Client) error {
+	var verifyErr error
+
+	err := wait.Poll(2*time.Second, rbacPropagationTimeout, func() (bool, error) {
+		verifyErr = VerifyMachineControllerRBAC(ctx, client)
+		return verifyErr == nil, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return verifyErr
+	}
+
+	return err
+}
+
+// requiredPermissions expands the rules of the machine-controller ClusterRole
+// and Roles into the individual permissions they grant
+func requiredPermissions() []authorizationv1.ResourceAttributes {
+	var permissions []authorizationv1.ResourceAttributes
+
+	add := func(namespace string, rules []rbacv1.PolicyRule) {
+		for _, rule := range rules {
+			names := rule.ResourceNames
+			if len(names) == 0 {
+				names = []string{""}
+			}
+
+			for _, group := range rule.APIGroups {
+				for _, resource := range rule.Resources {
+					resource, subresource := splitSubresource(resource)
+					for _, verb := range rule.Verbs {
+						for _, name := range names {
+							permissions = append(permissions, authorizationv1.ResourceAttributes{
+								Namespace:   namespace,
+								Verb:        verb,
+								Group:       group,
+								Resource:    resource,
+								Subresource: subresource,
+								Name:        name,
+							})
+						}
+					}
+				}
+			}
+		}
+	}
+
+	add("", machineControllerClusterRole().Rules)
+
+	roles := []*rbacv1.Role{
+		machineControllerKubeSystemRole(),
+		machineControllerKubePublicRole(),
+		machineControllerEndpointReaderRole(),
+		machineControllerClusterInfoReaderRole(),
+	}
+	for _, role := range roles {
+		add(role.Namespace, role.Rules)
+	}
+
+	return permissions
+}
+
+func splitSubresource(resource string) (string, string) {
+	parts := strings.SplitN(resource, "/", 2)
+	if len(parts) == 2 {
+		return parts[0], parts[1]
+	}
+	return parts[0], ""
+}
+
+// formatPermission describes the permission, e.g.
+// "create pods/eviction in all namespaces"
+func formatPermission(attrs authorizationv1.ResourceAttributes) string {
+	resource := attrs.Resource
+	if attrs.Group != "" {
+		resource = resource + "." + attrs.Group
+	}
+	if attrs.Subresource != "" {
+		resource = resource + "/" + attrs.Subresource
+	}
+	if attrs.Name != "" {
+		resource = fmt.Sprintf("%s %q", resource, attrs.Name)
+	}
+
+	scope := "in all namespaces"
+	if attrs.Namespace != "" {
+		scope = "in namespace " + attrs.Namespace
+	}
+
+	return fmt.Sprintf("%s %s %s", attrs.Verb, resource, scope)
+}
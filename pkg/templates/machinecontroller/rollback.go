@@ -0,0 +1,81 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinecontroller
+
+import (
+	"context"
+
+	"github.com/Masterminds/semver"
+	"github.com/pkg/errors"
+
+	"github.com/kubermatic/kubeone/pkg/util"
+
+	appsv1 "k8s.io/api/apps/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RollbackMachineController replaces the machine-controller and its webhook
+// deployments with the ones running the given previous version
+func RollbackMachineController(ctx *util.Context, previousVersion string) error {
+	if ctx.DynamicClient == nil {
+		return errors.New("kubernetes client not initialized")
+	}
+
+	if _, err := semver.NewVersion(previousVersion); err != nil {
+		return errors.Wrapf(err, "invalid machine-controller version %q", previousVersion)
+	}
+
+	deployment, err := machineControllerDeployment(ctx.Cluster, previousVersion)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate machine-controller deployment")
+	}
+
+	deployments := []*appsv1.Deployment{
+		deployment,
+		webhookDeployment(ctx.Cluster, previousVersion),
+	}
+
+	bgCtx := context.Background()
+
+	ctx.Logger.Infof("Rolling back machine-controller to %s…", previousVersion)
+	for _, dep := range deployments {
+		if err = deleteDeployment(bgCtx, ctx.DynamicClient, dep.Namespace, dep.Name); err != nil {
+			return errors.Wrapf(err, "failed to delete %s deployment", dep.Name)
+		}
+
+		if err = simpleCreateOrUpdate(bgCtx, ctx.DynamicClient, dep); err != nil {
+			return errors.Wrapf(err, "failed to ensure %s deployment", dep.Name)
+		}
+	}
+
+	return WaitReady(ctx)
+}
+
+// deleteDeployment deletes the deployment, ignoring it if it doesn't exist
+func deleteDeployment(ctx context.Context, client dynclient.Client, namespace, name string) error {
+	dep := &appsv1.Deployment{}
+	dep.Namespace = namespace
+	dep.Name = name
+
+	err := client.Delete(ctx, dep)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
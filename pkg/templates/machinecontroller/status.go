@@ -0,0 +1,173 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinecontroller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Status describes the health of machine-controller and its webhook
+type Status struct {
+	Deployments []DeploymentStatus
+	Pods        []PodStatus
+	// WebhookEndpoints is the number of ready endpoints of the webhook service
+	WebhookEndpoints int
+	// WebhookConfigured is true if the MutatingWebhookConfiguration exists
+	WebhookConfigured bool
+}
+
+// DeploymentStatus describes a single machine-controller deployment
+type DeploymentStatus struct {
+	Name          string
+	Found         bool
+	Replicas      int32
+	ReadyReplicas int32
+}
+
+// PodStatus describes a single machine-controller pod
+type PodStatus struct {
+	Name              string
+	Phase             corev1.PodPhase
+	Ready             bool
+	Restarts          int32
+	LastRestartReason string
+}
+
+// Healthy returns true if both deployments are fully available, all the
+// pods are ready and the webhook is reachable and configured
+func (s *Status) Healthy() bool {
+	for _, dep := range s.Deployments {
+		if !dep.Found || dep.ReadyReplicas < dep.Replicas {
+			return false
+		}
+	}
+
+	for _, pod := range s.Pods {
+		if !pod.Ready {
+			return false
+		}
+	}
+
+	return s.WebhookEndpoints > 0 && s.WebhookConfigured
+}
+
+// GetStatus collects the status of machine-controller and its webhook
+func GetStatus(ctx context.Context, client dynclient.Client) (*Status, error) {
+	status := &Status{}
+
+	for _, name := range []string{MachineControllerAppLabelValue, WebhookName} {
+		depStatus, err := deploymentStatus(ctx, client, name)
+		if err != nil {
+			return nil, err
+		}
+		status.Deployments = append(status.Deployments, depStatus)
+	}
+
+	for _, app := range []string{MachineControllerAppLabelValue, WebhookAppLabelValue} {
+		listOpts := dynclient.ListOptions{Namespace: MachineControllerNamespace}
+		err := listOpts.SetLabelSelector(fmt.Sprintf("%s=%s", MachineControllerAppLabelKey, app))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse machine-controller labels")
+		}
+
+		pods := corev1.PodList{}
+		if err = client.List(ctx, &listOpts, &pods); err != nil {
+			return nil, errors.Wrapf(err, "failed to list %s pods", app)
+		}
+
+		for _, pod := range pods.Items {
+			status.Pods = append(status.Pods, podStatus(pod))
+		}
+	}
+
+	endpoints := &corev1.Endpoints{}
+	key := types.NamespacedName{Namespace: WebhookNamespace, Name: WebhookName}
+	err := client.Get(ctx, key, endpoints)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return nil, errors.Wrap(err, "failed to get machine-controller webhook endpoints")
+	}
+	for _, subset := range endpoints.Subsets {
+		status.WebhookEndpoints += len(subset.Addresses)
+	}
+
+	webhookCfg := &admissionregistrationv1beta1.MutatingWebhookConfiguration{}
+	key = types.NamespacedName{Name: WebhookConfigurationName}
+	err = client.Get(ctx, key, webhookCfg)
+	switch {
+	case err == nil:
+		status.WebhookConfigured = true
+	case !k8serrors.IsNotFound(err):
+		return nil, errors.Wrap(err, "failed to get machine-controller mutating webhook")
+	}
+
+	return status, nil
+}
+
+func deploymentStatus(ctx context.Context, client dynclient.Client, name string) (DeploymentStatus, error) {
+	status := DeploymentStatus{Name: name}
+
+	dep := &appsv1.Deployment{}
+	key := types.NamespacedName{Namespace: MachineControllerNamespace, Name: name}
+	err := client.Get(ctx, key, dep)
+	if k8serrors.IsNotFound(err) {
+		return status, nil
+	}
+	if err != nil {
+		return status, errors.Wrapf(err, "failed to get %s deployment", name)
+	}
+
+	status.Found = true
+	status.ReadyReplicas = dep.Status.ReadyReplicas
+	status.Replicas = 1
+	if dep.Spec.Replicas != nil {
+		status.Replicas = *dep.Spec.Replicas
+	}
+
+	return status, nil
+}
+
+func podStatus(pod corev1.Pod) PodStatus {
+	status := PodStatus{
+		Name:  pod.Name,
+		Phase: pod.Status.Phase,
+	}
+
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady && cond.Status == corev1.ConditionTrue {
+			status.Ready = true
+		}
+	}
+
+	for _, cs := range pod.Status.ContainerStatuses {
+		status.Restarts += cs.RestartCount
+		if term := cs.LastTerminationState.Terminated; term != nil {
+			status.LastRestartReason = term.Reason
+		}
+	}
+
+	return status
+}
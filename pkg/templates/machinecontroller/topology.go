@@ -0,0 +1,98 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinecontroller
+
+import (
+	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	topologyKeyZone     = "failure-domain.beta.kubernetes.io/zone"
+	topologyKeyHostname = "kubernetes.io/hostname"
+)
+
+// AddDefaultTopologySpreadConstraints adds constraints spreading machine-controller
+// pods across the given zones and hosts. Nothing is added for single zone clusters.
+func AddDefaultTopologySpreadConstraints(config *kubeoneapi.MachineControllerConfig, zones []string) {
+	if config == nil || len(zones) < 2 {
+		return
+	}
+
+	defaults := []kubeoneapi.TopologySpreadConstraint{
+		{MaxSkew: 1, TopologyKey: topologyKeyZone, WhenUnsatisfiable: kubeoneapi.ScheduleAnyway},
+		{MaxSkew: 1, TopologyKey: topologyKeyHostname, WhenUnsatisfiable: kubeoneapi.ScheduleAnyway},
+	}
+
+	for _, d := range defaults {
+		found := false
+		for _, c := range config.TopologySpreadConstraints {
+			if c.TopologyKey == d.TopologyKey {
+				found = true
+				break
+			}
+		}
+		if !found {
+			config.TopologySpreadConstraints = append(config.TopologySpreadConstraints, d)
+		}
+	}
+}
+
+// topologySpreadAffinity renders the topology spread constraints as pod
+// anti-affinity, as the Kubernetes API version we build against doesn't
+// support topologySpreadConstraints in the PodSpec.
+//
+// The anti-affinity is always preferred, never required: the Deployment is
+// rolled out with maxUnavailable 0, so a required term would keep the new pod
+// pending as long as the old one occupies its domain. DoNotSchedule
+// constraints get a higher weight than ScheduleAnyway ones, and the weight
+// decreases as MaxSkew allows more pods in a domain.
+func topologySpreadAffinity(constraints []kubeoneapi.TopologySpreadConstraint) *corev1.Affinity {
+	if len(constraints) == 0 {
+		return nil
+	}
+
+	antiAffinity := &corev1.PodAntiAffinity{}
+	for _, c := range constraints {
+		weight := int32(100)
+		if c.WhenUnsatisfiable != kubeoneapi.DoNotSchedule {
+			weight = 50
+		}
+		if c.MaxSkew > 1 {
+			weight /= c.MaxSkew
+		}
+		if weight < 1 {
+			weight = 1
+		}
+
+		antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution, corev1.WeightedPodAffinityTerm{
+			Weight: weight,
+			PodAffinityTerm: corev1.PodAffinityTerm{
+				LabelSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						MachineControllerAppLabelKey: MachineControllerAppLabelValue,
+					},
+				},
+				TopologyKey: c.TopologyKey,
+			},
+		})
+	}
+
+	return &corev1.Affinity{PodAntiAffinity: antiAffinity}
+}
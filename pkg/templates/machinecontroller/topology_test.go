@@ -0,0 +1,81 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinecontroller
+
+import (
+	"testing"
+
+	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
+)
+
+func TestTopologySpreadAffinity(t *testing.T) {
+	tests := []struct {
+		name           string
+		constraints    []kubeoneapi.TopologySpreadConstraint
+		expectedWeight []int32
+	}{
+		{
+			name: "no constraints",
+		},
+		{
+			name: "do not schedule",
+			constraints: []kubeoneapi.TopologySpreadConstraint{
+				{MaxSkew: 1, TopologyKey: topologyKeyZone, WhenUnsatisfiable: kubeoneapi.DoNotSchedule},
+			},
+			expectedWeight: []int32{100},
+		},
+		{
+			name: "schedule anyway with max skew",
+			constraints: []kubeoneapi.TopologySpreadConstraint{
+				{MaxSkew: 1, TopologyKey: topologyKeyZone, WhenUnsatisfiable: kubeoneapi.ScheduleAnyway},
+				{MaxSkew: 2, TopologyKey: topologyKeyHostname, WhenUnsatisfiable: kubeoneapi.ScheduleAnyway},
+			},
+			expectedWeight: []int32{50, 25},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			affinity := topologySpreadAffinity(tc.constraints)
+			if len(tc.constraints) == 0 {
+				if affinity != nil {
+					t.Fatalf("expected no affinity, got %+v", affinity)
+				}
+				return
+			}
+
+			// a required term can't be satisfied while the old pod occupies
+			// its domain during a rollout with maxUnavailable 0
+			if len(affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution) != 0 {
+				t.Fatalf("expected only preferred anti-affinity terms")
+			}
+
+			terms := affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+			if len(terms) != len(tc.expectedWeight) {
+				t.Fatalf("expected %d terms, got %d", len(tc.expectedWeight), len(terms))
+			}
+			for i, term := range terms {
+				if term.Weight != tc.expectedWeight[i] {
+					t.Errorf("term %d: expected weight %d, got %d", i, tc.expectedWeight[i], term.Weight)
+				}
+				if term.PodAffinityTerm.TopologyKey != tc.constraints[i].TopologyKey {
+					t.Errorf("term %d: expected topology key %q, got %q", i, tc.constraints[i].TopologyKey, term.PodAffinityTerm.TopologyKey)
+				}
+			}
+		})
+	}
+}
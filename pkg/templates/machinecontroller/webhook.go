@@ -19,8 +19,10 @@ package machinecontroller
 import (
 	"context"
 	"crypto/rsa"
+	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -32,11 +34,16 @@ import (
 	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/wait"
 	certutil "k8s.io/client-go/util/cert"
 	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 // MachineController Webhook related constants
@@ -46,6 +53,10 @@ const (
 	WebhookAppLabelValue = WebhookName
 	WebhookTag           = MachineControllerTag
 	WebhookNamespace     = metav1.NamespaceSystem
+
+	WebhookConfigurationName = "machine-controller.kubermatic.io"
+
+	webhookServingCertSecretName = "machinecontroller-webhook-serving-cert"
 )
 
 // DeployWebhookConfiguration deploys MachineController webhook deployment on the cluster
@@ -63,7 +74,7 @@ func DeployWebhookConfiguration(ctx *util.Context) error {
 	bgCtx := context.Background()
 
 	// Deploy Webhook
-	err = simpleCreateOrUpdate(bgCtx, ctx.DynamicClient, webhookDeployment(ctx.Cluster))
+	err = simpleCreateOrUpdate(bgCtx, ctx.DynamicClient, webhookDeployment(ctx.Cluster, WebhookTag))
 	if err != nil {
 		return errors.Wrap(err, "failed to ensure machine-controller webhook deployment")
 	}
@@ -85,7 +96,7 @@ func DeployWebhookConfiguration(ctx *util.Context) error {
 		return errors.Wrap(err, "failed to ensure machine-controller webhook secret")
 	}
 
-	err = simpleCreateOrUpdate(bgCtx, ctx.DynamicClient, mutatingwebhookConfiguration(caCert))
+	err = ensureMutatingWebhookConfiguration(bgCtx, ctx.DynamicClient, caCert, ctx.Cluster.MachineController.WebhookTimeoutSeconds)
 	if err != nil {
 		return errors.Wrap(err, "failed to ensure machine-controller mutating webhook")
 	}
@@ -93,43 +104,220 @@ func DeployWebhookConfiguration(ctx *util.Context) error {
 	return nil
 }
 
-// WaitForWebhook waits for machine-controller-webhook to become running
-func WaitForWebhook(client dynclient.Client) error {
+// webhook readiness checks reported by WebhookNotReadyError
+const (
+	WebhookCheckPod           = "pod ready"
+	WebhookCheckEndpoints     = "service endpoints"
+	WebhookCheckConfiguration = "mutating webhook configuration"
+	WebhookCheckTLSSecret     = "TLS serving certificate"
+)
+
+// WebhookCheck is a failed webhook readiness check and the reason it failed
+type WebhookCheck struct {
+	Name   string
+	Reason string
+}
+
+// WebhookNotReadyError is returned by WaitForWebhookReady when the webhook
+// didn't become ready in time, listing the checks which still failed
+type WebhookNotReadyError struct {
+	FailedChecks []WebhookCheck
+}
+
+func (e *WebhookNotReadyError) Error() string {
+	failed := make([]string, 0, len(e.FailedChecks))
+	for _, check := range e.FailedChecks {
+		failed = append(failed, fmt.Sprintf("%s: %s", check.Name, check.Reason))
+	}
+
+	return fmt.Sprintf("machine-controller-webhook not ready: %s", strings.Join(failed, "; "))
+}
+
+// WaitForWebhookReady waits for machine-controller-webhook to become ready,
+// checking the webhook pod, the endpoints of its service, the mutating
+// webhook configuration and the TLS serving certificate. On timeout a
+// WebhookNotReadyError lists the failed checks.
+func WaitForWebhookReady(client dynclient.Client) error {
+	var failed []WebhookCheck
+
+	err := wait.Poll(5*time.Second, 3*time.Minute, func() (bool, error) {
+		var err error
+		failed, err = webhookReadinessChecks(context.Background(), client)
+		if err != nil {
+			return false, err
+		}
+
+		return len(failed) == 0, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return &WebhookNotReadyError{FailedChecks: failed}
+	}
+
+	return err
+}
+
+// webhookReadinessChecks runs all webhook readiness checks and returns the
+// failed ones
+func webhookReadinessChecks(ctx context.Context, client dynclient.Client) ([]WebhookCheck, error) {
+	checks := []struct {
+		name string
+		fn   func(context.Context, dynclient.Client) (string, error)
+	}{
+		{WebhookCheckPod, checkWebhookPod},
+		{WebhookCheckEndpoints, checkWebhookEndpoints},
+		{WebhookCheckConfiguration, checkWebhookConfiguration},
+		{WebhookCheckTLSSecret, checkWebhookTLSSecret},
+	}
+
+	var failed []WebhookCheck
+	for _, check := range checks {
+		reason, err := check.fn(ctx, client)
+		if err != nil {
+			return nil, err
+		}
+		if reason != "" {
+			failed = append(failed, WebhookCheck{Name: check.name, Reason: reason})
+		}
+	}
+
+	return failed, nil
+}
+
+// checkWebhookPod returns why no webhook pod is ready, or an empty string
+func checkWebhookPod(ctx context.Context, client dynclient.Client) (string, error) {
 	listOpts := dynclient.ListOptions{
 		Namespace: WebhookNamespace,
 	}
 	err := listOpts.SetLabelSelector(fmt.Sprintf("%s=%s", WebhookAppLabelKey, WebhookAppLabelValue))
 	if err != nil {
-		return errors.Wrap(err, "failed to parse machine-controller labels")
+		return "", errors.Wrap(err, "failed to parse machine-controller labels")
 	}
 
-	return wait.Poll(5*time.Second, 3*time.Minute, func() (bool, error) {
-		webhookPods := corev1.PodList{}
-		err = client.List(context.Background(), &listOpts, &webhookPods)
-		if err != nil {
-			return false, errors.Wrap(err, "failed to list machine-controller's webhook pods")
-		}
+	webhookPods := corev1.PodList{}
+	if err = client.List(ctx, &listOpts, &webhookPods); err != nil {
+		return "", errors.Wrap(err, "failed to list machine-controller's webhook pods")
+	}
 
-		if len(webhookPods.Items) == 0 {
-			return false, nil
+	if len(webhookPods.Items) == 0 {
+		return "no pods found", nil
+	}
+
+	whpod := webhookPods.Items[0]
+	if whpod.Status.Phase != corev1.PodRunning {
+		return fmt.Sprintf("pod %s is %s", whpod.Name, whpod.Status.Phase), nil
+	}
+
+	for _, podcond := range whpod.Status.Conditions {
+		if podcond.Type == corev1.PodReady && podcond.Status == corev1.ConditionTrue {
+			return "", nil
 		}
+	}
 
-		whpod := webhookPods.Items[0]
+	return fmt.Sprintf("pod %s is running but not ready", whpod.Name), nil
+}
 
-		if whpod.Status.Phase == corev1.PodRunning {
-			for _, podcond := range whpod.Status.Conditions {
-				if podcond.Type == corev1.PodReady && podcond.Status == corev1.ConditionTrue {
-					return true, nil
-				}
-			}
+// checkWebhookEndpoints returns why the webhook service has no ready
+// endpoints, or an empty string
+func checkWebhookEndpoints(ctx context.Context, client dynclient.Client) (string, error) {
+	endpoints := &corev1.Endpoints{}
+	key := types.NamespacedName{Namespace: WebhookNamespace, Name: WebhookName}
+	err := client.Get(ctx, key, endpoints)
+	if k8serrors.IsNotFound(err) {
+		return "endpoints not found", nil
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get machine-controller webhook endpoints")
+	}
+
+	for _, subset := range endpoints.Subsets {
+		if len(subset.Addresses) > 0 {
+			return "", nil
 		}
+	}
 
-		return false, nil
+	return "no ready addresses", nil
+}
+
+// checkWebhookConfiguration returns why the mutating webhook configuration
+// is missing, or an empty string
+func checkWebhookConfiguration(ctx context.Context, client dynclient.Client) (string, error) {
+	cfg := &admissionregistrationv1beta1.MutatingWebhookConfiguration{}
+	err := client.Get(ctx, types.NamespacedName{Name: WebhookConfigurationName}, cfg)
+	if k8serrors.IsNotFound(err) {
+		return fmt.Sprintf("%s not found", WebhookConfigurationName), nil
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get machine-controller mutating webhook configuration")
+	}
+
+	if len(cfg.Webhooks) == 0 {
+		return fmt.Sprintf("%s has no webhooks", WebhookConfigurationName), nil
+	}
+
+	return "", nil
+}
+
+// checkWebhookTLSSecret returns why the TLS serving certificate is invalid,
+// or an empty string
+func checkWebhookTLSSecret(ctx context.Context, client dynclient.Client) (string, error) {
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: WebhookNamespace, Name: webhookServingCertSecretName}
+	err := client.Get(ctx, key, secret)
+	if k8serrors.IsNotFound(err) {
+		return fmt.Sprintf("secret %s not found", webhookServingCertSecretName), nil
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get machine-controller webhook TLS secret")
+	}
+
+	return validateServingCertificate(secret.Data, time.Now()), nil
+}
+
+// validateServingCertificate returns why the certificate of the webhook TLS
+// secret data is invalid at the given time, or an empty string
+func validateServingCertificate(data map[string][]byte, now time.Time) string {
+	if _, err := tls.X509KeyPair(data["cert.pem"], data["key.pem"]); err != nil {
+		return fmt.Sprintf("invalid certificate and key: %v", err)
+	}
+
+	certs, err := certutil.ParseCertsPEM(data["cert.pem"])
+	if err != nil {
+		return fmt.Sprintf("invalid certificate: %v", err)
+	}
+
+	cert := certs[0]
+	if now.Before(cert.NotBefore) {
+		return fmt.Sprintf("certificate not valid before %s", cert.NotBefore.Format(time.RFC3339))
+	}
+	if now.After(cert.NotAfter) {
+		return fmt.Sprintf("certificate expired at %s", cert.NotAfter.Format(time.RFC3339))
+	}
+
+	caCerts, err := certutil.ParseCertsPEM(data["ca.crt"])
+	if err != nil {
+		return fmt.Sprintf("invalid CA certificate: %v", err)
+	}
+
+	roots := x509.NewCertPool()
+	for _, caCert := range caCerts {
+		roots.AddCert(caCert)
+	}
+
+	_, err = cert.Verify(x509.VerifyOptions{
+		DNSName:     fmt.Sprintf("%s.%s.svc", WebhookName, WebhookNamespace),
+		Roots:       roots,
+		CurrentTime: now,
+		KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
 	})
+	if err != nil {
+		return fmt.Sprintf("certificate not valid for the webhook service: %v", err)
+	}
+
+	return ""
 }
 
 // webhookDeployment returns the deployment for the machine-controllers MutatignAdmissionWebhook
-func webhookDeployment(cluster *kubeoneapi.KubeOneCluster) *appsv1.Deployment {
+func webhookDeployment(cluster *kubeoneapi.KubeOneCluster, version string) *appsv1.Deployment {
 	dep := &appsv1.Deployment{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "apps/v1",
@@ -191,7 +379,7 @@ func webhookDeployment(cluster *kubeoneapi.KubeOneCluster) *appsv1.Deployment {
 	dep.Spec.Template.Spec.Containers = []corev1.Container{
 		{
 			Name:            "machine-controller-webhook",
-			Image:           "kubermatic/machine-controller:" + WebhookTag,
+			Image:           cluster.ImageFor("kubermatic/machine-controller:" + version),
 			ImagePullPolicy: corev1.PullIfNotPresent,
 			Command:         []string{"/usr/local/bin/webhook"},
 			Args: []string{
@@ -201,6 +389,7 @@ func webhookDeployment(cluster *kubeoneapi.KubeOneCluster) *appsv1.Deployment {
 				"-listen-address", "0.0.0.0:9876",
 			},
 			Env:                      getEnvVarCredentials(cluster),
+			EnvFrom:                  getEnvFromCredentials(cluster),
 			TerminationMessagePath:   corev1.TerminationMessagePathDefault,
 			TerminationMessagePolicy: corev1.TerminationMessageReadFile,
 			ReadinessProbe: &corev1.Probe{
@@ -275,10 +464,10 @@ func service() *corev1.Service {
 
 func getServingCertVolume() corev1.Volume {
 	return corev1.Volume{
-		Name: "machinecontroller-webhook-serving-cert",
+		Name: webhookServingCertSecretName,
 		VolumeSource: corev1.VolumeSource{
 			Secret: &corev1.SecretVolumeSource{
-				SecretName:  "machinecontroller-webhook-serving-cert",
+				SecretName:  webhookServingCertSecretName,
 				DefaultMode: int32Ptr(0444),
 			},
 		},
@@ -295,7 +484,7 @@ func tlsServingCertificate(caKey *rsa.PrivateKey, caCert *x509.Certificate) (*co
 		},
 	}
 
-	se.Name = "machinecontroller-webhook-serving-cert"
+	se.Name = webhookServingCertSecretName
 	se.Namespace = WebhookNamespace
 	se.Data = map[string][]byte{}
 
@@ -340,7 +529,7 @@ func mutatingwebhookConfiguration(caCert *x509.Certificate) *admissionregistrati
 		},
 	}
 
-	cfg.Name = "machine-controller.kubermatic.io"
+	cfg.Name = WebhookConfigurationName
 	cfg.Namespace = WebhookNamespace
 
 	cfg.Webhooks = []admissionregistrationv1beta1.Webhook{
@@ -401,6 +590,35 @@ func mutatingwebhookConfiguration(caCert *x509.Certificate) *admissionregistrati
 	return cfg
 }
 
+// ensureMutatingWebhookConfiguration creates or updates the MutatingWebhookConfiguration
+// with the given webhook timeout. The vendored API types predate the timeoutSeconds
+// field, so the configuration is sent as an unstructured object.
+func ensureMutatingWebhookConfiguration(ctx context.Context, client dynclient.Client, caCert *x509.Certificate, timeoutSeconds int32) error {
+	cfg, err := runtime.DefaultUnstructuredConverter.ToUnstructured(mutatingwebhookConfiguration(caCert))
+	if err != nil {
+		return errors.Wrap(err, "failed to convert mutating webhook configuration")
+	}
+
+	webhooks, _, err := unstructured.NestedSlice(cfg, "webhooks")
+	if err != nil {
+		return errors.Wrap(err, "failed to read webhooks")
+	}
+	if timeoutSeconds > 0 {
+		for _, w := range webhooks {
+			w.(map[string]interface{})["timeoutSeconds"] = int64(timeoutSeconds)
+		}
+	}
+
+	// the webhooks are set on the existing object as well, so the
+	// timeout and the CA bundle are updated
+	obj := &unstructured.Unstructured{Object: cfg}
+	_, err = controllerutil.CreateOrUpdate(ctx, client, obj, func(existing runtime.Object) error {
+		return unstructured.SetNestedSlice(existing.(*unstructured.Unstructured).Object, webhooks, "webhooks")
+	})
+
+	return err
+}
+
 func int32Ptr(i int32) *int32 {
 	return &i
 }
@@ -0,0 +1,100 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinecontroller
+
+import (
+	"testing"
+	"time"
+
+	certutil "k8s.io/client-go/util/cert"
+)
+
+func TestValidateServingCertificate(t *testing.T) {
+	caKey, err := certutil.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, err := certutil.NewSelfSignedCACert(certutil.Config{CommonName: "kubernetes"}, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherCAKey, err := certutil.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherCACert, err := certutil.NewSelfSignedCACert(certutil.Config{CommonName: "other"}, otherCAKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secret, err := tlsServingCertificate(caKey, caCert)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	withData := func(key string, value []byte) map[string][]byte {
+		data := map[string][]byte{}
+		for k, v := range secret.Data {
+			data[k] = v
+		}
+		data[key] = value
+		return data
+	}
+
+	tests := []struct {
+		name    string
+		data    map[string][]byte
+		now     time.Time
+		invalid bool
+	}{
+		{
+			name: "valid certificate",
+			data: secret.Data,
+			now:  time.Now(),
+		},
+		{
+			name:    "expired certificate",
+			data:    secret.Data,
+			now:     time.Now().AddDate(2, 0, 0),
+			invalid: true,
+		},
+		{
+			name:    "missing key",
+			data:    withData("key.pem", nil),
+			now:     time.Now(),
+			invalid: true,
+		},
+		{
+			name:    "signed by another CA",
+			data:    withData("ca.crt", certutil.EncodeCertPEM(otherCACert)),
+			now:     time.Now(),
+			invalid: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			reason := validateServingCertificate(tc.data, tc.now)
+			if tc.invalid && reason == "" {
+				t.Error("expected the certificate to be invalid")
+			}
+			if !tc.invalid && reason != "" {
+				t.Errorf("expected the certificate to be valid, got %q", reason)
+			}
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/pkg/errors"
 
+	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
 	"github.com/kubermatic/kubeone/pkg/util"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -32,6 +33,13 @@ import (
 	apiregv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
 )
 
+const metricsServerImage = "k8s.gcr.io/metrics-server-amd64:v0.3.1"
+
+// Images returns the images used by metrics-server
+func Images() []string {
+	return []string{metricsServerImage}
+}
+
 // Deploy generate and POST all objects to apiserver
 func Deploy(ctx *util.Context) error {
 	if ctx.DynamicClient == nil {
@@ -44,7 +52,7 @@ func Deploy(ctx *util.Context) error {
 		metricsServerKubeSystemRoleBinding(),
 		metricsServerAPIService(),
 		metricsServerServiceAccount(),
-		metricsServerDeployment(),
+		metricsServerDeployment(ctx.Cluster),
 		metricsServerService(),
 		metricServerClusterRole(),
 		metricServerClusterRoleBinding(),
@@ -169,7 +177,7 @@ func metricsServerServiceAccount() *corev1.ServiceAccount {
 	}
 }
 
-func metricsServerDeployment() *appsv1.Deployment {
+func metricsServerDeployment(cluster *kubeoneapi.KubeOneCluster) *appsv1.Deployment {
 	k8sAppLabels := map[string]string{"k8s-app": "metrics-server"}
 
 	return &appsv1.Deployment{
@@ -204,7 +212,7 @@ func metricsServerDeployment() *appsv1.Deployment {
 					Containers: []corev1.Container{
 						{
 							Name:            "metrics-server",
-							Image:           "k8s.gcr.io/metrics-server-amd64:v0.3.1",
+							Image:           cluster.ImageFor(metricsServerImage),
 							ImagePullPolicy: corev1.PullIfNotPresent,
 							Args: []string{
 								"--kubelet-insecure-tls",
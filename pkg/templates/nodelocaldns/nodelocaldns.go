@@ -0,0 +1,358 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodelocaldns
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
+	"github.com/kubermatic/kubeone/pkg/util"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	nodeCacheImage   = "k8s.gcr.io/k8s-dns-node-cache:1.15.7"
+	nodeLocalDNSName = "node-local-dns"
+	upstreamSvcName  = "kube-dns-upstream"
+)
+
+// corefile is the Corefile of the upstream nodelocaldns addon. The
+// __PILLAR__CLUSTER__DNS__ and __PILLAR__UPSTREAM__SERVERS__ placeholders
+// are substituted by node-cache itself.
+const corefile = `__PILLAR__DNS__DOMAIN__:53 {
+    errors
+    cache {
+            success 9984 30
+            denial 9984 5
+    }
+    reload
+    loop
+    bind __PILLAR__LOCAL__DNS__ __PILLAR__DNS__SERVER__
+    forward . __PILLAR__CLUSTER__DNS__ {
+            force_tcp
+    }
+    prometheus :9253
+    health __PILLAR__LOCAL__DNS__:8080
+    }
+in-addr.arpa:53 {
+    errors
+    cache 30
+    reload
+    loop
+    bind __PILLAR__LOCAL__DNS__ __PILLAR__DNS__SERVER__
+    forward . __PILLAR__CLUSTER__DNS__ {
+            force_tcp
+    }
+    prometheus :9253
+    }
+ip6.arpa:53 {
+    errors
+    cache 30
+    reload
+    loop
+    bind __PILLAR__LOCAL__DNS__ __PILLAR__DNS__SERVER__
+    forward . __PILLAR__CLUSTER__DNS__ {
+            force_tcp
+    }
+    prometheus :9253
+    }
+.:53 {
+    errors
+    cache 30
+    reload
+    loop
+    bind __PILLAR__LOCAL__DNS__ __PILLAR__DNS__SERVER__
+    forward . __PILLAR__UPSTREAM__SERVERS__ {
+            force_tcp
+    }
+    prometheus :9253
+    }
+`
+
+// Images returns the images used by the NodeLocal DNSCache
+func Images() []string {
+	return []string{nodeCacheImage}
+}
+
+// Ensure deploys the NodeLocal DNSCache on every node of the cluster
+func Ensure(ctx *util.Context) error {
+	if ctx.Cluster.NodeLocalDNS == nil || !ctx.Cluster.NodeLocalDNS.Enabled {
+		return nil
+	}
+
+	if ctx.DynamicClient == nil {
+		return errors.New("kubernetes client not initialized")
+	}
+
+	ctx.Logger.Info("Ensure NodeLocal DNSCache is up to date")
+
+	bgCtx := context.Background()
+
+	// the cache also listens on the kube-dns service IP, so pods keep
+	// using the nameserver configured by kubelet
+	kubeDNS := &corev1.Service{}
+	key := types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: "kube-dns"}
+	if err := ctx.DynamicClient.Get(bgCtx, key, kubeDNS); err != nil {
+		return errors.Wrap(err, "failed to get kube-dns service")
+	}
+
+	localAddress := ctx.Cluster.NodeLocalDNS.LocalAddress
+	objs := []runtime.Object{
+		serviceAccount(),
+		upstreamService(),
+		configMap(ctx.Cluster, localAddress, kubeDNS.Spec.ClusterIP),
+		daemonSet(ctx.Cluster, localAddress, kubeDNS.Spec.ClusterIP),
+	}
+
+	for _, o := range objs {
+		if err := simpleCreateOrUpdate(bgCtx, ctx.DynamicClient, o); err != nil {
+			return errors.Wrapf(err, "failed to ensure NodeLocal DNSCache %T", o)
+		}
+	}
+
+	return nil
+}
+
+func serviceAccount() *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ServiceAccount",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nodeLocalDNSName,
+			Namespace: metav1.NamespaceSystem,
+		},
+	}
+}
+
+// upstreamService exposes CoreDNS under a second service IP, which the
+// cache forwards cluster queries to
+func upstreamService() *corev1.Service {
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Service",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      upstreamSvcName,
+			Namespace: metav1.NamespaceSystem,
+			Labels: map[string]string{
+				"k8s-app": "kube-dns",
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{
+				"k8s-app": "kube-dns",
+			},
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "dns",
+					Port:       53,
+					Protocol:   corev1.ProtocolUDP,
+					TargetPort: intstr.FromInt(53),
+				},
+				{
+					Name:       "dns-tcp",
+					Port:       53,
+					Protocol:   corev1.ProtocolTCP,
+					TargetPort: intstr.FromInt(53),
+				},
+			},
+		},
+	}
+}
+
+func configMap(cluster *kubeoneapi.KubeOneCluster, localAddress, dnsServer string) *corev1.ConfigMap {
+	r := strings.NewReplacer(
+		"__PILLAR__DNS__DOMAIN__", cluster.ClusterNetwork.ServiceDomainName,
+		"__PILLAR__LOCAL__DNS__", localAddress,
+		"__PILLAR__DNS__SERVER__", dnsServer,
+	)
+
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nodeLocalDNSName,
+			Namespace: metav1.NamespaceSystem,
+		},
+		Data: map[string]string{
+			"Corefile": r.Replace(corefile),
+		},
+	}
+}
+
+func daemonSet(cluster *kubeoneapi.KubeOneCluster, localAddress, dnsServer string) *appsv1.DaemonSet {
+	var (
+		privileged     = true
+		fileOrCreate   = corev1.HostPathFileOrCreate
+		maxUnavailable = intstr.FromString("10%")
+		gracePeriod    int64
+	)
+
+	labels := map[string]string{
+		"k8s-app": nodeLocalDNSName,
+	}
+
+	return &appsv1.DaemonSet{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apps/v1",
+			Kind:       "DaemonSet",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nodeLocalDNSName,
+			Namespace: metav1.NamespaceSystem,
+			Labels:    labels,
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			UpdateStrategy: appsv1.DaemonSetUpdateStrategy{
+				Type: appsv1.RollingUpdateDaemonSetStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateDaemonSet{
+					MaxUnavailable: &maxUnavailable,
+				},
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName:            nodeLocalDNSName,
+					PriorityClassName:             "system-node-critical",
+					HostNetwork:                   true,
+					DNSPolicy:                     corev1.DNSDefault,
+					TerminationGracePeriodSeconds: &gracePeriod,
+					Tolerations: []corev1.Toleration{
+						{
+							Key:      "CriticalAddonsOnly",
+							Operator: corev1.TolerationOpExists,
+						},
+						{
+							Operator: corev1.TolerationOpExists,
+							Effect:   corev1.TaintEffectNoExecute,
+						},
+						{
+							Operator: corev1.TolerationOpExists,
+							Effect:   corev1.TaintEffectNoSchedule,
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:  "node-cache",
+							Image: cluster.ImageFor(nodeCacheImage),
+							Args: []string{
+								"-localip", localAddress + "," + dnsServer,
+								"-conf", "/etc/Corefile",
+								"-upstreamsvc", upstreamSvcName,
+							},
+							SecurityContext: &corev1.SecurityContext{
+								Privileged: &privileged,
+							},
+							Ports: []corev1.ContainerPort{
+								{
+									Name:          "dns",
+									ContainerPort: 53,
+									Protocol:      corev1.ProtocolUDP,
+								},
+								{
+									Name:          "dns-tcp",
+									ContainerPort: 53,
+									Protocol:      corev1.ProtocolTCP,
+								},
+								{
+									Name:          "metrics",
+									ContainerPort: 9253,
+									Protocol:      corev1.ProtocolTCP,
+								},
+							},
+							LivenessProbe: &corev1.Probe{
+								Handler: corev1.Handler{
+									HTTPGet: &corev1.HTTPGetAction{
+										Host: localAddress,
+										Path: "/health",
+										Port: intstr.FromInt(8080),
+									},
+								},
+								InitialDelaySeconds: 60,
+								TimeoutSeconds:      5,
+							},
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("25m"),
+									corev1.ResourceMemory: resource.MustParse("5Mi"),
+								},
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "xtables-lock",
+									MountPath: "/run/xtables.lock",
+								},
+								{
+									Name:      "config-volume",
+									MountPath: "/etc/coredns",
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "xtables-lock",
+							VolumeSource: corev1.VolumeSource{
+								HostPath: &corev1.HostPathVolumeSource{
+									Path: "/run/xtables.lock",
+									Type: &fileOrCreate,
+								},
+							},
+						},
+						{
+							Name: "config-volume",
+							VolumeSource: corev1.VolumeSource{
+								ConfigMap: &corev1.ConfigMapVolumeSource{
+									LocalObjectReference: corev1.LocalObjectReference{
+										Name: nodeLocalDNSName,
+									},
+									// node-cache renders /etc/Corefile from this base
+									Items: []corev1.KeyToPath{
+										{
+											Key:  "Corefile",
+											Path: "Corefile.base",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package velero
+
+import (
+	"strings"
+
+	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
+
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	veleroGroup      = "velero.io"
+	veleroAPIVersion = veleroGroup + "/v1"
+)
+
+// crds returns the CustomResourceDefinitions of the Velero API
+func crds() []*apiextensions.CustomResourceDefinition {
+	kinds := []string{
+		"Backup",
+		"BackupStorageLocation",
+		"DeleteBackupRequest",
+		"DownloadRequest",
+		"PodVolumeBackup",
+		"PodVolumeRestore",
+		"ResticRepository",
+		"Restore",
+		"Schedule",
+		"ServerStatusRequest",
+		"VolumeSnapshotLocation",
+	}
+
+	out := make([]*apiextensions.CustomResourceDefinition, 0, len(kinds))
+	for _, kind := range kinds {
+		out = append(out, crd(kind))
+	}
+	return out
+}
+
+func crd(kind string) *apiextensions.CustomResourceDefinition {
+	singular := strings.ToLower(kind)
+	plural := singular + "s"
+	if strings.HasSuffix(singular, "y") {
+		plural = strings.TrimSuffix(singular, "y") + "ies"
+	}
+
+	return &apiextensions.CustomResourceDefinition{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apiextensions.k8s.io/v1beta1",
+			Kind:       "CustomResourceDefinition",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: plural + "." + veleroGroup,
+			Labels: map[string]string{
+				"component": veleroName,
+			},
+		},
+		Spec: apiextensions.CustomResourceDefinitionSpec{
+			Scope: apiextensions.NamespaceScoped,
+			Group: veleroGroup,
+			Versions: []apiextensions.CustomResourceDefinitionVersion{
+				{
+					Name:    "v1",
+					Served:  true,
+					Storage: true,
+				},
+			},
+			Names: apiextensions.CustomResourceDefinitionNames{
+				Kind:     kind,
+				Plural:   plural,
+				Singular: singular,
+			},
+		},
+	}
+}
+
+// backupStorageLocation configures the bucket backups are stored in
+func backupStorageLocation(v *kubeoneapi.VeleroConfig, p plugin, creds map[string]string) *unstructured.Unstructured {
+	config := map[string]interface{}{}
+	switch v.BackendType {
+	case kubeoneapi.VeleroBackendS3:
+		config["region"] = v.Region
+	case kubeoneapi.VeleroBackendAzure:
+		config["resourceGroup"] = creds["AZURE_RESOURCE_GROUP"]
+		config["storageAccount"] = creds["AZURE_STORAGE_ACCOUNT_ID"]
+	}
+
+	return veleroObject("BackupStorageLocation", "default", map[string]interface{}{
+		"provider": p.provider,
+		"objectStorage": map[string]interface{}{
+			"bucket": v.BucketName,
+		},
+		"config": config,
+	})
+}
+
+// volumeSnapshotLocation configures where snapshots of the persistent
+// volumes are taken
+func volumeSnapshotLocation(v *kubeoneapi.VeleroConfig, p plugin) *unstructured.Unstructured {
+	config := map[string]interface{}{}
+	if v.BackendType == kubeoneapi.VeleroBackendS3 {
+		config["region"] = v.Region
+	}
+
+	return veleroObject("VolumeSnapshotLocation", "default", map[string]interface{}{
+		"provider": p.provider,
+		"config":   config,
+	})
+}
+
+// dailyBackupSchedule backs up the whole cluster every night
+func dailyBackupSchedule() *unstructured.Unstructured {
+	return veleroObject("Schedule", "daily", map[string]interface{}{
+		"schedule": dailySchedule,
+		"template": map[string]interface{}{
+			"ttl": dailyTTL,
+		},
+	})
+}
+
+func veleroObject(kind, name string, spec map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": veleroAPIVersion,
+			"kind":       kind,
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": Namespace,
+			},
+			"spec": spec,
+		},
+	}
+}
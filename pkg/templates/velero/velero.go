@@ -0,0 +1,374 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package velero
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
+	"github.com/kubermatic/kubeone/pkg/util"
+	"github.com/kubermatic/kubeone/pkg/util/credentials"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	veleroImage   = "velero/velero:"
+	veleroVersion = "v1.2.0"
+	veleroName    = "velero"
+
+	// Namespace Velero and its backup resources are deployed in
+	Namespace = "velero"
+
+	credentialsSecretName = "cloud-credentials"
+	credentialsSecretKey  = "cloud"
+	credentialsPath       = "/credentials"
+
+	// backups are taken every night and kept for 30 days
+	dailySchedule = "0 2 * * *"
+	dailyTTL      = "720h0m0s"
+)
+
+// plugin describes the Velero plugin of an object storage backend
+type plugin struct {
+	// provider is the name the plugin is registered with
+	provider string
+	image    string
+}
+
+var plugins = map[kubeoneapi.VeleroBackend]plugin{
+	kubeoneapi.VeleroBackendS3: {
+		provider: "aws",
+		image:    "velero/velero-plugin-for-aws:v1.0.0",
+	},
+	kubeoneapi.VeleroBackendGCS: {
+		provider: "gcp",
+		image:    "velero/velero-plugin-for-gcp:v1.0.0",
+	},
+	kubeoneapi.VeleroBackendAzure: {
+		provider: "azure",
+		image:    "velero/velero-plugin-for-microsoft-azure:v1.0.0",
+	},
+}
+
+// Ensure deploys Velero with the plugin of the configured backend, the
+// default backup storage location and a daily backup schedule
+func Ensure(ctx *util.Context) error {
+	v := ctx.Cluster.Velero
+	if v == nil || !v.Enabled {
+		return nil
+	}
+
+	if ctx.DynamicClient == nil {
+		return errors.New("kubernetes client not initialized")
+	}
+
+	p, ok := plugins[v.BackendType]
+	if !ok {
+		return errors.Errorf("velero backend %q not supported", v.BackendType)
+	}
+
+	creds, err := credentials.VeleroCredentials(v.BackendType)
+	if err != nil {
+		return errors.Wrap(err, "unable to fetch velero credentials")
+	}
+
+	ctx.Logger.Info("Ensure Velero is up to date")
+
+	bgCtx := context.Background()
+	for _, crd := range crds() {
+		if err = simpleCreateOrUpdate(bgCtx, ctx.DynamicClient, crd); err != nil {
+			return errors.Wrap(err, "failed to ensure velero CRDs")
+		}
+	}
+
+	// HACK: re-init dynamic client in order to re-init RestMapper, to drop caches
+	if err = util.HackIssue321InitDynamicClient(ctx); err != nil {
+		return errors.Wrap(err, "failed to re-init dynamic client")
+	}
+
+	objs := []runtime.Object{
+		namespace(),
+		serviceAccount(),
+		clusterRoleBinding(),
+		credentialsSecret(v.BackendType, creds),
+		deployment(ctx.Cluster, p),
+		backupStorageLocation(v, p, creds),
+		volumeSnapshotLocation(v, p),
+		dailyBackupSchedule(),
+	}
+
+	for _, o := range objs {
+		if err = simpleCreateOrUpdate(bgCtx, ctx.DynamicClient, o); err != nil {
+			return errors.Wrapf(err, "failed to ensure velero %T", o)
+		}
+	}
+
+	return nil
+}
+
+// Images returns the images used by Velero and the plugin of the configured backend
+func Images(cluster *kubeoneapi.KubeOneCluster) []string {
+	images := []string{veleroImage + version(cluster)}
+	if cluster.Velero == nil {
+		return images
+	}
+	if p, ok := plugins[cluster.Velero.BackendType]; ok {
+		images = append(images, p.image)
+	}
+	return images
+}
+
+// version returns the Velero version configured in the manifest, if any,
+// or the version tested with KubeOne
+func version(cluster *kubeoneapi.KubeOneCluster) string {
+	if cluster.Velero != nil && cluster.Velero.Version != "" {
+		return cluster.Velero.Version
+	}
+	return veleroVersion
+}
+
+// credentialsFile renders the credentials in the format the plugin of
+// the backend expects
+func credentialsFile(backend kubeoneapi.VeleroBackend, creds map[string]string) string {
+	switch backend {
+	case kubeoneapi.VeleroBackendS3:
+		return fmt.Sprintf("[default]\naws_access_key_id=%s\naws_secret_access_key=%s\n",
+			creds[credentials.AWSAccessKeyID], creds[credentials.AWSSecretAccessKey])
+	case kubeoneapi.VeleroBackendGCS:
+		return creds["GOOGLE_CREDENTIALS"]
+	default:
+		keys := make([]string, 0, len(creds)+1)
+		for k, v := range creds {
+			keys = append(keys, k+"="+v)
+		}
+		keys = append(keys, "AZURE_CLOUD_NAME=AzurePublicCloud")
+		sort.Strings(keys)
+		return strings.Join(keys, "\n") + "\n"
+	}
+}
+
+func namespace() *corev1.Namespace {
+	return &corev1.Namespace{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Namespace",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: Namespace,
+		},
+	}
+}
+
+func serviceAccount() *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ServiceAccount",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      veleroName,
+			Namespace: Namespace,
+		},
+	}
+}
+
+// clusterRoleBinding grants Velero access to all resources, as it backs
+// up and restores the whole cluster
+func clusterRoleBinding() *rbacv1.ClusterRoleBinding {
+	return &rbacv1.ClusterRoleBinding{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "rbac.authorization.k8s.io/v1",
+			Kind:       "ClusterRoleBinding",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: veleroName,
+		},
+		RoleRef: rbacv1.RoleRef{
+			Name:     "cluster-admin",
+			Kind:     "ClusterRole",
+			APIGroup: rbacv1.GroupName,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Name:      veleroName,
+				Namespace: Namespace,
+			},
+		},
+	}
+}
+
+func credentialsSecret(backend kubeoneapi.VeleroBackend, creds map[string]string) *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      credentialsSecretName,
+			Namespace: Namespace,
+		},
+		Type: corev1.SecretTypeOpaque,
+		StringData: map[string]string{
+			credentialsSecretKey: credentialsFile(backend, creds),
+		},
+	}
+}
+
+func deployment(cluster *kubeoneapi.KubeOneCluster, p plugin) *appsv1.Deployment {
+	var (
+		replicas  int32 = 1
+		revisions int32 = 2
+	)
+
+	labels := map[string]string{
+		"component": veleroName,
+	}
+	credentialsFilePath := credentialsPath + "/" + credentialsSecretKey
+
+	return &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      veleroName,
+			Namespace: Namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas:             &replicas,
+			RevisionHistoryLimit: &revisions,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: veleroName,
+					RestartPolicy:      corev1.RestartPolicyAlways,
+					// the plugin binary is copied to the shared plugins volume
+					InitContainers: []corev1.Container{
+						{
+							Name:  "velero-plugin-for-" + p.provider,
+							Image: cluster.ImageFor(p.image),
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "plugins",
+									MountPath: "/target",
+								},
+							},
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:    veleroName,
+							Image:   cluster.ImageFor(veleroImage + version(cluster)),
+							Command: []string{"/velero"},
+							Args:    []string{"server"},
+							Env: []corev1.EnvVar{
+								{
+									Name: "VELERO_NAMESPACE",
+									ValueFrom: &corev1.EnvVarSource{
+										FieldRef: &corev1.ObjectFieldSelector{
+											FieldPath: "metadata.namespace",
+										},
+									},
+								},
+								{
+									Name:  "VELERO_SCRATCH_DIR",
+									Value: "/scratch",
+								},
+								{
+									Name:  "AWS_SHARED_CREDENTIALS_FILE",
+									Value: credentialsFilePath,
+								},
+								{
+									Name:  "GOOGLE_APPLICATION_CREDENTIALS",
+									Value: credentialsFilePath,
+								},
+								{
+									Name:  "AZURE_CREDENTIALS_FILE",
+									Value: credentialsFilePath,
+								},
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "plugins",
+									MountPath: "/plugins",
+								},
+								{
+									Name:      "scratch",
+									MountPath: "/scratch",
+								},
+								{
+									Name:      credentialsSecretName,
+									MountPath: credentialsPath,
+								},
+							},
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("500m"),
+									corev1.ResourceMemory: resource.MustParse("128Mi"),
+								},
+								Limits: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("1"),
+									corev1.ResourceMemory: resource.MustParse("256Mi"),
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "plugins",
+							VolumeSource: corev1.VolumeSource{
+								EmptyDir: &corev1.EmptyDirVolumeSource{},
+							},
+						},
+						{
+							Name: "scratch",
+							VolumeSource: corev1.VolumeSource{
+								EmptyDir: &corev1.EmptyDirVolumeSource{},
+							},
+						},
+						{
+							Name: credentialsSecretName,
+							VolumeSource: corev1.VolumeSource{
+								Secret: &corev1.SecretVolumeSource{
+									SecretName: credentialsSecretName,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -24,6 +24,7 @@ import (
 
 	"github.com/pkg/errors"
 
+	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
 	"github.com/kubermatic/kubeone/pkg/util"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -101,7 +102,7 @@ func Deploy(ctx *util.Context) error {
 		peers = append(peers, h.PrivateAddress)
 	}
 
-	ds := daemonSet(ctx.Cluster.ClusterNetwork.CNI.Encrypted, strings.Join(peers, " "))
+	ds := daemonSet(ctx.Cluster, ctx.Cluster.ClusterNetwork.CNI.Encrypted, strings.Join(peers, " "))
 	if err := simpleCreateOrUpdate(bgCtx, ctx.DynamicClient, ds); err != nil {
 		return errors.Wrap(err, "failed to ensure weave DaemonSet")
 	}
@@ -109,6 +110,11 @@ func Deploy(ctx *util.Context) error {
 	return nil
 }
 
+// Images returns the images used by weave-net
+func Images() []string {
+	return []string{weaveKubeImage + version, weaveNPCImage + version}
+}
+
 func genPassword() (string, error) {
 	pi := make([]byte, 32)
 	_, err := rand.Reader.Read(pi)
@@ -287,7 +293,7 @@ func dsEnv(passwordRef bool, peers string) []corev1.EnvVar {
 	return env
 }
 
-func daemonSet(passwordRef bool, peers string) *appsv1.DaemonSet {
+func daemonSet(cluster *kubeoneapi.KubeOneCluster, passwordRef bool, peers string) *appsv1.DaemonSet {
 	var (
 		priviledged  = true
 		fileOrCreate = corev1.HostPathFileOrCreate
@@ -323,7 +329,7 @@ func daemonSet(passwordRef bool, peers string) *appsv1.DaemonSet {
 							Name:    "weave",
 							Command: []string{"/home/weave/launch.sh"},
 							Env:     dsEnv(passwordRef, peers),
-							Image:   weaveKubeImage + version,
+							Image:   cluster.ImageFor(weaveKubeImage + version),
 							ReadinessProbe: &corev1.Probe{
 								Handler: corev1.Handler{
 									HTTPGet: &corev1.HTTPGetAction{
@@ -385,7 +391,7 @@ func daemonSet(passwordRef bool, peers string) *appsv1.DaemonSet {
 									},
 								},
 							},
-							Image: weaveNPCImage + version,
+							Image: cluster.ImageFor(weaveNPCImage + version),
 							Resources: corev1.ResourceRequirements{
 								Requests: corev1.ResourceList{
 									corev1.ResourceCPU: resource.MustParse("10m"),
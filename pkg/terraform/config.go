@@ -18,6 +18,7 @@ package terraform
 
 import (
 	"encoding/json"
+	"sort"
 	"strconv"
 
 	"github.com/pkg/errors"
@@ -25,7 +26,8 @@ import (
 	kubeonev1alpha1 "github.com/kubermatic/kubeone/pkg/apis/kubeone/v1alpha1"
 )
 
-type controlPlane struct {
+// ControlPlane describes the control plane hosts in the terraform output format
+type ControlPlane struct {
 	ClusterName       string   `json:"cluster_name"`
 	CloudProvider     *string  `json:"cloud_provider"`
 	PublicAddress     []string `json:"public_address"`
@@ -91,15 +93,22 @@ type packetWorkerConfig struct {
 
 // Config represents configuration in the terraform output format
 type Config struct {
+	KubeOneSchemaVersion struct {
+		Value json.Number `json:"value"`
+	} `json:"kubeone_schema_version"`
+
 	KubeOneAPI struct {
 		Value struct {
 			Endpoint string `json:"endpoint"`
+			// APIServerLBAddress is the address of an internal load
+			// balancer, which is preferred over the endpoint if set
+			APIServerLBAddress string `json:"api_server_lb_address"`
 		} `json:"value"`
 	} `json:"kubeone_api"`
 
 	KubeOneHosts struct {
 		Value struct {
-			ControlPlane []controlPlane `json:"control_plane"`
+			ControlPlane []ControlPlane `json:"control_plane"`
 		} `json:"value"`
 	} `json:"kubeone_hosts"`
 
@@ -113,26 +122,75 @@ type cloudProviderFlags struct {
 	value interface{}
 }
 
-// NewConfigFromJSON creates a new config object from json
+// NewConfigFromJSON creates a new config object from json. Outputs using an
+// older schema version are migrated to the current one.
 func NewConfigFromJSON(j []byte) (c *Config, err error) {
 	c = &Config{}
-	return c, json.Unmarshal(j, c)
+	if err = json.Unmarshal(j, c); err != nil {
+		return nil, err
+	}
+
+	return c, c.migrate()
+}
+
+// Validate checks does the terraform output contain all information required
+// to build the cluster topology
+func (c *Config) Validate() error {
+	if len(c.KubeOneHosts.Value.ControlPlane) == 0 {
+		return errors.New("no control plane hosts are given")
+	}
+
+	cp := c.KubeOneHosts.Value.ControlPlane[0]
+	if len(cp.PublicAddress) == 0 && len(cp.PrivateAddress) == 0 {
+		return errors.New("no control plane addresses are given")
+	}
+
+	if len(c.KubeOneWorkers.Value) == 0 {
+		return errors.New("no worker sets are given")
+	}
+
+	return nil
+}
+
+// APIServer returns the kube-apiserver endpoint, if any
+func (c *Config) APIServer() string {
+	if c.KubeOneAPI.Value.APIServerLBAddress != "" {
+		return c.KubeOneAPI.Value.APIServerLBAddress
+	}
+
+	return c.KubeOneAPI.Value.Endpoint
+}
+
+// ControlPlane returns the control plane hosts description. Only call this
+// after validating the config to ensure control plane hosts exist.
+func (c *Config) ControlPlane() ControlPlane {
+	return c.KubeOneHosts.Value.ControlPlane[0]
+}
+
+// WorkerSets returns names of the workersets defined in the terraform output
+func (c *Config) WorkerSets() []string {
+	names := []string{}
+	for name := range c.KubeOneWorkers.Value {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
 // Apply adds the terraform configuration options to the given
 // cluster config.
 func (c *Config) Apply(cluster *kubeonev1alpha1.KubeOneCluster) error {
-	if c.KubeOneAPI.Value.Endpoint != "" {
+	if endpoint := c.APIServer(); endpoint != "" {
 		cluster.APIEndpoint = kubeonev1alpha1.APIEndpoint{
-			Host: c.KubeOneAPI.Value.Endpoint,
+			Host: endpoint,
 		}
 	}
 
-	if len(c.KubeOneHosts.Value.ControlPlane) == 0 {
-		return errors.New("no control plane hosts are given")
+	if err := c.Validate(); err != nil {
+		return err
 	}
 
-	cp := c.KubeOneHosts.Value.ControlPlane[0]
+	cp := c.ControlPlane()
 
 	if cp.CloudProvider != nil {
 		cluster.CloudProvider.Name = kubeonev1alpha1.CloudProviderName(*cp.CloudProvider)
@@ -194,7 +252,12 @@ func (c *Config) Apply(cluster *kubeonev1alpha1.KubeOneCluster) error {
 			existingWorkerSet = &cluster.Workers[len(cluster.Workers)-1]
 		}
 
-		switch cluster.CloudProvider.Name {
+		provider := cluster.CloudProvider.Name
+		if existingWorkerSet.Config.CloudProvider != "" {
+			provider = existingWorkerSet.Config.CloudProvider
+		}
+
+		switch provider {
 		case kubeonev1alpha1.CloudProviderNameAWS:
 			err = c.updateAWSWorkerset(existingWorkerSet, workersetValue[0])
 		case kubeonev1alpha1.CloudProviderNameGCE:
@@ -210,7 +273,7 @@ func (c *Config) Apply(cluster *kubeonev1alpha1.KubeOneCluster) error {
 		case kubeonev1alpha1.CloudProviderNamePacket:
 			err = c.updatePacketWorkerset(existingWorkerSet, workersetValue[0])
 		default:
-			return errors.Errorf("unknown provider %v", cluster.CloudProvider.Name)
+			return errors.Errorf("unknown provider %v", provider)
 		}
 
 		if err != nil {
@@ -0,0 +1,174 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package terraform
+
+import (
+	"testing"
+)
+
+func TestConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	testcases := []struct {
+		name          string
+		output        string
+		expectedError bool
+	}{
+		{
+			name: "valid output with public addresses",
+			output: `{
+				"kubeone_api": {"value": {"endpoint": "lb.example.com"}},
+				"kubeone_hosts": {"value": {"control_plane": [{"cluster_name": "test", "public_address": ["1.1.1.1"]}]}},
+				"kubeone_workers": {"value": {"test-pool1": [{"replicas": 1}]}}
+			}`,
+			expectedError: false,
+		},
+		{
+			name: "valid output with private addresses only",
+			output: `{
+				"kubeone_hosts": {"value": {"control_plane": [{"cluster_name": "test", "private_address": ["10.0.0.1"]}]}},
+				"kubeone_workers": {"value": {"test-pool1": [{"replicas": 1}]}}
+			}`,
+			expectedError: false,
+		},
+		{
+			name: "invalid output without workers",
+			output: `{
+				"kubeone_hosts": {"value": {"control_plane": [{"cluster_name": "test", "public_address": ["1.1.1.1"]}]}}
+			}`,
+			expectedError: true,
+		},
+		{
+			name:          "invalid output without control plane",
+			output:        `{"kubeone_api": {"value": {"endpoint": "lb.example.com"}}}`,
+			expectedError: true,
+		},
+		{
+			name: "invalid output without control plane addresses",
+			output: `{
+				"kubeone_hosts": {"value": {"control_plane": [{"cluster_name": "test"}]}}
+			}`,
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := NewConfigFromJSON([]byte(tc.output))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = c.Validate()
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("expected error = %v, but got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestConfigAPIServer(t *testing.T) {
+	testcases := []struct {
+		name             string
+		output           string
+		expectedEndpoint string
+	}{
+		{
+			name:             "public load balancer",
+			output:           `{"kubeone_api": {"value": {"endpoint": "lb.example.com"}}}`,
+			expectedEndpoint: "lb.example.com",
+		},
+		{
+			name:             "internal load balancer is preferred",
+			output:           `{"kubeone_api": {"value": {"endpoint": "lb.example.com", "api_server_lb_address": "internal-lb.example.com"}}}`,
+			expectedEndpoint: "internal-lb.example.com",
+		},
+		{
+			name:             "internal load balancer disabled",
+			output:           `{"kubeone_api": {"value": {"endpoint": "lb.example.com", "api_server_lb_address": ""}}}`,
+			expectedEndpoint: "lb.example.com",
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := NewConfigFromJSON([]byte(tc.output))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if endpoint := c.APIServer(); endpoint != tc.expectedEndpoint {
+				t.Fatalf("expected endpoint %q, but got %q", tc.expectedEndpoint, endpoint)
+			}
+		})
+	}
+}
+
+func TestConfigSchemaMigration(t *testing.T) {
+	testcases := []struct {
+		name            string
+		output          string
+		expectedSSHPort string
+		expectedError   bool
+	}{
+		{
+			name:            "legacy output without schema version",
+			output:          `{"kubeone_hosts": {"value": {"control_plane": [{"public_address": ["1.1.1.1"]}]}}}`,
+			expectedSSHPort: "22",
+		},
+		{
+			name: "current schema version",
+			output: `{
+				"kubeone_schema_version": {"value": "2"},
+				"kubeone_hosts": {"value": {"control_plane": [{"public_address": ["1.1.1.1"], "ssh_port": "2222"}]}}
+			}`,
+			expectedSSHPort: "2222",
+		},
+		{
+			name:          "newer schema version",
+			output:        `{"kubeone_schema_version": {"value": 3}}`,
+			expectedError: true,
+		},
+		{
+			name:          "invalid schema version",
+			output:        `{"kubeone_schema_version": {"value": "v2"}}`,
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := NewConfigFromJSON([]byte(tc.output))
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("expected error = %v, but got %v", tc.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+
+			if version, _ := c.Version(); version != SchemaVersion {
+				t.Fatalf("expected schema version %d, but got %d", SchemaVersion, version)
+			}
+			if port := c.ControlPlane().SSHPort; port != tc.expectedSSHPort {
+				t.Fatalf("expected ssh port %q, but got %q", tc.expectedSSHPort, port)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package terraform
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Output returns the value of a single output of the terraform state in the
+// given directory, without parsing the whole JSON output. terraform prints
+// scalar values as is, so only string, number and bool outputs are supported.
+// The args, e.g. -state, are passed to terraform output before the key.
+func Output(dir, key string, args ...string) (string, error) {
+	cmdArgs := append([]string{"output"}, args...)
+	cmd := exec.Command("terraform", append(cmdArgs, key)...)
+	cmd.Dir = dir
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read terraform output %q: %s", key, strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
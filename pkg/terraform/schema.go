@@ -0,0 +1,94 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package terraform
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// SchemaVersion is the version of the terraform output format
+	// understood by KubeOne
+	SchemaVersion = 2
+
+	// legacySchemaVersion is assumed for outputs not setting
+	// kubeone_schema_version
+	legacySchemaVersion = 1
+
+	defaultSSHPort = "22"
+)
+
+// migrations upgrade the terraform output from the schema version used as
+// the key to the next one
+var migrations = map[int]func(c *Config){
+	1: migrateV1ToV2,
+}
+
+// Version returns the schema version of the terraform output
+func (c *Config) Version() (int, error) {
+	v := c.KubeOneSchemaVersion.Value.String()
+	if v == "" {
+		return legacySchemaVersion, nil
+	}
+
+	version, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid kubeone_schema_version %q", v)
+	}
+
+	return version, nil
+}
+
+// migrate upgrades the terraform output to the current schema version
+func (c *Config) migrate() error {
+	version, err := c.Version()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case version > SchemaVersion:
+		return errors.Errorf("terraform output schema version %d is newer than the supported version %d, please upgrade KubeOne", version, SchemaVersion)
+	case version < legacySchemaVersion:
+		return errors.Errorf("invalid terraform output schema version %d", version)
+	case version < SchemaVersion:
+		logrus.Warnf("The terraform output uses the schema version %d, while the current version is %d. "+
+			"Please update the output.tf of your terraform configuration, see the examples shipped with KubeOne.", version, SchemaVersion)
+	}
+
+	for ; version < SchemaVersion; version++ {
+		migrations[version](c)
+	}
+	c.KubeOneSchemaVersion.Value = json.Number(strconv.Itoa(SchemaVersion))
+
+	return nil
+}
+
+// migrateV1ToV2 sets defaults for the fields which are required as of the
+// schema version 2
+func migrateV1ToV2(c *Config) {
+	for i := range c.KubeOneHosts.Value.ControlPlane {
+		cp := &c.KubeOneHosts.Value.ControlPlane[i]
+		if cp.SSHPort == "" {
+			cp.SSHPort = defaultSSHPort
+		}
+	}
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package upgrade
 
 import (
+	"fmt"
+
 	"github.com/pkg/errors"
 
 	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
@@ -73,6 +75,22 @@ sudo mkdir -p /etc/systemd/system/kubelet.service.d
 curl -sSL "https://raw.githubusercontent.com/kubernetes/kubernetes/${RELEASE}/build/debs/10-kubeadm.conf" | \
      sed "s:/usr/bin:/opt/bin:g" | \
      sudo tee /etc/systemd/system/kubelet.service.d/10-kubeadm.conf
+`
+	// the kubelet unit and drop-in written during the installation refer
+	// to /opt/bin, so only the binaries are replaced
+	upgradeKubeBinariesFlatcarCommand = `
+source /etc/kubeone/proxy-env
+
+sudo mkdir -p /opt/cni/bin
+curl -fsSL "https://github.com/containernetworking/plugins/releases/download/{{ .CNI_VERSION }}/cni-plugins-amd64-{{ .CNI_VERSION }}.tgz" | \
+     sudo tar -C /opt/cni/bin -xz
+
+RELEASE="v{{ .KUBERNETES_VERSION }}"
+
+cd /opt/bin
+sudo curl -fsSL --remote-name-all \
+     https://storage.googleapis.com/kubernetes-release/release/${RELEASE}/bin/linux/amd64/{kubeadm,kubelet,kubectl}
+sudo chmod +x {kubeadm,kubelet,kubectl}
 `
 )
 
@@ -80,13 +98,16 @@ func upgradeKubernetesBinaries(ctx *util.Context, node kubeoneapi.HostConfig) er
 	var err error
 
 	switch node.OperatingSystem {
-	case "ubuntu", "debian":
+	case kubeoneapi.OperatingSystemNameUbuntu, kubeoneapi.OperatingSystemNameDebian:
 		err = upgradeKubernetesBinariesDebian(ctx)
 
-	case "coreos":
+	case kubeoneapi.OperatingSystemNameCoreOS:
 		err = upgradeKubernetesBinariesCoreOS(ctx)
 
-	case "centos":
+	case kubeoneapi.OperatingSystemNameFlatcar:
+		err = upgradeKubernetesBinariesFlatcar(ctx)
+
+	case kubeoneapi.OperatingSystemNameCentOS, kubeoneapi.OperatingSystemNameAmazonLinux:
 		err = upgradeKubernetesBinariesCentOS(ctx)
 
 	default:
@@ -122,3 +143,12 @@ func upgradeKubernetesBinariesCoreOS(ctx *util.Context) error {
 
 	return errors.WithStack(err)
 }
+
+func upgradeKubernetesBinariesFlatcar(ctx *util.Context) error {
+	_, _, err := ctx.Runner.Run(upgradeKubeBinariesFlatcarCommand, util.TemplateVariables{
+		"KUBERNETES_VERSION": ctx.Cluster.Versions.Kubernetes,
+		"CNI_VERSION":        fmt.Sprintf("v%s", ctx.Cluster.Versions.KubernetesCNIVersion()),
+	})
+
+	return errors.WithStack(err)
+}
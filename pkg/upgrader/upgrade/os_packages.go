@@ -0,0 +1,76 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
+	"github.com/kubermatic/kubeone/pkg/util"
+)
+
+// the Kubernetes packages are held on Debian and excluded from the
+// repository on CentOS, so they are left to upgradeKubernetesBinaries
+const (
+	upgradeOSPackagesDebianCommand = `
+source /etc/kubeone/proxy-env
+
+sudo apt-get update
+{{ if .PACKAGES -}}
+sudo DEBIAN_FRONTEND=noninteractive apt-get install -y --only-upgrade \
+     -o Dpkg::Options::="--force-confold" {{ .PACKAGES }}
+{{- else -}}
+sudo DEBIAN_FRONTEND=noninteractive apt-get upgrade -y \
+     -o Dpkg::Options::="--force-confold"
+{{- end }}
+`
+	upgradeOSPackagesCentOSCommand = `
+source /etc/kubeone/proxy-env
+
+sudo yum update -y {{ .PACKAGES }}
+`
+)
+
+// upgradeOSPackages upgrades the operating system packages of the node,
+// limited to the packages configured in the manifest if there are any
+func upgradeOSPackages(ctx *util.Context, node kubeoneapi.HostConfig) error {
+	var cmd string
+
+	switch node.OperatingSystem {
+	case kubeoneapi.OperatingSystemNameUbuntu, kubeoneapi.OperatingSystemNameDebian:
+		cmd = upgradeOSPackagesDebianCommand
+
+	case kubeoneapi.OperatingSystemNameCentOS, kubeoneapi.OperatingSystemNameAmazonLinux:
+		cmd = upgradeOSPackagesCentOSCommand
+
+	case kubeoneapi.OperatingSystemNameCoreOS, kubeoneapi.OperatingSystemNameFlatcar:
+		// the operating system is updated as a whole by its update engine
+		ctx.Logger.Warnf("Upgrading packages is not supported on '%s', skipping", node.OperatingSystem)
+		return nil
+
+	default:
+		return errors.Errorf("'%s' is not a supported operating system", node.OperatingSystem)
+	}
+
+	_, _, err := ctx.Runner.Run(cmd, util.TemplateVariables{
+		"PACKAGES": strings.Join(ctx.Cluster.OSUpgrade.Packages, " "),
+	})
+
+	return errors.WithStack(err)
+}
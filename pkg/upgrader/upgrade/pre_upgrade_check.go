@@ -0,0 +1,175 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"github.com/pkg/errors"
+
+	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
+	"github.com/kubermatic/kubeone/pkg/ssh"
+	"github.com/kubermatic/kubeone/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// preUpgradeCheck verifies the cluster is healthy before it's upgraded. All
+// checks are run and reported, the upgrade fails if any of them fails.
+func preUpgradeCheck(ctx *util.Context) error {
+	if ctx.DynamicClient == nil {
+		return errors.New("kubernetes dynamic client is not initialized")
+	}
+
+	checks := []struct {
+		name string
+		fn   func(*util.Context) error
+	}{
+		{name: "all nodes are ready", fn: verifyAllNodesReady},
+		{name: "etcd is healthy", fn: verifyEtcdHealth},
+		{name: "no PodDisruptionBudget is violated", fn: verifyPodDisruptionBudgets},
+		{name: "the upgrade doesn't skip a minor version", fn: verifyUpgradePath},
+	}
+
+	ctx.Logger.Infoln("Verifying the cluster is healthy…")
+
+	var failed []string
+	for _, c := range checks {
+		if err := c.fn(ctx); err != nil {
+			ctx.Logger.Errorf("Pre-upgrade check %q failed: %v", c.name, err)
+			failed = append(failed, c.name)
+		}
+	}
+
+	if len(failed) > 0 {
+		return errors.Errorf("unable to verify %s", strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+// verifyAllNodesReady ensures all nodes, including the workers, are ready
+func verifyAllNodesReady(ctx *util.Context) error {
+	nodes := corev1.NodeList{}
+	if err := ctx.DynamicClient.List(context.Background(), &dynclient.ListOptions{}, &nodes); err != nil {
+		return errors.Wrap(err, "unable to list nodes")
+	}
+
+	return verifyNodesRunning(&nodes, ctx.Verbose)
+}
+
+// verifyEtcdHealth checks the health of all etcd members using etcdctl in
+// the etcd pod of the leader
+func verifyEtcdHealth(ctx *util.Context) error {
+	endpoints := make([]string, 0, len(ctx.Cluster.Hosts))
+	for _, host := range ctx.Cluster.Hosts {
+		endpoints = append(endpoints, fmt.Sprintf("https://%s:2379", host.PrivateAddress))
+	}
+
+	return ctx.RunTaskOnLeader(func(ctx *util.Context, node *kubeoneapi.HostConfig, _ ssh.Connection) error {
+		_, _, err := ctx.Runner.Run(etcdHealthCommand, util.TemplateVariables{
+			"NODE_NAME": node.Hostname,
+			"ENDPOINTS": strings.Join(endpoints, ","),
+		})
+		return err
+	})
+}
+
+const etcdHealthCommand = `
+sudo kubectl --kubeconfig=/etc/kubernetes/admin.conf -n kube-system exec etcd-{{ .NODE_NAME }} -- \
+	sh -c "ETCDCTL_API=3 etcdctl \
+		--endpoints={{ .ENDPOINTS }} \
+		--cacert=/etc/kubernetes/pki/etcd/ca.crt \
+		--cert=/etc/kubernetes/pki/etcd/healthcheck-client.crt \
+		--key=/etc/kubernetes/pki/etcd/healthcheck-client.key \
+		endpoint health"
+`
+
+// verifyPodDisruptionBudgets ensures no PodDisruptionBudget has fewer
+// healthy pods than it requires, as draining nodes would be blocked
+func verifyPodDisruptionBudgets(ctx *util.Context) error {
+	pdbs := policyv1beta1.PodDisruptionBudgetList{}
+	if err := ctx.DynamicClient.List(context.Background(), &dynclient.ListOptions{}, &pdbs); err != nil {
+		return errors.Wrap(err, "unable to list PodDisruptionBudgets")
+	}
+
+	var violated []string
+	for _, pdb := range pdbs.Items {
+		if pdb.Status.CurrentHealthy < pdb.Status.DesiredHealthy {
+			violated = append(violated, fmt.Sprintf("%s/%s (%d/%d healthy)",
+				pdb.Namespace, pdb.Name, pdb.Status.CurrentHealthy, pdb.Status.DesiredHealthy))
+		}
+	}
+
+	if len(violated) > 0 {
+		return errors.Errorf("PodDisruptionBudgets violated: %s", strings.Join(violated, ", "))
+	}
+
+	return nil
+}
+
+// verifyUpgradePath ensures the requested version is at most one minor
+// version newer than the oldest kubelet of the control plane
+func verifyUpgradePath(ctx *util.Context) error {
+	reqVer, err := semver.NewVersion(ctx.Cluster.Versions.Kubernetes)
+	if err != nil {
+		return errors.Wrap(err, "provided version is invalid")
+	}
+
+	nodes := corev1.NodeList{}
+	nodeListOpts := dynclient.ListOptions{}
+	if err = nodeListOpts.SetLabelSelector(fmt.Sprintf("%s=%s", labelControlPlaneNode, "")); err != nil {
+		return errors.Wrap(err, "failed to set node selector labels")
+	}
+	if err = ctx.DynamicClient.List(context.Background(), &nodeListOpts, &nodes); err != nil {
+		return errors.Wrap(err, "unable to list nodes")
+	}
+
+	var current *semver.Version
+	for _, n := range nodes.Items {
+		ver, err := semver.NewVersion(n.Status.NodeInfo.KubeletVersion)
+		if err != nil {
+			return errors.Wrapf(err, "unable to parse kubelet version of node %s", n.Name)
+		}
+		if current == nil || ver.LessThan(current) {
+			current = ver
+		}
+	}
+	if current == nil {
+		return errors.New("no control plane nodes found")
+	}
+
+	return checkUpgradePath(current, reqVer)
+}
+
+// checkUpgradePath returns an error if upgrading from the current to the
+// requested version skips a minor version
+func checkUpgradePath(currVer, reqVer *semver.Version) error {
+	if reqVer.Major() != currVer.Major() {
+		return errors.Errorf("upgrading from %s to %s changes the major version", currVer, reqVer)
+	}
+	if reqVer.Minor() > currVer.Minor()+1 {
+		return errors.Errorf("upgrading from %s to %s skips a minor version, upgrade to %d.%d first",
+			currVer, reqVer, currVer.Major(), currVer.Minor()+1)
+	}
+	return nil
+}
@@ -40,7 +40,7 @@ func runPreflightChecks(ctx *util.Context) error {
 		return errors.New("kubernetes dynamic client is not initialized")
 	}
 
-	// Check are Docker, Kubelet and Kubeadm installed
+	// Check are the container runtime, Kubelet and Kubeadm installed
 	if err := checkPrerequisites(ctx); err != nil {
 		return errors.Wrap(err, "unable to check are prerequisites installed")
 	}
@@ -99,18 +99,20 @@ func runPreflightChecks(ctx *util.Context) error {
 	return nil
 }
 
-// checkPrerequisites checks are Docker, Kubelet, and Kubeadm installed on every machine in the cluster
+// checkPrerequisites checks are the container runtime, Kubelet, and Kubeadm installed on every machine in the cluster
 func checkPrerequisites(ctx *util.Context) error {
 	return ctx.RunTaskOnAllNodes(func(ctx *util.Context, _ *kubeoneapi.HostConfig, _ ssh.Connection) error {
 		ctx.Logger.Infoln("Checking are all prerequisites installed…")
-		_, _, err := ctx.Runner.Run(checkPrerequisitesCommand, util.TemplateVariables{})
+		_, _, err := ctx.Runner.Run(checkPrerequisitesCommand, util.TemplateVariables{
+			"CONTAINER_RUNTIME": ctx.Cluster.ContainerRuntime.Service(),
+		})
 		return err
 	}, true)
 }
 
 const checkPrerequisitesCommand = `
-# Check is Docker installed
-if ! type docker &>/dev/null; then exit 1; fi
+# Check is the container runtime installed
+if ! type {{ .CONTAINER_RUNTIME }} &>/dev/null; then exit 1; fi
 # Check is Kubelet installed
 if ! type kubelet &>/dev/null; then exit 1; fi
 # Check is Kubeadm installed
@@ -294,7 +296,8 @@ func verifyVersionSkew(ctx *util.Context, nodes *corev1.NodeList, verbose bool)
 }
 
 func parseContainerImageVersion(image string) (*semver.Version, error) {
-	ver := strings.Split(image, ":")
+	// the registry may contain a port, so only look for the tag after the last slash
+	ver := strings.Split(image[strings.LastIndex(image, "/")+1:], ":")
 	if len(ver) != 2 {
 		return nil, errors.Errorf("invalid container image format: %s", image)
 	}
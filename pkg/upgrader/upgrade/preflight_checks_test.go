@@ -56,6 +56,11 @@ func TestParseContainerImageVersionValid(t *testing.T) {
 			image:           "gcr.io/kubernetes:1.14",
 			expectedVersion: semver.MustParse("v1.14.0"),
 		},
+		{
+			name:            "local registry image with port",
+			image:           "registry.local:5000/kube-apiserver:v1.14.1",
+			expectedVersion: semver.MustParse("v1.14.1"),
+		},
 	}
 	for _, tc := range testcases {
 		tc := tc
@@ -94,6 +99,11 @@ func TestParseContainerImageVersionInvalid(t *testing.T) {
 			image:         "gcr.io/kubernetes/kube-apiserver",
 			expectedError: fmt.Errorf("invalid container image format: gcr.io/kubernetes/kube-apiserver"),
 		},
+		{
+			name:          "local registry image with port without version",
+			image:         "registry.local:5000/kube-apiserver",
+			expectedError: fmt.Errorf("invalid container image format: registry.local:5000/kube-apiserver"),
+		},
 	}
 	for _, tc := range testcases {
 		tc := tc
@@ -261,3 +271,46 @@ func TestCheckVersionSkewInvalid(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckUpgradePath(t *testing.T) {
+	t.Parallel()
+
+	testcases := []struct {
+		name           string
+		currentVersion *semver.Version
+		desiredVersion *semver.Version
+		expectedError  bool
+	}{
+		{
+			name:           "upgrade 1.13.3 to 1.13.5",
+			currentVersion: semver.MustParse("1.13.3"),
+			desiredVersion: semver.MustParse("1.13.5"),
+		},
+		{
+			name:           "upgrade 1.13.3 to 1.14.1",
+			currentVersion: semver.MustParse("v1.13.3"),
+			desiredVersion: semver.MustParse("v1.14.1"),
+		},
+		{
+			name:           "upgrade 1.13.3 to 1.15.0 skipping 1.14",
+			currentVersion: semver.MustParse("1.13.3"),
+			desiredVersion: semver.MustParse("1.15.0"),
+			expectedError:  true,
+		},
+		{
+			name:           "upgrade 1.14.1 to 2.0.0",
+			currentVersion: semver.MustParse("1.14.1"),
+			desiredVersion: semver.MustParse("2.0.0"),
+			expectedError:  true,
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkUpgradePath(tc.currentVersion, tc.desiredVersion)
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("expected error = %v, but got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
@@ -39,6 +39,12 @@ const (
 	// timeoutNodeUpgrade is time for how long kubeone will wait after finishing the upgrade
 	// process on the node
 	timeoutNodeUpgrade = 15 * time.Second
+	// timeoutNodeReady is time for how long kubeone will wait for the upgraded
+	// node to become ready
+	timeoutNodeReady = 5 * time.Minute
+	// timeoutClusterHealthy is time for how long kubeone will wait for the
+	// cluster to become healthy before upgrading the next control plane node
+	timeoutClusterHealthy = 5 * time.Minute
 )
 
 // Upgrade performs all the steps required to upgrade Kubernetes on
@@ -48,10 +54,10 @@ func Upgrade(ctx *util.Context) error {
 	commonSteps := []task.Task{
 		{Fn: util.BuildKubernetesClientset, ErrMsg: "unable to build kubernetes clientset"},
 		{Fn: determineHostname, ErrMsg: "unable to determine hostname"},
-		{Fn: determineOS, ErrMsg: "unable to determine operating system"},
+		{Fn: util.DetermineOS, ErrMsg: "unable to determine operating system"},
 		{Fn: runPreflightChecks, ErrMsg: "preflight checks failed"},
-		{Fn: upgradeLeader, ErrMsg: "unable to upgrade leader control plane", Retries: 3},
-		{Fn: upgradeFollower, ErrMsg: "unable to upgrade follower control plane", Retries: 3},
+		{Fn: preUpgradeCheck, ErrMsg: "pre-upgrade checks failed"},
+		{Fn: upgradeControlPlane, ErrMsg: "unable to upgrade control plane"},
 		{Fn: features.Activate, ErrMsg: "unable to activate features"},
 		{Fn: certificate.DownloadCA, ErrMsg: "unable to download ca from leader", Retries: 3},
 		{Fn: credentials.Ensure, ErrMsg: "unable to ensure credentials secret"},
@@ -61,11 +67,14 @@ func Upgrade(ctx *util.Context) error {
 		{Fn: upgradeMachineDeployments, ErrMsg: "unable to upgrade MachineDeployments", Retries: 3},
 	}
 
+	ctx.Events.RecordApplyStart("Upgrade")
 	for _, step := range commonSteps {
 		if err := step.Run(ctx); err != nil {
+			ctx.Events.RecordStepFailed(step.ErrMsg, err)
 			return errors.Wrap(err, step.ErrMsg)
 		}
 	}
+	ctx.Events.RecordApplyComplete("Upgrade")
 
 	return nil
 }
@@ -0,0 +1,91 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
+	"github.com/kubermatic/kubeone/pkg/task"
+	"github.com/kubermatic/kubeone/pkg/util"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// upgradeControlPlane upgrades the control plane nodes one at a time,
+// starting with the leader which runs 'kubeadm upgrade apply'. Only a single
+// etcd member is down at any time, so the quorum is maintained. The upgrade
+// stops as soon as the cluster doesn't become healthy after upgrading a node.
+func upgradeControlPlane(ctx *util.Context) error {
+	leader, err := ctx.Cluster.Leader()
+	if err != nil {
+		return err
+	}
+
+	type nodeUpgrade struct {
+		host     kubeoneapi.HostConfig
+		executor util.NodeTask
+	}
+
+	upgrades := []nodeUpgrade{{host: leader, executor: upgradeLeaderExecutor}}
+	for _, follower := range ctx.Cluster.Followers() {
+		upgrades = append(upgrades, nodeUpgrade{host: follower, executor: upgradeFollowerExecutor})
+	}
+
+	for _, u := range upgrades {
+		u := u
+		upgradeNode := task.Task{
+			Fn: func(ctx *util.Context) error {
+				return ctx.RunTaskOnNodes([]kubeoneapi.HostConfig{u.host}, u.executor, false)
+			},
+			Retries: 3,
+		}
+
+		if err := upgradeNode.Run(ctx); err != nil {
+			return errors.Wrapf(err, "unable to upgrade control plane node %s", u.host.PublicAddress)
+		}
+
+		ctx.Logger.Infoln("Verifying the cluster is healthy…")
+		if err := waitForClusterHealthy(ctx); err != nil {
+			return errors.Wrapf(err, "cluster is unhealthy after upgrading node %s, stopping the upgrade", u.host.PublicAddress)
+		}
+	}
+
+	return nil
+}
+
+// waitForClusterHealthy is the circuit breaker of the rolling upgrade, it
+// waits until all nodes are ready and all etcd members are healthy
+func waitForClusterHealthy(ctx *util.Context) error {
+	var lastErr error
+	err := wait.Poll(10*time.Second, timeoutClusterHealthy, func() (bool, error) {
+		if lastErr = verifyAllNodesReady(ctx); lastErr != nil {
+			return false, nil
+		}
+		if lastErr = verifyEtcdHealth(ctx); lastErr != nil {
+			return false, nil
+		}
+		return true, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return lastErr
+	}
+
+	return err
+}
@@ -26,17 +26,27 @@ import (
 	"github.com/kubermatic/kubeone/pkg/util"
 )
 
-func upgradeFollower(ctx *util.Context) error {
-	return ctx.RunTaskOnFollowers(upgradeFollowerExecutor, false)
-}
-
 func upgradeFollowerExecutor(ctx *util.Context, node *kubeoneapi.HostConfig, conn ssh.Connection) error {
 	logger := ctx.Logger.WithField("node", node.PublicAddress)
 
 	logger.Infoln("Labeling follower control plane…")
 	err := labelNode(ctx.DynamicClient, node)
 	if err != nil {
-		return errors.Wrap(err, "failed to label leader control plane node")
+		return errors.Wrap(err, "failed to label follower control plane node")
+	}
+
+	logger.Infoln("Draining follower control plane…")
+	err = drainNode(ctx, node)
+	if err != nil {
+		return errors.Wrap(err, "failed to drain follower control plane node")
+	}
+
+	if ctx.UpgradeOSPackages {
+		logger.Infoln("Upgrading operating system packages on follower control plane…")
+		err = upgradeOSPackages(ctx, *node)
+		if err != nil {
+			return errors.Wrap(err, "failed to upgrade operating system packages on follower control plane")
+		}
 	}
 
 	logger.Infoln("Upgrading Kubernetes binaries on follower control plane…")
@@ -57,6 +67,18 @@ func upgradeFollowerExecutor(ctx *util.Context, node *kubeoneapi.HostConfig, con
 	logger.Infof("Waiting %v seconds to ensure all components are up…", timeoutNodeUpgrade.String())
 	time.Sleep(timeoutNodeUpgrade)
 
+	logger.Infoln("Uncordoning follower control plane…")
+	err = uncordonNode(ctx, node)
+	if err != nil {
+		return errors.Wrap(err, "failed to uncordon follower control plane node")
+	}
+
+	logger.Infoln("Waiting for follower control plane to become ready…")
+	err = waitForNodeReady(ctx, node)
+	if err != nil {
+		return errors.Wrap(err, "follower control plane node didn't become ready")
+	}
+
 	logger.Infoln("Unlabeling follower control plane…")
 	err = unlabelNode(ctx.DynamicClient, node)
 	if err != nil {
@@ -26,10 +26,6 @@ import (
 	"github.com/kubermatic/kubeone/pkg/util"
 )
 
-func upgradeLeader(ctx *util.Context) error {
-	return ctx.RunTaskOnLeader(upgradeLeaderExecutor)
-}
-
 func upgradeLeaderExecutor(ctx *util.Context, node *kubeoneapi.HostConfig, conn ssh.Connection) error {
 	logger := ctx.Logger.WithField("node", node.PublicAddress)
 
@@ -38,6 +34,18 @@ func upgradeLeaderExecutor(ctx *util.Context, node *kubeoneapi.HostConfig, conn
 		return errors.Wrap(err, "failed to label leader control plane node")
 	}
 
+	logger.Infoln("Draining leader control plane…")
+	if err := drainNode(ctx, node); err != nil {
+		return errors.Wrap(err, "failed to drain leader control plane node")
+	}
+
+	if ctx.UpgradeOSPackages {
+		logger.Infoln("Upgrading operating system packages on leader control plane…")
+		if err := upgradeOSPackages(ctx, *node); err != nil {
+			return errors.Wrap(err, "failed to upgrade operating system packages on leader control plane")
+		}
+	}
+
 	logger.Infoln("Upgrading Kubernetes binaries on leader control plane…")
 	if err := upgradeKubernetesBinaries(ctx, *node); err != nil {
 		return errors.Wrap(err, "failed to upgrade kubernetes binaries on leader control plane")
@@ -64,6 +72,16 @@ func upgradeLeaderExecutor(ctx *util.Context, node *kubeoneapi.HostConfig, conn
 	logger.Infof("Waiting %v seconds to ensure all components are up…", timeoutNodeUpgrade.String())
 	time.Sleep(timeoutNodeUpgrade)
 
+	logger.Infoln("Uncordoning leader control plane…")
+	if err := uncordonNode(ctx, node); err != nil {
+		return errors.Wrap(err, "failed to uncordon leader control plane node")
+	}
+
+	logger.Infoln("Waiting for leader control plane to become ready…")
+	if err := waitForNodeReady(ctx, node); err != nil {
+		return errors.Wrap(err, "leader control plane node didn't become ready")
+	}
+
 	logger.Infoln("Unlabeling leader control plane…")
 	if err := unlabelNode(ctx.DynamicClient, node); err != nil {
 		return errors.Wrap(err, "failed to unlabel leader control plane node")
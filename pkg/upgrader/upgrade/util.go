@@ -18,6 +18,7 @@ package upgrade
 
 import (
 	"context"
+	"time"
 
 	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
 	"github.com/kubermatic/kubeone/pkg/ssh"
@@ -27,6 +28,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/retry"
 	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -45,19 +48,6 @@ func determineHostname(ctx *util.Context) error {
 	}, true)
 }
 
-func determineOS(ctx *util.Context) error {
-	ctx.Logger.Infoln("Determine operating system…")
-	return ctx.RunTaskOnAllNodes(func(ctx *util.Context, node *kubeoneapi.HostConfig, conn ssh.Connection) error {
-		osID, _, err := ctx.Runner.Run("source /etc/os-release && echo -n $ID", nil)
-		if err != nil {
-			return err
-		}
-
-		node.SetOperatingSystem(osID)
-		return nil
-	}, true)
-}
-
 func labelNode(client dynclient.Client, host *kubeoneapi.HostConfig) error {
 	retErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		node := corev1.Node{
@@ -95,3 +85,43 @@ func unlabelNode(client dynclient.Client, host *kubeoneapi.HostConfig) error {
 
 	return errors.Wrapf(retErr, "failed to remove label %s from node %s", labelUpgradeLock, host.Hostname)
 }
+
+// drainNode cordons the node and evicts its pods, so the workloads and the
+// leader leases of the control plane components move to the other nodes
+func drainNode(ctx *util.Context, host *kubeoneapi.HostConfig) error {
+	evictionClient, err := util.NewEvictionClient(ctx.RESTConfig)
+	if err != nil {
+		return errors.Wrap(err, "unable to build eviction client")
+	}
+
+	bgCtx := context.Background()
+	if err = util.SetNodeUnschedulable(bgCtx, ctx.DynamicClient, host.Hostname, true); err != nil {
+		return errors.Wrapf(err, "unable to cordon node %s", host.Hostname)
+	}
+
+	return errors.Wrapf(util.EvictNodePods(bgCtx, ctx.DynamicClient, evictionClient, host.Hostname),
+		"unable to evict pods from node %s", host.Hostname)
+}
+
+func uncordonNode(ctx *util.Context, host *kubeoneapi.HostConfig) error {
+	err := util.SetNodeUnschedulable(context.Background(), ctx.DynamicClient, host.Hostname, false)
+	return errors.Wrapf(err, "unable to uncordon node %s", host.Hostname)
+}
+
+// waitForNodeReady waits until the kubelet of the node reports it's ready
+func waitForNodeReady(ctx *util.Context, host *kubeoneapi.HostConfig) error {
+	return wait.Poll(5*time.Second, timeoutNodeReady, func() (bool, error) {
+		node := corev1.Node{}
+		// the API server of the node may be restarting, so errors are retried
+		if err := ctx.DynamicClient.Get(context.Background(), types.NamespacedName{Name: host.Hostname}, &node); err != nil {
+			return false, nil
+		}
+
+		for _, c := range node.Status.Conditions {
+			if c.Type == corev1.NodeReady {
+				return c.Status == corev1.ConditionTrue, nil
+			}
+		}
+		return false, nil
+	})
+}
@@ -23,13 +23,17 @@ import (
 	"github.com/kubermatic/kubeone/pkg/ssh"
 	"github.com/kubermatic/kubeone/pkg/upgrader/upgrade"
 	"github.com/kubermatic/kubeone/pkg/util"
+	"github.com/kubermatic/kubeone/pkg/util/credentials"
 )
 
 // Options groups the various possible options for running KubeOne upgrade
 type Options struct {
 	ForceUpgrade              bool
 	UpgradeMachineDeployments bool
+	UpgradeOSPackages         bool
 	Verbose                   bool
+	Debug                     bool
+	InCluster                 bool
 }
 
 // Upgrader is entrypoint for the upgrade process
@@ -54,14 +58,21 @@ func (u *Upgrader) Upgrade(options *Options) error {
 // createContext creates a basic, non-host bound context with all relevant information, but no Runner yet.
 // The various task helper functions will take care of setting up Runner structs for each task individually
 func (u *Upgrader) createContext(options *Options) *util.Context {
-	return &util.Context{
+	ctx := &util.Context{
 		Cluster:                   u.cluster,
 		Connector:                 ssh.NewConnector(),
 		Configuration:             util.NewConfiguration(),
 		WorkDir:                   "kubeone",
 		Logger:                    u.logger,
 		Verbose:                   options.Verbose,
+		Debug:                     options.Debug,
 		ForceUpgrade:              options.ForceUpgrade,
 		UpgradeMachineDeployments: options.UpgradeMachineDeployments,
+		UpgradeOSPackages:         options.UpgradeOSPackages,
+		InCluster:                 options.InCluster,
+		Redactor:                  util.NewRedactor(credentials.SensitiveValues(u.cluster)...),
 	}
+	ctx.Events = util.NewClusterEventRecorder(ctx)
+
+	return ctx
 }
@@ -17,7 +17,12 @@ limitations under the License.
 package config
 
 import (
+	"bytes"
 	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 
 	"github.com/pkg/errors"
 
@@ -36,6 +41,23 @@ func SetKubeOneClusterDynamicDefaults(cfg *kubeoneapi.KubeOneCluster) error {
 	if err := SetKubeOneClusterCredentials(cfg); err != nil {
 		return errors.Wrap(err, "unable to set dynamic defaults for a given KubeOneCluster object")
 	}
+	if err := checkVeleroCredentials(cfg); err != nil {
+		return errors.Wrap(err, "unable to set dynamic defaults for a given KubeOneCluster object")
+	}
+	return nil
+}
+
+// checkVeleroCredentials makes sure the credentials of the Velero backend
+// are available, so Velero doesn't fail after the cluster is provisioned
+func checkVeleroCredentials(cfg *kubeoneapi.KubeOneCluster) error {
+	if cfg.Velero == nil || !cfg.Velero.Enabled {
+		return nil
+	}
+
+	if _, err := credentials.VeleroCredentials(cfg.Velero.BackendType); err != nil {
+		return errors.Wrap(err, "unable to fetch velero credentials")
+	}
+
 	return nil
 }
 
@@ -46,7 +68,12 @@ func SetKubeOneClusterCredentials(cfg *kubeoneapi.KubeOneCluster) error {
 		return nil
 	}
 
-	creds, err := credentials.ProviderCredentials(cfg.CloudProvider.Name)
+	// machine-controller loads its credentials from the referenced secret
+	if cfg.MachineController != nil && cfg.MachineController.CredentialsSecretRef != nil && !cfg.CloudProvider.External {
+		return nil
+	}
+
+	creds, err := credentials.ClusterCredentials(cfg)
 	if err != nil {
 		return errors.Wrap(err, "unable to fetch cloud provider credentials")
 	}
@@ -56,22 +83,18 @@ func SetKubeOneClusterCredentials(cfg *kubeoneapi.KubeOneCluster) error {
 }
 
 // SourceKubeOneClusterFromTerraformOutput sources information about the cluster from the Terraform output
-func SourceKubeOneClusterFromTerraformOutput(terraformOutput []byte, cluster *kubeonev1alpha1.KubeOneCluster) error {
-	tfConfig, err := terraform.NewConfigFromJSON(terraformOutput)
-	if err != nil {
-		return errors.Wrap(err, "failed to parse Terraform config")
-	}
+func SourceKubeOneClusterFromTerraformOutput(tfConfig *terraform.Config, cluster *kubeonev1alpha1.KubeOneCluster) error {
 	return tfConfig.Apply(cluster)
 }
 
 // DefaultedKubeOneCluster converts a versioned KubeOneCluster object to an internal representation of KubeOneCluster
 // object while sourcing information from Terraform output, applying default values and validating the KubeOneCluster
 // object
-func DefaultedKubeOneCluster(versionedCluster *kubeonev1alpha1.KubeOneCluster, tfOutput []byte) (*kubeoneapi.KubeOneCluster, error) {
+func DefaultedKubeOneCluster(versionedCluster *kubeonev1alpha1.KubeOneCluster, tfConfig *terraform.Config) (*kubeoneapi.KubeOneCluster, error) {
 	internalCfg := &kubeoneapi.KubeOneCluster{}
 
-	if tfOutput != nil {
-		if err := SourceKubeOneClusterFromTerraformOutput(tfOutput, versionedCluster); err != nil {
+	if tfConfig != nil {
+		if err := SourceKubeOneClusterFromTerraformOutput(tfConfig, versionedCluster); err != nil {
 			return nil, errors.Wrap(err, "unable to source information about cluster from a given terraform output")
 		}
 	}
@@ -102,9 +125,9 @@ func LoadKubeOneCluster(clusterCfgPath, tfOutputPath string) (*kubeoneapi.KubeOn
 		return nil, errors.New("cluster configuration path not provided")
 	}
 
-	cluster, err := ioutil.ReadFile(clusterCfgPath)
+	cluster, err := readManifest(clusterCfgPath)
 	if err != nil {
-		return nil, errors.Wrap(err, "unable to read the given cluster configuration file")
+		return nil, err
 	}
 
 	var tfOutput []byte
@@ -118,6 +141,92 @@ func LoadKubeOneCluster(clusterCfgPath, tfOutputPath string) (*kubeoneapi.KubeOn
 	return BytesToKubeOneCluster(cluster, tfOutput)
 }
 
+// LoadKubeOneClusterOverlays returns the KubeOneCluster object parsed from the KubeOneCluster configuration
+// files merged using MergeManifests, the later files overriding the earlier ones, and optionally Terraform output
+func LoadKubeOneClusterOverlays(clusterCfgPaths []string, tfOutputPath string) (*kubeoneapi.KubeOneCluster, error) {
+	if len(clusterCfgPaths) == 1 {
+		return LoadKubeOneCluster(clusterCfgPaths[0], tfOutputPath)
+	}
+	if len(clusterCfgPaths) == 0 {
+		return nil, errors.New("cluster configuration path not provided")
+	}
+
+	manifests := make([][]byte, 0, len(clusterCfgPaths))
+	for _, path := range clusterCfgPaths {
+		manifest, err := readManifest(path)
+		if err != nil {
+			return nil, errors.Wrap(err, path)
+		}
+		manifests = append(manifests, manifest)
+	}
+
+	cluster, err := MergeManifests(manifests...)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to merge the given cluster configuration files")
+	}
+
+	var tfOutput []byte
+	if len(tfOutputPath) > 0 {
+		tfOutput, err = ioutil.ReadFile(tfOutputPath)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to read the given terraform output file")
+		}
+	}
+
+	return BytesToKubeOneCluster(cluster, tfOutput)
+}
+
+// kustomizationFileNames are the file names kustomize recognizes as a kustomization
+var kustomizationFileNames = []string{"kustomization.yaml", "kustomization.yml", "Kustomization"}
+
+// readManifest reads the cluster configuration file. Kustomize directories are
+// built using kustomize, or kubectl if kustomize isn't installed.
+func readManifest(path string) ([]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read the given cluster configuration file")
+	}
+
+	if info.IsDir() {
+		for _, name := range kustomizationFileNames {
+			if _, err := os.Stat(filepath.Join(path, name)); err == nil {
+				return buildKustomization(path)
+			}
+		}
+		return nil, errors.Errorf("the given cluster configuration path %s is a directory", path)
+	}
+
+	cluster, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read the given cluster configuration file")
+	}
+
+	return cluster, nil
+}
+
+// buildKustomization returns the output of kustomize build for the given
+// kustomize directory
+func buildKustomization(path string) ([]byte, error) {
+	var cmd *exec.Cmd
+	if _, err := exec.LookPath("kustomize"); err == nil {
+		cmd = exec.Command("kustomize", "build", path)
+	} else if _, err := exec.LookPath("kubectl"); err == nil {
+		cmd = exec.Command("kubectl", "kustomize", path)
+	} else {
+		return nil, errors.Errorf("neither kustomize nor kubectl is installed, unable to build the kustomize directory %s", path)
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to build the kustomize directory %s: %s", path, strings.TrimSpace(stderr.String()))
+	}
+
+	return out, nil
+}
+
 // BytesToKubeOneCluster returns the KubeOneCluster object parsed from the KubeOneCluster manifest and optionally
 // Terraform output
 func BytesToKubeOneCluster(cluster, tfOutput []byte) (*kubeoneapi.KubeOneCluster, error) {
@@ -126,5 +235,14 @@ func BytesToKubeOneCluster(cluster, tfOutput []byte) (*kubeoneapi.KubeOneCluster
 		return nil, err
 	}
 
-	return DefaultedKubeOneCluster(initCfg, tfOutput)
+	var tfConfig *terraform.Config
+	if tfOutput != nil {
+		var err error
+		tfConfig, err = terraform.NewConfigFromJSON(tfOutput)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse Terraform config")
+		}
+	}
+
+	return DefaultedKubeOneCluster(initCfg, tfConfig)
 }
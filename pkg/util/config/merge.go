@@ -0,0 +1,128 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"encoding/json"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+// mergeKey identifies the items of lists merged item by item, e.g. the workers
+const mergeKey = "name"
+
+// MergeManifests merges the manifests, the later ones overriding the earlier
+// ones, and returns the result as JSON. The semantics follow the strategic
+// merge patch:
+//   - objects are merged recursively
+//   - a null value removes the key
+//   - lists of objects having a name are merged by name, e.g. an overlay
+//     changing the replicas of a single workerset
+//   - other lists and values are replaced
+func MergeManifests(manifests ...[]byte) ([]byte, error) {
+	var merged interface{}
+
+	for i, manifest := range manifests {
+		jsonManifest, err := yaml.YAMLToJSON(manifest)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to parse manifest %d", i+1)
+		}
+
+		var doc interface{}
+		if err := json.Unmarshal(jsonManifest, &doc); err != nil {
+			return nil, errors.Wrapf(err, "unable to parse manifest %d", i+1)
+		}
+
+		if i == 0 {
+			merged = doc
+			continue
+		}
+		merged = mergeValues(merged, doc)
+	}
+
+	return json.Marshal(merged)
+}
+
+func mergeValues(base, overlay interface{}) interface{} {
+	switch o := overlay.(type) {
+	case map[string]interface{}:
+		b, ok := base.(map[string]interface{})
+		if !ok {
+			return removeNulls(o)
+		}
+		for k, v := range o {
+			if v == nil {
+				delete(b, k)
+				continue
+			}
+			b[k] = mergeValues(b[k], v)
+		}
+		return b
+
+	case []interface{}:
+		b, ok := base.([]interface{})
+		if !ok || !namedItems(b) || !namedItems(o) {
+			return o
+		}
+		for _, item := range o {
+			name := item.(map[string]interface{})[mergeKey]
+			found := false
+			for i := range b {
+				if b[i].(map[string]interface{})[mergeKey] == name {
+					b[i] = mergeValues(b[i], item)
+					found = true
+					break
+				}
+			}
+			if !found {
+				b = append(b, item)
+			}
+		}
+		return b
+	}
+
+	return overlay
+}
+
+// namedItems returns whether all items of the list are objects with a name
+func namedItems(list []interface{}) bool {
+	for _, item := range list {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if _, ok := m[mergeKey].(string); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// removeNulls removes the null values of an object added by an overlay, so
+// they are treated the same as for merged objects
+func removeNulls(m map[string]interface{}) map[string]interface{} {
+	for k, v := range m {
+		switch val := v.(type) {
+		case nil:
+			delete(m, k)
+		case map[string]interface{}:
+			m[k] = removeNulls(val)
+		}
+	}
+	return m
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestMergeManifests(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string
+		overlay  string
+		expected string
+	}{
+		{
+			name:     "merge objects",
+			base:     "versions:\n  kubernetes: 1.18.2\nclusterNetwork:\n  podSubnet: 10.244.0.0/16\n",
+			overlay:  "versions:\n  kubernetes: 1.18.5\n",
+			expected: `{"versions":{"kubernetes":"1.18.5"},"clusterNetwork":{"podSubnet":"10.244.0.0/16"}}`,
+		},
+		{
+			name:     "null removes key",
+			base:     "clusterNetwork:\n  podSubnet: 10.244.0.0/16\n  serviceSubnet: 10.96.0.0/12\n",
+			overlay:  "clusterNetwork:\n  podSubnet: null\n",
+			expected: `{"clusterNetwork":{"serviceSubnet":"10.96.0.0/12"}}`,
+		},
+		{
+			name:     "merge named lists by name",
+			base:     "workers:\n- name: pool1\n  replicas: 1\n- name: pool2\n  replicas: 2\n",
+			overlay:  "workers:\n- name: pool2\n  replicas: 5\n- name: pool3\n  replicas: 1\n",
+			expected: `{"workers":[{"name":"pool1","replicas":1},{"name":"pool2","replicas":5},{"name":"pool3","replicas":1}]}`,
+		},
+		{
+			name:     "replace other lists",
+			base:     "apiEndpoint:\n  alternativeNames: [a, b]\n",
+			overlay:  "apiEndpoint:\n  alternativeNames: [c]\n",
+			expected: `{"apiEndpoint":{"alternativeNames":["c"]}}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			merged, err := MergeManifests([]byte(tc.base), []byte(tc.overlay))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var got, expected interface{}
+			if err := json.Unmarshal(merged, &got); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(tc.expected), &expected); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, expected) {
+				t.Errorf("expected %s, got %s", tc.expected, merged)
+			}
+		})
+	}
+}
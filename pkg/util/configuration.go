@@ -114,6 +114,13 @@ func (c *Configuration) Download(conn ssh.Connection, source string, prefix stri
 	return nil
 }
 
+// Redact removes the sensitive values from all files
+func (c *Configuration) Redact(r *Redactor) {
+	for filename, content := range c.files {
+		c.files[filename] = r.Redact(content)
+	}
+}
+
 // Debug list filenames and their size to the standard output
 func (c *Configuration) Debug() {
 	for filename, content := range c.files {
@@ -40,10 +40,34 @@ type Context struct {
 	RESTConfig                *rest.Config
 	DynamicClient             dynclient.Client
 	Verbose                   bool
+	Debug                     bool
+	Parallelism               int
 	BackupFile                string
 	DestroyWorkers            bool
+	ForceDrain                bool
+	SkipWorkers               bool
+	Retry                     bool
+	Progress                  ProgressReporter
 	ForceUpgrade              bool
 	UpgradeMachineDeployments bool
+	UpgradeOSPackages         bool
+	Events                    EventRecorder
+	State                     *State
+	Redactor                  *Redactor
+	// InCluster builds the Kubernetes clients from the service account of
+	// the pod kubeone runs in instead of the downloaded kubeconfig
+	InCluster bool
+	// PlanFile is where apply writes the JSON plan of the phases it runs
+	PlanFile string
+	// Approve is asked to confirm the plan of apply before the cluster is
+	// changed, the changes are applied without confirmation if it's nil
+	Approve func(plan string) (bool, error)
+	// TargetHost is the public address of the only host the node tasks run
+	// on, the tasks run on all hosts if it's empty
+	TargetHost string
+	// Prune deletes the MachineDeployments created by KubeOne which are no
+	// longer in the manifest
+	Prune bool
 }
 
 // Clone returns a shallow copy of the context.
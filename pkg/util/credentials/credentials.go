@@ -47,6 +47,18 @@ const (
 	VSphereUsername         = "VSPHERE_USERNAME"
 )
 
+// requiredKeys are the credentials machine-controller requires for each
+// cloud provider
+var requiredKeys = map[kubeone.CloudProviderName][]string{
+	kubeone.CloudProviderNameAWS:          {AWSAccessKeyID, AWSSecretAccessKey},
+	kubeone.CloudProviderNameDigitalOcean: {DigitalOceanTokenKey},
+	kubeone.CloudProviderNameGCE:          {GoogleServiceAccountKey},
+	kubeone.CloudProviderNameHetzner:      {HetznerTokenKey},
+	kubeone.CloudProviderNameOpenStack:    {OpenStackAuthURL, OpenStackDomainName, OpenStackPassword, OpenStackRegionName, OpenStackTenantName, OpenStackUserName},
+	kubeone.CloudProviderNamePacket:       {PacketAPIKey, PacketProjectID},
+	kubeone.CloudProviderNameVSphere:      {VSphereAddress, VSpherePasswords, VSphereUsername},
+}
+
 // ProviderEnvironmentVariable is used to match environment variable used by KubeOne to environment variable used by
 // machine-controller.
 type ProviderEnvironmentVariable struct {
@@ -127,6 +139,108 @@ func ProviderCredentials(p kubeone.CloudProviderName) (map[string]string, error)
 	return nil, errors.New("no provider matched")
 }
 
+// ClusterCredentials parses the credentials of the cluster cloud provider
+// from environment, along with the credentials of the other cloud providers
+// the workers are created on, unless machine-controller uses the referenced
+// credentials secret
+func ClusterCredentials(cluster *kubeone.KubeOneCluster) (map[string]string, error) {
+	creds, err := ProviderCredentials(cluster.CloudProvider.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	mc := cluster.MachineController
+	if mc == nil || !mc.Deploy || mc.CredentialsSecretRef != nil {
+		return creds, nil
+	}
+
+	for _, p := range cluster.WorkerCloudProviders() {
+		if p == cluster.CloudProvider.Name {
+			continue
+		}
+
+		workerCreds, err := ProviderCredentials(p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to fetch %s credentials for the workers", p)
+		}
+		for k, v := range workerCreds {
+			creds[k] = v
+		}
+	}
+
+	return creds, nil
+}
+
+// SensitiveValues returns the values to be redacted from output: the
+// secret credentials of the cluster cloud providers found in environment,
+// and the cloud config. Providers without credentials are skipped.
+func SensitiveValues(cluster *kubeone.KubeOneCluster) []string {
+	values := []string{cluster.CloudProvider.CloudConfig}
+
+	providers := append([]kubeone.CloudProviderName{cluster.CloudProvider.Name}, cluster.WorkerCloudProviders()...)
+	for _, p := range providers {
+		creds, err := ProviderCredentials(p)
+		if err != nil {
+			continue
+		}
+
+		for k, v := range creds {
+			if isSecretKey(k) {
+				values = append(values, v)
+			}
+		}
+	}
+
+	return values
+}
+
+// isSecretKey returns whether the credential holds a secret, as opposed to
+// e.g. the auth URL or the region
+func isSecretKey(key string) bool {
+	for _, s := range []string{"PASSWORD", "TOKEN", "SECRET", "KEY", "SERVICE_ACCOUNT"} {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// MissingKeys returns the credentials machine-controller requires for the
+// cloud provider which are missing from the given ones
+func MissingKeys(p kubeone.CloudProviderName, creds map[string]string) []string {
+	var missing []string
+	for _, key := range requiredKeys[p] {
+		if creds[key] == "" {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
+
+// VeleroCredentials parses the credentials Velero needs to access the given
+// object storage backend from environment
+func VeleroCredentials(backend kubeone.VeleroBackend) (map[string]string, error) {
+	switch backend {
+	case kubeone.VeleroBackendS3:
+		return ProviderCredentials(kubeone.CloudProviderNameAWS)
+	case kubeone.VeleroBackendGCS:
+		return parseCredentialVariables([]ProviderEnvironmentVariable{
+			{Name: "GOOGLE_CREDENTIALS"},
+		})
+	case kubeone.VeleroBackendAzure:
+		return parseCredentialVariables([]ProviderEnvironmentVariable{
+			{Name: "AZURE_SUBSCRIPTION_ID"},
+			{Name: "AZURE_TENANT_ID"},
+			{Name: "AZURE_CLIENT_ID"},
+			{Name: "AZURE_CLIENT_SECRET"},
+			{Name: "AZURE_RESOURCE_GROUP"},
+			{Name: "AZURE_STORAGE_ACCOUNT_ID"},
+		})
+	}
+
+	return nil, errors.New("no velero backend matched")
+}
+
 func parseCredentialVariables(envVars []ProviderEnvironmentVariable) (map[string]string, error) {
 	creds := make(map[string]string)
 	for _, env := range envVars {
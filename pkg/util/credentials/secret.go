@@ -50,9 +50,14 @@ func Ensure(ctx *util.Context) error {
 		return nil
 	}
 
+	if ctx.Cluster.MachineController.CredentialsSecretRef != nil && !ctx.Cluster.CloudProvider.External {
+		ctx.Logger.Info("Skipping creating credentials secret because machine-controller uses the referenced secret.")
+		return nil
+	}
+
 	ctx.Logger.Infoln("Creating credentials secret…")
 
-	creds, err := ProviderCredentials(ctx.Cluster.CloudProvider.Name)
+	creds, err := ClusterCredentials(ctx.Cluster)
 	if err != nil {
 		return errors.Wrap(err, "unable to fetch cloud provider credentials")
 	}
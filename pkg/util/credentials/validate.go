@@ -0,0 +1,167 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentials
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/kubermatic/kubeone/pkg/apis/kubeone"
+)
+
+// validationTimeout bounds the API call used to validate the credentials
+const validationTimeout = 15 * time.Second
+
+// API endpoints queried to validate the credentials, variables so tests can
+// point them to a local server
+var (
+	digitalOceanAPI = "https://api.digitalocean.com/v2"
+	hetznerAPI      = "https://api.hetzner.cloud/v1"
+	packetAPI       = "https://api.packet.net"
+)
+
+// ErrValidationUnsupported is returned by Validate for providers whose
+// credentials can't be validated without the provider SDK
+var ErrValidationUnsupported = errors.New("credentials validation is not supported for the cloud provider")
+
+// UnreachableError is returned by Validate when the provider API couldn't be
+// reached, in which case the validity of the credentials is unknown
+type UnreachableError struct {
+	Err error
+}
+
+func (e *UnreachableError) Error() string {
+	return "provider API unreachable: " + e.Err.Error()
+}
+
+// Validate performs a lightweight read-only API call against the cloud
+// provider to make sure the given credentials, as returned by
+// ProviderCredentials, are accepted
+func Validate(p kubeone.CloudProviderName, creds map[string]string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), validationTimeout)
+	defer cancel()
+
+	var req *http.Request
+	var err error
+
+	switch p {
+	case kubeone.CloudProviderNameDigitalOcean:
+		req, err = newRequest(ctx, http.MethodGet, digitalOceanAPI+"/account", nil)
+		if err == nil {
+			req.Header.Set("Authorization", "Bearer "+creds[DigitalOceanTokenKey])
+		}
+	case kubeone.CloudProviderNameHetzner:
+		req, err = newRequest(ctx, http.MethodGet, hetznerAPI+"/locations", nil)
+		if err == nil {
+			req.Header.Set("Authorization", "Bearer "+creds[HetznerTokenKey])
+		}
+	case kubeone.CloudProviderNamePacket:
+		req, err = newRequest(ctx, http.MethodGet, packetAPI+"/projects/"+creds[PacketProjectID], nil)
+		if err == nil {
+			req.Header.Set("X-Auth-Token", creds[PacketAPIKey])
+		}
+	case kubeone.CloudProviderNameOpenStack:
+		req, err = openStackTokenRequest(ctx, creds)
+	default:
+		return ErrValidationUnsupported
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to build credentials validation request")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return &UnreachableError{Err: err}
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized, resp.StatusCode == http.StatusForbidden:
+		return errors.Errorf("%s credentials were rejected by the provider API: %s", p, responseMessage(resp))
+	case resp.StatusCode == http.StatusNotFound && p == kubeone.CloudProviderNamePacket:
+		return errors.Errorf("packet project %q not found", creds[PacketProjectID])
+	case resp.StatusCode >= 400:
+		return errors.Errorf("unexpected response from the %s API: %s", p, responseMessage(resp))
+	}
+
+	return nil
+}
+
+// openStackTokenRequest builds a Keystone v3 password authentication request
+func openStackTokenRequest(ctx context.Context, creds map[string]string) (*http.Request, error) {
+	type domain struct {
+		Name string `json:"name"`
+	}
+	body := map[string]interface{}{
+		"auth": map[string]interface{}{
+			"identity": map[string]interface{}{
+				"methods": []string{"password"},
+				"password": map[string]interface{}{
+					"user": map[string]interface{}{
+						"name":     creds[OpenStackUserName],
+						"password": creds[OpenStackPassword],
+						"domain":   domain{Name: creds[OpenStackDomainName]},
+					},
+				},
+			},
+		},
+	}
+
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	authURL := strings.TrimSuffix(creds[OpenStackAuthURL], "/")
+	if !strings.HasSuffix(authURL, "/v3") {
+		authURL += "/v3"
+	}
+
+	req, err := newRequest(ctx, http.MethodPost, authURL+"/auth/tokens", bytes.NewReader(buf))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return req, nil
+}
+
+func newRequest(ctx context.Context, method, url string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return req.WithContext(ctx), nil
+}
+
+// responseMessage returns the status and the beginning of the response body
+func responseMessage(resp *http.Response) string {
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+	msg := strings.TrimSpace(string(body))
+	if msg == "" {
+		return resp.Status
+	}
+	return resp.Status + ": " + msg
+}
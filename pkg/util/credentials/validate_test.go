@@ -0,0 +1,74 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentials
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kubermatic/kubeone/pkg/apis/kubeone"
+)
+
+func TestValidate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer valid" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	hetznerAPI = srv.URL
+
+	tests := []struct {
+		name          string
+		provider      kubeone.CloudProviderName
+		token         string
+		expectedError bool
+	}{
+		{
+			name:          "valid token",
+			provider:      kubeone.CloudProviderNameHetzner,
+			token:         "valid",
+			expectedError: false,
+		},
+		{
+			name:          "rejected token",
+			provider:      kubeone.CloudProviderNameHetzner,
+			token:         "invalid",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Validate(tc.provider, map[string]string{HetznerTokenKey: tc.token})
+			if (err != nil) != tc.expectedError {
+				t.Errorf("expected error = %v, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestValidateUnsupported(t *testing.T) {
+	err := Validate(kubeone.CloudProviderNameVSphere, nil)
+	if err != ErrValidationUnsupported {
+		t.Errorf("expected ErrValidationUnsupported, got %v", err)
+	}
+}
@@ -0,0 +1,29 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+// Difference describes how a single field of a resource running in the
+// cluster differs from the desired state generated from the manifest. An
+// empty Field means the whole resource is missing from the cluster, unless
+// Removed is set, which means the resource is not in the manifest.
+type Difference struct {
+	Resource string
+	Field    string
+	Existing string
+	Desired  string
+	Removed  bool
+}
@@ -0,0 +1,222 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/retry"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// annotationMirrorPod is set on static pods mirrored by the kubelet
+	annotationMirrorPod = "kubernetes.io/config.mirror"
+	// timeoutEviction is how long we retry evicting a pod blocked by
+	// a PodDisruptionBudget before giving up
+	timeoutEviction = 2 * time.Minute
+)
+
+// SetNodeUnschedulable cordons or uncordons the node
+func SetNodeUnschedulable(ctx context.Context, client dynclient.Client, nodeName string, unschedulable bool) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		node := corev1.Node{}
+		if err := client.Get(ctx, types.NamespacedName{Name: nodeName}, &node); err != nil {
+			return err
+		}
+
+		if node.Spec.Unschedulable == unschedulable {
+			return nil
+		}
+
+		node.Spec.Unschedulable = unschedulable
+		return client.Update(ctx, &node)
+	})
+}
+
+// EvictNodePods evicts all pods running on the node, except for the ones
+// managed by DaemonSets and static pods, which are not possible to evict
+func EvictNodePods(ctx context.Context, client dynclient.Client, evictionClient rest.Interface, nodeName string) error {
+	pods, err := evictablePods(ctx, client, nodeName)
+	if err != nil {
+		return err
+	}
+
+	for i := range pods {
+		pod := &pods[i]
+
+		var evictErr error
+		err = wait.Poll(5*time.Second, timeoutEviction, func() (bool, error) {
+			evictErr = evictPod(evictionClient, pod)
+			switch {
+			case evictErr == nil, k8serrors.IsNotFound(evictErr):
+				return true, nil
+			case k8serrors.IsTooManyRequests(evictErr):
+				// eviction is blocked by a PodDisruptionBudget, try again
+				return false, nil
+			default:
+				return false, evictErr
+			}
+		})
+
+		if err == wait.ErrWaitTimeout {
+			pdbs, pdbErr := matchingPodDisruptionBudgets(ctx, client, pod)
+			if pdbErr != nil {
+				return errors.Wrapf(pdbErr, "unable to evict pod %s/%s", pod.Namespace, pod.Name)
+			}
+			return errors.Errorf("unable to evict pod %s/%s, blocked by PodDisruptionBudget(s) %s",
+				pod.Namespace, pod.Name, strings.Join(pdbs, ", "))
+		}
+		if err != nil {
+			return errors.Wrapf(err, "unable to evict pod %s/%s", pod.Namespace, pod.Name)
+		}
+	}
+
+	return nil
+}
+
+// DeleteNodePods deletes all pods running on the node, except for the ones
+// managed by DaemonSets and static pods. Unlike evictions, deletions don't
+// honor PodDisruptionBudgets.
+func DeleteNodePods(ctx context.Context, client dynclient.Client, nodeName string) error {
+	pods, err := evictablePods(ctx, client, nodeName)
+	if err != nil {
+		return err
+	}
+
+	for i := range pods {
+		pod := &pods[i]
+		if err := client.Delete(ctx, pod); err != nil && !k8serrors.IsNotFound(err) {
+			return errors.Wrapf(err, "unable to delete pod %s/%s", pod.Namespace, pod.Name)
+		}
+	}
+
+	return nil
+}
+
+// evictablePods lists the pods running on the node which can be evicted
+func evictablePods(ctx context.Context, client dynclient.Client, nodeName string) ([]corev1.Pod, error) {
+	pods := corev1.PodList{}
+	podListOpts := dynclient.ListOptions{}
+	if err := podListOpts.SetFieldSelector("spec.nodeName=" + nodeName); err != nil {
+		return nil, errors.Wrap(err, "failed to set pod field selector")
+	}
+
+	if err := client.List(ctx, &podListOpts, &pods); err != nil {
+		return nil, errors.Wrap(err, "unable to list pods")
+	}
+
+	var evictable []corev1.Pod
+	for i := range pods.Items {
+		if isEvictable(&pods.Items[i]) {
+			evictable = append(evictable, pods.Items[i])
+		}
+	}
+
+	return evictable, nil
+}
+
+func isEvictable(pod *corev1.Pod) bool {
+	if _, ok := pod.Annotations[annotationMirrorPod]; ok {
+		return false
+	}
+
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return false
+	}
+
+	for _, owner := range pod.OwnerReferences {
+		if owner.Controller != nil && *owner.Controller && owner.Kind == "DaemonSet" {
+			return false
+		}
+	}
+
+	return true
+}
+
+func evictPod(evictionClient rest.Interface, pod *corev1.Pod) error {
+	eviction := &policyv1beta1.Eviction{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: policyv1beta1.SchemeGroupVersion.String(),
+			Kind:       "Eviction",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+		},
+	}
+
+	return evictionClient.Post().
+		AbsPath("/api/v1").
+		Namespace(pod.Namespace).
+		Resource("pods").
+		Name(pod.Name).
+		SubResource("eviction").
+		Body(eviction).
+		Do().
+		Error()
+}
+
+// matchingPodDisruptionBudgets returns names of PodDisruptionBudgets selecting the pod
+func matchingPodDisruptionBudgets(ctx context.Context, client dynclient.Client, pod *corev1.Pod) ([]string, error) {
+	pdbs := policyv1beta1.PodDisruptionBudgetList{}
+	if err := client.List(ctx, &dynclient.ListOptions{Namespace: pod.Namespace}, &pdbs); err != nil {
+		return nil, errors.Wrap(err, "unable to list PodDisruptionBudgets")
+	}
+
+	names := []string{}
+	for _, pdb := range pdbs.Items {
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid selector in PodDisruptionBudget %s", pdb.Name)
+		}
+		if selector.Matches(labels.Set(pod.Labels)) {
+			names = append(names, fmt.Sprintf("%s/%s", pdb.Namespace, pdb.Name))
+		}
+	}
+
+	return names, nil
+}
+
+// NewEvictionClient returns a REST client able to create policy/v1beta1 Evictions
+func NewEvictionClient(config *rest.Config) (rest.Interface, error) {
+	if config == nil {
+		return nil, errors.New("rest config is not initialized")
+	}
+
+	cfg := rest.CopyConfig(config)
+	cfg.GroupVersion = &policyv1beta1.SchemeGroupVersion
+	cfg.APIPath = "/apis"
+	cfg.NegotiatedSerializer = serializer.DirectCodecFactory{CodecFactory: scheme.Codecs}
+
+	return rest.RESTClientFor(cfg)
+}
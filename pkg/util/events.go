@@ -0,0 +1,167 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// EventsConfigMapName is the name of the ConfigMap the events are recorded on
+	EventsConfigMapName = "kubeone-events"
+	// EventsNamespace is the namespace the events are recorded in
+	EventsNamespace = metav1.NamespaceSystem
+
+	eventSourceComponent = "kubeone"
+)
+
+// Reasons of the recorded events
+const (
+	EventReasonApplyStart    = "ApplyStart"
+	EventReasonApplyComplete = "ApplyComplete"
+	EventReasonStepFailed    = "StepFailed"
+)
+
+// EventRecorder records auditable events about KubeOne operations
+type EventRecorder interface {
+	// RecordApplyStart records that the operation has started
+	RecordApplyStart(operation string)
+	// RecordApplyComplete records that the operation has finished successfully
+	RecordApplyComplete(operation string)
+	// RecordStepFailed records that a step of the operation has failed
+	RecordStepFailed(step string, err error)
+}
+
+type pendingEvent struct {
+	eventType string
+	reason    string
+	message   string
+	timestamp metav1.Time
+}
+
+// ClusterEventRecorder records the events as Kubernetes Events on the
+// kubeone-events ConfigMap in the kube-system namespace. Events recorded
+// before the cluster is accessible are only logged, and are sent to the
+// cluster as soon as the Kubernetes client of the context is initialized.
+type ClusterEventRecorder struct {
+	ctx     *Context
+	lock    sync.Mutex
+	pending []pendingEvent
+}
+
+// NewClusterEventRecorder constructor
+func NewClusterEventRecorder(ctx *Context) *ClusterEventRecorder {
+	return &ClusterEventRecorder{ctx: ctx}
+}
+
+// RecordApplyStart records that the operation has started
+func (r *ClusterEventRecorder) RecordApplyStart(operation string) {
+	r.record(corev1.EventTypeNormal, EventReasonApplyStart, fmt.Sprintf("%s of cluster %s started", operation, r.ctx.Cluster.Name))
+}
+
+// RecordApplyComplete records that the operation has finished successfully
+func (r *ClusterEventRecorder) RecordApplyComplete(operation string) {
+	r.record(corev1.EventTypeNormal, EventReasonApplyComplete, fmt.Sprintf("%s of cluster %s completed", operation, r.ctx.Cluster.Name))
+}
+
+// RecordStepFailed records that a step of the operation has failed
+func (r *ClusterEventRecorder) RecordStepFailed(step string, err error) {
+	r.record(corev1.EventTypeWarning, EventReasonStepFailed, fmt.Sprintf("%s: %v", step, err))
+}
+
+func (r *ClusterEventRecorder) record(eventType, reason, message string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.pending = append(r.pending, pendingEvent{
+		eventType: eventType,
+		reason:    reason,
+		message:   message,
+		timestamp: metav1.Now(),
+	})
+
+	// the cluster is not accessible yet
+	if r.ctx.DynamicClient == nil {
+		r.ctx.Logger.WithField("event", reason).Infoln(message)
+		return
+	}
+
+	if err := r.flush(); err != nil {
+		r.ctx.Logger.Warnf("Unable to record event: %v", err)
+	}
+}
+
+// flush sends the pending events to the cluster
+func (r *ClusterEventRecorder) flush() error {
+	bgCtx := context.Background()
+
+	cm := &corev1.ConfigMap{}
+	key := types.NamespacedName{Namespace: EventsNamespace, Name: EventsConfigMapName}
+	err := r.ctx.DynamicClient.Get(bgCtx, key, cm)
+	if k8serrors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      EventsConfigMapName,
+				Namespace: EventsNamespace,
+			},
+		}
+		err = r.ctx.DynamicClient.Create(bgCtx, cm)
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to ensure events ConfigMap")
+	}
+
+	for len(r.pending) > 0 {
+		e := r.pending[0]
+		event := &corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s.%x", EventsConfigMapName, e.timestamp.UnixNano()),
+				Namespace: EventsNamespace,
+			},
+			InvolvedObject: corev1.ObjectReference{
+				APIVersion: "v1",
+				Kind:       "ConfigMap",
+				Name:       cm.Name,
+				Namespace:  cm.Namespace,
+				UID:        cm.UID,
+			},
+			Type:           e.eventType,
+			Reason:         e.reason,
+			Message:        e.message,
+			Source:         corev1.EventSource{Component: eventSourceComponent},
+			FirstTimestamp: e.timestamp,
+			LastTimestamp:  e.timestamp,
+			Count:          1,
+		}
+
+		if err := r.ctx.DynamicClient.Create(bgCtx, event); err != nil {
+			return errors.Wrapf(err, "failed to create %s event", e.reason)
+		}
+		r.pending = r.pending[1:]
+	}
+
+	return nil
+}
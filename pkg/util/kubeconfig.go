@@ -17,12 +17,15 @@ limitations under the License.
 package util
 
 import (
+	"fmt"
+
 	"github.com/pkg/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
 	"github.com/kubermatic/kubeone/pkg/ssh"
 
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
@@ -50,10 +53,36 @@ func DownloadKubeconfig(cluster *kubeoneapi.KubeOneCluster) ([]byte, error) {
 	return []byte(kubeconfig), nil
 }
 
+// LocalKubeconfig points all clusters of the kubeconfig to the API server
+// proxied on the given local port
+func LocalKubeconfig(kubeconfig []byte, port int) ([]byte, error) {
+	config, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse kubeconfig")
+	}
+
+	for _, cluster := range config.Clusters {
+		cluster.Server = fmt.Sprintf("https://127.0.0.1:%d", port)
+	}
+
+	return clientcmd.Write(*config)
+}
+
 // BuildKubernetesClientset builds core kubernetes and apiextensions clientsets
 func BuildKubernetesClientset(ctx *Context) error {
 	ctx.Logger.Infoln("Building Kubernetes clientset…")
 
+	if ctx.InCluster {
+		var err error
+		ctx.RESTConfig, err = rest.InClusterConfig()
+		if err != nil {
+			return errors.Wrap(err, "unable to build in-cluster config, is kubeone running inside a pod?")
+		}
+
+		err = HackIssue321InitDynamicClient(ctx)
+		return errors.Wrap(err, "unable to build dynamic client")
+	}
+
 	kubeconfig, err := DownloadKubeconfig(ctx.Cluster)
 	if err != nil {
 		return errors.Wrap(err, "unable to download kubeconfig")
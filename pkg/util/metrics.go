@@ -0,0 +1,134 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Metrics records the duration of an operation and of its phases, and
+// writes them in the Prometheus text exposition format. It implements
+// ProgressReporter, where every step is recorded as a phase.
+type Metrics struct {
+	started time.Time
+	phases  []phaseMetric
+}
+
+type phaseMetric struct {
+	name     string
+	started  time.Time
+	duration time.Duration
+	done     bool
+}
+
+// NewMetrics returns metrics for an operation starting now
+func NewMetrics() *Metrics {
+	return &Metrics{started: time.Now()}
+}
+
+// Start starts recording phases of the operation
+func (m *Metrics) Start(int) {
+	m.phases = nil
+}
+
+// Step finishes the current phase and starts recording the next one
+func (m *Metrics) Step(name string) {
+	m.finishPhase()
+	m.phases = append(m.phases, phaseMetric{name: name, started: time.Now()})
+}
+
+// Finish finishes the current phase
+func (m *Metrics) Finish() {
+	m.finishPhase()
+}
+
+func (m *Metrics) finishPhase() {
+	if len(m.phases) == 0 {
+		return
+	}
+
+	last := &m.phases[len(m.phases)-1]
+	if !last.done {
+		last.duration = time.Since(last.started)
+		last.done = true
+	}
+}
+
+// WriteFile writes the metrics of the given operation to path. A phase
+// still running when the operation failed is reported as failed.
+func (m *Metrics) WriteFile(path, operation string, succeeded bool) error {
+	status := "success"
+	if !succeeded {
+		status = "failure"
+	}
+
+	buf := &bytes.Buffer{}
+
+	fmt.Fprintln(buf, "# HELP kubeone_operation_duration_seconds Duration of the kubeone operation.")
+	fmt.Fprintln(buf, "# TYPE kubeone_operation_duration_seconds gauge")
+	fmt.Fprintf(buf, "kubeone_operation_duration_seconds{operation=\"%s\",status=\"%s\"} %f\n",
+		escapeLabelValue(operation), status, time.Since(m.started).Seconds())
+
+	if len(m.phases) > 0 {
+		fmt.Fprintln(buf, "# HELP kubeone_operation_phase_duration_seconds Duration of the kubeone operation phases.")
+		fmt.Fprintln(buf, "# TYPE kubeone_operation_phase_duration_seconds gauge")
+		for _, phase := range m.phases {
+			phaseStatus, duration := "success", phase.duration
+			if !phase.done {
+				phaseStatus, duration = "failure", time.Since(phase.started)
+			}
+			fmt.Fprintf(buf, "kubeone_operation_phase_duration_seconds{operation=\"%s\",phase=\"%s\",status=\"%s\"} %f\n",
+				escapeLabelValue(operation), escapeLabelValue(phase.name), phaseStatus, duration.Seconds())
+		}
+	}
+
+	// write to a temporary file and rename it, so collectors never read a
+	// partially written file
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path))
+	if err != nil {
+		return errors.Wrap(err, "unable to create metrics file")
+	}
+	defer os.Remove(tmp.Name())
+
+	if err = tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "unable to write metrics file")
+	}
+	if _, err = tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "unable to write metrics file")
+	}
+	if err = tmp.Close(); err != nil {
+		return errors.Wrap(err, "unable to write metrics file")
+	}
+
+	return errors.Wrap(os.Rename(tmp.Name(), path), "unable to write metrics file")
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
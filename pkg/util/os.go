@@ -0,0 +1,124 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bufio"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
+	"github.com/kubermatic/kubeone/pkg/ssh"
+)
+
+// testedOSVersions are the releases the installation scripts are tested
+// against. Container Linux is left out as it's updated continuously.
+var testedOSVersions = map[kubeoneapi.OperatingSystemName][]string{
+	kubeoneapi.OperatingSystemNameUbuntu:      {"16.04", "18.04", "20.04", "22.04"},
+	kubeoneapi.OperatingSystemNameDebian:      {"9", "10"},
+	kubeoneapi.OperatingSystemNameCentOS:      {"7"},
+	kubeoneapi.OperatingSystemNameAmazonLinux: {"2"},
+}
+
+// DetermineOS identifies the distribution of every host by its
+// /etc/os-release, so that later steps can pick the matching scripts.
+func DetermineOS(ctx *Context) error {
+	ctx.Logger.Infoln("Determine operating system…")
+	return ctx.RunTaskOnAllNodes(determineNodeOS, true)
+}
+
+func determineNodeOS(ctx *Context, node *kubeoneapi.HostConfig, _ ssh.Connection) error {
+	stdout, _, err := ctx.Runner.Run("cat /etc/os-release", nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to read /etc/os-release")
+	}
+
+	release := parseOSRelease(stdout)
+
+	os, err := distribution(release)
+	if err != nil {
+		return err
+	}
+
+	node.SetOperatingSystem(os, release["VERSION_ID"])
+
+	if !isTestedOSVersion(os, node.OperatingSystemVersion) {
+		ctx.Logger.Warnf("%s %s is not among the tested releases, the installation might fail", os, node.OperatingSystemVersion)
+	}
+
+	return nil
+}
+
+// isTestedOSVersion returns whether the installation scripts are tested
+// against the given release of the operating system.
+func isTestedOSVersion(os kubeoneapi.OperatingSystemName, version string) bool {
+	versions, ok := testedOSVersions[os]
+	if !ok {
+		return true
+	}
+
+	for _, v := range versions {
+		if v == version {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseOSRelease returns the variables of an os-release(5) file.
+func parseOSRelease(content string) map[string]string {
+	release := map[string]string{}
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		release[parts[0]] = strings.Trim(parts[1], `"'`)
+	}
+
+	return release
+}
+
+// distribution maps the ID of an os-release to a supported operating
+// system. Derivatives, e.g. Linux Mint, are matched by their ID_LIKE.
+func distribution(release map[string]string) (kubeoneapi.OperatingSystemName, error) {
+	ids := append([]string{release["ID"]}, strings.Fields(release["ID_LIKE"])...)
+
+	for _, id := range ids {
+		switch os := kubeoneapi.OperatingSystemName(id); os {
+		case kubeoneapi.OperatingSystemNameUbuntu,
+			kubeoneapi.OperatingSystemNameDebian,
+			kubeoneapi.OperatingSystemNameCentOS,
+			kubeoneapi.OperatingSystemNameAmazonLinux,
+			kubeoneapi.OperatingSystemNameCoreOS,
+			kubeoneapi.OperatingSystemNameFlatcar:
+			return os, nil
+		}
+	}
+
+	return "", errors.Errorf("'%s' is not a supported operating system", release["ID"])
+}
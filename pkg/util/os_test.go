@@ -0,0 +1,136 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
+)
+
+func TestDistribution(t *testing.T) {
+	testcases := []struct {
+		name          string
+		osRelease     string
+		expected      kubeoneapi.OperatingSystemName
+		expectedError bool
+	}{
+		{
+			name: "ubuntu",
+			osRelease: `NAME="Ubuntu"
+VERSION="18.04.2 LTS (Bionic Beaver)"
+ID=ubuntu
+ID_LIKE=debian
+VERSION_ID="18.04"`,
+			expected: kubeoneapi.OperatingSystemNameUbuntu,
+		},
+		{
+			name: "centos",
+			osRelease: `NAME="CentOS Linux"
+VERSION="7 (Core)"
+ID="centos"
+ID_LIKE="rhel fedora"
+VERSION_ID="7"`,
+			expected: kubeoneapi.OperatingSystemNameCentOS,
+		},
+		{
+			name: "flatcar",
+			osRelease: `NAME="Flatcar Container Linux by Kinvolk"
+ID=flatcar
+ID_LIKE=coreos
+VERSION=2079.3.0
+VERSION_ID=2079.3.0`,
+			expected: kubeoneapi.OperatingSystemNameFlatcar,
+		},
+		{
+			name: "amazon linux 2 is not mistaken for centos",
+			osRelease: `NAME="Amazon Linux"
+VERSION="2"
+ID="amzn"
+ID_LIKE="centos rhel fedora"
+VERSION_ID="2"`,
+			expected: kubeoneapi.OperatingSystemNameAmazonLinux,
+		},
+		{
+			name: "derivative matched by ID_LIKE",
+			osRelease: `NAME="Linux Mint"
+ID=linuxmint
+ID_LIKE="ubuntu debian"`,
+			expected: kubeoneapi.OperatingSystemNameUbuntu,
+		},
+		{
+			name: "unsupported distribution",
+			osRelease: `NAME="Arch Linux"
+ID=arch`,
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			os, err := distribution(parseOSRelease(tc.osRelease))
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("expected error: %v, got %v", tc.expectedError, err)
+			}
+			if os != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, os)
+			}
+		})
+	}
+}
+
+func TestIsTestedOSVersion(t *testing.T) {
+	testcases := []struct {
+		name     string
+		os       kubeoneapi.OperatingSystemName
+		version  string
+		expected bool
+	}{
+		{
+			name:     "ubuntu 22.04",
+			os:       kubeoneapi.OperatingSystemNameUbuntu,
+			version:  "22.04",
+			expected: true,
+		},
+		{
+			name:     "ubuntu 14.04",
+			os:       kubeoneapi.OperatingSystemNameUbuntu,
+			version:  "14.04",
+			expected: false,
+		},
+		{
+			name:     "centos 8",
+			os:       kubeoneapi.OperatingSystemNameCentOS,
+			version:  "8",
+			expected: false,
+		},
+		{
+			name:     "flatcar is continuously updated",
+			os:       kubeoneapi.OperatingSystemNameFlatcar,
+			version:  "2079.3.0",
+			expected: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := isTestedOSVersion(tc.os, tc.version); got != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// outputLock is shared by all PrefixedWriters, so the lines written by
+// concurrent SSH sessions never interleave, even on different writers
+// ending up on the same terminal
+var outputLock sync.Mutex
+
+// PrefixedWriter writes complete lines, each prefixed with the given
+// prefix, e.g. the host the output comes from. Partial lines are
+// buffered until they are completed or the writer is closed.
+type PrefixedWriter struct {
+	out      io.Writer
+	prefix   []byte
+	redactor *Redactor
+	lock     sync.Mutex
+	buffer   bytes.Buffer
+}
+
+// NewPrefixedWriter constructor
+func NewPrefixedWriter(out io.Writer, prefix string) *PrefixedWriter {
+	return &PrefixedWriter{
+		out:    out,
+		prefix: []byte(prefix),
+	}
+}
+
+// WithRedactor makes the writer redact the sensitive values from the lines
+// it writes. Redaction works on complete lines, so values spanning
+// several lines are not redacted.
+func (w *PrefixedWriter) WithRedactor(r *Redactor) *PrefixedWriter {
+	w.redactor = r
+	return w
+}
+
+// Write writes the complete lines of p, along with the buffered partial line
+func (w *PrefixedWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.buffer.Write(p)
+
+	var lines []byte
+	for {
+		data := w.buffer.Bytes()
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			break
+		}
+		lines = append(lines, w.prefix...)
+		lines = append(lines, data[:i+1]...)
+		w.buffer.Next(i + 1)
+	}
+
+	if err := w.writeOut(lines); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Close writes the buffered partial line, if any. The underlying writer
+// is not closed.
+func (w *PrefixedWriter) Close() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.buffer.Len() == 0 {
+		return nil
+	}
+
+	line := append(append([]byte{}, w.prefix...), w.buffer.Bytes()...)
+	line = append(line, '\n')
+	w.buffer.Reset()
+
+	return w.writeOut(line)
+}
+
+func (w *PrefixedWriter) writeOut(p []byte) error {
+	if len(p) == 0 {
+		return nil
+	}
+
+	if w.redactor != nil {
+		p = []byte(w.redactor.Redact(string(p)))
+	}
+
+	outputLock.Lock()
+	defer outputLock.Unlock()
+
+	_, err := w.out.Write(p)
+	return err
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestPrefixedWriter(t *testing.T) {
+	var out bytes.Buffer
+
+	w := NewPrefixedWriter(&out, "[host] ")
+	fmt.Fprint(w, "first ")
+	fmt.Fprint(w, "line\nsecond line\nunterminated")
+
+	if got := out.String(); got != "[host] first line\n[host] second line\n" {
+		t.Errorf("unexpected output before close: %q", got)
+	}
+
+	w.Close()
+
+	if got := out.String(); !strings.HasSuffix(got, "[host] unterminated\n") {
+		t.Errorf("partial line not flushed on close: %q", got)
+	}
+}
+
+func TestPrefixedWriterConcurrent(t *testing.T) {
+	var out bytes.Buffer
+	const hosts, lines = 8, 100
+
+	wg := sync.WaitGroup{}
+	for h := 0; h < hosts; h++ {
+		wg.Add(1)
+		go func(h int) {
+			defer wg.Done()
+			w := NewPrefixedWriter(&out, fmt.Sprintf("[host-%d] ", h))
+			for l := 0; l < lines; l++ {
+				// write each line in several chunks
+				fmt.Fprintf(w, "line %d ", l)
+				fmt.Fprintf(w, "of host-%d", h)
+				fmt.Fprint(w, "\n")
+			}
+			w.Close()
+		}(h)
+	}
+	wg.Wait()
+
+	outLines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(outLines) != hosts*lines {
+		t.Fatalf("expected %d lines, got %d", hosts*lines, len(outLines))
+	}
+
+	for _, line := range outLines {
+		var prefixHost, host, l int
+		if _, err := fmt.Sscanf(line, "[host-%d] line %d of host-%d", &prefixHost, &l, &host); err != nil || prefixHost != host {
+			t.Fatalf("interleaved line: %q", line)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// ProgressReporter reports progress of long-running operations
+type ProgressReporter interface {
+	// Start starts reporting for the operation consisting of totalSteps steps
+	Start(totalSteps int)
+	// Step reports that the next step has started
+	Step(name string)
+	// Finish reports that the operation is done
+	Finish()
+}
+
+// NewProgressReporter returns the text progress reporter if f is a terminal,
+// otherwise progress is not reported
+func NewProgressReporter(f *os.File) ProgressReporter {
+	if terminal.IsTerminal(int(f.Fd())) {
+		return NewTextProgressReporter(f)
+	}
+
+	return NoopProgressReporter{}
+}
+
+// NoopProgressReporter doesn't report progress
+type NoopProgressReporter struct{}
+
+// Start does nothing
+func (NoopProgressReporter) Start(int) {}
+
+// Step does nothing
+func (NoopProgressReporter) Step(string) {}
+
+// Finish does nothing
+func (NoopProgressReporter) Finish() {}
+
+// TextProgressReporter writes the current step along with the estimated
+// time remaining, based on the average duration of the finished steps
+type TextProgressReporter struct {
+	out        io.Writer
+	totalSteps int
+	step       int
+	started    time.Time
+}
+
+// NewTextProgressReporter constructor
+func NewTextProgressReporter(out io.Writer) *TextProgressReporter {
+	return &TextProgressReporter{out: out}
+}
+
+// Start starts reporting for the operation consisting of totalSteps steps
+func (p *TextProgressReporter) Start(totalSteps int) {
+	p.totalSteps = totalSteps
+	p.step = 0
+	p.started = time.Now()
+}
+
+// Step writes the step name along with the estimated time remaining
+func (p *TextProgressReporter) Step(name string) {
+	p.step++
+
+	line := fmt.Sprintf("Step %d/%d: %s", p.step, p.totalSteps, name)
+	if finished := p.step - 1; finished > 0 {
+		avg := time.Since(p.started) / time.Duration(finished)
+		remaining := avg * time.Duration(p.totalSteps-finished)
+		line = fmt.Sprintf("%s (about %s remaining)", line, remaining.Round(time.Second))
+	}
+
+	fmt.Fprintln(p.out, line)
+}
+
+// Finish writes the total duration of the operation
+func (p *TextProgressReporter) Finish() {
+	fmt.Fprintf(p.out, "Done %d steps in %s\n", p.totalSteps, time.Since(p.started).Round(time.Second))
+}
+
+// MultiProgressReporter reports progress to all of its reporters
+type MultiProgressReporter []ProgressReporter
+
+// Start starts reporting on all reporters
+func (m MultiProgressReporter) Start(totalSteps int) {
+	for _, r := range m {
+		r.Start(totalSteps)
+	}
+}
+
+// Step reports the step on all reporters
+func (m MultiProgressReporter) Step(name string) {
+	for _, r := range m {
+		r.Step(name)
+	}
+}
+
+// Finish finishes reporting on all reporters
+func (m MultiProgressReporter) Finish() {
+	for _, r := range m {
+		r.Finish()
+	}
+}
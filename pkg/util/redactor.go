@@ -0,0 +1,79 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// RedactedValue replaces the sensitive values
+const RedactedValue = "[REDACTED]"
+
+// minRedactedLength is the length of the shortest value redacted, so that
+// trivial values, e.g. a "1" from the environment, don't garble the output
+const minRedactedLength = 4
+
+// Redactor replaces sensitive values, such as credentials, with
+// [REDACTED] in the strings passed through it. A nil Redactor
+// returns the strings unchanged.
+type Redactor struct {
+	lock   sync.RWMutex
+	values []string
+}
+
+// NewRedactor constructor
+func NewRedactor(values ...string) *Redactor {
+	r := &Redactor{}
+	r.Add(values...)
+	return r
+}
+
+// Add adds sensitive values to be redacted
+func (r *Redactor) Add(values ...string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if len(v) >= minRedactedLength {
+			r.values = append(r.values, v)
+		}
+	}
+
+	// values containing other values are replaced first
+	sort.Slice(r.values, func(i, j int) bool {
+		return len(r.values[i]) > len(r.values[j])
+	})
+}
+
+// Redact returns s with all sensitive values replaced
+func (r *Redactor) Redact(s string) string {
+	if r == nil {
+		return s
+	}
+
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	for _, v := range r.values {
+		s = strings.Replace(s, v, RedactedValue, -1)
+	}
+
+	return s
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestRedactor(t *testing.T) {
+	r := NewRedactor("s3cr3t", "s3cr3t-token", "", "1")
+
+	got := r.Redact("token=s3cr3t-token password=s3cr3t replicas=1")
+	if expected := "token=[REDACTED] password=[REDACTED] replicas=1"; got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+
+	var nilRedactor *Redactor
+	if got := nilRedactor.Redact("s3cr3t"); got != "s3cr3t" {
+		t.Errorf("nil redactor changed the input: %q", got)
+	}
+}
+
+func TestPrefixedWriterRedactor(t *testing.T) {
+	var out bytes.Buffer
+
+	w := NewPrefixedWriter(&out, "[host] ").WithRedactor(NewRedactor("s3cr3t"))
+	fmt.Fprint(w, "password=s3")
+	fmt.Fprint(w, "cr3t\n")
+	w.Close()
+
+	if got := out.String(); got != "[host] password=[REDACTED]\n" {
+		t.Errorf("unexpected output: %q", got)
+	}
+}
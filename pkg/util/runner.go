@@ -22,7 +22,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/koron-go/prefixw"
 	"github.com/pkg/errors"
 
 	"github.com/kubermatic/kubeone/pkg/ssh"
@@ -35,6 +34,10 @@ type Runner struct {
 	Prefix  string
 	OS      string
 	Verbose bool
+	Debug   bool
+	Host    string
+	// Redactor removes sensitive values from the printed commands and output
+	Redactor *Redactor
 }
 
 // Run executes a given command/script, optionally printing its output to
@@ -51,19 +54,23 @@ func (r *Runner) Run(cmd string, variables TemplateVariables) (string, string, e
 
 	cmd = r.prepareShell(cmd)
 
+	if r.Debug {
+		r.printCommand(cmd)
+	}
+
 	if !r.Verbose {
 		var stdout, stderr string
 
 		stdout, stderr, _, err = r.Conn.Exec(cmd)
 		if err != nil {
-			err = errors.Wrap(err, stderr)
+			err = errors.Wrap(err, r.Redactor.Redact(stderr))
 		}
 
 		return stdout, stderr, err
 	}
 
-	stdout := NewTee(prefixw.New(os.Stdout, r.Prefix))
-	stderr := NewTee(prefixw.New(os.Stderr, r.Prefix))
+	stdout := NewTee(NewPrefixedWriter(os.Stdout, r.Prefix).WithRedactor(r.Redactor))
+	stderr := NewTee(NewPrefixedWriter(os.Stderr, r.Prefix).WithRedactor(r.Redactor))
 
 	// run the command
 	_, err = r.Conn.Stream(cmd, stdout, stderr)
@@ -107,6 +114,14 @@ func (r *Runner) WaitForCondition(cmd string, timeout time.Duration, validator v
 	return false
 }
 
+// printCommand prints the exact command about to be executed on the
+// remote host, along with the user and the host it's executed as/on.
+func (r *Runner) printCommand(cmd string) {
+	w := NewPrefixedWriter(os.Stderr, r.Prefix).WithRedactor(r.Redactor)
+	fmt.Fprintf(w, "+ ssh %s\n%s\n", r.Host, cmd)
+	w.Close()
+}
+
 // prepareShell sets up the shell depending on the OS it's running on.
 func (r *Runner) prepareShell(cmd string) string {
 	// ensure we fail early
@@ -0,0 +1,144 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultStateFile is where the progress of an installation is recorded
+const DefaultStateFile = ".kubeone/state.json"
+
+// State records the steps completed by an installation, so that an
+// interrupted installation can be resumed without running them again.
+// A nil State records nothing.
+type State struct {
+	path string
+
+	Cluster string `json:"cluster"`
+	// ManifestHash identifies the manifest the steps were completed with,
+	// the steps are run again if the manifest changed
+	ManifestHash   string   `json:"manifestHash"`
+	CompletedSteps []string `json:"completedSteps"`
+	// JoinToken is the bootstrap token in the kubeadm configs, it must
+	// not change once the leader is initialized
+	JoinToken string `json:"joinToken,omitempty"`
+}
+
+// ManifestHash returns the hash of the cluster configuration, recorded in
+// the state to detect changes of the manifest between runs
+func ManifestHash(cluster interface{}) (string, error) {
+	buf, err := json.Marshal(cluster)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal cluster configuration")
+	}
+
+	sum := sha256.Sum256(buf)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// LoadState reads the state of the given cluster from path. The state is
+// empty if the file doesn't exist, belongs to another cluster, was
+// recorded with another manifest or if ignore is set, in which case it's
+// overwritten by the next step.
+func LoadState(path, cluster, manifestHash string, ignore bool) (*State, error) {
+	state := &State{path: path, Cluster: cluster, ManifestHash: manifestHash}
+	if ignore {
+		return state, nil
+	}
+
+	buf, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read state file")
+	}
+
+	saved := &State{}
+	if err = json.Unmarshal(buf, saved); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse state file %s, use --ignore-state to start over", path)
+	}
+	if saved.Cluster != cluster || saved.ManifestHash != manifestHash {
+		return state, nil
+	}
+
+	saved.path = path
+	return saved, nil
+}
+
+// Completed returns whether the step was completed by a previous run
+func (s *State) Completed(step string) bool {
+	if s == nil {
+		return false
+	}
+
+	for _, completed := range s.CompletedSteps {
+		if completed == step {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Complete records the step as completed along with the join token
+// used by the run
+func (s *State) Complete(step, joinToken string) error {
+	if s == nil {
+		return nil
+	}
+
+	if !s.Completed(step) {
+		s.CompletedSteps = append(s.CompletedSteps, step)
+	}
+	s.JoinToken = joinToken
+
+	buf, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal state")
+	}
+
+	// the join token allows joining the cluster
+	if err = os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return errors.Wrap(err, "failed to create state directory")
+	}
+
+	return errors.Wrap(ioutil.WriteFile(s.path, buf, 0600), "failed to write state file")
+}
+
+// Clear removes the state file once the installation is done, so the
+// next run starts over
+func (s *State) Clear() error {
+	if s == nil {
+		return nil
+	}
+
+	err := os.Remove(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	return errors.Wrap(err, "failed to remove state file")
+}
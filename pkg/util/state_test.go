@@ -0,0 +1,79 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubeone-state")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, ".kubeone", "state.json")
+
+	state, err := LoadState(path, "test", "hash", false)
+	if err != nil {
+		t.Fatalf("failed to load missing state: %v", err)
+	}
+	if err = state.Complete("Installing prerequisites", "abcdef.0123456789abcdef"); err != nil {
+		t.Fatalf("failed to record step: %v", err)
+	}
+
+	state, err = LoadState(path, "test", "hash", false)
+	if err != nil {
+		t.Fatalf("failed to load state: %v", err)
+	}
+	if !state.Completed("Installing prerequisites") || state.Completed("Joining control plane nodes") {
+		t.Errorf("unexpected completed steps %v", state.CompletedSteps)
+	}
+	if state.JoinToken != "abcdef.0123456789abcdef" {
+		t.Errorf("join token wasn't restored, got %q", state.JoinToken)
+	}
+
+	discarded := []struct {
+		name   string
+		hash   string
+		ignore bool
+	}{
+		{name: "other", hash: "hash"},
+		{name: "test", hash: "changed"},
+		{name: "test", hash: "hash", ignore: true},
+	}
+	for _, d := range discarded {
+		state, err = LoadState(path, d.name, d.hash, d.ignore)
+		if err != nil {
+			t.Fatalf("failed to load state: %v", err)
+		}
+		if len(state.CompletedSteps) > 0 {
+			t.Errorf("expected empty state for cluster %q (hash: %s, ignore: %v), got %v", d.name, d.hash, d.ignore, state.CompletedSteps)
+		}
+	}
+
+	if err = state.Clear(); err != nil {
+		t.Fatalf("failed to clear state: %v", err)
+	}
+	if _, err = os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected state file to be removed, got %v", err)
+	}
+}
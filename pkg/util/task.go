@@ -48,34 +48,47 @@ func (c *Context) runTask(node *kubeoneapi.HostConfig, task NodeTask, prefixed b
 	}
 
 	c.Runner = &Runner{
-		Conn:    conn,
-		Verbose: c.Verbose,
-		OS:      node.OperatingSystem,
-		Prefix:  prefix,
+		Conn:     conn,
+		Verbose:  c.Verbose,
+		Debug:    c.Debug,
+		Redactor: c.Redactor,
+		Host:     fmt.Sprintf("%s@%s", node.SSHUsername, node.PublicAddress),
+		OS:       string(node.OperatingSystem),
+		Prefix:   prefix,
 	}
 
 	return task(c, node, conn)
 }
 
 // RunTaskOnNodes runs the given task on the given selection of hosts.
+// When running in parallel, at most c.Parallelism hosts are worked on
+// at once; a Parallelism of zero doesn't limit the concurrency.
 func (c *Context) RunTaskOnNodes(nodes []kubeoneapi.HostConfig, task NodeTask, parallel bool) error {
 	var err error
 
 	wg := sync.WaitGroup{}
 	hasErrors := false
 
+	limit := c.Parallelism
+	if limit < 1 || limit > len(nodes) {
+		limit = len(nodes)
+	}
+	sem := make(chan struct{}, limit)
+
 	for i := range nodes {
 		ctx := c.Clone()
 		ctx.Logger = ctx.Logger.WithField("node", nodes[i].PublicAddress)
 
 		if parallel {
 			wg.Add(1)
+			sem <- struct{}{}
 			go func(ctx *Context, node *kubeoneapi.HostConfig) {
 				err = ctx.runTask(node, task, parallel)
 				if err != nil {
 					ctx.Logger.Error(err)
 					hasErrors = true
 				}
+				<-sem
 				wg.Done()
 			}(ctx, &nodes[i])
 		} else {
@@ -95,9 +108,10 @@ func (c *Context) RunTaskOnNodes(nodes []kubeoneapi.HostConfig, task NodeTask, p
 	return err
 }
 
-// RunTaskOnAllNodes runs the given task on all hosts.
+// RunTaskOnAllNodes runs the given task on all hosts, or only on the
+// target host if one is set.
 func (c *Context) RunTaskOnAllNodes(task NodeTask, parallel bool) error {
-	return c.RunTaskOnNodes(c.Cluster.Hosts, task, parallel)
+	return c.RunTaskOnNodes(c.targetNodes(c.Cluster.Hosts), task, parallel)
 }
 
 // RunTaskOnLeader runs the given task on the leader host.
@@ -114,7 +128,27 @@ func (c *Context) RunTaskOnLeader(task NodeTask) error {
 	return c.RunTaskOnNodes(hosts, task, false)
 }
 
-// RunTaskOnFollowers runs the given task on the follower hosts.
+// RunTaskOnFollowers runs the given task on the follower hosts, or only
+// on the target host if it's a follower.
+// Tasks changing the etcd membership must not run in parallel, as
+// etcd can only safely add or remove one member at a time.
 func (c *Context) RunTaskOnFollowers(task NodeTask, parallel bool) error {
-	return c.RunTaskOnNodes(c.Cluster.Followers(), task, parallel)
+	return c.RunTaskOnNodes(c.targetNodes(c.Cluster.Followers()), task, parallel)
+}
+
+// targetNodes limits the nodes to the target host, if one is set. The
+// returned slice shares the hosts with the given one, so the information
+// the tasks populate, e.g. the hostname, is kept.
+func (c *Context) targetNodes(nodes []kubeoneapi.HostConfig) []kubeoneapi.HostConfig {
+	if c.TargetHost == "" {
+		return nodes
+	}
+
+	for i := range nodes {
+		if nodes[i].PublicAddress == c.TargetHost {
+			return nodes[i : i+1]
+		}
+	}
+
+	return nil
 }
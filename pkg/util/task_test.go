@@ -0,0 +1,79 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	kubeoneapi "github.com/kubermatic/kubeone/pkg/apis/kubeone"
+)
+
+func TestTargetNodes(t *testing.T) {
+	hosts := []kubeoneapi.HostConfig{
+		{PublicAddress: "192.0.2.1"},
+		{PublicAddress: "192.0.2.2"},
+		{PublicAddress: "192.0.2.3"},
+	}
+
+	tests := []struct {
+		name       string
+		targetHost string
+		expected   []string
+	}{
+		{
+			name:     "no target host",
+			expected: []string{"192.0.2.1", "192.0.2.2", "192.0.2.3"},
+		},
+		{
+			name:       "target host",
+			targetHost: "192.0.2.2",
+			expected:   []string{"192.0.2.2"},
+		},
+		{
+			name:       "target host not in the nodes",
+			targetHost: "192.0.2.4",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := &Context{TargetHost: tc.targetHost}
+			nodes := ctx.targetNodes(hosts)
+
+			var addresses []string
+			for _, n := range nodes {
+				addresses = append(addresses, n.PublicAddress)
+			}
+			if len(addresses) != len(tc.expected) {
+				t.Fatalf("expected nodes %v, got %v", tc.expected, addresses)
+			}
+			for i := range addresses {
+				if addresses[i] != tc.expected[i] {
+					t.Fatalf("expected nodes %v, got %v", tc.expected, addresses)
+				}
+			}
+		})
+	}
+
+	// tasks populate the hosts through the returned nodes
+	ctx := &Context{TargetHost: "192.0.2.3"}
+	ctx.targetNodes(hosts)[0].Hostname = "node-3"
+	if hosts[2].Hostname != "node-3" {
+		t.Errorf("expected the target node to share the host, got hostname %q", hosts[2].Hostname)
+	}
+}
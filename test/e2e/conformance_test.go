@@ -27,17 +27,24 @@ import (
 	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+type conformanceTestCase struct {
+	name                  string
+	provider              string
+	kubernetesVersion     string
+	scenario              string
+	configFilePath        string
+	expectedNumberOfNodes int
+	// terraformVars are passed to the terraform code, only supported on AWS
+	terraformVars map[string]string
+	// controlPlaneOSImage is the operating system the control plane nodes
+	// are expected to run, e.g. "Ubuntu 22.04"
+	controlPlaneOSImage string
+}
+
 func TestClusterConformance(t *testing.T) {
 	t.Parallel()
 
-	testcases := []struct {
-		name                  string
-		provider              string
-		kubernetesVersion     string
-		scenario              string
-		configFilePath        string
-		expectedNumberOfNodes int
-	}{
+	testcases := []conformanceTestCase{
 		{
 			name:                  "verify k8s 1.13.5 cluster deployment on AWS",
 			provider:              AWS,
@@ -88,6 +95,101 @@ func TestClusterConformance(t *testing.T) {
 		},
 	}
 
+	runClusterConformance(t, testcases)
+}
+
+// TestCRIOClusterConformance runs the conformance tests against a cluster
+// using CRI-O as the container runtime on the control plane nodes
+func TestCRIOClusterConformance(t *testing.T) {
+	t.Parallel()
+
+	testcases := []conformanceTestCase{
+		{
+			name:                  "verify k8s 1.14.1 cluster deployment with CRI-O on AWS",
+			provider:              AWS,
+			kubernetesVersion:     "v1.14.1",
+			scenario:              NodeConformance,
+			configFilePath:        "../../test/e2e/testdata/config_aws_1.14.1_crio.yaml",
+			expectedNumberOfNodes: 6, // 3 control planes + 3 workers
+		},
+	}
+
+	runClusterConformance(t, testcases)
+}
+
+// TestUbuntu2204ClusterConformance runs the conformance tests against a
+// cluster with the control plane nodes running Ubuntu 22.04. Ubuntu 22.04
+// boots with cgroup v2, which kubelet 1.14 doesn't support, so KubeOne
+// switches the nodes to cgroup v1 and reboots them before installing
+// Kubernetes.
+func TestUbuntu2204ClusterConformance(t *testing.T) {
+	t.Parallel()
+
+	testcases := []conformanceTestCase{
+		{
+			name:                  "verify k8s 1.14.1 cluster deployment on Ubuntu 22.04 on AWS",
+			provider:              AWS,
+			kubernetesVersion:     "v1.14.1",
+			scenario:              NodeConformance,
+			configFilePath:        "../../test/e2e/testdata/config_aws_1.14.1.yaml",
+			expectedNumberOfNodes: 6, // 3 control planes + 3 workers
+			terraformVars: map[string]string{
+				"ubuntu_release": "jammy-22.04",
+			},
+			controlPlaneOSImage: "Ubuntu 22.04",
+		},
+	}
+
+	runClusterConformance(t, testcases)
+}
+
+// TestAmazonLinuxClusterConformance runs the conformance tests against a
+// cluster with the control plane nodes running Amazon Linux 2
+func TestAmazonLinuxClusterConformance(t *testing.T) {
+	t.Parallel()
+
+	testcases := []conformanceTestCase{
+		{
+			name:                  "verify k8s 1.14.1 cluster deployment on Amazon Linux 2 on AWS",
+			provider:              AWS,
+			kubernetesVersion:     "v1.14.1",
+			scenario:              NodeConformance,
+			configFilePath:        "../../test/e2e/testdata/config_aws_1.14.1.yaml",
+			expectedNumberOfNodes: 6, // 3 control planes + 3 workers
+			terraformVars: map[string]string{
+				"os":           "amzn2",
+				"ssh_username": "ec2-user",
+			},
+		},
+	}
+
+	runClusterConformance(t, testcases)
+}
+
+// TestFlatcarClusterConformance runs the conformance tests against a
+// cluster with the control plane nodes running Flatcar Container Linux
+func TestFlatcarClusterConformance(t *testing.T) {
+	t.Parallel()
+
+	testcases := []conformanceTestCase{
+		{
+			name:                  "verify k8s 1.14.1 cluster deployment on Flatcar on AWS",
+			provider:              AWS,
+			kubernetesVersion:     "v1.14.1",
+			scenario:              NodeConformance,
+			configFilePath:        "../../test/e2e/testdata/config_aws_1.14.1_flatcar.yaml",
+			expectedNumberOfNodes: 6, // 3 control planes + 3 workers
+			terraformVars: map[string]string{
+				"os":           "flatcar",
+				"ssh_username": "core",
+			},
+		},
+	}
+
+	runClusterConformance(t, testcases)
+}
+
+func runClusterConformance(t *testing.T, testcases []conformanceTestCase) {
 	for _, tc := range testcases {
 		// to satisfy scope linter
 		tc := tc
@@ -107,6 +209,13 @@ func TestClusterConformance(t *testing.T) {
 			if err != nil {
 				t.Fatal(err)
 			}
+			if len(tc.terraformVars) > 0 {
+				awsProvisioner, ok := pr.(*AWSProvisioner)
+				if !ok {
+					t.Fatalf("terraform variables are not supported for %s", tc.provider)
+				}
+				awsProvisioner.SetTerraformVars(tc.terraformVars)
+			}
 			target := NewKubeone(testPath, tc.configFilePath)
 			clusterVerifier := NewKubetest(tc.kubernetesVersion, "../../_build", map[string]string{
 				"KUBERNETES_CONFORMANCE_TEST": "y",
@@ -162,6 +271,14 @@ func TestClusterConformance(t *testing.T) {
 				t.Fatalf("version mismatch: %v", err)
 			}
 
+			if tc.controlPlaneOSImage != "" {
+				t.Log("verifying control plane operating system")
+				err = verifyControlPlaneOSImage(client, tc.controlPlaneOSImage)
+				if err != nil {
+					t.Fatalf("operating system mismatch: %v", err)
+				}
+			}
+
 			t.Log("run e2e tests")
 			err = clusterVerifier.Verify(tc.scenario)
 			if err != nil {
@@ -18,6 +18,7 @@ package e2e
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -37,11 +38,46 @@ func CreateProvisioner(testPath string, identifier string, provider string) (Pro
 		return NewDOProvisioner(testPath, identifier)
 	case Hetzner:
 		return NewHetznerProvisioner(testPath, identifier)
+	case AliCloud:
+		return NewAliCloudProvisioner(testPath, identifier)
+	case OVH:
+		return NewOVHProvisioner(testPath, identifier)
 	default:
 		return nil, fmt.Errorf("unsuported provider %v", provider)
 	}
 }
 
+// CreateIsolatedProvisioner returns a provisioner running terraform in a copy
+// of the provider's example code inside testPath, so clusters of the same
+// provider can be provisioned in parallel
+func CreateIsolatedProvisioner(testPath, identifier, provider string, vars map[string]string) (Provisioner, error) {
+	pr, err := CreateProvisioner(testPath, identifier, provider)
+	if err != nil {
+		return nil, err
+	}
+
+	var tf *terraform
+	switch p := pr.(type) {
+	case *AWSProvisioner:
+		tf = p.terraform
+	case *DOProvisioner:
+		tf = p.terraform
+	case *HetznerProvisioner:
+		tf = p.terraform
+	case *AliCloudProvisioner:
+		tf = p.terraform
+	case *OVHProvisioner:
+		tf = p.terraform
+	}
+
+	if err = tf.isolate(path.Join(testPath, "terraform")); err != nil {
+		return nil, err
+	}
+	tf.vars = vars
+
+	return pr, nil
+}
+
 // IsCommandAvailable checks if command is available OS
 func IsCommandAvailable(name string) bool {
 	path, err := exec.LookPath(name)
@@ -55,12 +91,29 @@ func IsCommandAvailable(name string) bool {
 	return false
 }
 
+// commandError describes a command which exited unsuccessfully
+type commandError struct {
+	err      error
+	exitCode int
+	stderr   string
+}
+
+func (e *commandError) Error() string {
+	return e.err.Error()
+}
+
 // executeCommand executes given command
 func executeCommand(path, name string, arg []string, additionalEnv map[string]string) (string, error) {
+	return executeCommandWithContext(context.Background(), path, name, arg, additionalEnv)
+}
+
+// executeCommandWithContext executes given command, the process is killed
+// once the given context is done
+func executeCommandWithContext(ctx context.Context, path, name string, arg []string, additionalEnv map[string]string) (string, error) {
 	var stdoutBuf, stderrBuf bytes.Buffer
 	var errStdout, errStderr error
 
-	cmd := exec.Command(name, arg...)
+	cmd := exec.CommandContext(ctx, name, arg...)
 	if len(path) > 0 {
 		cmd.Dir = path
 	}
@@ -100,7 +153,11 @@ func executeCommand(path, name string, arg []string, additionalEnv map[string]st
 	<-doneStderr
 	err = cmd.Wait()
 	if err != nil {
-		return "", err
+		exitCode := -1
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			exitCode = exitErr.ExitCode()
+		}
+		return "", &commandError{err: err, exitCode: exitCode, stderr: stderrBuf.String()}
 	}
 	if errStdout != nil {
 		return "", errStdout
@@ -39,11 +39,11 @@ func NewKubeone(kubeoneDir, configurationFile string) Kubeone {
 }
 
 // Install starts k8s cluster deployment
-func (p *Kubeone) Install(tfJSON string) error {
+func (p *Kubeone) Install(tf *TerraformOutput) error {
 	// deliberate delay, to give nodes time to start
 	time.Sleep(2 * time.Minute)
 
-	err := p.storeTFJson(tfJSON)
+	err := p.storeTFJson(tf)
 	if err != nil {
 		return err
 	}
@@ -89,10 +89,11 @@ func (p *Kubeone) Reset() error {
 	return nil
 }
 
-// StoreTFJson saves tf.json in temporary test directory
-func (p *Kubeone) storeTFJson(tfJSON string) error {
+// StoreTFJson saves the raw terraform output as tf.json in temporary test
+// directory, so KubeOne parses the same output as it would outside the tests
+func (p *Kubeone) storeTFJson(tf *TerraformOutput) error {
 	tfJSONPath := fmt.Sprintf("%s/tf.json", p.KubeoneDir)
-	err := CreateFile(tfJSONPath, tfJSON)
+	err := CreateFile(tfJSONPath, string(tf.Raw))
 	if err != nil {
 		return fmt.Errorf("saving tf.json for given path %s failed: %v", tfJSONPath, err)
 	}
@@ -0,0 +1,192 @@
+// +build e2e
+
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kubermatic/kubeone/pkg/templates/machinecontroller"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	clusterv1alpha1 "sigs.k8s.io/cluster-api/pkg/apis/cluster/v1alpha1"
+	clusterscheme "sigs.k8s.io/cluster-api/pkg/client/clientset_generated/clientset/scheme"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestMachineSetReady(t *testing.T) {
+	t.Parallel()
+
+	testcases := []struct {
+		name                  string
+		provider              string
+		kubernetesVersion     string
+		configFilePath        string
+		expectedNumberOfNodes int
+	}{
+		{
+			name:                  "verify MachineSet readiness on AWS",
+			provider:              AWS,
+			kubernetesVersion:     "v1.14.1",
+			configFilePath:        "../../test/e2e/testdata/config_aws_1.14.1.yaml",
+			expectedNumberOfNodes: 6, // 3 control planes + 3 workers
+		},
+		{
+			name:                  "verify MachineSet readiness on DO",
+			provider:              DigitalOcean,
+			kubernetesVersion:     "v1.14.1",
+			configFilePath:        "../../test/e2e/testdata/config_do_1.14.1.yaml",
+			expectedNumberOfNodes: 6, // 3 control planes + 3 workers
+		},
+		{
+			name:                  "verify MachineSet readiness on Hetzner",
+			provider:              Hetzner,
+			kubernetesVersion:     "v1.14.1",
+			configFilePath:        "../../test/e2e/testdata/config_hetzner_1.14.1.yaml",
+			expectedNumberOfNodes: 6, // 3 control planes + 3 workers
+		},
+	}
+
+	for _, tc := range testcases {
+		// to satisfy scope linter
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if len(testRunIdentifier) == 0 {
+				t.Fatalf("-identifier must be set")
+			}
+			if testProvider != tc.provider {
+				t.SkipNow()
+			}
+			if testClusterVersion != tc.kubernetesVersion {
+				t.SkipNow()
+			}
+			testPath := fmt.Sprintf("../../_build/%s", testRunIdentifier)
+
+			pr, err := CreateProvisioner(testPath, testRunIdentifier, tc.provider)
+			if err != nil {
+				t.Fatal(err)
+			}
+			target := NewKubeone(testPath, tc.configFilePath)
+
+			t.Log("check prerequisites")
+			err = ValidateCommon()
+			if err != nil {
+				t.Fatalf("%v", err)
+			}
+
+			teardown := setupTearDown(pr, target)
+			defer teardown(t)
+
+			t.Log("start provisioning")
+			tf, err := pr.Provision()
+			if err != nil {
+				t.Fatalf("provisioning failed: %v", err)
+			}
+
+			t.Log("start cluster deployment")
+			err = target.Install(tf)
+			if err != nil {
+				t.Fatalf("k8s cluster deployment failed: %v", err)
+			}
+
+			t.Log("create kubeconfig")
+			kubeconfig, err := target.CreateKubeconfig()
+			if err != nil {
+				t.Fatalf("creating kubeconfig failed: %v", err)
+			}
+
+			t.Log("build kubernetes clientset")
+			restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+			if err != nil {
+				t.Fatalf("unable to build config from kubeconfig bytes: %v", err)
+			}
+
+			if err = clusterscheme.AddToScheme(scheme.Scheme); err != nil {
+				t.Fatalf("failed to register cluster-api scheme: %v", err)
+			}
+
+			client, err := dynclient.New(restConfig, dynclient.Options{})
+			if err != nil {
+				t.Fatalf("failed to init dynamic client: %s", err)
+			}
+
+			t.Log("waiting for nodes to become ready")
+			err = waitForNodesReady(client, tc.expectedNumberOfNodes)
+			if err != nil {
+				t.Fatalf("nodes are not ready: %v", err)
+			}
+
+			t.Log("create MachineSet")
+			machineSet, err := createMachineSet(client, testRunIdentifier)
+			if err != nil {
+				t.Fatalf("creating MachineSet failed: %v", err)
+			}
+
+			t.Log("waiting for MachineSet to become ready")
+			err = machinecontroller.WaitForMachineSetReady(context.Background(), client, machineSet.Namespace, machineSet.Name, 15*time.Minute)
+			if err != nil {
+				t.Fatalf("MachineSet is not ready: %v", err)
+			}
+		})
+	}
+}
+
+// createMachineSet creates a single replica MachineSet using the machine
+// template of the first MachineDeployment deployed by KubeOne
+func createMachineSet(client dynclient.Client, identifier string) (*clusterv1alpha1.MachineSet, error) {
+	ctx := context.Background()
+
+	machineDeployments := clusterv1alpha1.MachineDeploymentList{}
+	listOpts := &dynclient.ListOptions{Namespace: metav1.NamespaceSystem}
+	if err := client.List(ctx, listOpts, &machineDeployments); err != nil {
+		return nil, fmt.Errorf("unable to list MachineDeployments: %v", err)
+	}
+	if len(machineDeployments.Items) == 0 {
+		return nil, fmt.Errorf("no MachineDeployments found")
+	}
+
+	name := fmt.Sprintf("%s-e2e-machineset", identifier)
+	selector := map[string]string{"e2e-machineset": name}
+
+	template := machineDeployments.Items[0].Spec.Template.DeepCopy()
+	template.Labels = selector
+
+	var replicas int32 = 1
+	machineSet := &clusterv1alpha1.MachineSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: metav1.NamespaceSystem,
+		},
+		Spec: clusterv1alpha1.MachineSetSpec{
+			Replicas: &replicas,
+			Selector: metav1.LabelSelector{MatchLabels: selector},
+			Template: *template,
+		},
+	}
+
+	if err := client.Create(ctx, machineSet); err != nil {
+		return nil, fmt.Errorf("unable to create MachineSet: %v", err)
+	}
+
+	return machineSet, nil
+}
@@ -39,12 +39,14 @@ var (
 	testRunIdentifier  string
 	testClusterVersion string
 	testProvider       string
+	testMatrixFile     string
 )
 
 func init() {
 	flag.StringVar(&testRunIdentifier, "identifier", "", "The unique identifier for this test run")
 	flag.StringVar(&testClusterVersion, "cluster-version", "", "Cluster version to run tests for")
 	flag.StringVar(&testProvider, "provider", "", "Provider to run tests on")
+	flag.StringVar(&testMatrixFile, "test-matrix", "", "Test matrix file listing the combinations run by TestClusterMatrix")
 	flag.Parse()
 }
 
@@ -135,6 +137,26 @@ func verifyVersion(client dynclient.Client, namespace string, targetVersion stri
 	return nil
 }
 
+// verifyControlPlaneOSImage checks that the control plane nodes run the
+// expected operating system, e.g. "Ubuntu 22.04"
+func verifyControlPlaneOSImage(client dynclient.Client, osImage string) error {
+	nodes := corev1.NodeList{}
+	nodeListOpts := dynclient.ListOptions{}
+	_ = nodeListOpts.SetLabelSelector(fmt.Sprintf("%s=%s", labelControlPlaneNode, ""))
+	err := client.List(context.Background(), &nodeListOpts, &nodes)
+	if err != nil {
+		return errors.Wrap(err, "failed to list nodes")
+	}
+
+	for _, n := range nodes.Items {
+		if !strings.HasPrefix(n.Status.NodeInfo.OSImage, osImage) {
+			return errors.Errorf("node %s runs %q, expected %s", n.Name, n.Status.NodeInfo.OSImage, osImage)
+		}
+	}
+
+	return nil
+}
+
 func parseContainerImageVersion(image string) (*semver.Version, error) {
 	ver := strings.Split(image, ":")
 	if len(ver) != 2 {
@@ -0,0 +1,185 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+// configFilePrefixes are the prefixes of the testdata manifests per provider
+var configFilePrefixes = map[string]string{
+	AWS:          "aws",
+	DigitalOcean: "do",
+	Hetzner:      "hetzner",
+}
+
+// sshUsernames are the users used to log in to the AWS instances per
+// operating system, other providers only support Ubuntu
+var sshUsernames = map[string]string{
+	"ubuntu":  "ubuntu",
+	"amzn2":   "ec2-user",
+	"flatcar": "core",
+}
+
+// TestMatrix lists the combinations run by TestClusterMatrix
+type TestMatrix struct {
+	Combinations []MatrixCombination `json:"combinations"`
+}
+
+// MatrixCombination is a single cluster tested by TestClusterMatrix
+type MatrixCombination struct {
+	Provider          string `json:"provider"`
+	OS                string `json:"os"`
+	KubernetesVersion string `json:"kubernetesVersion"`
+	CNI               string `json:"cni"`
+}
+
+// LoadTestMatrix reads and validates the test matrix file
+func LoadTestMatrix(path string) (*TestMatrix, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read the test matrix")
+	}
+
+	m := &TestMatrix{}
+	if err = yaml.Unmarshal(b, m); err != nil {
+		return nil, errors.Wrap(err, "unable to parse the test matrix")
+	}
+
+	for i := range m.Combinations {
+		c := &m.Combinations[i]
+		if c.OS == "" {
+			c.OS = "ubuntu"
+		}
+		if c.CNI == "" {
+			c.CNI = "canal"
+		}
+		if err = c.validate(); err != nil {
+			return nil, errors.Wrapf(err, "invalid combination %d", i)
+		}
+	}
+
+	return m, nil
+}
+
+func (c MatrixCombination) validate() error {
+	if _, ok := configFilePrefixes[c.Provider]; !ok {
+		return errors.Errorf("unsupported provider %q", c.Provider)
+	}
+	if _, ok := sshUsernames[c.OS]; !ok {
+		return errors.Errorf("unsupported operating system %q", c.OS)
+	}
+	if c.Provider != AWS && c.OS != "ubuntu" {
+		return errors.Errorf("operating system %q is only supported on %s", c.OS, AWS)
+	}
+	if !strings.HasPrefix(c.KubernetesVersion, "v") {
+		return errors.Errorf("kubernetes version %q must start with v", c.KubernetesVersion)
+	}
+	return nil
+}
+
+// Name identifies the combination in the test results
+func (c MatrixCombination) Name() string {
+	return fmt.Sprintf("%s-%s-%s-%s", c.Provider, c.OS, c.KubernetesVersion, c.CNI)
+}
+
+// TerraformVars returns the variables passed to the terraform code of the
+// provider. The cluster name must be unique, as combinations of the same
+// provider run in parallel.
+func (c MatrixCombination) TerraformVars(clusterName string) map[string]string {
+	vars := map[string]string{
+		"cluster_name": clusterName,
+	}
+	if c.Provider == AWS {
+		vars["os"] = c.OS
+		vars["ssh_username"] = sshUsernames[c.OS]
+	}
+	return vars
+}
+
+// WriteManifest writes the KubeOne manifest of the combination to dir, based
+// on the testdata manifest of the provider and Kubernetes version
+func (c MatrixCombination) WriteManifest(dir string) (string, error) {
+	base := fmt.Sprintf("../../test/e2e/testdata/config_%s_%s.yaml",
+		configFilePrefixes[c.Provider], strings.TrimPrefix(c.KubernetesVersion, "v"))
+
+	b, err := ioutil.ReadFile(base)
+	if err != nil {
+		return "", errors.Wrapf(err, "no manifest for %s", c.Name())
+	}
+
+	manifest := map[string]interface{}{}
+	if err = yaml.Unmarshal(b, &manifest); err != nil {
+		return "", errors.Wrapf(err, "unable to parse %s", base)
+	}
+
+	manifest["clusterNetwork"] = map[string]interface{}{
+		"cni": map[string]interface{}{
+			"provider": c.CNI,
+		},
+	}
+
+	b, err = yaml.Marshal(manifest)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to encode the manifest")
+	}
+
+	path := filepath.Join(dir, "config.yaml")
+	return path, CreateFile(path, string(b))
+}
+
+// matrixResults collects the results of the combinations running in parallel
+type matrixResults struct {
+	lock    sync.Mutex
+	results map[string]string
+}
+
+func (r *matrixResults) record(name, result string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.results == nil {
+		r.results = map[string]string{}
+	}
+	r.results[name] = result
+}
+
+// String formats the results, one combination per line
+func (r *matrixResults) String() string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	names := make([]string, 0, len(r.results))
+	for name := range r.results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		lines = append(lines, fmt.Sprintf("%-40s %s", name, r.results[name]))
+	}
+	return strings.Join(lines, "\n")
+}
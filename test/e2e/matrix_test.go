@@ -0,0 +1,164 @@
+// +build e2e
+
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// TestClusterMatrix runs the conformance tests against every combination of
+// the test matrix given by -test-matrix. Each combination provisions its own
+// cluster and all combinations run in parallel. The -provider and
+// -cluster-version flags limit the combinations that are run.
+func TestClusterMatrix(t *testing.T) {
+	if len(testMatrixFile) == 0 {
+		t.Skip("-test-matrix is not set")
+	}
+	if len(testRunIdentifier) == 0 {
+		t.Fatalf("-identifier must be set")
+	}
+
+	matrix, err := LoadTestMatrix(testMatrixFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := &matrixResults{}
+
+	// the group only returns once all parallel combinations are done
+	t.Run("matrix", func(t *testing.T) {
+		for i, c := range matrix.Combinations {
+			// to satisfy scope linter
+			i, c := i, c
+			t.Run(c.Name(), func(t *testing.T) {
+				if (len(testProvider) > 0 && testProvider != c.Provider) ||
+					(len(testClusterVersion) > 0 && testClusterVersion != c.KubernetesVersion) {
+					results.record(c.Name(), "skipped")
+					t.SkipNow()
+				}
+				t.Parallel()
+
+				defer func() {
+					result := "passed"
+					if t.Failed() {
+						result = "failed"
+					}
+					results.record(c.Name(), result)
+				}()
+
+				runMatrixCombination(t, c, fmt.Sprintf("%s-%d", testRunIdentifier, i))
+			})
+		}
+	})
+
+	t.Logf("test matrix results:\n%s", results)
+}
+
+func runMatrixCombination(t *testing.T, c MatrixCombination, identifier string) {
+	testPath, err := filepath.Abs(fmt.Sprintf("../../_build/%s", identifier))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pr, err := CreateIsolatedProvisioner(testPath, identifier, c.Provider, c.TerraformVars(identifier))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configFilePath, err := c.WriteManifest(testPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	target := NewKubeone(testPath, configFilePath)
+
+	kubeconfigPath := filepath.Join(testPath, "kubeconfig")
+	clusterVerifier := NewKubetest(c.KubernetesVersion, "../../_build", map[string]string{
+		"KUBERNETES_CONFORMANCE_TEST": "y",
+		"KUBECONFIG":                  kubeconfigPath,
+	})
+
+	t.Log("check prerequisites")
+	err = ValidateCommon()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	teardown := setupTearDown(pr, target)
+	defer teardown(t)
+
+	t.Log("start provisioning")
+	tf, err := pr.Provision()
+	if err != nil {
+		t.Fatalf("provisioning failed: %v", err)
+	}
+
+	t.Log("start cluster deployment")
+	err = target.Install(tf)
+	if err != nil {
+		t.Fatalf("k8s cluster deployment failed: %v", err)
+	}
+
+	t.Log("create kubeconfig")
+	kubeconfig, err := target.CreateKubeconfig()
+	if err != nil {
+		t.Fatalf("creating kubeconfig failed: %v", err)
+	}
+
+	// the default kubeconfig is shared by all combinations
+	err = CreateFile(kubeconfigPath, string(kubeconfig))
+	if err != nil {
+		t.Fatalf("saving kubeconfig failed: %v", err)
+	}
+
+	t.Log("build kubernetes clientset")
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		t.Fatalf("unable to build config from kubeconfig bytes: %v", err)
+	}
+
+	client, err := dynclient.New(restConfig, dynclient.Options{})
+	if err != nil {
+		t.Fatalf("failed to init dynamic client: %s", err)
+	}
+
+	t.Log("waiting for nodes to become ready")
+	err = waitForNodesReady(client, 6) // 3 control planes + 3 workers
+	if err != nil {
+		t.Fatalf("nodes are not ready: %v", err)
+	}
+
+	t.Log("verifying cluster version")
+	err = verifyVersion(client, metav1.NamespaceSystem, c.KubernetesVersion)
+	if err != nil {
+		t.Fatalf("version mismatch: %v", err)
+	}
+
+	t.Log("run e2e tests")
+	err = clusterVerifier.Verify(NodeConformance)
+	if err != nil {
+		t.Fatalf("e2e tests failed: %v", err)
+	}
+}
@@ -17,9 +17,15 @@ limitations under the License.
 package e2e
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
+	"time"
+
+	tfconfig "github.com/kubermatic/kubeone/pkg/terraform"
 )
 
 const (
@@ -29,22 +35,124 @@ const (
 	DigitalOcean = "digitalocean"
 	// Hetzner cloud provider
 	Hetzner = "hetzner"
+	// AliCloud cloud provider
+	AliCloud = "alicloud"
+	// OVH cloud provider
+	OVH = "ovh"
 
 	tfStateFileName = "terraform.tfstate"
+
+	// defaultDestroyTimeout is how long terraform destroy is allowed to run
+	// before the terraform process is killed
+	defaultDestroyTimeout = 15 * time.Minute
+)
+
+// Provisioner phases reported by ProvisionerError
+const (
+	PhaseInit    = "init"
+	PhaseApply   = "apply"
+	PhaseOutput  = "output"
+	PhaseDestroy = "destroy"
 )
 
+// ProvisionerError describes a failure of the provisioner, callers can
+// type-assert it to take provider specific recovery actions
+type ProvisionerError struct {
+	// Provider is the cloud provider the provisioner is running for
+	Provider string
+	// Phase is the terraform phase that failed
+	Phase string
+	// ExitCode is the exit code of the terraform command, -1 if the
+	// failure didn't come from the terraform command
+	ExitCode int
+	// Stderr is the standard error output of the terraform command
+	Stderr string
+	// Err is the underlying error
+	Err error
+}
+
+func (e *ProvisionerError) Error() string {
+	return fmt.Sprintf("%s provisioner failed in %s phase (exit code %d): %v", e.Provider, e.Phase, e.ExitCode, e.Err)
+}
+
 // Provisioner provisions and cleanups the cluster
 type Provisioner interface {
-	Provision() (string, error)
+	Provision() (*TerraformOutput, error)
 	Cleanup() error
 }
 
 // terraform structure
 type terraform struct {
+	// provider is the cloud provider the terraform code is for
+	provider string
 	// terraformDir the path to where your terraform code is located
 	terraformDir string
 	// identifier aka. the build number, a unique identifier for the test run.
 	idendifier string
+	// destroyTimeout is how long terraform destroy is allowed to run
+	destroyTimeout time.Duration
+	// backendConfig is passed to terraform init as --backend-config flags
+	backendConfig map[string]string
+	// vars are passed to terraform apply and destroy as -var flags
+	vars map[string]string
+	// WorkspaceName is the terraform workspace used for the test run, the
+	// default workspace is used if empty
+	WorkspaceName string
+	// runner executes the terraform commands, the commands are executed
+	// on the local machine if nil
+	runner terraformRunner
+}
+
+// terraformRunner executes commands, tests replace it to simulate terraform
+// failures
+type terraformRunner interface {
+	// ExecuteCommand runs cmd in dir with the given environment variables,
+	// formatted as key=value, added to the current environment. The command
+	// is killed once ctx is done.
+	ExecuteCommand(ctx context.Context, dir, cmd string, args []string, env []string) (string, error)
+}
+
+// execRunner executes the commands on the local machine
+type execRunner struct{}
+
+// ExecuteCommand executes the command, returning a *commandError if it exits
+// unsuccessfully
+func (execRunner) ExecuteCommand(ctx context.Context, dir, cmd string, args []string, env []string) (string, error) {
+	var additionalEnv map[string]string
+	if len(env) > 0 {
+		additionalEnv = make(map[string]string, len(env))
+		for _, e := range env {
+			kv := strings.SplitN(e, "=", 2)
+			if len(kv) == 2 {
+				additionalEnv[kv[0]] = kv[1]
+			}
+		}
+	}
+
+	return executeCommandWithContext(ctx, dir, cmd, args, additionalEnv)
+}
+
+// newTerraform creates and initialize the terraform structure for the
+// provider's example terraform code
+func newTerraform(provider, identifier string) (*terraform, error) {
+	destroyTimeout, err := terraformDestroyTimeout()
+	if err != nil {
+		return nil, err
+	}
+
+	backendConfig, err := terraformBackendConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return &terraform{
+		provider:       provider,
+		terraformDir:   fmt.Sprintf("../../examples/terraform/%s/", provider),
+		idendifier:     identifier,
+		destroyTimeout: destroyTimeout,
+		backendConfig:  backendConfig,
+		WorkspaceName:  os.Getenv("TERRAFORM_WORKSPACE"),
+	}, nil
 }
 
 // AWSProvisioner describes AWS provisioner
@@ -55,9 +163,9 @@ type AWSProvisioner struct {
 
 // NewAWSProvisioner creates and initialize AWSProvisioner structure
 func NewAWSProvisioner(testPath, identifier string) (*AWSProvisioner, error) {
-	terraform := &terraform{
-		terraformDir: "../../examples/terraform/aws/",
-		idendifier:   identifier,
+	terraform, err := newTerraform(AWS, identifier)
+	if err != nil {
+		return nil, err
 	}
 
 	return &AWSProvisioner{
@@ -66,17 +174,23 @@ func NewAWSProvisioner(testPath, identifier string) (*AWSProvisioner, error) {
 	}, nil
 }
 
+// SetTerraformVars sets the variables of the terraform code, e.g. to pick
+// the operating system of the instances
+func (p *AWSProvisioner) SetTerraformVars(vars map[string]string) {
+	p.terraform.vars = vars
+}
+
 // Provision starts provisioning on AWS
-func (p *AWSProvisioner) Provision() (string, error) {
+func (p *AWSProvisioner) Provision() (*TerraformOutput, error) {
 	awsKeyID := os.Getenv("AWS_ACCESS_KEY_ID")
 	awsSecret := os.Getenv("AWS_SECRET_ACCESS_KEY")
 	if len(awsKeyID) == 0 || len(awsSecret) == 0 {
-		return "", errors.New("unable to run the test suite, AWS_ACCESS_KEY_ID or AWS_SECRET_ACCESS_KEY environment variables cannot be empty")
+		return nil, errors.New("unable to run the test suite, AWS_ACCESS_KEY_ID or AWS_SECRET_ACCESS_KEY environment variables cannot be empty")
 	}
 
 	tf, err := p.terraform.initAndApply()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	return tf, nil
@@ -84,17 +198,12 @@ func (p *AWSProvisioner) Provision() (string, error) {
 
 // Cleanup destroys infrastructure created by terraform
 func (p *AWSProvisioner) Cleanup() error {
-	err := p.terraform.destroy()
-	if err != nil {
-		return fmt.Errorf("%v", err)
-	}
-
-	_, err = executeCommand("", "rm", []string{"-rf", p.testPath}, nil)
-	if err != nil {
-		return fmt.Errorf("%v", err)
+	if err := p.terraform.destroy(); err != nil {
+		return err
 	}
 
-	return nil
+	_, err := executeCommand("", "rm", []string{"-rf", p.testPath}, nil)
+	return err
 }
 
 // DOProvisioner describes DigitalOcean provisioner
@@ -105,9 +214,9 @@ type DOProvisioner struct {
 
 // NewDOProvisioner creates and initialize DOProvisioner structure
 func NewDOProvisioner(testPath, identifier string) (*DOProvisioner, error) {
-	terraform := &terraform{
-		terraformDir: "../../examples/terraform/digitalocean/",
-		idendifier:   identifier,
+	terraform, err := newTerraform(DigitalOcean, identifier)
+	if err != nil {
+		return nil, err
 	}
 
 	return &DOProvisioner{
@@ -117,15 +226,15 @@ func NewDOProvisioner(testPath, identifier string) (*DOProvisioner, error) {
 }
 
 // Provision starts provisioning on DigitalOcean
-func (p *DOProvisioner) Provision() (string, error) {
+func (p *DOProvisioner) Provision() (*TerraformOutput, error) {
 	doToken := os.Getenv("DIGITALOCEAN_TOKEN")
 	if len(doToken) == 0 {
-		return "", errors.New("unable to run the test suite, DIGITALOCEAN_TOKEN environment variable cannot be empty")
+		return nil, errors.New("unable to run the test suite, DIGITALOCEAN_TOKEN environment variable cannot be empty")
 	}
 
 	tf, err := p.terraform.initAndApply()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	return tf, nil
@@ -133,17 +242,12 @@ func (p *DOProvisioner) Provision() (string, error) {
 
 // Cleanup destroys infrastructure created by terraform
 func (p *DOProvisioner) Cleanup() error {
-	err := p.terraform.destroy()
-	if err != nil {
-		return fmt.Errorf("%v", err)
+	if err := p.terraform.destroy(); err != nil {
+		return err
 	}
 
-	_, err = executeCommand("", "rm", []string{"-rf", p.testPath}, nil)
-	if err != nil {
-		return fmt.Errorf("%v", err)
-	}
-
-	return nil
+	_, err := executeCommand("", "rm", []string{"-rf", p.testPath}, nil)
+	return err
 }
 
 // HetznerProvisioner describes the Hetzner provisioner
@@ -154,9 +258,9 @@ type HetznerProvisioner struct {
 
 // NewHetznerProvisioner creates and initialize the HetznerProvisioner structure
 func NewHetznerProvisioner(testPath, identifier string) (*HetznerProvisioner, error) {
-	terraform := &terraform{
-		terraformDir: "../../examples/terraform/hetzner/",
-		idendifier:   identifier,
+	terraform, err := newTerraform(Hetzner, identifier)
+	if err != nil {
+		return nil, err
 	}
 
 	return &HetznerProvisioner{
@@ -166,15 +270,15 @@ func NewHetznerProvisioner(testPath, identifier string) (*HetznerProvisioner, er
 }
 
 // Provision starts provisioning on Hetzner
-func (p *HetznerProvisioner) Provision() (string, error) {
+func (p *HetznerProvisioner) Provision() (*TerraformOutput, error) {
 	hcloudToken := os.Getenv("HCLOUD_TOKEN")
 	if len(hcloudToken) == 0 {
-		return "", errors.New("unable to run the test suite, HCLOUD_TOKEN environment variable cannot be empty")
+		return nil, errors.New("unable to run the test suite, HCLOUD_TOKEN environment variable cannot be empty")
 	}
 
 	tf, err := p.terraform.initAndApply()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	return tf, nil
@@ -182,55 +286,393 @@ func (p *HetznerProvisioner) Provision() (string, error) {
 
 // Cleanup destroys infrastructure created by terraform
 func (p *HetznerProvisioner) Cleanup() error {
-	err := p.terraform.destroy()
+	if err := p.terraform.destroy(); err != nil {
+		return err
+	}
+
+	_, err := executeCommand("", "rm", []string{"-rf", p.testPath}, nil)
+	return err
+}
+
+// AliCloudProvisioner describes the Alibaba Cloud provisioner
+type AliCloudProvisioner struct {
+	testPath  string
+	terraform *terraform
+}
+
+// NewAliCloudProvisioner creates and initialize the AliCloudProvisioner structure
+func NewAliCloudProvisioner(testPath, identifier string) (*AliCloudProvisioner, error) {
+	terraform, err := newTerraform(AliCloud, identifier)
 	if err != nil {
-		return fmt.Errorf("%v", err)
+		return nil, err
 	}
 
-	_, err = executeCommand("", "rm", []string{"-rf", p.testPath}, nil)
+	return &AliCloudProvisioner{
+		terraform: terraform,
+		testPath:  testPath,
+	}, nil
+}
+
+// Provision starts provisioning on Alibaba Cloud
+func (p *AliCloudProvisioner) Provision() (*TerraformOutput, error) {
+	for _, env := range []string{"ALICLOUD_ACCESS_KEY", "ALICLOUD_SECRET_KEY"} {
+		if len(os.Getenv(env)) == 0 {
+			return nil, fmt.Errorf("unable to run the test suite, %s environment variable cannot be empty", env)
+		}
+	}
+
+	tf, err := p.terraform.initAndApply()
 	if err != nil {
-		return fmt.Errorf("%v", err)
+		return nil, err
 	}
 
-	return nil
+	return tf, nil
+}
+
+// Cleanup destroys infrastructure created by terraform
+func (p *AliCloudProvisioner) Cleanup() error {
+	if err := p.terraform.destroy(); err != nil {
+		return err
+	}
+
+	_, err := executeCommand("", "rm", []string{"-rf", p.testPath}, nil)
+	return err
+}
+
+// OVHProvisioner describes the OVHcloud provisioner
+type OVHProvisioner struct {
+	testPath  string
+	terraform *terraform
+}
+
+// NewOVHProvisioner creates and initialize the OVHProvisioner structure
+func NewOVHProvisioner(testPath, identifier string) (*OVHProvisioner, error) {
+	terraform, err := newTerraform(OVH, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OVHProvisioner{
+		terraform: terraform,
+		testPath:  testPath,
+	}, nil
+}
+
+// Provision starts provisioning on OVHcloud
+func (p *OVHProvisioner) Provision() (*TerraformOutput, error) {
+	for _, env := range []string{"TF_VAR_project_id", "TF_VAR_openstack_user_name", "TF_VAR_openstack_password"} {
+		if len(os.Getenv(env)) == 0 {
+			return nil, fmt.Errorf("unable to run the test suite, %s environment variable cannot be empty", env)
+		}
+	}
+
+	tf, err := p.terraform.initAndApply()
+	if err != nil {
+		return nil, err
+	}
+
+	return tf, nil
+}
+
+// Cleanup destroys infrastructure created by terraform
+func (p *OVHProvisioner) Cleanup() error {
+	if err := p.terraform.destroy(); err != nil {
+		return err
+	}
+
+	_, err := executeCommand("", "rm", []string{"-rf", p.testPath}, nil)
+	return err
 }
 
 // initAndApply method to initialize a terraform working directory
 // and build infrastructure
-func (p *terraform) initAndApply() (string, error) {
+func (p *terraform) initAndApply() (*TerraformOutput, error) {
 	initCmd := []string{"init"}
-	if len(p.idendifier) > 0 {
+	if _, ok := p.backendConfig["key"]; !ok && len(p.idendifier) > 0 {
 		initCmd = append(initCmd, fmt.Sprintf("--backend-config=key=%s", p.idendifier))
 	}
 
-	_, err := executeCommand(p.terraformDir, "terraform", initCmd, nil)
+	// sort keys to keep the order of flags stable
+	backendKeys := make([]string, 0, len(p.backendConfig))
+	for k := range p.backendConfig {
+		backendKeys = append(backendKeys, k)
+	}
+	sort.Strings(backendKeys)
+
+	for _, k := range backendKeys {
+		// empty value is used to unset the option, e.g. the default key
+		if len(p.backendConfig[k]) == 0 {
+			continue
+		}
+		initCmd = append(initCmd, fmt.Sprintf("--backend-config=%s=%s", k, p.backendConfig[k]))
+	}
+
+	_, err := p.terraformCommand(initCmd...)
 	if err != nil {
-		return "", fmt.Errorf("terraform init command failed: %v", err)
+		return nil, p.newError(PhaseInit, err)
 	}
 
-	_, err = executeCommand(p.terraformDir, "terraform", []string{"apply", "-auto-approve"}, nil)
+	if err = p.selectWorkspace(); err != nil {
+		return nil, p.newError(PhaseInit, err)
+	}
+
+	_, err = p.terraformCommand(p.varArgs("apply", "-auto-approve")...)
 	if err != nil {
-		return "", fmt.Errorf("terraform apply command failed: %v", err)
+		return nil, p.newError(PhaseApply, err)
 	}
 
 	return p.getTFJson()
 }
 
-// destroy method
+// terraformCommand runs terraform with the given arguments in the terraform
+// directory
+func (p *terraform) terraformCommand(args ...string) (string, error) {
+	return p.terraformCommandWithContext(context.Background(), args...)
+}
+
+// terraformCommandWithContext runs terraform like terraformCommand, killing
+// it once ctx is done
+func (p *terraform) terraformCommandWithContext(ctx context.Context, args ...string) (string, error) {
+	runner := p.runner
+	if runner == nil {
+		runner = execRunner{}
+	}
+
+	return runner.ExecuteCommand(ctx, p.terraformDir, "terraform", args, nil)
+}
+
+// varArgs appends the -var flags to the given arguments, sorted by name to
+// keep the order of flags stable
+func (p *terraform) varArgs(args ...string) []string {
+	keys := make([]string, 0, len(p.vars))
+	for k := range p.vars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		args = append(args, "-var", fmt.Sprintf("%s=%s", k, p.vars[k]))
+	}
+
+	return args
+}
+
+// newError creates the ProvisionerError for the failed phase, sourcing
+// the exit code and stderr from the failed terraform command, if any
+func (p *terraform) newError(phase string, err error) *ProvisionerError {
+	pErr := &ProvisionerError{
+		Provider: p.provider,
+		Phase:    phase,
+		ExitCode: -1,
+		Err:      err,
+	}
+
+	if cmdErr, ok := err.(*commandError); ok {
+		pErr.ExitCode = cmdErr.exitCode
+		pErr.Stderr = cmdErr.stderr
+	}
+
+	return pErr
+}
+
+// destroy method, kills terraform if it doesn't finish in destroyTimeout
 func (p *terraform) destroy() error {
-	_, err := executeCommand(p.terraformDir, "terraform", []string{"destroy", "-auto-approve"}, nil)
+	// the workspace of the test run is selected first, so the state of
+	// the default workspace isn't destroyed instead
+	if len(p.WorkspaceName) > 0 {
+		if _, err := p.terraformCommand("workspace", "select", p.WorkspaceName); err != nil {
+			return p.newError(PhaseDestroy, err)
+		}
+	}
+
+	if err := p.PreDestroyCheck(); err != nil {
+		return p.newError(PhaseDestroy, fmt.Errorf("refusing to destroy infrastructure: %v", err))
+	}
+
+	timeout := p.destroyTimeout
+	if timeout == 0 {
+		timeout = defaultDestroyTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	_, err := p.terraformCommandWithContext(ctx, p.varArgs("destroy", "-auto-approve")...)
+	if ctx.Err() == context.DeadlineExceeded {
+		pErr := p.newError(PhaseDestroy, err)
+		pErr.Err = fmt.Errorf("timed out after %v: %v", timeout, err)
+		return pErr
+	}
 	if err != nil {
-		return fmt.Errorf("terraform destroy command failed: %v", err)
+		return p.newError(PhaseDestroy, err)
+	}
+
+	if err = p.deleteWorkspace(); err != nil {
+		return p.newError(PhaseDestroy, err)
 	}
 	return nil
 }
 
-// GetTFJson reads an output from a state file
-func (p *terraform) getTFJson() (string, error) {
-	tf, err := executeCommand(p.terraformDir, "terraform", []string{"output", fmt.Sprintf("-state=%v", tfStateFileName), "-json"}, nil)
+// isolate copies the terraform code to dir and runs terraform there
+func (p *terraform) isolate(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("unable to create directory %s: %v", dir, err)
+	}
+
+	// the state of previous runs isn't copied
+	_, err := executeCommand("", "cp", []string{"-r", p.terraformDir + "/.", dir}, nil)
+	if err != nil {
+		return err
+	}
+	_, err = executeCommand(dir, "rm", []string{"-rf", ".terraform", tfStateFileName}, nil)
 	if err != nil {
-		return "", fmt.Errorf("generating tf json failed: %v", err)
+		return err
+	}
+
+	p.terraformDir = dir + "/"
+	return nil
+}
+
+// selectWorkspace selects the workspace, creating it if it doesn't exist
+func (p *terraform) selectWorkspace() error {
+	if len(p.WorkspaceName) == 0 {
+		return nil
+	}
+
+	_, err := p.terraformCommand("workspace", "select", p.WorkspaceName)
+	if err == nil {
+		return nil
+	}
+
+	_, err = p.terraformCommand("workspace", "new", p.WorkspaceName)
+	return err
+}
+
+// deleteWorkspace deletes the workspace, the current workspace can't be
+// deleted so the default workspace is selected first
+func (p *terraform) deleteWorkspace() error {
+	if len(p.WorkspaceName) == 0 {
+		return nil
+	}
+
+	_, err := p.terraformCommand("workspace", "select", "default")
+	if err != nil {
+		return err
+	}
+
+	_, err = p.terraformCommand("workspace", "delete", p.WorkspaceName)
+	return err
+}
+
+// outputArgs returns arguments for the terraform output command. The state
+// file is only passed for the default workspace, as other workspaces keep
+// the state in their own location.
+func (p *terraform) outputArgs(args ...string) []string {
+	return append(append([]string{"output"}, p.stateArgs()...), args...)
+}
+
+// stateArgs returns the flags selecting the state file, the state of the
+// workspace is used if a workspace is selected
+func (p *terraform) stateArgs() []string {
+	if len(p.WorkspaceName) == 0 {
+		return []string{fmt.Sprintf("-state=%v", tfStateFileName)}
+	}
+	return nil
+}
+
+// Output reads a single scalar output value from the state
+func (p *terraform) Output(key string) (string, error) {
+	value, err := tfconfig.Output(p.terraformDir, key, p.stateArgs()...)
+	if err != nil {
+		return "", p.newError(PhaseOutput, err)
+	}
+
+	return value, nil
+}
+
+// PreDestroyCheck ensures the terraform state belongs to the test run by
+// comparing the cluster name from the state with the test run identifier.
+// A partial state, e.g. left by a failed apply, may lack the outputs, so
+// it's destroyed with a warning instead of leaking the infrastructure.
+func (p *terraform) PreDestroyCheck() error {
+	if len(p.idendifier) == 0 {
+		return nil
+	}
+
+	tfJSON, err := p.terraformCommand(p.outputArgs("-json")...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "WARNING: reading terraform state failed, destroying it anyway: %v\n", err)
+		return nil
+	}
+
+	tf, err := tfconfig.NewConfigFromJSON([]byte(tfJSON))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "WARNING: unable to parse terraform output, destroying the state anyway: %v\n", err)
+		return nil
+	}
+
+	if len(tf.KubeOneHosts.Value.ControlPlane) == 0 {
+		fmt.Fprintln(os.Stderr, "WARNING: unable to determine cluster name from terraform state, destroying it anyway")
+		return nil
+	}
+
+	clusterName := tf.ControlPlane().ClusterName
+	if clusterName != p.idendifier {
+		return fmt.Errorf("terraform state belongs to cluster %q, but the test run identifier is %q", clusterName, p.idendifier)
+	}
+
+	return nil
+}
+
+// terraformDestroyTimeout returns the timeout for terraform destroy, which can
+// be overridden using the TERRAFORM_DESTROY_TIMEOUT environment variable
+func terraformDestroyTimeout() (time.Duration, error) {
+	timeout := os.Getenv("TERRAFORM_DESTROY_TIMEOUT")
+	if len(timeout) == 0 {
+		return defaultDestroyTimeout, nil
+	}
+
+	d, err := time.ParseDuration(timeout)
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse TERRAFORM_DESTROY_TIMEOUT: %v", err)
+	}
+
+	return d, nil
+}
+
+// GetTFJson reads an output from a state file and parses it
+func (p *terraform) getTFJson() (*TerraformOutput, error) {
+	tfJSON, err := p.terraformCommand(p.outputArgs("-json")...)
+	if err != nil {
+		return nil, p.newError(PhaseOutput, err)
+	}
+
+	tf, err := ParseTerraformOutput(tfJSON)
+	if err != nil {
+		return nil, p.newError(PhaseOutput, err)
 	}
 
 	return tf, nil
 }
+
+// terraformBackendConfig returns the terraform backend configuration sourced
+// from the TERRAFORM_BACKEND_CONFIG environment variable, formatted as a
+// comma-separated list of key=value pairs
+func terraformBackendConfig() (map[string]string, error) {
+	backendConfig := map[string]string{}
+
+	cfg := os.Getenv("TERRAFORM_BACKEND_CONFIG")
+	if len(cfg) == 0 {
+		return backendConfig, nil
+	}
+
+	for _, kv := range strings.Split(cfg, ",") {
+		pair := strings.SplitN(kv, "=", 2)
+		if len(pair) != 2 || len(pair[0]) == 0 {
+			return nil, fmt.Errorf("unable to parse TERRAFORM_BACKEND_CONFIG, %q is not in key=value format", kv)
+		}
+		backendConfig[strings.TrimSpace(pair[0])] = strings.TrimSpace(pair[1])
+	}
+
+	return backendConfig, nil
+}
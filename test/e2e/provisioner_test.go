@@ -0,0 +1,298 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeRunner simulates terraform, failing the subcommands with the
+// configured errors. The subcommands starting with hang run until they
+// are killed.
+type fakeRunner struct {
+	errors map[string]error
+	hang   string
+	output string
+	calls  []string
+}
+
+func (r *fakeRunner) ExecuteCommand(ctx context.Context, _, cmd string, args []string, _ []string) (string, error) {
+	call := strings.Join(append([]string{cmd}, args...), " ")
+	r.calls = append(r.calls, call)
+
+	if r.hang != "" && strings.HasPrefix(call, r.hang) {
+		<-ctx.Done()
+		return "", &commandError{err: errors.New("signal: killed"), exitCode: -1}
+	}
+
+	for prefix, err := range r.errors {
+		if strings.HasPrefix(call, prefix) {
+			return "", err
+		}
+	}
+
+	return r.output, nil
+}
+
+func TestInitAndApplyErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		workspace     string
+		errors        map[string]error
+		output        string
+		expectedPhase string
+		expectedCode  int
+		expectedErr   string
+		expectedCalls int
+	}{
+		{
+			name: "init failure",
+			errors: map[string]error{
+				"terraform init": &commandError{err: errors.New("exit status 1"), exitCode: 1, stderr: "backend not reachable"},
+			},
+			expectedPhase: PhaseInit,
+			expectedCode:  1,
+			expectedErr:   "aws provisioner failed in init phase (exit code 1): exit status 1",
+			expectedCalls: 1,
+		},
+		{
+			name:      "workspace creation failure",
+			workspace: "e2e",
+			errors: map[string]error{
+				"terraform workspace": &commandError{err: errors.New("exit status 1"), exitCode: 1},
+			},
+			expectedPhase: PhaseInit,
+			expectedCode:  1,
+			expectedErr:   "aws provisioner failed in init phase (exit code 1): exit status 1",
+			expectedCalls: 3,
+		},
+		{
+			name: "apply failure",
+			errors: map[string]error{
+				"terraform apply": &commandError{err: errors.New("exit status 2"), exitCode: 2, stderr: "quota exceeded"},
+			},
+			expectedPhase: PhaseApply,
+			expectedCode:  2,
+			expectedErr:   "aws provisioner failed in apply phase (exit code 2): exit status 2",
+			expectedCalls: 2,
+		},
+		{
+			name: "apply not started",
+			errors: map[string]error{
+				"terraform apply": errors.New(`exec: "terraform": executable file not found in $PATH`),
+			},
+			expectedPhase: PhaseApply,
+			expectedCode:  -1,
+			expectedErr:   `aws provisioner failed in apply phase (exit code -1): exec: "terraform": executable file not found in $PATH`,
+			expectedCalls: 2,
+		},
+		{
+			name:          "invalid output",
+			output:        "not json",
+			expectedPhase: PhaseOutput,
+			expectedCode:  -1,
+			expectedErr:   "aws provisioner failed in output phase (exit code -1): unable to parse terraform output",
+			expectedCalls: 3,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			runner := &fakeRunner{errors: tc.errors, output: tc.output}
+			tf := &terraform{
+				provider:      AWS,
+				terraformDir:  "../../examples/terraform/aws/",
+				WorkspaceName: tc.workspace,
+				runner:        runner,
+			}
+
+			_, err := tf.initAndApply()
+			pErr, ok := err.(*ProvisionerError)
+			if !ok {
+				t.Fatalf("expected a *ProvisionerError, got %T: %v", err, err)
+			}
+
+			if pErr.Phase != tc.expectedPhase {
+				t.Errorf("expected phase %q, got %q", tc.expectedPhase, pErr.Phase)
+			}
+			if pErr.ExitCode != tc.expectedCode {
+				t.Errorf("expected exit code %d, got %d", tc.expectedCode, pErr.ExitCode)
+			}
+			if cmdErr, ok := tc.errors["terraform "+tc.expectedPhase].(*commandError); ok && pErr.Stderr != cmdErr.stderr {
+				t.Errorf("expected stderr %q, got %q", cmdErr.stderr, pErr.Stderr)
+			}
+			if !strings.HasPrefix(pErr.Error(), tc.expectedErr) {
+				t.Errorf("expected error starting with %q, got %q", tc.expectedErr, pErr.Error())
+			}
+			if len(runner.calls) != tc.expectedCalls {
+				t.Errorf("expected %d terraform commands, got %d: %v", tc.expectedCalls, len(runner.calls), runner.calls)
+			}
+		})
+	}
+}
+
+func TestPreDestroyCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		errors      map[string]error
+		output      string
+		expectedErr bool
+	}{
+		{
+			name: "unreadable state",
+			errors: map[string]error{
+				"terraform output": &commandError{err: errors.New("exit status 1"), exitCode: 1},
+			},
+		},
+		{
+			name:   "partial state",
+			output: `{}`,
+		},
+		{
+			name:   "state of the test run",
+			output: `{"kubeone_schema_version": {"value": "2"}, "kubeone_hosts": {"value": {"control_plane": [{"cluster_name": "e2e-1", "public_address": ["192.0.2.1"]}]}}}`,
+		},
+		{
+			name:        "state of another cluster",
+			output:      `{"kubeone_schema_version": {"value": "2"}, "kubeone_hosts": {"value": {"control_plane": [{"cluster_name": "other", "public_address": ["192.0.2.1"]}]}}}`,
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			tf := &terraform{
+				provider:   AWS,
+				idendifier: "e2e-1",
+				runner:     &fakeRunner{errors: tc.errors, output: tc.output},
+			}
+
+			err := tf.PreDestroyCheck()
+			if tc.expectedErr && err == nil {
+				t.Error("expected the destroy to be refused")
+			}
+			if !tc.expectedErr && err != nil {
+				t.Errorf("expected the destroy to be allowed, got %v", err)
+			}
+		})
+	}
+}
+
+func TestDestroySelectsWorkspace(t *testing.T) {
+	runner := &fakeRunner{errors: map[string]error{
+		"terraform workspace select": &commandError{err: errors.New("exit status 1"), exitCode: 1},
+	}}
+	tf := &terraform{
+		provider:      AWS,
+		WorkspaceName: "e2e",
+		runner:        runner,
+	}
+
+	err := tf.destroy()
+	if pErr, ok := err.(*ProvisionerError); !ok || pErr.Phase != PhaseDestroy {
+		t.Fatalf("expected a destroy phase error, got %v", err)
+	}
+	if len(runner.calls) != 1 || runner.calls[0] != "terraform workspace select e2e" {
+		t.Errorf("expected only the workspace to be selected, got %v", runner.calls)
+	}
+}
+
+func TestDestroyErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		workspace     string
+		errors        map[string]error
+		hang          string
+		expectedErr   string
+		expectedCode  int
+		expectedCalls []string
+	}{
+		{
+			name:          "destroyed",
+			workspace:     "e2e",
+			expectedCalls: []string{"terraform workspace select e2e", "terraform output", "terraform destroy", "terraform workspace select default", "terraform workspace delete e2e"},
+		},
+		{
+			name: "destroy failure",
+			errors: map[string]error{
+				"terraform destroy": &commandError{err: errors.New("exit status 1"), exitCode: 1, stderr: "dependency violation"},
+			},
+			expectedErr:   "aws provisioner failed in destroy phase (exit code 1): exit status 1",
+			expectedCode:  1,
+			expectedCalls: []string{"terraform output", "terraform destroy"},
+		},
+		{
+			name:          "destroy timeout",
+			workspace:     "e2e",
+			hang:          "terraform destroy",
+			expectedErr:   "aws provisioner failed in destroy phase (exit code -1): timed out after 10ms",
+			expectedCode:  -1,
+			expectedCalls: []string{"terraform workspace select e2e", "terraform output", "terraform destroy"},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			output := `{"kubeone_schema_version": {"value": "2"}, "kubeone_hosts": {"value": {"control_plane": [{"cluster_name": "e2e-1", "public_address": ["192.0.2.1"]}]}}}`
+			runner := &fakeRunner{errors: tc.errors, hang: tc.hang, output: output}
+			tf := &terraform{
+				provider:       AWS,
+				idendifier:     "e2e-1",
+				WorkspaceName:  tc.workspace,
+				destroyTimeout: 10 * time.Millisecond,
+				runner:         runner,
+			}
+
+			err := tf.destroy()
+			if tc.expectedErr == "" {
+				if err != nil {
+					t.Fatalf("expected the destroy to succeed, got %v", err)
+				}
+			} else {
+				pErr, ok := err.(*ProvisionerError)
+				if !ok {
+					t.Fatalf("expected a *ProvisionerError, got %T: %v", err, err)
+				}
+				if pErr.Phase != PhaseDestroy {
+					t.Errorf("expected phase %q, got %q", PhaseDestroy, pErr.Phase)
+				}
+				if pErr.ExitCode != tc.expectedCode {
+					t.Errorf("expected exit code %d, got %d", tc.expectedCode, pErr.ExitCode)
+				}
+				if !strings.HasPrefix(pErr.Error(), tc.expectedErr) {
+					t.Errorf("expected error starting with %q, got %q", tc.expectedErr, pErr.Error())
+				}
+			}
+
+			if len(runner.calls) != len(tc.expectedCalls) {
+				t.Fatalf("expected terraform commands %v, got %v", tc.expectedCalls, runner.calls)
+			}
+			for i, call := range runner.calls {
+				if !strings.HasPrefix(call, tc.expectedCalls[i]) {
+					t.Errorf("expected command %d to start with %q, got %q", i, tc.expectedCalls[i], call)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// scenarioEnvVar selects the scenario run by TestSelectedScenario
+const scenarioEnvVar = "TEST_SCENARIO"
+
+// Scenarios selectable with TEST_SCENARIO
+const (
+	ScenarioBasic              = "basic"
+	ScenarioUpgrade            = "upgrade"
+	ScenarioScale              = "scale"
+	ScenarioMachineReplacement = "machine-replacement"
+	ScenarioReset              = "reset"
+)
+
+// TestScenario is an end-to-end test run against a cluster provisioned and
+// installed by TestSelectedScenario
+type TestScenario interface {
+	// Name is the TEST_SCENARIO value selecting the scenario
+	Name() string
+	// InstallInitialVersion installs the initial instead of the target
+	// Kubernetes version, e.g. to upgrade the cluster to the target version
+	InstallInitialVersion() bool
+	// Run runs the scenario against the installed cluster
+	Run(t *testing.T, env *ScenarioEnv) error
+}
+
+// ScenarioEnv is the cluster a scenario runs against
+type ScenarioEnv struct {
+	TestPath              string
+	Provisioner           Provisioner
+	Kubeone               Kubeone
+	Client                dynclient.Client
+	InitialVersion        string
+	TargetVersion         string
+	TargetConfigPath      string
+	ExpectedNumberOfNodes int
+	// Reset is set by scenarios resetting the cluster themselves, so the
+	// cleanup only destroys the infrastructure
+	Reset bool
+}
+
+// scenario implements TestScenario with a function
+type scenario struct {
+	name                  string
+	installInitialVersion bool
+	run                   func(t *testing.T, env *ScenarioEnv) error
+}
+
+func (s scenario) Name() string {
+	return s.name
+}
+
+func (s scenario) InstallInitialVersion() bool {
+	return s.installInitialVersion
+}
+
+func (s scenario) Run(t *testing.T, env *ScenarioEnv) error {
+	return s.run(t, env)
+}
+
+var scenarios = map[string]TestScenario{}
+
+// RegisterScenario makes the scenario selectable with TEST_SCENARIO
+func RegisterScenario(s TestScenario) {
+	scenarios[s.Name()] = s
+}
+
+// SelectedScenario returns the scenario selected with TEST_SCENARIO,
+// defaulting to the basic scenario
+func SelectedScenario() (TestScenario, error) {
+	name := os.Getenv(scenarioEnvVar)
+	if name == "" {
+		name = ScenarioBasic
+	}
+
+	s, ok := scenarios[name]
+	if !ok {
+		names := make([]string, 0, len(scenarios))
+		for n := range scenarios {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+
+		return nil, errors.Errorf("unknown %s %q, must be one of %v", scenarioEnvVar, name, names)
+	}
+
+	return s, nil
+}
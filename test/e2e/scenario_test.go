@@ -0,0 +1,347 @@
+// +build e2e
+
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	clusterv1alpha1 "sigs.k8s.io/cluster-api/pkg/apis/cluster/v1alpha1"
+	clusterscheme "sigs.k8s.io/cluster-api/pkg/client/clientset_generated/clientset/scheme"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func init() {
+	RegisterScenario(scenario{name: ScenarioBasic, run: runBasicScenario})
+	RegisterScenario(scenario{name: ScenarioUpgrade, installInitialVersion: true, run: runUpgradeScenario})
+	RegisterScenario(scenario{name: ScenarioScale, run: runScaleScenario})
+	RegisterScenario(scenario{name: ScenarioMachineReplacement, run: runMachineReplacementScenario})
+	RegisterScenario(scenario{name: ScenarioReset, run: runResetScenario})
+}
+
+// TestSelectedScenario provisions a cluster and runs the scenario selected
+// with the TEST_SCENARIO environment variable against it
+func TestSelectedScenario(t *testing.T) {
+	t.Parallel()
+
+	testcases := []struct {
+		provider              string
+		initialVersion        string
+		targetVersion         string
+		initialConfigPath     string
+		targetConfigPath      string
+		expectedNumberOfNodes int
+	}{
+		{
+			provider:              AWS,
+			initialVersion:        "v1.13.5",
+			targetVersion:         "v1.14.1",
+			initialConfigPath:     "../../test/e2e/testdata/config_aws_1.13.5.yaml",
+			targetConfigPath:      "../../test/e2e/testdata/config_aws_1.14.1.yaml",
+			expectedNumberOfNodes: 6, // 3 control planes + 3 workers
+		},
+		{
+			provider:              DigitalOcean,
+			initialVersion:        "v1.13.5",
+			targetVersion:         "v1.14.1",
+			initialConfigPath:     "../../test/e2e/testdata/config_do_1.13.5.yaml",
+			targetConfigPath:      "../../test/e2e/testdata/config_do_1.14.1.yaml",
+			expectedNumberOfNodes: 6, // 3 control planes + 3 workers
+		},
+		{
+			provider:              Hetzner,
+			initialVersion:        "v1.13.5",
+			targetVersion:         "v1.14.1",
+			initialConfigPath:     "../../test/e2e/testdata/config_hetzner_1.13.5.yaml",
+			targetConfigPath:      "../../test/e2e/testdata/config_hetzner_1.14.1.yaml",
+			expectedNumberOfNodes: 6, // 3 control planes + 3 workers
+		},
+	}
+
+	s, err := SelectedScenario()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range testcases {
+		// to satisfy scope linter
+		tc := tc
+		t.Run(fmt.Sprintf("%s scenario on %s", s.Name(), tc.provider), func(t *testing.T) {
+			if len(testRunIdentifier) == 0 {
+				t.Fatalf("-identifier must be set")
+			}
+			if testProvider != tc.provider {
+				t.SkipNow()
+			}
+			if testClusterVersion != tc.targetVersion {
+				t.SkipNow()
+			}
+			testPath := fmt.Sprintf("../../_build/%s", testRunIdentifier)
+
+			pr, err := CreateProvisioner(testPath, testRunIdentifier, tc.provider)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			version, configPath := tc.targetVersion, tc.targetConfigPath
+			if s.InstallInitialVersion() {
+				version, configPath = tc.initialVersion, tc.initialConfigPath
+			}
+
+			env := &ScenarioEnv{
+				TestPath:              testPath,
+				Provisioner:           pr,
+				Kubeone:               NewKubeone(testPath, configPath),
+				InitialVersion:        tc.initialVersion,
+				TargetVersion:         tc.targetVersion,
+				TargetConfigPath:      tc.targetConfigPath,
+				ExpectedNumberOfNodes: tc.expectedNumberOfNodes,
+			}
+
+			t.Log("check prerequisites")
+			err = ValidateCommon()
+			if err != nil {
+				t.Fatalf("%v", err)
+			}
+
+			defer func() {
+				t.Log("cleanup ....")
+				if !env.Reset {
+					if errKubeone := env.Kubeone.Reset(); errKubeone != nil {
+						t.Errorf("%v", errKubeone)
+					}
+				}
+				if errProvisioner := pr.Cleanup(); errProvisioner != nil {
+					t.Errorf("%v", errProvisioner)
+				}
+			}()
+
+			t.Log("start provisioning")
+			tf, err := pr.Provision()
+			if err != nil {
+				t.Fatalf("provisioning failed: %v", err)
+			}
+
+			t.Log("start cluster deployment")
+			err = env.Kubeone.Install(tf)
+			if err != nil {
+				t.Fatalf("k8s cluster deployment failed: %v", err)
+			}
+
+			t.Log("create kubeconfig")
+			kubeconfig, err := env.Kubeone.CreateKubeconfig()
+			if err != nil {
+				t.Fatalf("creating kubeconfig failed: %v", err)
+			}
+
+			t.Log("build kubernetes clientset")
+			restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+			if err != nil {
+				t.Fatalf("unable to build config from kubeconfig bytes: %v", err)
+			}
+
+			if err = clusterscheme.AddToScheme(scheme.Scheme); err != nil {
+				t.Fatalf("failed to register cluster-api scheme: %v", err)
+			}
+
+			env.Client, err = dynclient.New(restConfig, dynclient.Options{})
+			if err != nil {
+				t.Fatalf("failed to init dynamic client: %s", err)
+			}
+
+			t.Log("waiting for nodes to become ready")
+			err = waitForNodesReady(env.Client, env.ExpectedNumberOfNodes)
+			if err != nil {
+				t.Fatalf("nodes are not ready: %v", err)
+			}
+
+			t.Log("verifying cluster version")
+			err = verifyVersion(env.Client, metav1.NamespaceSystem, version)
+			if err != nil {
+				t.Fatalf("version mismatch: %v", err)
+			}
+
+			t.Logf("run %s scenario", s.Name())
+			if err = s.Run(t, env); err != nil {
+				t.Fatalf("%s scenario failed: %v", s.Name(), err)
+			}
+		})
+	}
+}
+
+// runBasicScenario smoke tests the installed cluster
+func runBasicScenario(t *testing.T, env *ScenarioEnv) error {
+	t.Log("waiting for kube-system pods to run")
+	return waitForPodsRunning(env.Client, metav1.NamespaceSystem)
+}
+
+// runUpgradeScenario upgrades the cluster from the initial to the target version
+func runUpgradeScenario(t *testing.T, env *ScenarioEnv) error {
+	t.Logf("waiting %s for nodes to settle down", delayUpgrade.String())
+	time.Sleep(delayUpgrade)
+
+	// the cleanup resets the cluster using the target configuration
+	env.Kubeone = NewKubeone(env.TestPath, env.TargetConfigPath)
+
+	t.Log("start cluster upgrade")
+	if err := env.Kubeone.Upgrade(); err != nil {
+		return err
+	}
+
+	t.Log("waiting for nodes to become ready")
+	if err := waitForNodesReady(env.Client, env.ExpectedNumberOfNodes); err != nil {
+		return errors.Wrap(err, "nodes are not ready")
+	}
+
+	t.Log("verifying cluster version after upgrade")
+	if err := verifyVersion(env.Client, metav1.NamespaceSystem, env.TargetVersion); err != nil {
+		return errors.Wrap(err, "version mismatch after running upgrade")
+	}
+
+	t.Log("polling nodes to verify are all workers upgraded")
+	return errors.Wrap(waitForNodesUpgraded(env.Client, env.TargetVersion), "nodes are not running the target version")
+}
+
+// runScaleScenario scales the first worker MachineDeployment up and back down
+func runScaleScenario(t *testing.T, env *ScenarioEnv) error {
+	t.Log("scale MachineDeployment up")
+	if err := scaleMachineDeployment(env.Client, 1); err != nil {
+		return err
+	}
+
+	t.Log("waiting for the new node to become ready")
+	if err := waitForNodesReady(env.Client, env.ExpectedNumberOfNodes+1); err != nil {
+		return errors.Wrap(err, "nodes are not ready after scaling up")
+	}
+
+	t.Log("scale MachineDeployment down")
+	if err := scaleMachineDeployment(env.Client, -1); err != nil {
+		return err
+	}
+
+	t.Log("waiting for the node to be removed")
+	return errors.Wrap(waitForNodesReady(env.Client, env.ExpectedNumberOfNodes), "nodes are not ready after scaling down")
+}
+
+// runMachineReplacementScenario deletes a worker Machine and waits for
+// machine-controller to replace it
+func runMachineReplacementScenario(t *testing.T, env *ScenarioEnv) error {
+	machines := clusterv1alpha1.MachineList{}
+	listOpts := &dynclient.ListOptions{Namespace: metav1.NamespaceSystem}
+	if err := env.Client.List(context.Background(), listOpts, &machines); err != nil {
+		return errors.Wrap(err, "failed to list Machines")
+	}
+	if len(machines.Items) == 0 {
+		return errors.New("no worker Machines found")
+	}
+
+	deleted := machines.Items[0]
+	t.Logf("delete Machine %s", deleted.Name)
+	if err := env.Client.Delete(context.Background(), &deleted); err != nil {
+		return errors.Wrapf(err, "failed to delete Machine %s", deleted.Name)
+	}
+
+	t.Log("waiting for the Machine to be replaced")
+	err := wait.Poll(10*time.Second, 15*time.Minute, func() (bool, error) {
+		current := clusterv1alpha1.MachineList{}
+		if err := env.Client.List(context.Background(), listOpts, &current); err != nil {
+			return false, errors.Wrap(err, "failed to list Machines")
+		}
+		if len(current.Items) != len(machines.Items) {
+			return false, nil
+		}
+		for _, m := range current.Items {
+			if m.Name == deleted.Name || m.Status.NodeRef == nil {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		return errors.Wrapf(err, "Machine %s was not replaced", deleted.Name)
+	}
+
+	t.Log("waiting for nodes to become ready")
+	return errors.Wrap(waitForNodesReady(env.Client, env.ExpectedNumberOfNodes), "nodes are not ready")
+}
+
+// runResetScenario resets the cluster and verifies the API server is gone
+func runResetScenario(t *testing.T, env *ScenarioEnv) error {
+	t.Log("reset cluster")
+	env.Reset = true
+	if err := env.Kubeone.Reset(); err != nil {
+		return err
+	}
+
+	t.Log("verifying the API server is no longer serving")
+	err := wait.Poll(5*time.Second, 2*time.Minute, func() (bool, error) {
+		nodes := corev1.NodeList{}
+		return env.Client.List(context.Background(), &dynclient.ListOptions{}, &nodes) != nil, nil
+	})
+	return errors.Wrap(err, "API server still serving after reset")
+}
+
+// waitForPodsRunning waits for all pods of the namespace to be running or completed
+func waitForPodsRunning(client dynclient.Client, namespace string) error {
+	return wait.Poll(5*time.Second, 5*time.Minute, func() (bool, error) {
+		pods := corev1.PodList{}
+		if err := client.List(context.Background(), &dynclient.ListOptions{Namespace: namespace}, &pods); err != nil {
+			return false, errors.Wrap(err, "unable to list pods")
+		}
+
+		for _, p := range pods.Items {
+			if p.Status.Phase != corev1.PodRunning && p.Status.Phase != corev1.PodSucceeded {
+				return false, nil
+			}
+		}
+		return len(pods.Items) > 0, nil
+	})
+}
+
+// scaleMachineDeployment changes the replicas of the first worker
+// MachineDeployment by delta
+func scaleMachineDeployment(client dynclient.Client, delta int32) error {
+	machineDeployments := clusterv1alpha1.MachineDeploymentList{}
+	listOpts := &dynclient.ListOptions{Namespace: metav1.NamespaceSystem}
+	if err := client.List(context.Background(), listOpts, &machineDeployments); err != nil {
+		return errors.Wrap(err, "failed to list MachineDeployments")
+	}
+	if len(machineDeployments.Items) == 0 {
+		return errors.New("no worker MachineDeployments found")
+	}
+
+	md := machineDeployments.Items[0]
+	var replicas int32
+	if md.Spec.Replicas != nil {
+		replicas = *md.Spec.Replicas
+	}
+	replicas += delta
+	md.Spec.Replicas = &replicas
+
+	return errors.Wrapf(client.Update(context.Background(), &md), "failed to scale MachineDeployment %s", md.Name)
+}
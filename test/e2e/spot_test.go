@@ -0,0 +1,273 @@
+// +build e2e
+
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	clusterv1alpha1 "sigs.k8s.io/cluster-api/pkg/apis/cluster/v1alpha1"
+	clusterscheme "sigs.k8s.io/cluster-api/pkg/client/clientset_generated/clientset/scheme"
+	dynclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestAWSSpotInstanceReplacement(t *testing.T) {
+	t.Parallel()
+
+	const (
+		provider              = AWS
+		kubernetesVersion     = "v1.14.1"
+		configFilePath        = "../../test/e2e/testdata/config_aws_1.14.1.yaml"
+		expectedNumberOfNodes = 6 // 3 control planes + 3 workers
+	)
+
+	if len(testRunIdentifier) == 0 {
+		t.Fatalf("-identifier must be set")
+	}
+	if testProvider != provider || testClusterVersion != kubernetesVersion {
+		t.SkipNow()
+	}
+	testPath := fmt.Sprintf("../../_build/%s", testRunIdentifier)
+
+	pr, err := CreateProvisioner(testPath, testRunIdentifier, provider)
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := NewKubeone(testPath, configFilePath)
+
+	t.Log("check prerequisites")
+	err = ValidateCommon()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	teardown := setupTearDown(pr, target)
+	defer teardown(t)
+
+	t.Log("start provisioning")
+	tf, err := pr.Provision()
+	if err != nil {
+		t.Fatalf("provisioning failed: %v", err)
+	}
+
+	t.Log("start cluster deployment")
+	err = target.Install(tf)
+	if err != nil {
+		t.Fatalf("k8s cluster deployment failed: %v", err)
+	}
+
+	t.Log("create kubeconfig")
+	kubeconfig, err := target.CreateKubeconfig()
+	if err != nil {
+		t.Fatalf("creating kubeconfig failed: %v", err)
+	}
+
+	t.Log("build kubernetes clientset")
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		t.Fatalf("unable to build config from kubeconfig bytes: %v", err)
+	}
+
+	if err = clusterscheme.AddToScheme(scheme.Scheme); err != nil {
+		t.Fatalf("failed to register cluster-api scheme: %v", err)
+	}
+
+	client, err := dynclient.New(restConfig, dynclient.Options{})
+	if err != nil {
+		t.Fatalf("failed to init dynamic client: %s", err)
+	}
+
+	t.Log("waiting for nodes to become ready")
+	err = waitForNodesReady(client, expectedNumberOfNodes)
+	if err != nil {
+		t.Fatalf("nodes are not ready: %v", err)
+	}
+
+	t.Log("create spot instance MachineDeployment")
+	md, err := createSpotMachineDeployment(client, testRunIdentifier)
+	if err != nil {
+		t.Fatalf("creating MachineDeployment failed: %v", err)
+	}
+
+	t.Log("waiting for spot instance node to become ready")
+	node, err := waitForMachineDeploymentNode(client, md, "", 15*time.Minute)
+	if err != nil {
+		t.Fatalf("spot instance node is not ready: %v", err)
+	}
+
+	t.Logf("terminate spot instance %s", node.Spec.ProviderID)
+	err = terminateInstance(node.Spec.ProviderID)
+	if err != nil {
+		t.Fatalf("terminating spot instance failed: %v", err)
+	}
+
+	t.Log("waiting for replacement node to become ready")
+	_, err = waitForMachineDeploymentNode(client, md, node.Spec.ProviderID, 15*time.Minute)
+	if err != nil {
+		t.Fatalf("spot instance was not replaced: %v", err)
+	}
+}
+
+// createSpotMachineDeployment creates a single replica MachineDeployment
+// requesting spot instances, using the machine template of the first
+// MachineDeployment deployed by KubeOne
+func createSpotMachineDeployment(client dynclient.Client, identifier string) (*clusterv1alpha1.MachineDeployment, error) {
+	ctx := context.Background()
+
+	machineDeployments := clusterv1alpha1.MachineDeploymentList{}
+	listOpts := &dynclient.ListOptions{Namespace: metav1.NamespaceSystem}
+	if err := client.List(ctx, listOpts, &machineDeployments); err != nil {
+		return nil, fmt.Errorf("unable to list MachineDeployments: %v", err)
+	}
+	if len(machineDeployments.Items) == 0 {
+		return nil, fmt.Errorf("no MachineDeployments found")
+	}
+
+	name := fmt.Sprintf("%s-e2e-spot", identifier)
+	selector := map[string]string{"e2e-spot": name}
+
+	template := machineDeployments.Items[0].Spec.Template.DeepCopy()
+	template.Labels = selector
+
+	providerSpec := map[string]interface{}{}
+	if err := json.Unmarshal(template.Spec.ProviderSpec.Value.Raw, &providerSpec); err != nil {
+		return nil, fmt.Errorf("unable to parse providerSpec: %v", err)
+	}
+	cloudProviderSpec, ok := providerSpec["cloudProviderSpec"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("providerSpec has no cloudProviderSpec")
+	}
+	cloudProviderSpec["isSpotInstance"] = true
+
+	encoded, err := json.Marshal(providerSpec)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode providerSpec: %v", err)
+	}
+	template.Spec.ProviderSpec.Value.Raw = encoded
+
+	var replicas int32 = 1
+	md := &clusterv1alpha1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: metav1.NamespaceSystem,
+		},
+		Spec: clusterv1alpha1.MachineDeploymentSpec{
+			Replicas: &replicas,
+			Selector: metav1.LabelSelector{MatchLabels: selector},
+			Template: *template,
+		},
+	}
+
+	if err := client.Create(ctx, md); err != nil {
+		return nil, fmt.Errorf("unable to create MachineDeployment: %v", err)
+	}
+
+	return md, nil
+}
+
+// waitForMachineDeploymentNode waits for a ready node backing a machine of
+// the MachineDeployment, other than the one with the given provider ID
+func waitForMachineDeploymentNode(client dynclient.Client, md *clusterv1alpha1.MachineDeployment, excludedProviderID string, timeout time.Duration) (*corev1.Node, error) {
+	ctx := context.Background()
+
+	listOpts := &dynclient.ListOptions{Namespace: md.Namespace}
+	if err := listOpts.SetLabelSelector(fmt.Sprintf("e2e-spot=%s", md.Name)); err != nil {
+		return nil, fmt.Errorf("failed to parse the label selector: %v", err)
+	}
+
+	var ready *corev1.Node
+	err := wait.Poll(10*time.Second, timeout, func() (bool, error) {
+		machines := clusterv1alpha1.MachineList{}
+		if err := client.List(ctx, listOpts, &machines); err != nil {
+			return false, fmt.Errorf("unable to list machines: %v", err)
+		}
+
+		for _, machine := range machines.Items {
+			if machine.Status.NodeRef == nil {
+				continue
+			}
+
+			node := &corev1.Node{}
+			if err := client.Get(ctx, types.NamespacedName{Name: machine.Status.NodeRef.Name}, node); err != nil {
+				continue
+			}
+			if node.Spec.ProviderID == excludedProviderID {
+				continue
+			}
+
+			if nodeReady(node) {
+				ready = node
+				return true, nil
+			}
+		}
+
+		return false, nil
+	})
+
+	return ready, err
+}
+
+func nodeReady(node *corev1.Node) bool {
+	for _, c := range node.Status.Conditions {
+		if c.Type == corev1.NodeReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// terminateInstance terminates the EC2 instance with the given provider ID,
+// e.g. aws:///eu-west-3a/i-0123456789abcdef0, the way AWS interrupts spot
+// instances
+func terminateInstance(providerID string) error {
+	parts := strings.Split(strings.TrimPrefix(providerID, "aws:///"), "/")
+	if len(parts) != 2 || len(parts[0]) == 0 {
+		return fmt.Errorf("unexpected provider ID %q", providerID)
+	}
+	zone, instanceID := parts[0], parts[1]
+	region := zone[:len(zone)-1]
+
+	sess, err := session.NewSession(&aws.Config{Region: aws.String(region)})
+	if err != nil {
+		return fmt.Errorf("unable to create AWS session: %v", err)
+	}
+
+	_, err = ec2.New(sess).TerminateInstances(&ec2.TerminateInstancesInput{
+		InstanceIds: []*string{aws.String(instanceID)},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to terminate instance %s: %v", instanceID, err)
+	}
+
+	return nil
+}
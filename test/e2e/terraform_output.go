@@ -0,0 +1,50 @@
+/*
+Copyright 2019 The KubeOne Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"errors"
+	"fmt"
+
+	tfconfig "github.com/kubermatic/kubeone/pkg/terraform"
+)
+
+// TerraformOutput is the terraform output consumed by KubeOne
+type TerraformOutput struct {
+	*tfconfig.Config
+
+	// Raw is the output as printed by terraform, passed to KubeOne as is
+	Raw []byte
+}
+
+// ParseTerraformOutput unmarshals and validates the terraform output
+func ParseTerraformOutput(raw string) (*TerraformOutput, error) {
+	tf, err := tfconfig.NewConfigFromJSON([]byte(raw))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse terraform output: %v", err)
+	}
+
+	if err = tf.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid terraform output: %v", err)
+	}
+
+	if len(tf.APIServer()) == 0 {
+		return nil, errors.New("invalid terraform output: kube-apiserver endpoint is missing")
+	}
+
+	return &TerraformOutput{Config: tf, Raw: []byte(raw)}, nil
+}